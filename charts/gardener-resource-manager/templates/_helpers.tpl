@@ -0,0 +1,41 @@
+{{- define "gardener-resource-manager.namespacedRules" -}}
+- apiGroups:
+  - resources.gardener.cloud
+  resources:
+  - managedresources
+  - managedresources/status
+  verbs:
+  - get
+  - list
+  - watch
+  - patch
+  - update
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+{{- end -}}
+
+{{- define "gardener-resource-manager.rateLimiterFlags" -}}
+{{- with .rateLimiter }}
+{{- if .baseDelay }}
+- --{{ $.prefix }}rate-limiter-base-delay={{ .baseDelay }}
+{{- end }}
+{{- if .maxDelay }}
+- --{{ $.prefix }}rate-limiter-max-delay={{ .maxDelay }}
+{{- end }}
+{{- if .qps }}
+- --{{ $.prefix }}rate-limiter-qps={{ .qps }}
+{{- end }}
+{{- if .burst }}
+- --{{ $.prefix }}rate-limiter-burst={{ .burst }}
+{{- end }}
+{{- end }}
+{{- end -}}
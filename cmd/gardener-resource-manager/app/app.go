@@ -17,35 +17,77 @@ package app
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/validation"
+	resourcesv1beta1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1beta1"
+	"github.com/gardener/gardener-resource-manager/pkg/audit"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/kubeconfig"
 	"github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
 	"github.com/gardener/gardener-resource-manager/pkg/controller/managedresources/health"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/networkpolicy"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/node"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/tokenrequestor"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	"github.com/gardener/gardener-resource-manager/pkg/debug"
+	"github.com/gardener/gardener-resource-manager/pkg/defaults"
+	"github.com/gardener/gardener-resource-manager/pkg/features"
+	"github.com/gardener/gardener-resource-manager/pkg/healthz"
 	logpkg "github.com/gardener/gardener-resource-manager/pkg/log"
 	"github.com/gardener/gardener-resource-manager/pkg/mapper"
+	"github.com/gardener/gardener-resource-manager/pkg/metrics"
 	managerpredicate "github.com/gardener/gardener-resource-manager/pkg/predicate"
+	"github.com/gardener/gardener-resource-manager/pkg/tracing"
+	"github.com/gardener/gardener-resource-manager/pkg/version"
+	"github.com/gardener/gardener-resource-manager/pkg/webhook/certificates"
+	"github.com/gardener/gardener-resource-manager/pkg/webhook/highavailability"
+	"github.com/gardener/gardener-resource-manager/pkg/webhook/kubernetesservicehost"
+	managedresourcewebhook "github.com/gardener/gardener-resource-manager/pkg/webhook/managedresource"
+	"github.com/gardener/gardener-resource-manager/pkg/webhook/podschedulername"
+	"github.com/gardener/gardener-resource-manager/pkg/webhook/projectedtokenmount"
+	"github.com/gardener/gardener-resource-manager/pkg/webhook/protection"
+	"github.com/gardener/gardener-resource-manager/pkg/webhook/secretpolicy"
+	"github.com/gardener/gardener-resource-manager/pkg/webhook/tokeninvalidator"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	extensionspredicate "github.com/gardener/gardener/extensions/pkg/predicate"
 	hvpav1alpha1 "github.com/gardener/hvpa-controller/api/v1alpha1"
+	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apiextensionsinstall "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	memcache "k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
+	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	apiregistrationinstall "k8s.io/kube-aggregator/pkg/apis/apiregistration/install"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -54,22 +96,47 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	runtimehealthz "sigs.k8s.io/controller-runtime/pkg/healthz"
 	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 )
 
 var log = runtimelog.Log.WithName("gardener-resource-manager")
 
+// controllerLoggerNames are the names of the components whose log level can be overridden.
+var controllerLoggerNames = sets.NewString("reconciler", "secret-reconciler", "health-reconciler", "garbage-collector", "token-requestor", "network-policy-controller", "node-controller", "target-kubeconfig-controller")
+
+// refinedFlags maps flags to the flag whose behavior they refine, i.e. without which they have no effect.
+var refinedFlags = map[string]string{
+	"--protection-allowed-users":               "--protect-managed-objects",
+	"--high-availability-min-replicas":         "--high-availability",
+	"--webhook-server-dns-names":               "--webhook-certificate-secret",
+	"--tracing-insecure":                       "--tracing-endpoint",
+	"--tracing-sampling-ratio":                 "--tracing-endpoint",
+	"--shard-index":                            "--shards",
+	"--garbage-collector-sync-period":          "--garbage-collector",
+	"--garbage-collector-min-age":              "--garbage-collector",
+	"--garbage-collector-dry-run":              "--garbage-collector",
+	"--keep-objects-ttl":                       "--garbage-collector",
+	"--token-requestor-max-concurrent-workers": "--token-requestor",
+	"--network-policy-controller-namespaces":   "--network-policy-controller",
+	"--network-policy-max-concurrent-workers":  "--network-policy-controller",
+	"--node-max-concurrent-workers":            "--node-controller",
+}
+
 // NewControllerManagerCommand creates a new command for running a gardener resource manager controllers.
 func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
-	runtimelog.SetLogger(logpkg.ZapLogger(false))
 	entryLog := log.WithName("entrypoint")
 
 	var (
 		leaderElection              bool
+		leaderElectionID            string
 		leaderElectionNamespace     string
 		leaderElectionLeaseDuration time.Duration
 		leaderElectionRenewDeadline time.Duration
@@ -78,18 +145,112 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 		cacheResyncPeriod       time.Duration
 		targetCacheResyncPeriod time.Duration
 		syncPeriod              time.Duration
+		syncJitter              float64
+		maxResyncDeferral       time.Duration
 		healthSyncPeriod        time.Duration
 
+		gracefulShutdownTimeout time.Duration
+		statusDebounceWindow    time.Duration
+		discoveryCacheTTL       time.Duration
+
 		maxConcurrentWorkers       int
 		secretMaxConcurrentWorkers int
 		healthMaxConcurrentWorkers int
+		maxConcurrentApplies       int
+		cacheDecodedObjects        bool
+		namespaceRateLimiterQPS    float64
+		namespaceRateLimiterBurst  int
+
+		tokenRequestor                     bool
+		tokenRequestorMaxConcurrentWorkers int
+
+		networkPolicyController           bool
+		networkPolicyControllerNamespaces []string
+		networkPolicyMaxConcurrentWorkers int
+
+		nodeController           bool
+		nodeMaxConcurrentWorkers int
+
+		garbageCollector        bool
+		garbageCollectorOptions managedresources.GarbageCollectorOptions
+		keepObjectsTTL          time.Duration
+		pruneSkipKinds          []string
+		pruneGracePeriod        time.Duration
+		deletionLabelSelector   string
+
+		clusterScopedObjectsPolicy map[string]string
+		restrictObjectNamespaces   map[string]string
+
+		stripFinalizers        []string
+		stripFinalizersTimeout time.Duration
+
+		waitForReadyTimeout time.Duration
+		reconcileTimeout    time.Duration
+
+		upgradeDeprecatedAPIs bool
+		strictDecoding        bool
+		duplicateObjects      string
+
+		rateLimiter       = utils.DefaultRateLimiterOptions()
+		secretRateLimiter = utils.DefaultRateLimiterOptions()
+		healthRateLimiter = utils.DefaultRateLimiterOptions()
+
+		targetKubeconfigPath   string
+		targetKubeconfigSecret string
+		kubeconfigPath         string
+
+		namespaces                   []string
+		resourceClass                string
+		clusterID                    string
+		managedResourceLabelSelector string
+		shards                       int
+		shardIndex                   int
+		alwaysUpdate                 bool
+
+		logLevel          string
+		logFormat         string
+		logLevelOverrides map[string]string
+
+		logErrorSamplingInterval time.Duration
+
+		tracingEndpoint      string
+		tracingInsecure      bool
+		tracingSamplingRatio float64
+
+		debugBindAddress string
+		enablePprof      bool
+
+		auditLogPath string
+		targetEvents bool
+
+		healthBindAddress       string
+		targetReachabilityCheck bool
 
-		targetKubeconfigPath string
-		kubeconfigPath       string
+		metricsBindAddress   string
+		metricsServerOptions metrics.ServerOptions
 
-		namespace     string
-		resourceClass string
-		alwaysUpdate  bool
+		webhookServerPort    int
+		webhookServerCertDir string
+
+		webhookCertificateSecret string
+		webhookServerDNSNames    []string
+
+		secretPolicy secretpolicy.Policy
+
+		protectManagedObjects  bool
+		protectionAllowedUsers []string
+
+		tokenInvalidator bool
+
+		projectedTokenMount           bool
+		projectedTokenMountExpiration time.Duration
+
+		podSchedulerName string
+
+		kubernetesServiceHost string
+
+		highAvailability            bool
+		highAvailabilityMinReplicas int32
 	)
 
 	cmd := &cobra.Command{
@@ -99,13 +260,365 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 			ctx, cancel := context.WithCancel(parentCtx)
 			defer cancel()
 
-			entryLog.Info("Starting gardener-resource-manager...")
+			// newLogger creates a logger for the given level, which samples repeated errors if configured.
+			newLogger := func(level string) (logr.Logger, error) {
+				l, err := logpkg.NewLogger(level, logFormat)
+				if err != nil {
+					return nil, err
+				}
+				if logErrorSamplingInterval > 0 {
+					l = logpkg.NewSamplingLogger(l, logErrorSamplingInterval)
+				}
+				return l, nil
+			}
+
+			logger, err := newLogger(logLevel)
+			if err != nil {
+				return fmt.Errorf("could not instantiate logger: %+v", err)
+			}
+			runtimelog.SetLogger(logger)
+
+			// controllerLogger returns the logger for the component with the given name, respecting the configured
+			// level overrides.
+			controllerLogger := func(name string) (logr.Logger, error) {
+				level, ok := logLevelOverrides[name]
+				if !ok {
+					return log.WithName(name), nil
+				}
+				l, err := newLogger(level)
+				if err != nil {
+					return nil, fmt.Errorf("could not instantiate logger for %s: %+v", name, err)
+				}
+				return l.WithName("gardener-resource-manager").WithName(name), nil
+			}
+
+			for name := range logLevelOverrides {
+				if !controllerLoggerNames.Has(name) {
+					return fmt.Errorf("--log-level-overrides contains unknown controller %q, must be one of %s", name, strings.Join(controllerLoggerNames.List(), ", "))
+				}
+			}
+
+			reconcilerLog, err := controllerLogger("reconciler")
+			if err != nil {
+				return err
+			}
+			secretReconcilerLog, err := controllerLogger("secret-reconciler")
+			if err != nil {
+				return err
+			}
+			healthReconcilerLog, err := controllerLogger("health-reconciler")
+			if err != nil {
+				return err
+			}
+			garbageCollectorLog, err := controllerLogger("garbage-collector")
+			if err != nil {
+				return err
+			}
+			tokenRequestorLog, err := controllerLogger("token-requestor")
+			if err != nil {
+				return err
+			}
+			networkPolicyControllerLog, err := controllerLogger("network-policy-controller")
+			if err != nil {
+				return err
+			}
+			nodeControllerLog, err := controllerLogger("node-controller")
+			if err != nil {
+				return err
+			}
+			targetKubeconfigControllerLog, err := controllerLogger("target-kubeconfig-controller")
+			if err != nil {
+				return err
+			}
+
+			for _, class := range strings.Split(resourceClass, ",") {
+				class = strings.TrimSpace(class)
+				if class == "" {
+					return fmt.Errorf("--resource-class must not contain empty classes, got %q", resourceClass)
+				}
+				if class == managedresources.WildcardClass {
+					continue
+				}
+				if errs := validation.ValidateClass(class, field.NewPath("--resource-class")); len(errs) > 0 {
+					return fmt.Errorf("invalid resource class, it must be usable as part of the finalizer name: %+v", errs.ToAggregate())
+				}
+			}
+
+			for flag, workers := range map[string]int{
+				"--max-concurrent-workers":                 maxConcurrentWorkers,
+				"--secret-max-concurrent-workers":          secretMaxConcurrentWorkers,
+				"--health-max-concurrent-workers":          healthMaxConcurrentWorkers,
+				"--max-concurrent-applies":                 maxConcurrentApplies,
+				"--token-requestor-max-concurrent-workers": tokenRequestorMaxConcurrentWorkers,
+				"--network-policy-max-concurrent-workers":  networkPolicyMaxConcurrentWorkers,
+				"--node-max-concurrent-workers":            nodeMaxConcurrentWorkers,
+			} {
+				if workers < 1 {
+					return fmt.Errorf("%s must be at least 1", flag)
+				}
+			}
+
+			for prefix, opts := range map[string]utils.RateLimiterOptions{
+				"--":        rateLimiter,
+				"--secret-": secretRateLimiter,
+				"--health-": healthRateLimiter,
+			} {
+				if err := opts.Validate(); err != nil {
+					return fmt.Errorf("invalid %srate-limiter-* flags: %+v", prefix, err)
+				}
+			}
+
+			if leaderElection {
+				if leaderElectionLeaseDuration <= leaderElectionRenewDeadline {
+					return fmt.Errorf("--leader-election-lease-duration must be greater than --leader-election-renew-deadline")
+				}
+				if leaderElectionRenewDeadline <= time.Duration(leaderelection.JitterFactor*float64(leaderElectionRetryPeriod)) {
+					return fmt.Errorf("--leader-election-renew-deadline must be greater than %.1f times --leader-election-retry-period", leaderelection.JitterFactor)
+				}
+			}
+
+			if gracefulShutdownTimeout < 0 {
+				return fmt.Errorf("--graceful-shutdown-timeout must not be negative")
+			}
+			if statusDebounceWindow < 0 {
+				return fmt.Errorf("--status-debounce-window must not be negative")
+			}
+			if syncJitter < 0 || syncJitter > 1 {
+				return fmt.Errorf("--sync-jitter must be between 0 and 1")
+			}
+			if namespaceRateLimiterQPS < 0 {
+				return fmt.Errorf("--namespace-rate-limiter-qps must not be negative")
+			}
+			if namespaceRateLimiterQPS > 0 && namespaceRateLimiterBurst < 1 {
+				return fmt.Errorf("--namespace-rate-limiter-burst must be at least 1")
+			}
+			if maxResyncDeferral < 0 {
+				return fmt.Errorf("--max-resync-deferral must not be negative")
+			}
+			pruneSkipGroupKinds := make([]schema.GroupKind, 0, len(pruneSkipKinds))
+			for _, kind := range pruneSkipKinds {
+				groupKind := schema.ParseGroupKind(kind)
+				if len(groupKind.Kind) == 0 {
+					return fmt.Errorf("--prune-skip-kinds must only contain kinds of the form <kind>[.<group>], got %q", kind)
+				}
+				pruneSkipGroupKinds = append(pruneSkipGroupKinds, groupKind)
+			}
+			var applyPolicy *managedresources.ApplyPolicy
+			if len(clusterScopedObjectsPolicy) > 0 || len(restrictObjectNamespaces) > 0 {
+				applyPolicy = &managedresources.ApplyPolicy{
+					ClusterScopedObjects: map[string]managedresources.ClusterScopedObjectPolicy{},
+					ObjectNamespaces:     restrictObjectNamespaces,
+				}
+				for class, p := range clusterScopedObjectsPolicy {
+					policy, err := managedresources.ParseClusterScopedObjectPolicy(p)
+					if err != nil {
+						return fmt.Errorf("invalid --cluster-scoped-objects-policy for class %q: %w", class, err)
+					}
+					applyPolicy.ClusterScopedObjects[class] = policy
+				}
+				for class, namespace := range restrictObjectNamespaces {
+					if namespace == "" {
+						continue
+					}
+					if errs := apimachineryvalidation.IsDNS1123Label(namespace); len(errs) > 0 {
+						return fmt.Errorf("invalid --restrict-object-namespaces for class %q: %s", class, strings.Join(errs, ", "))
+					}
+				}
+			}
+			duplicateObjectPolicy, err := managedresources.ParseDuplicateObjectPolicy(duplicateObjects)
+			if err != nil {
+				return fmt.Errorf("invalid --duplicate-objects: %w", err)
+			}
+			if stripFinalizersTimeout < 0 {
+				return fmt.Errorf("--strip-finalizers-timeout must not be negative")
+			}
+			if pruneGracePeriod < 0 {
+				return fmt.Errorf("--prune-grace-period must not be negative")
+			}
+			var deletionSelector labels.Selector
+			if deletionLabelSelector != "" {
+				deletionSelector, err = labels.Parse(deletionLabelSelector)
+				if err != nil {
+					return fmt.Errorf("could not parse --deletion-label-selector: %+v", err)
+				}
+				garbageCollectorOptions.DeletionSelector = deletionSelector
+			}
+			if waitForReadyTimeout < 0 {
+				return fmt.Errorf("--wait-for-ready-timeout must not be negative")
+			}
+			if reconcileTimeout < 0 {
+				return fmt.Errorf("--reconcile-timeout must not be negative")
+			}
+			if discoveryCacheTTL < 0 {
+				return fmt.Errorf("--discovery-cache-ttl must not be negative")
+			}
+			if garbageCollector {
+				if garbageCollectorOptions.SyncPeriod <= 0 {
+					return fmt.Errorf("--garbage-collector-sync-period must be greater than 0")
+				}
+				if garbageCollectorOptions.MinAge < 0 {
+					return fmt.Errorf("--garbage-collector-min-age must not be negative")
+				}
+				if keepObjectsTTL < 0 {
+					return fmt.Errorf("--keep-objects-ttl must not be negative")
+				}
+				// objects of ManagedResources in other namespaces would be considered orphaned
+				if len(namespaces) > 0 {
+					return fmt.Errorf("--garbage-collector cannot be used together with --namespace")
+				}
+			}
+
+			if metricsServerOptions.CertDir == "" && (metricsServerOptions.ClientCAFile != "" || metricsServerOptions.TokenReview) {
+				return fmt.Errorf("--metrics-client-ca-file and --metrics-token-review require --metrics-tls-cert-dir to be set")
+			}
+			if metricsServerOptions.CertDir != "" && metricsBindAddress == "0" {
+				return fmt.Errorf("--metrics-tls-cert-dir cannot be used together with --metrics-bind-address=0, which disables the metrics endpoint")
+			}
+
+			if tracingSamplingRatio < 0 || tracingSamplingRatio > 1 {
+				return fmt.Errorf("--tracing-sampling-ratio must be between 0 and 1")
+			}
+
+			if enablePprof && debugBindAddress == "" {
+				return fmt.Errorf("--enable-pprof requires --debug-bind-address to be set")
+			}
+
+			if protectManagedObjects && (webhookServerPort == 0 || len(protectionAllowedUsers) == 0) {
+				return fmt.Errorf("--protect-managed-objects requires --webhook-server-port and --protection-allowed-users to be set")
+			}
+
+			if secretPolicy.Enabled() && webhookServerPort == 0 {
+				return fmt.Errorf("--secret-max-size and --secret-key-extensions require --webhook-server-port to be set")
+			}
+			if tokenInvalidator && webhookServerPort == 0 {
+				return fmt.Errorf("--token-invalidator requires --webhook-server-port to be set")
+			}
+			if projectedTokenMount && webhookServerPort == 0 {
+				return fmt.Errorf("--projected-token-mount requires --webhook-server-port to be set")
+			}
+			if podSchedulerName != "" && webhookServerPort == 0 {
+				return fmt.Errorf("--pod-scheduler-name requires --webhook-server-port to be set")
+			}
+			if kubernetesServiceHost != "" && webhookServerPort == 0 {
+				return fmt.Errorf("--kubernetes-service-host requires --webhook-server-port to be set")
+			}
+			if highAvailability && webhookServerPort == 0 {
+				return fmt.Errorf("--high-availability requires --webhook-server-port to be set")
+			}
+
+			enabled := map[string]bool{
+				"--protect-managed-objects":    protectManagedObjects,
+				"--high-availability":          highAvailability,
+				"--webhook-certificate-secret": webhookCertificateSecret != "",
+				"--tracing-endpoint":           tracingEndpoint != "",
+				"--shards":                     shards > 1,
+				"--garbage-collector":          garbageCollector,
+				"--token-requestor":            tokenRequestor,
+				"--network-policy-controller":  networkPolicyController,
+				"--node-controller":            nodeController,
+			}
+			for flag, refined := range refinedFlags {
+				if cmd.Flags().Changed(strings.TrimPrefix(flag, "--")) && !enabled[refined] {
+					return fmt.Errorf("%s has no effect without %s", flag, refined)
+				}
+			}
+
+			var shard *managedresources.Shard
+			if shards > 1 {
+				if shardIndex < 0 {
+					hostname, err := os.Hostname()
+					if err != nil {
+						return fmt.Errorf("could not determine hostname for deriving the shard index: %+v", err)
+					}
+					if shardIndex, err = managedresources.ShardIndexFromHostname(hostname); err != nil {
+						return fmt.Errorf("could not derive the shard index from the hostname, set --shard-index: %+v", err)
+					}
+				}
+				if shard, err = managedresources.NewShard(shardIndex, shards); err != nil {
+					return fmt.Errorf("invalid --shards or --shard-index: %+v", err)
+				}
+				// the replicas of every shard elect their own leader
+				leaderElectionID = fmt.Sprintf("%s-shard-%d", leaderElectionID, shard.Index)
+			} else if shards < 1 {
+				return fmt.Errorf("--shards must be at least 1")
+			}
+
+			var webhookCertificateSecretKey types.NamespacedName
+			if webhookCertificateSecret != "" {
+				parts := strings.Split(webhookCertificateSecret, "/")
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					return fmt.Errorf("--webhook-certificate-secret must be of the form <namespace>/<name>")
+				}
+				if len(webhookServerDNSNames) == 0 {
+					return fmt.Errorf("--webhook-certificate-secret requires --webhook-server-dns-names to be set")
+				}
+				webhookCertificateSecretKey = types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+			}
+
+			var targetKubeconfigSecretKey types.NamespacedName
+			if targetKubeconfigSecret != "" {
+				parts := strings.Split(targetKubeconfigSecret, "/")
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					return fmt.Errorf("--target-kubeconfig-secret must be of the form <namespace>/<name>")
+				}
+				if targetKubeconfigPath == "" {
+					return fmt.Errorf("--target-kubeconfig-secret requires --target-kubeconfig to be set")
+				}
+				if len(namespaces) > 0 && !sets.NewString(namespaces...).Has(parts[0]) {
+					return fmt.Errorf("--target-kubeconfig-secret must be in one of the namespaces given with --namespace")
+				}
+				targetKubeconfigSecretKey = types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+			}
+
+			// parse the kubeconfig of the target cluster before starting anything, so that a broken kubeconfig is reported
+			// immediately
+			targetConfig, err := getTargetConfig(targetKubeconfigPath)
+			if err != nil {
+				return fmt.Errorf("unable to create REST config for target cluster: %+v", err)
+			}
+
+			entryLog.Info("Starting gardener-resource-manager...", "version", version.Version, "gitCommit", version.GitCommit, "buildDate", version.BuildDate)
 			cmd.Flags().VisitAll(func(flag *pflag.Flag) {
 				entryLog.Info(fmt.Sprintf("FLAG: --%s=%s", flag.Name, flag.Value))
 			})
+			entryLog.Info("Feature gates: " + features.DefaultFeatureGate.String())
+
+			if tracingEndpoint != "" {
+				shutdownTracing, err := tracing.Setup(tracingEndpoint, tracingInsecure, tracingSamplingRatio)
+				if err != nil {
+					return fmt.Errorf("could not set up tracing: %+v", err)
+				}
+				defer func() {
+					shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer shutdownCancel()
+					if err := shutdownTracing(shutdownCtx); err != nil {
+						entryLog.Error(err, "Could not flush pending traces")
+					}
+				}()
+				entryLog.Info("Exporting traces to " + tracingEndpoint)
+			}
+
+			var auditSink audit.Sink
+			switch auditLogPath {
+			case "":
+			case "-":
+				auditSink = audit.NewLogSink(log.WithName("audit"))
+				entryLog.Info("Writing audit log to log stream")
+			default:
+				fileSink, err := audit.NewFileSink(log.WithName("audit"), auditLogPath)
+				if err != nil {
+					return fmt.Errorf("could not set up audit log: %+v", err)
+				}
+				defer func() {
+					if err := fileSink.Close(); err != nil {
+						entryLog.Error(err, "Could not close audit log")
+					}
+				}()
+				auditSink = fileSink
+				entryLog.Info("Writing audit log to " + auditLogPath)
+			}
 
 			var cfg *rest.Config
-			var err error
 			if kubeconfigPath != "" {
 				cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
 				if err != nil {
@@ -116,32 +629,47 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 				cfg = config.GetConfigOrDie()
 			}
 
-			mgr, err := manager.New(cfg, manager.Options{
+			mgrOptions := manager.Options{
 				LeaderElection:          leaderElection,
-				LeaderElectionID:        "gardener-resource-manager",
+				LeaderElectionID:        leaderElectionID,
 				LeaderElectionNamespace: leaderElectionNamespace,
 				LeaseDuration:           &leaderElectionLeaseDuration,
 				RenewDeadline:           &leaderElectionRenewDeadline,
 				RetryPeriod:             &leaderElectionRetryPeriod,
 				SyncPeriod:              &cacheResyncPeriod,
-				Namespace:               namespace,
-			})
+				HealthProbeBindAddress:  healthBindAddress,
+				MetricsBindAddress:      metricsBindAddress,
+			}
+			if metricsServerOptions.CertDir != "" {
+				// the metrics are served by our own server supporting TLS and authentication
+				mgrOptions.MetricsBindAddress = "0"
+			}
+			switch len(namespaces) {
+			case 0:
+			case 1:
+				mgrOptions.Namespace = namespaces[0]
+			default:
+				mgrOptions.NewCache = cache.MultiNamespacedCacheBuilder(namespaces)
+			}
+
+			mgr, err := manager.New(cfg, mgrOptions)
 			if err != nil {
 				return fmt.Errorf("could not instantiate manager: %+v", err)
 			}
 
 			utilruntime.Must(resourcesv1alpha1.AddToScheme(mgr.GetScheme()))
+			utilruntime.Must(resourcesv1beta1.AddToScheme(mgr.GetScheme()))
+
+			if err := managedresources.AddSecretRefsIndex(mgr.GetFieldIndexer()); err != nil {
+				return fmt.Errorf("unable to add index for the secret references of ManagedResources: %+v", err)
+			}
 
 			targetScheme := runtime.NewScheme()
 			utilruntime.Must(scheme.AddToScheme(targetScheme)) // add most of the standard k8s APIs
 			apiextensionsinstall.Install(targetScheme)
 			apiregistrationinstall.Install(targetScheme)
 			utilruntime.Must(hvpav1alpha1.AddToScheme(targetScheme))
-
-			targetConfig, err := getTargetConfig(targetKubeconfigPath)
-			if err != nil {
-				return fmt.Errorf("unable to create REST config for target cluster: %+v", err)
-			}
+			utilruntime.Must(defaults.AddToScheme(targetScheme)) // default desired objects like the API server
 
 			targetRESTMapper, err := getTargetRESTMapper(targetConfig)
 			if err != nil {
@@ -165,35 +693,189 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 				return fmt.Errorf("unable to create client for target cluster: %+v", err)
 			}
 
+			targetDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(targetConfig)
+			if err != nil {
+				return fmt.Errorf("unable to create discovery client for target cluster: %+v", err)
+			}
+			targetCapabilities := managedresources.NewCapabilities(targetDiscoveryClient)
+
+			if err := addRESTMapperInvalidator(mgr, log.WithName("restmapper"), utils.Resetters{targetRESTMapper, targetCapabilities}, targetCache, discoveryCacheTTL); err != nil {
+				return err
+			}
+
+			if err := addHealthChecks(mgr, targetCache, targetConfig, targetReachabilityCheck); err != nil {
+				return err
+			}
+
+			var targetEventRecorder record.EventRecorder
+			if targetEvents {
+				targetClientset, err := kubernetes.NewForConfig(targetConfig)
+				if err != nil {
+					return fmt.Errorf("unable to create clientset for target cluster: %+v", err)
+				}
+
+				eventBroadcaster := record.NewBroadcaster()
+				eventWatcher := eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: targetClientset.CoreV1().Events("")})
+				defer eventWatcher.Stop()
+
+				targetEventRecorder = eventBroadcaster.NewRecorder(targetScheme, corev1.EventSource{Component: "gardener-resource-manager"})
+				entryLog.Info("Recording events on objects in the target cluster")
+			}
+
 			if resourceClass == "" {
 				resourceClass = managedresources.DefaultClass
 			}
 			filter := managedresources.NewClassFilter(resourceClass)
+			if managedResourceLabelSelector != "" {
+				selector, err := labels.Parse(managedResourceLabelSelector)
+				if err != nil {
+					return fmt.Errorf("could not parse --managed-resource-label-selector: %+v", err)
+				}
+				filter = filter.WithLabelSelector(selector)
+			}
+			if shard != nil {
+				filter = filter.WithShard(shard)
+			}
 
-			entryLog.Info("Managed namespace: " + namespace)
-			entryLog.Info("Resource class: " + filter.ResourceClass())
+			entryLog.Info("Managed namespaces: " + strings.Join(namespaces, ","))
+			entryLog.Info("Resource classes: " + resourceClass)
+			if clusterID != "" {
+				entryLog.Info("Cluster ID: " + clusterID)
+			}
+			if managedResourceLabelSelector != "" {
+				entryLog.Info("ManagedResource label selector: " + managedResourceLabelSelector)
+			}
+			if deletionLabelSelector != "" {
+				entryLog.Info("Deletion label selector: " + deletionLabelSelector)
+			}
+			if shard != nil {
+				entryLog.Info("Reconciling shard of ManagedResources", "shardIndex", shard.Index, "shards", shard.Count, "leaderElectionID", leaderElectionID)
+			}
 			entryLog.Info("Cache resync period " + cacheResyncPeriod.String())
 
+			// Reconciliations use their own context, which is only cancelled after the reconciliations in progress have
+			// been drained on shutdown, so that they are not aborted in the middle of applying a bundle.
+			reconcileCtx, cancelReconciles := context.WithCancel(context.Background())
+			defer cancelReconciles()
+			drainer := utils.NewDrainer()
+			statusDebouncer := managedresources.NewStatusDebouncer(reconcileCtx, reconcilerLog, mgr.GetClient(), statusDebounceWindow)
+
+			var decodeCache *managedresources.DecodeCache
+			if cacheDecodedObjects {
+				decodeCache = managedresources.NewDecodeCache()
+			}
+
+			// the reconciliations are only tracked if the debug endpoints are served
+			var tracker *debug.Tracker
+			if debugBindAddress != "" {
+				tracker = debug.NewTracker(mgr.GetClient(), &resourcesv1alpha1.ManagedResource{})
+				debugServer, err := debug.NewServer(log.WithName("debug"), debugBindAddress)
+				if err != nil {
+					return fmt.Errorf("unable to set up debug server: %+v", err)
+				}
+				debugServer.Handle(debug.ManagedResourcesPath, debug.NewManagedResourcesHandler(mgr.GetClient(), tracker))
+				if enablePprof {
+					debugServer.EnableProfiling()
+					entryLog.Info("Serving profiling endpoints on " + debugBindAddress)
+				}
+				if err := mgr.Add(debugServer); err != nil {
+					return fmt.Errorf("unable to add debug server to manager: %+v", err)
+				}
+			}
+
+			if metricsServerOptions.CertDir != "" && metricsBindAddress != "0" {
+				if err := mgr.Add(metrics.NewServer(log.WithName("metrics"), metricsBindAddress, mgr.GetClient(), metricsServerOptions)); err != nil {
+					return fmt.Errorf("unable to add metrics server to manager: %+v", err)
+				}
+				entryLog.Info("Serving metrics via TLS", "clientCertificates", metricsServerOptions.ClientCAFile != "", "tokenReview", metricsServerOptions.TokenReview)
+			}
+
+			if webhookServerPort != 0 {
+				server := mgr.GetWebhookServer()
+				server.Port = webhookServerPort
+				server.CertDir = webhookServerCertDir
+				server.Register(managedresourcewebhook.ConversionPath, &conversion.Webhook{})
+				server.Register(managedresourcewebhook.DefaulterPath, &webhook.Admission{Handler: managedresourcewebhook.NewDefaulter(filter.ResourceClass())})
+				server.Register(managedresourcewebhook.ValidatorPath, &webhook.Admission{Handler: managedresourcewebhook.NewValidator()})
+				if secretPolicy.Enabled() {
+					server.Register(secretpolicy.Path, &webhook.Admission{Handler: secretpolicy.NewHandler(mgr.GetClient(), filter, secretPolicy)})
+					entryLog.Info("Enforcing the secret policy", "maxSize", secretPolicy.MaxSize, "keyExtensions", secretPolicy.KeyExtensions)
+				}
+				if protectManagedObjects {
+					server.Register(protection.Path, &webhook.Admission{Handler: protection.NewHandler(protectionAllowedUsers...)})
+					entryLog.Info("Protecting managed objects in the target cluster", "allowedUsers", protectionAllowedUsers)
+				}
+				if tokenInvalidator {
+					server.Register(tokeninvalidator.Path, &webhook.Admission{Handler: tokeninvalidator.NewHandler(targetClient)})
+					entryLog.Info("Invalidating static tokens of service accounts using projected tokens only")
+				}
+				if projectedTokenMount {
+					server.Register(projectedtokenmount.Path, &webhook.Admission{Handler: projectedtokenmount.NewHandler(projectedTokenMountExpiration)})
+					entryLog.Info("Mounting projected service account tokens into labeled pods", "expiration", projectedTokenMountExpiration.String())
+				}
+				if podSchedulerName != "" {
+					server.Register(podschedulername.Path, &webhook.Admission{Handler: podschedulername.NewHandler(podSchedulerName)})
+					entryLog.Info("Setting the scheduler name of pods", "schedulerName", podSchedulerName)
+				}
+				if kubernetesServiceHost != "" {
+					server.Register(kubernetesservicehost.Path, &webhook.Admission{Handler: kubernetesservicehost.NewHandler(kubernetesServiceHost)})
+					entryLog.Info("Injecting the host of the API server into pods", "host", kubernetesServiceHost)
+				}
+				if highAvailability {
+					server.Register(highavailability.Path, &webhook.Admission{Handler: highavailability.NewHandler(targetClient, highAvailabilityMinReplicas)})
+					entryLog.Info("Applying high availability defaults to labeled workloads", "minReplicas", highAvailabilityMinReplicas)
+				}
+				entryLog.Info("Serving admission webhooks", "port", webhookServerPort)
+
+				if webhookCertificateSecret != "" {
+					if err := addWebhookCertificateManager(ctx, mgr, webhookCertificateSecretKey, webhookServerDNSNames, webhookServerCertDir, protectManagedObjects, targetConfig, targetScheme, targetRESTMapper); err != nil {
+						return err
+					}
+					entryLog.Info("Managing webhook serving certificates", "secret", webhookCertificateSecret)
+				}
+			}
+
+			// requests throttled per namespace keep their priority in the work queue
+			throttledRequests := utils.NewThrottledRequests()
 			c, err := controller.New("resource-controller", mgr, controller.Options{
 				MaxConcurrentReconciles: maxConcurrentWorkers,
-				Reconciler: extensionscontroller.OperationAnnotationWrapper(
+				Reconciler: drainer.Wrap(utils.LimitPerNamespace(tracker.Wrap("resource-controller", utils.JitterRequeues(extensionscontroller.OperationAnnotationWrapper(
 					&resourcesv1alpha1.ManagedResource{},
-					managedresources.NewReconciler(
-						ctx,
-						log.WithName("reconciler"),
-						mgr.GetClient(),
-						targetClient,
-						targetRESTMapper,
-						targetScheme,
-						filter,
-						alwaysUpdate,
-						syncPeriod,
-					),
-				),
+					managedresources.NewReconciler(reconcileCtx, reconcilerLog, mgr.GetClient(), targetClient, managedresources.ReconcilerOptions{
+						TargetRESTMapper:       targetRESTMapper,
+						TargetScheme:           targetScheme,
+						TargetCapabilities:     targetCapabilities,
+						UpgradeDeprecatedAPIs:  upgradeDeprecatedAPIs,
+						StrictDecoding:         strictDecoding,
+						DuplicateObjects:       duplicateObjectPolicy,
+						Class:                  filter,
+						ClusterID:              clusterID,
+						AlwaysUpdate:           alwaysUpdate,
+						SyncPeriod:             syncPeriod,
+						MaxConcurrentApplies:   maxConcurrentApplies,
+						KeepObjectsTTL:         keepObjectsTTL,
+						PruneSkipKinds:         pruneSkipGroupKinds,
+						PruneGracePeriod:       pruneGracePeriod,
+						DeletionSelector:       deletionSelector,
+						ApplyPolicy:            applyPolicy,
+						StripFinalizers:        stripFinalizers,
+						StripFinalizersTimeout: stripFinalizersTimeout,
+						WaitForReadyTimeout:    waitForReadyTimeout,
+						ReconcileTimeout:       reconcileTimeout,
+						AuditSink:              auditSink,
+						EventRecorder:          mgr.GetEventRecorderFor("gardener-resource-manager"),
+						TargetEventRecorder:    targetEventRecorder,
+						StatusDebouncer:        statusDebouncer,
+						DecodeCache:            decodeCache,
+					}),
+				), syncJitter)), namespaceRateLimiterQPS, namespaceRateLimiterBurst, throttledRequests)),
 			})
 			if err != nil {
 				return fmt.Errorf("unable to set up individual controller: %+v", err)
 			}
+			if err := utils.SetPriorityQueue(c, "resource-controller", rateLimiter.RateLimiter(), maxResyncDeferral, throttledRequests); err != nil {
+				return fmt.Errorf("unable to set up work queue: %+v", err)
+			}
 
 			if err := c.Watch(
 				&source.Kind{Type: &resourcesv1alpha1.ManagedResource{}},
@@ -201,6 +883,8 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 				filter, extensionspredicate.Or(
 					predicate.GenerationChangedPredicate{},
 					extensionspredicate.HasOperationAnnotation(),
+					managerpredicate.HasOperation(resourcesv1alpha1.OperationForceApply),
+					managerpredicate.AnnotationChanged(resourcesv1alpha1.Ignore),
 					managerpredicate.ConditionStatusChanged(resourcesv1alpha1.ResourcesHealthy, managerpredicate.ConditionChangedToUnhealthy),
 				),
 			); err != nil {
@@ -208,24 +892,47 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 			}
 			if err := c.Watch(
 				&source.Kind{Type: &corev1.Secret{}},
-				&handler.EnqueueRequestsFromMapFunc{ToRequests: mapper.SecretToManagedResourceMapper(filter)},
+				&handler.EnqueueRequestsFromMapFunc{ToRequests: mapper.SecretToManagedResourceMapper(ctx, filter)},
+				// changes of the finalizers maintained by the secret controller don't require a reconciliation
+				managerpredicate.SecretDataChanged(),
 			); err != nil {
 				return fmt.Errorf("unable to watch Secrets mapping to ManagedResources: %+v", err)
 			}
 
-			entryLog.Info("Managed resource controller", "syncPeriod", syncPeriod.String())
+			entryLog.Info("Managed resource controller", "syncPeriod", syncPeriod.String(), "syncJitter", syncJitter)
 			entryLog.Info("Managed resource controller", "maxConcurrentWorkers", maxConcurrentWorkers)
+			entryLog.Info("Managed resource controller", "maxConcurrentApplies", maxConcurrentApplies)
+			entryLog.Info("Managed resource controller", "waitForReadyTimeout", waitForReadyTimeout.String())
+			entryLog.Info("Managed resource controller", "reconcileTimeout", reconcileTimeout.String())
+			entryLog.Info("Managed resource controller", "upgradeDeprecatedAPIs", upgradeDeprecatedAPIs)
+			entryLog.Info("Managed resource controller", "strictDecoding", strictDecoding)
+			entryLog.Info("Managed resource controller", "duplicateObjects", duplicateObjectPolicy)
+			entryLog.Info("Managed resource controller", "cacheDecodedObjects", cacheDecodedObjects)
+			entryLog.Info("Managed resource controller", "rateLimiterMaxDelay", rateLimiter.MaxDelay.String())
+			entryLog.Info("Managed resource controller", "maxResyncDeferral", maxResyncDeferral.String())
+			entryLog.Info("Managed resource controller", "namespaceRateLimiterQPS", namespaceRateLimiterQPS, "namespaceRateLimiterBurst", namespaceRateLimiterBurst)
+			entryLog.Info("Managed resource controller", "statusDebounceWindow", statusDebounceWindow.String())
+			if len(stripFinalizers) > 0 {
+				entryLog.Info("Managed resource controller", "stripFinalizers", stripFinalizers, "stripFinalizersTimeout", stripFinalizersTimeout.String())
+			}
+			if applyPolicy != nil {
+				entryLog.Info("Managed resource controller", "clusterScopedObjectsPolicy", clusterScopedObjectsPolicy, "restrictObjectNamespaces", restrictObjectNamespaces)
+			}
 
 			secretController, err := controller.New("secret-controller", mgr, controller.Options{
 				MaxConcurrentReconciles: secretMaxConcurrentWorkers,
 				Reconciler: managedresources.NewSecretReconciler(
-					log.WithName("secret-reconciler"),
+					reconcileCtx,
+					secretReconcilerLog,
 					filter,
 				),
 			})
 			if err != nil {
 				return fmt.Errorf("unable to set up secret controller: %+v", err)
 			}
+			if err := utils.SetRateLimiter(secretController, "secret-controller", secretRateLimiter.RateLimiter()); err != nil {
+				return fmt.Errorf("unable to set up rate limiter: %+v", err)
+			}
 
 			if err := secretController.Watch(
 				&source.Kind{Type: &resourcesv1alpha1.ManagedResource{}},
@@ -242,26 +949,30 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 				&handler.EnqueueRequestForObject{},
 				// Only requeue secrets from create/update events with the controller's finalizer to not flood the controller
 				// with too many unnecessary requests for all secrets in cluster/namespace.
-				managerpredicate.HasFinalizer(filter.FinalizerName()),
+				managerpredicate.HasMatchingFinalizer(filter.OwnsFinalizer),
 			); err != nil {
 				return fmt.Errorf("unable to watch Secrets: %+v", err)
 			}
 
 			healthController, err := controller.New("health-controller", mgr, controller.Options{
 				MaxConcurrentReconciles: healthMaxConcurrentWorkers,
-				Reconciler: health.NewHealthReconciler(
-					ctx,
-					log.WithName("health-reconciler"),
+				Reconciler: drainer.Wrap(tracker.Wrap("health-controller", utils.JitterRequeues(health.NewHealthReconciler(
+					reconcileCtx,
+					healthReconcilerLog,
 					mgr.GetClient(),
 					targetClient,
 					targetScheme,
 					filter,
 					healthSyncPeriod,
-				),
+					statusDebouncer,
+				), syncJitter))),
 			})
 			if err != nil {
 				return fmt.Errorf("unable to set up individual controller: %+v", err)
 			}
+			if err := utils.SetRateLimiter(healthController, "health-controller", healthRateLimiter.RateLimiter()); err != nil {
+				return fmt.Errorf("unable to set up rate limiter: %+v", err)
+			}
 
 			if err := healthController.Watch(
 				&source.Kind{Type: &resourcesv1alpha1.ManagedResource{}},
@@ -290,6 +1001,42 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 
 			entryLog.Info("Managed resource health controller", "syncPeriod", healthSyncPeriod.String())
 			entryLog.Info("Managed resource health controller", "maxConcurrentWorkers", healthMaxConcurrentWorkers)
+			entryLog.Info("Managed resource health controller", "rateLimiterMaxDelay", healthRateLimiter.MaxDelay.String())
+
+			if garbageCollector {
+				if err := addGarbageCollector(ctx, mgr, garbageCollectorLog, targetConfig, targetScheme, targetRESTMapper, filter, clusterID, auditSink, garbageCollectorOptions); err != nil {
+					return err
+				}
+				entryLog.Info("Garbage collector", "syncPeriod", garbageCollectorOptions.SyncPeriod.String(), "minAge", garbageCollectorOptions.MinAge.String(), "dryRun", garbageCollectorOptions.DryRun, "keepObjectsTTL", keepObjectsTTL.String())
+			}
+
+			if tokenRequestor {
+				if err := addTokenRequestor(reconcileCtx, mgr, tokenRequestorLog, targetConfig, tokenRequestorMaxConcurrentWorkers); err != nil {
+					return err
+				}
+				entryLog.Info("Token requestor", "maxConcurrentWorkers", tokenRequestorMaxConcurrentWorkers)
+			}
+
+			if networkPolicyController {
+				if err := addNetworkPolicyController(reconcileCtx, mgr, networkPolicyControllerLog, targetClient, targetCache, networkPolicyControllerNamespaces, networkPolicyMaxConcurrentWorkers); err != nil {
+					return err
+				}
+				entryLog.Info("Network policy controller", "namespaces", networkPolicyControllerNamespaces, "maxConcurrentWorkers", networkPolicyMaxConcurrentWorkers)
+			}
+
+			if nodeController {
+				if err := addNodeController(reconcileCtx, mgr, nodeControllerLog, targetConfig, targetScheme, targetRESTMapper, targetClient, targetCache, nodeMaxConcurrentWorkers); err != nil {
+					return err
+				}
+				entryLog.Info("Node controller", "maxConcurrentWorkers", nodeMaxConcurrentWorkers)
+			}
+
+			if targetKubeconfigSecret != "" {
+				if err := addTargetKubeconfigController(reconcileCtx, mgr, targetKubeconfigControllerLog, targetKubeconfigSecretKey, targetKubeconfigPath); err != nil {
+					return err
+				}
+				entryLog.Info("Target kubeconfig controller", "secret", targetKubeconfigSecret)
+			}
 
 			var wg sync.WaitGroup
 			errChan := make(chan error)
@@ -299,7 +1046,8 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 				defer wg.Done()
 
 				wg.Add(1)
-				if err := targetCache.Start(ctx.Done()); err != nil {
+				// the target cache is still needed by the reconciliations drained on shutdown
+				if err := targetCache.Start(reconcileCtx.Done()); err != nil {
 					errChan <- fmt.Errorf("error syncing target cache: %+v", err)
 				}
 			}()
@@ -318,38 +1066,378 @@ func NewControllerManagerCommand(parentCtx context.Context) *cobra.Command {
 			select {
 			case err := <-errChan:
 				cancel()
+				cancelReconciles()
 				wg.Wait()
 				return err
 
 			case <-parentCtx.Done():
-				wg.Wait()
 				entryLog.Info("Stop signal received, shutting down.")
+				if drainer.Drain(gracefulShutdownTimeout) {
+					entryLog.Info("All reconciliations in progress have finished")
+				} else {
+					entryLog.Info("Graceful shutdown timeout expired, aborting reconciliations in progress", "timeout", gracefulShutdownTimeout.String())
+				}
+				statusDebouncer.Flush()
+				cancelReconciles()
+				wg.Wait()
 				return nil
 			}
 		},
 	}
 
+	cmd.AddCommand(newVersionCommand())
+	cmd.AddCommand(newDiagnoseCommand())
+	cmd.AddCommand(newTreeCommand())
+	cmd.AddCommand(newRenderCommand())
+
 	cmd.Flags().BoolVar(&leaderElection, "leader-election", true, "enable or disable leader election")
+	cmd.Flags().StringVar(&leaderElectionID, "leader-election-id", "gardener-resource-manager", "name of the config map used as leader election lock, must be unique per resource class in the leader election namespace")
 	cmd.Flags().StringVar(&leaderElectionNamespace, "leader-election-namespace", "", "namespace for leader election")
 	cmd.Flags().DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "lease duration for leader election")
 	cmd.Flags().DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 10*time.Second, "renew deadline for leader election")
 	cmd.Flags().DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 2*time.Second, "retry period for leader election")
 	cmd.Flags().DurationVar(&cacheResyncPeriod, "cache-resync-period", 24*time.Hour, "duration how often the controller's cache is resynced")
 	cmd.Flags().DurationVar(&syncPeriod, "sync-period", time.Minute, "duration how often existing resources should be synced")
+	cmd.Flags().Float64Var(&syncJitter, "sync-jitter", 0.2, "maximum fraction by which the periodic syncs of ManagedResources and their health checks are brought forward at random, to spread them over the sync period (between 0 and 1)")
+	cmd.Flags().DurationVar(&maxResyncDeferral, "max-resync-deferral", 30*time.Second, "maximum duration for which periodic syncs of ManagedResources are held back in favor of changed, created, deleted or annotated ManagedResources (not held back if 0)")
 	cmd.Flags().DurationVar(&targetCacheResyncPeriod, "target-cache-resync-period", 24*time.Hour, "duration how often the controller's cache for the target cluster is resynced")
 	cmd.Flags().IntVar(&maxConcurrentWorkers, "max-concurrent-workers", 10, "number of worker threads for concurrent reconciliation of resources")
+	cmd.Flags().IntVar(&maxConcurrentApplies, "max-concurrent-applies", managedresources.DefaultMaxConcurrentApplies, "number of objects of one ManagedResource which are applied in parallel (CustomResourceDefinitions and Namespaces are applied before all other objects)")
+	cmd.Flags().DurationVar(&waitForReadyTimeout, "wait-for-ready-timeout", managedresources.DefaultWaitForReadyTimeout, "maximum duration the objects of a ManagedResource following an object annotated with "+resourcesv1alpha1.WaitForReady+"=true wait for it to become healthy")
+	cmd.Flags().BoolVar(&strictDecoding, "strict-decoding", false, "fail to decode objects of kinds known to the gardener-resource-manager if they contain unknown fields (e.g. misspelled ones), instead of applying them without these fields")
+	cmd.Flags().StringVar(&duplicateObjects, "duplicate-objects", string(managedresources.DuplicateObjectPolicyFail), fmt.Sprintf("policy for ManagedResources containing the same object multiple times (%s: neither apply nor delete any of their objects, %s: apply them in order and record a warning event)", managedresources.DuplicateObjectPolicyFail, managedresources.DuplicateObjectPolicyWarn))
+	cmd.Flags().BoolVar(&upgradeDeprecatedAPIs, "upgrade-deprecated-apis", false, "apply objects of known deprecated API versions (e.g. extensions/v1beta1 Ingresses) in the newest version of their kind served by the target cluster, converting their fields if necessary")
+	cmd.Flags().DurationVar(&reconcileTimeout, "reconcile-timeout", managedresources.DefaultReconcileTimeout, "maximum duration of a single reconciliation of a ManagedResource, it can be overridden with the "+resourcesv1alpha1.ReconcileTimeout+" annotation (not bounded if 0)")
+	cmd.Flags().BoolVar(&cacheDecodedObjects, "cache-decoded-objects", true, "cache the objects decoded from the secrets of ManagedResources until the secrets change, trading memory for the time needed to decode them with every reconciliation")
 	cmd.Flags().IntVar(&secretMaxConcurrentWorkers, "secret-max-concurrent-workers", 5, "number of worker threads for concurrent secret reconciliation of resources")
+	cmd.Flags().DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 20*time.Second, "duration to wait for reconciliations in progress to finish when shutting down, they are aborted afterwards")
+	cmd.Flags().DurationVar(&statusDebounceWindow, "status-debounce-window", 2*time.Second, "duration for which intermediate status updates of ManagedResources are deferred, so that they can be written together with the next status update (disabled if 0)")
+	cmd.Flags().DurationVar(&discoveryCacheTTL, "discovery-cache-ttl", 10*time.Minute, "duration after which the cached discovery information of the target cluster is refreshed, it is also refreshed whenever a CustomResourceDefinition changes (never refreshed periodically if 0)")
 	cmd.Flags().DurationVar(&healthSyncPeriod, "health-sync-period", time.Minute, "duration how often the health of existing resources should be synced")
 	cmd.Flags().IntVar(&healthMaxConcurrentWorkers, "health-max-concurrent-workers", 10, "number of worker threads for concurrent health reconciliation of resources")
+	addRateLimiterFlags(cmd.Flags(), "", "resource", &rateLimiter)
+	cmd.Flags().BoolVar(&garbageCollector, "garbage-collector", false, "periodically delete objects in the target cluster labeled with one of the resource classes which are not contained in any ManagedResource anymore")
+	cmd.Flags().DurationVar(&garbageCollectorOptions.SyncPeriod, "garbage-collector-sync-period", time.Hour, "duration how often orphaned objects in the target cluster are deleted")
+	cmd.Flags().DurationVar(&garbageCollectorOptions.MinAge, "garbage-collector-min-age", time.Hour, "minimum age of orphaned objects which are deleted, so that objects are not deleted before the status of the ManagedResource creating them is written")
+	cmd.Flags().BoolVar(&garbageCollectorOptions.DryRun, "garbage-collector-dry-run", false, "only log the orphaned objects which would be deleted by the garbage collector")
+	cmd.Flags().BoolVar(&tokenRequestor, "token-requestor", false, "request tokens for ServiceAccounts in the target cluster and write them into the secrets labelled with "+resourcesv1alpha1.ResourceManagerPurpose+"="+resourcesv1alpha1.LabelPurposeTokenRequest+", renewing them before they expire")
+	cmd.Flags().IntVar(&tokenRequestorMaxConcurrentWorkers, "token-requestor-max-concurrent-workers", 5, "number of worker threads for concurrent token requests")
+	cmd.Flags().BoolVar(&networkPolicyController, "network-policy-controller", false, "maintain ingress NetworkPolicies for the Services in the target cluster annotated with "+resourcesv1alpha1.NetworkingFromPodSelector)
+	cmd.Flags().StringSliceVar(&networkPolicyControllerNamespaces, "network-policy-controller-namespaces", nil, "namespaces of the target cluster in which the Services are observed by the network policy controller, can be given multiple times (defaults to all namespaces)")
+	cmd.Flags().IntVar(&networkPolicyMaxConcurrentWorkers, "network-policy-max-concurrent-workers", 5, "number of worker threads for concurrent reconciliation of Services by the network policy controller")
+	cmd.Flags().BoolVar(&nodeController, "node-controller", false, "remove the "+node.TaintCriticalComponentsNotReady+" taint from the nodes of the target cluster once the pods of all DaemonSets labelled with "+node.CriticalComponentLabel+"=true are ready on them")
+	cmd.Flags().IntVar(&nodeMaxConcurrentWorkers, "node-max-concurrent-workers", 5, "number of worker threads for concurrent reconciliation of nodes by the node controller")
+	cmd.Flags().DurationVar(&keepObjectsTTL, "keep-objects-ttl", 0, "duration after which the objects of ManagedResources deleted with .spec.keepObjects=true are deleted by the garbage collector unless they have been adopted by another ManagedResource (kept forever if 0)")
+	cmd.Flags().StringSliceVar(&pruneSkipKinds, "prune-skip-kinds", nil, "kinds of the form <kind>[.<group>] (e.g. PersistentVolumeClaim,Namespace) whose objects are released instead of deleted when they are removed from a ManagedResource, in addition to its .spec.prune.skipKinds")
+	cmd.Flags().DurationVar(&pruneGracePeriod, "prune-grace-period", 0, "duration for which objects removed from a ManagedResource are kept and annotated as pending prune before they are deleted, unless overridden by its .spec.prune.gracePeriod (deleted immediately if 0)")
+	cmd.Flags().StringVar(&deletionLabelSelector, "deletion-label-selector", "", "label selector which objects in the target cluster must match to be deleted by pruning, the deletion of ManagedResources and the garbage collector, e.g. landscape=dev (objects not matching it are left untouched, all objects may be deleted if empty)")
+	cmd.Flags().StringToStringVar(&clusterScopedObjectsPolicy, "cluster-scoped-objects-policy", nil, "policy for cluster-scoped objects in the ManagedResources of the given resource classes, e.g. tenant=Forbid,shoot=RequireConfirmation (Allow, Forbid or RequireConfirmation with the confirmation.gardener.cloud/cluster-scoped=true annotation, allowed for classes not given)")
+	cmd.Flags().StringSliceVar(&stripFinalizers, "strip-finalizers", nil, "finalizers which are removed from the objects of deleted ManagedResources whose deletion is blocked for longer than --strip-finalizers-timeout, must be known to be safe to remove")
+	cmd.Flags().DurationVar(&stripFinalizersTimeout, "strip-finalizers-timeout", 30*time.Minute, "duration after which the --strip-finalizers are removed from the objects of deleted ManagedResources which are still being deleted")
+	cmd.Flags().StringToStringVar(&restrictObjectNamespaces, "restrict-object-namespaces", nil, "namespace to which the namespaced objects of the ManagedResources of the given resource classes are restricted, e.g. tenant=,shoot=kube-system (the namespace of the ManagedResource if empty, unrestricted for classes not given)")
+	cmd.Flags().Float64Var(&namespaceRateLimiterQPS, "namespace-rate-limiter-qps", 10, "number of reconciliations per second of the ManagedResources of each namespace, further reconciliations are delayed (not limited if 0)")
+	cmd.Flags().IntVar(&namespaceRateLimiterBurst, "namespace-rate-limiter-burst", 100, "number of reconciliations of the ManagedResources of each namespace which are not limited by --namespace-rate-limiter-qps")
+	addRateLimiterFlags(cmd.Flags(), "secret-", "secret", &secretRateLimiter)
+	addRateLimiterFlags(cmd.Flags(), "health-", "health", &healthRateLimiter)
 	cmd.Flags().StringVar(&kubeconfigPath, "kubeconfig", "", "path to the kubeconfig for the source cluster")
 	cmd.Flags().StringVar(&targetKubeconfigPath, "target-kubeconfig", "", "path to the kubeconfig for the target cluster")
-	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace in which the ManagedResources should be observed (defaults to all namespaces)")
-	cmd.Flags().StringVar(&resourceClass, "resource-class", managedresources.DefaultClass, "resource class used to filter resource resources")
+	cmd.Flags().StringVar(&targetKubeconfigSecret, "target-kubeconfig-secret", "", "<namespace>/<name> of the secret mounted as --target-kubeconfig, whose rotations are reported and validated (not observed if empty)")
+	cmd.Flags().StringSliceVar(&namespaces, "namespace", nil, "namespaces in which the ManagedResources should be observed, can be given multiple times (defaults to all namespaces)")
+	cmd.Flags().StringVar(&resourceClass, "resource-class", managedresources.DefaultClass, "resource class used to filter resource resources, can be a comma-separated list of classes the first of which is the primary class, or "+managedresources.WildcardClass+" for all classes")
+	cmd.Flags().StringVar(&clusterID, "cluster-id", "", "identifier of the source cluster (e.g. the seed), which is part of the "+resourcesv1alpha1.OriginAnnotation+" annotation of all managed objects to distinguish the ManagedResources of multiple source clusters sharing a target cluster")
+	cmd.Flags().StringVar(&managedResourceLabelSelector, "managed-resource-label-selector", "", "label selector restricting the ManagedResources of the resource class which are reconciled by this instance (all if empty)")
+	cmd.Flags().IntVar(&shards, "shards", 1, "number of shards the ManagedResources of the resource class are distributed to by their UID (or the "+managedresources.ShardLabel+" label), each of which is reconciled by another instance")
+	cmd.Flags().IntVar(&shardIndex, "shard-index", -1, "index of the shard reconciled by this instance if --shards is greater than 1, derived from the ordinal suffix of the hostname if negative (e.g. for StatefulSets)")
 	cmd.Flags().BoolVar(&alwaysUpdate, "always-update", false, "if set to false then a resource will only be updated if its desired state differs from the actual state. otherwise, an update request will be always sent.")
+	cmd.Flags().StringVar(&logLevel, "log-level", "info", "minimum level of log entries which should be written (one of debug, info, warn, error)")
+	cmd.Flags().StringVar(&logFormat, "log-format", logpkg.FormatJSON, fmt.Sprintf("format of the log output (one of %s, %s)", logpkg.FormatJSON, logpkg.FormatText))
+	cmd.Flags().StringToStringVar(&logLevelOverrides, "log-level-overrides", nil, "log levels for individual controllers overriding --log-level (e.g. reconciler=debug,health-reconciler=error)")
+	cmd.Flags().DurationVar(&logErrorSamplingInterval, "log-error-sampling-interval", 0, "log identical errors (e.g. of a ManagedResource failing on every requeue) only once per interval together with the number of suppressed repetitions (disabled if 0)")
+	cmd.Flags().StringVar(&tracingEndpoint, "tracing-endpoint", "", "address of an OTLP (gRPC) collector to which traces of reconciliations are exported (tracing is disabled if empty)")
+	cmd.Flags().BoolVar(&tracingInsecure, "tracing-insecure", false, "disable transport security for the connection to the OTLP collector")
+	cmd.Flags().Float64Var(&tracingSamplingRatio, "tracing-sampling-ratio", 1, "ratio of reconciliations which should be traced (between 0 and 1)")
+	cmd.Flags().StringVar(&healthBindAddress, "health-bind-address", ":8081", "bind address for the liveness (/healthz) and readiness (/readyz) probes (disabled if empty)")
+	cmd.Flags().StringVar(&metricsBindAddress, "metrics-bind-address", ":8080", "bind address for the metrics endpoint (disabled if 0)")
+	cmd.Flags().StringVar(&metricsServerOptions.CertDir, "metrics-tls-cert-dir", "", "directory containing the serving certificate (tls.crt) and key (tls.key) for serving the metrics via TLS (plain HTTP if empty)")
+	cmd.Flags().StringVar(&metricsServerOptions.ClientCAFile, "metrics-client-ca-file", "", "path to a CA bundle, only clients presenting a certificate signed by it (or a token allowed by --metrics-token-review) may scrape the metrics")
+	cmd.Flags().BoolVar(&metricsServerOptions.TokenReview, "metrics-token-review", false, "only allow clients presenting a bearer token whose user may get the /metrics path (or a certificate allowed by --metrics-client-ca-file) to scrape the metrics")
+	cmd.Flags().BoolVar(&targetReachabilityCheck, "target-reachability-check", false, "include the reachability of the target cluster's API server in the readiness probe")
+	cmd.Flags().StringVar(&auditLogPath, "audit-log-path", "", "path to a file to which all mutations performed in the target cluster are appended as JSON lines, '-' writes them to the log stream (disabled if empty)")
+	cmd.Flags().BoolVar(&targetEvents, "target-events", false, "record events on the objects in the target cluster whenever they are created, updated or deleted")
+	cmd.Flags().IntVar(&webhookServerPort, "webhook-server-port", 0, "port on which the admission webhooks for ManagedResources are served (disabled if 0)")
+	cmd.Flags().StringVar(&webhookServerCertDir, "webhook-server-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "directory containing the serving certificate (tls.crt) and key (tls.key) of the webhook server")
+	cmd.Flags().StringVar(&webhookCertificateSecret, "webhook-certificate-secret", "", "<namespace>/<name> of a secret in which self-managed webhook serving certificates are stored (certificates are read from --webhook-server-cert-dir if empty)")
+	cmd.Flags().StringSliceVar(&webhookServerDNSNames, "webhook-server-dns-names", nil, "DNS names of the self-managed webhook serving certificate")
+	cmd.Flags().IntVar(&secretPolicy.MaxSize, "secret-max-size", 0, "maximum decoded size in bytes of secrets referenced by ManagedResources of this class, enforced by a webhook (disabled if 0)")
+	cmd.Flags().StringSliceVar(&secretPolicy.KeyExtensions, "secret-key-extensions", nil, "file extensions (e.g. .yaml,.json) one of which the keys of secrets referenced by ManagedResources of this class must have, enforced by a webhook (disabled if empty)")
+	cmd.Flags().BoolVar(&protectManagedObjects, "protect-managed-objects", false, "serve a webhook for the target cluster rejecting updates and deletions of managed objects by other users than the ones given in --protection-allowed-users")
+	cmd.Flags().StringSliceVar(&protectionAllowedUsers, "protection-allowed-users", nil, "users allowed to modify managed objects in the target cluster, must include the user of the gardener-resource-manager itself")
+	cmd.Flags().BoolVar(&tokenInvalidator, "token-invalidator", false, "serve a webhook for the target cluster invalidating the static tokens of service accounts labeled with "+tokeninvalidator.ProjectedTokenOnlyLabel+"=true")
+	cmd.Flags().BoolVar(&projectedTokenMount, "projected-token-mount", false, "serve a webhook for the target cluster mounting projected service account tokens into pods labeled with "+projectedtokenmount.InjectLabel+"=true")
+	cmd.Flags().DurationVar(&projectedTokenMountExpiration, "projected-token-mount-expiration", 12*time.Hour, "expiration of the service account tokens mounted by the projected token mount webhook (at least 10m)")
+	cmd.Flags().StringVar(&podSchedulerName, "pod-scheduler-name", "", "serve a webhook for the target cluster setting the scheduler name of pods using the default scheduler to the given scheduler (disabled if empty)")
+	cmd.Flags().StringVar(&kubernetesServiceHost, "kubernetes-service-host", "", "serve a webhook for the target cluster injecting the "+kubernetesservicehost.EnvKubernetesServiceHost+" environment variable with the given host into the containers of pods (disabled if empty)")
+	cmd.Flags().BoolVar(&highAvailability, "high-availability", false, "serve a webhook for the target cluster applying high availability defaults to Deployments and StatefulSets labeled (or in namespaces labeled) with "+highavailability.Label+"=true")
+	cmd.Flags().Int32Var(&highAvailabilityMinReplicas, "high-availability-min-replicas", 2, "minimum number of replicas of highly available workloads")
+	cmd.Flags().StringVar(&debugBindAddress, "debug-bind-address", "", "loopback address on which debug endpoints exposing the internal controller state are served, e.g. 127.0.0.1:8082 (disabled if empty)")
+	cmd.Flags().BoolVar(&enablePprof, "enable-pprof", false, "serve the net/http/pprof profiling endpoints under /debug/pprof/ on the debug bind address")
+
+	features.DefaultFeatureGate.AddFlag(cmd.Flags())
 
 	return cmd
 }
 
+// addRateLimiterFlags adds the flags configuring the rate limiter of the given controller with the given prefix.
+func addRateLimiterFlags(fs *pflag.FlagSet, prefix, controllerName string, opts *utils.RateLimiterOptions) {
+	fs.DurationVar(&opts.BaseDelay, prefix+"rate-limiter-base-delay", opts.BaseDelay, "delay of the first retry of a failed item of the "+controllerName+" controller, doubled with every further failure")
+	fs.DurationVar(&opts.MaxDelay, prefix+"rate-limiter-max-delay", opts.MaxDelay, "maximum delay of the retries of a failed item of the "+controllerName+" controller")
+	fs.Float64Var(&opts.QPS, prefix+"rate-limiter-qps", opts.QPS, "overall number of retries per second of the "+controllerName+" controller")
+	fs.IntVar(&opts.Burst, prefix+"rate-limiter-burst", opts.Burst, "burst of the overall retries of the "+controllerName+" controller")
+}
+
+// addHealthChecks adds the liveness and readiness checks to the manager. The instance is ready as soon as the caches for
+// the source and the target cluster have been synced and (if enabled) the target cluster's API server is reachable.
+// Standby instances waiting for leadership are ready as well, as otherwise rolling updates would never finish.
+func addHealthChecks(mgr manager.Manager, targetCache cache.Cache, targetConfig *rest.Config, targetReachabilityCheck bool) error {
+	if err := mgr.AddHealthzCheck("ping", runtimehealthz.Ping); err != nil {
+		return fmt.Errorf("unable to add liveness check: %+v", err)
+	}
+
+	for name, c := range map[string]cache.Cache{"cache-sync": mgr.GetCache(), "target-cache-sync": targetCache} {
+		check := healthz.NewCacheSyncCheck(c)
+		if err := mgr.Add(check); err != nil {
+			return fmt.Errorf("unable to add cache sync check to manager: %+v", err)
+		}
+		if err := mgr.AddReadyzCheck(name, check.Check); err != nil {
+			return fmt.Errorf("unable to add readiness check: %+v", err)
+		}
+	}
+
+	if targetReachabilityCheck {
+		config := rest.CopyConfig(targetConfig)
+		config.Timeout = 5 * time.Second
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return fmt.Errorf("unable to create discovery client for target cluster: %+v", err)
+		}
+		if err := mgr.AddReadyzCheck("target-reachable", healthz.NewAPIServerCheck(discoveryClient)); err != nil {
+			return fmt.Errorf("unable to add readiness check: %+v", err)
+		}
+	}
+
+	return nil
+}
+
+// addWebhookCertificateManager generates the webhook serving certificates (if necessary) before the webhook server is
+// started and adds the certificate manager rotating them to the manager. The CA bundle is injected into the labeled
+// webhook configurations of the source cluster and, if the protection webhook is served, of the target cluster.
+func addWebhookCertificateManager(ctx context.Context, mgr manager.Manager, secretKey types.NamespacedName, dnsNames []string, certDir string, protectManagedObjects bool, targetConfig *rest.Config, targetScheme *runtime.Scheme, targetRESTMapper meta.RESTMapper) error {
+	sourceClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return fmt.Errorf("unable to create client for webhook certificates: %+v", err)
+	}
+
+	webhookClients := []client.Client{sourceClient}
+	if protectManagedObjects {
+		targetWebhookClient, err := client.New(targetConfig, client.Options{Scheme: targetScheme, Mapper: targetRESTMapper})
+		if err != nil {
+			return fmt.Errorf("unable to create client for webhook configurations in target cluster: %+v", err)
+		}
+		webhookClients = append(webhookClients, targetWebhookClient)
+	}
+
+	certificateManager := certificates.NewManager(log.WithName("webhook-certificates"), sourceClient, secretKey, dnsNames, certDir, webhookClients...)
+	if err := certificateManager.Sync(ctx); err != nil {
+		return fmt.Errorf("unable to set up webhook certificates: %+v", err)
+	}
+	if err := mgr.Add(certificateManager); err != nil {
+		return fmt.Errorf("unable to add webhook certificate manager to manager: %+v", err)
+	}
+	return nil
+}
+
+// addRESTMapperInvalidator resets the given REST mapper of the target cluster after the given TTL and whenever a
+// CustomResourceDefinition in the target cluster changes.
+func addRESTMapperInvalidator(mgr manager.Manager, log logr.Logger, targetRESTMapper utils.Resetter, targetCache cache.Cache, ttl time.Duration) error {
+	invalidator := utils.NewRESTMapperInvalidator(log, targetRESTMapper, ttl)
+
+	crdInformer, err := targetCache.GetInformer(&apiextensionsv1beta1.CustomResourceDefinition{})
+	if err != nil {
+		return fmt.Errorf("unable to get informer for CustomResourceDefinitions of the target cluster: %+v", err)
+	}
+	crdInformer.AddEventHandler(invalidator)
+
+	if err := mgr.Add(invalidator); err != nil {
+		return fmt.Errorf("unable to add REST mapper invalidator to manager: %+v", err)
+	}
+	return nil
+}
+
+// addGarbageCollector adds a garbage collector for orphaned objects in the target cluster to the given manager. It
+// lists the objects of the target cluster directly, as they would be cached otherwise.
+func addGarbageCollector(ctx context.Context, mgr manager.Manager, log logr.Logger, targetConfig *rest.Config, targetScheme *runtime.Scheme, targetRESTMapper meta.RESTMapper, filter *managedresources.ClassFilter, clusterID string, auditSink audit.Sink, options managedresources.GarbageCollectorOptions) error {
+	targetDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(targetConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create discovery client for garbage collector: %+v", err)
+	}
+	targetClient, err := client.New(targetConfig, client.Options{Scheme: targetScheme, Mapper: targetRESTMapper})
+	if err != nil {
+		return fmt.Errorf("unable to create client for garbage collector: %+v", err)
+	}
+
+	if err := mgr.Add(managedresources.NewGarbageCollector(ctx, log, mgr.GetClient(), targetClient, targetDiscoveryClient, filter, clusterID, auditSink, options)); err != nil {
+		return fmt.Errorf("unable to add garbage collector to manager: %+v", err)
+	}
+	return nil
+}
+
+// addTokenRequestor adds the token requestor controller to the given manager. It requests the tokens with a clientset
+// for the target cluster, as TokenRequests are not supported by the controller-runtime client.
+func addTokenRequestor(ctx context.Context, mgr manager.Manager, log logr.Logger, targetConfig *rest.Config, maxConcurrentWorkers int) error {
+	targetClientset, err := kubernetes.NewForConfig(targetConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create clientset for token requestor: %+v", err)
+	}
+
+	tokenRequestorController, err := controller.New("token-requestor", mgr, controller.Options{
+		MaxConcurrentReconciles: maxConcurrentWorkers,
+		Reconciler:              tokenrequestor.NewReconciler(ctx, log, targetClientset.CoreV1()),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set up token requestor: %+v", err)
+	}
+
+	if err := tokenRequestorController.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestForObject{},
+		managerpredicate.HasLabel(resourcesv1alpha1.ResourceManagerPurpose, resourcesv1alpha1.LabelPurposeTokenRequest),
+	); err != nil {
+		return fmt.Errorf("unable to watch Secrets: %+v", err)
+	}
+	return nil
+}
+
+// addNetworkPolicyController adds the network policy controller to the given manager. It watches the Services and
+// NetworkPolicies of the target cluster with the given cache.
+func addNetworkPolicyController(ctx context.Context, mgr manager.Manager, log logr.Logger, targetClient client.Client, targetCache cache.Cache, namespaces []string, maxConcurrentWorkers int) error {
+	networkPolicyController, err := controller.New("network-policy-controller", mgr, controller.Options{
+		MaxConcurrentReconciles: maxConcurrentWorkers,
+		Reconciler:              networkpolicy.NewReconciler(ctx, log, targetClient),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set up network policy controller: %+v", err)
+	}
+
+	for _, watch := range []struct {
+		obj     runtime.Object
+		handler handler.EventHandler
+	}{
+		{&corev1.Service{}, &handler.EnqueueRequestForObject{}},
+		// correct changes of the NetworkPolicies by others
+		{&networkingv1.NetworkPolicy{}, &handler.EnqueueRequestForOwner{OwnerType: &corev1.Service{}, IsController: true}},
+	} {
+		src := &source.Kind{Type: watch.obj}
+		// inject the target cache before the controller injects the cache of the manager, which is not overridden then
+		if _, err := inject.CacheInto(targetCache, src); err != nil {
+			return fmt.Errorf("unable to inject target cache: %+v", err)
+		}
+		if err := networkPolicyController.Watch(src, watch.handler, managerpredicate.InNamespace(namespaces...)); err != nil {
+			return fmt.Errorf("unable to watch %T of the target cluster: %+v", watch.obj, err)
+		}
+	}
+	return nil
+}
+
+// addNodeController adds the node controller to the given manager. It watches the nodes of the target cluster with the
+// given cache, but lists the critical DaemonSets and pods directly and watches the critical pods with an own informer,
+// as caching all pods would be too expensive.
+func addNodeController(ctx context.Context, mgr manager.Manager, log logr.Logger, targetConfig *rest.Config, targetScheme *runtime.Scheme, targetRESTMapper meta.RESTMapper, targetClient client.Client, targetCache cache.Cache, maxConcurrentWorkers int) error {
+	targetReader, err := client.New(targetConfig, client.Options{Scheme: targetScheme, Mapper: targetRESTMapper})
+	if err != nil {
+		return fmt.Errorf("unable to create client for node controller: %+v", err)
+	}
+	targetClientset, err := kubernetes.NewForConfig(targetConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create clientset for node controller: %+v", err)
+	}
+
+	nodeController, err := controller.New("node-controller", mgr, controller.Options{
+		MaxConcurrentReconciles: maxConcurrentWorkers,
+		Reconciler:              node.NewReconciler(ctx, log, targetClient, targetReader),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set up node controller: %+v", err)
+	}
+
+	nodeSource := &source.Kind{Type: &corev1.Node{}}
+	// inject the target cache before the controller injects the cache of the manager, which is not overridden then
+	if _, err := inject.CacheInto(targetCache, nodeSource); err != nil {
+		return fmt.Errorf("unable to inject target cache: %+v", err)
+	}
+	if err := nodeController.Watch(nodeSource, &handler.EnqueueRequestForObject{}, managerpredicate.HasTaint(node.TaintCriticalComponentsNotReady)); err != nil {
+		return fmt.Errorf("unable to watch Nodes of the target cluster: %+v", err)
+	}
+
+	criticalPodsSelector := node.CriticalComponentLabel + "=true"
+	podInformer := toolscache.NewSharedIndexInformer(&toolscache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = criticalPodsSelector
+			return targetClientset.CoreV1().Pods(metav1.NamespaceAll).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = criticalPodsSelector
+			return targetClientset.CoreV1().Pods(metav1.NamespaceAll).Watch(options)
+		},
+	}, &corev1.Pod{}, 0, toolscache.Indexers{})
+	if err := mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		podInformer.Run(stop)
+		return nil
+	})); err != nil {
+		return fmt.Errorf("unable to add informer for critical pods to manager: %+v", err)
+	}
+
+	if err := nodeController.Watch(
+		&source.Informer{Informer: podInformer},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			pod, ok := obj.Object.(*corev1.Pod)
+			if !ok || pod.Spec.NodeName == "" {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: pod.Spec.NodeName}}}
+		})},
+	); err != nil {
+		return fmt.Errorf("unable to watch critical Pods of the target cluster: %+v", err)
+	}
+	return nil
+}
+
+// addTargetKubeconfigController adds the target kubeconfig controller to the given manager. The secret is mounted as
+// --target-kubeconfig, hence the kubeconfig is contained in the data key named like the file, and rotations are
+// detected by comparing it to the content of the file the gardener-resource-manager has been started with.
+func addTargetKubeconfigController(ctx context.Context, mgr manager.Manager, log logr.Logger, secretKey types.NamespacedName, kubeconfigPath string) error {
+	data, err := ioutil.ReadFile(kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("unable to read kubeconfig of the target cluster: %+v", err)
+	}
+
+	targetKubeconfigController, err := controller.New("target-kubeconfig-controller", mgr, controller.Options{
+		MaxConcurrentReconciles: 1,
+		Reconciler:              kubeconfig.NewReconciler(ctx, log, mgr.GetEventRecorderFor("gardener-resource-manager"), secretKey, filepath.Base(kubeconfigPath), data),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set up target kubeconfig controller: %+v", err)
+	}
+
+	if err := targetKubeconfigController.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestForObject{},
+		managerpredicate.HasName(secretKey.Namespace, secretKey.Name),
+	); err != nil {
+		return fmt.Errorf("unable to watch Secrets: %+v", err)
+	}
+	return nil
+}
+
 func getTargetRESTMapper(config *rest.Config) (*restmapper.DeferredDiscoveryRESTMapper, error) {
 	targetDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
@@ -373,7 +1461,7 @@ func getTargetConfig(kubeconfigPath string) (*rest.Config, error) {
 			return c, nil
 		}
 	}
-	return nil, fmt.Errorf("could not create config for cluster")
+	return nil, fmt.Errorf("could not create config for cluster, neither --target-kubeconfig nor $KUBECONFIG are set, and no in-cluster config or ~/.kube/config is available")
 }
 
 func getTargetCache(config *rest.Config, options cache.Options) (cache.Cache, error) {
@@ -0,0 +1,195 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/diagnose"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apiextensionsinstall "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// diagnoseOptions are the options of the commands inspecting a single ManagedResource.
+type diagnoseOptions struct {
+	kubeconfigPath       string
+	targetKubeconfigPath string
+	namespace            string
+	name                 string
+	output               string
+	timeout              time.Duration
+}
+
+func (o *diagnoseOptions) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfigPath, "kubeconfig", "", "path to the kubeconfig for the source cluster")
+	fs.StringVar(&o.targetKubeconfigPath, "target-kubeconfig", "", "path to the kubeconfig for the target cluster (defaults to the source cluster)")
+	fs.StringVarP(&o.namespace, "namespace", "n", "default", "namespace of the ManagedResource")
+	fs.StringVar(&o.name, "name", "", "name of the ManagedResource")
+	fs.StringVarP(&o.output, "output", "o", "", "output format (empty or json)")
+	fs.DurationVar(&o.timeout, "timeout", time.Minute, "timeout for reading the ManagedResource and its objects")
+}
+
+func (o *diagnoseOptions) validate() error {
+	if o.name == "" {
+		return fmt.Errorf("--name must be set")
+	}
+	if o.output != "" && o.output != "json" {
+		return fmt.Errorf("--output must be empty or json")
+	}
+	return nil
+}
+
+func (o *diagnoseOptions) key() types.NamespacedName {
+	return types.NamespacedName{Namespace: o.namespace, Name: o.name}
+}
+
+// clients returns uncached clients for the source and the target cluster, and the scheme of the target client.
+func (o *diagnoseOptions) clients() (client.Client, client.Client, *runtime.Scheme, error) {
+	var (
+		sourceConfig *rest.Config
+		err          error
+	)
+	if o.kubeconfigPath != "" {
+		sourceConfig, err = clientcmd.BuildConfigFromFlags("", o.kubeconfigPath)
+	} else {
+		sourceConfig, err = config.GetConfig()
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not instantiate rest config: %+v", err)
+	}
+
+	targetConfig := sourceConfig
+	if o.targetKubeconfigPath != "" {
+		if targetConfig, err = getTargetConfig(o.targetKubeconfigPath); err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to create REST config for target cluster: %+v", err)
+		}
+	}
+
+	sourceScheme := runtime.NewScheme()
+	utilruntime.Must(scheme.AddToScheme(sourceScheme))
+	utilruntime.Must(resourcesv1alpha1.AddToScheme(sourceScheme))
+
+	targetScheme := runtime.NewScheme()
+	utilruntime.Must(scheme.AddToScheme(targetScheme))
+	apiextensionsinstall.Install(targetScheme)
+
+	sourceClient, err := client.New(sourceConfig, client.Options{Scheme: sourceScheme})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to create client for source cluster: %+v", err)
+	}
+	targetClient, err := client.New(targetConfig, client.Options{Scheme: targetScheme})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to create client for target cluster: %+v", err)
+	}
+	return sourceClient, targetClient, targetScheme, nil
+}
+
+// print writes the given value as JSON if requested, otherwise with the given function.
+func (o *diagnoseOptions) print(cmd *cobra.Command, value interface{}, write func() error) error {
+	if o.output != "json" {
+		return write()
+	}
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(out))
+	return nil
+}
+
+// newDiagnoseCommand creates a new command explaining why a ManagedResource is not reconciled or deleted.
+func newDiagnoseCommand() *cobra.Command {
+	opts := &diagnoseOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Explain why a ManagedResource is stuck",
+		Long: "Explain why a ManagedResource is stuck by summarizing its finalizers and conditions, the state of its secrets " +
+			"in the source cluster and the state of its objects in the target cluster.",
+		Args: cobra.NoArgs,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			sourceClient, targetClient, _, err := opts.clients()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+			defer cancel()
+
+			report, err := diagnose.Diagnose(ctx, sourceClient, targetClient, opts.key())
+			if err != nil {
+				return err
+			}
+			return opts.print(cmd, report, func() error { return report.Write(cmd.OutOrStdout()) })
+		},
+	}
+
+	opts.addFlags(cmd.Flags())
+	return cmd
+}
+
+// newTreeCommand creates a new command printing a ManagedResource, its secrets and its objects with their live health
+// as a tree.
+func newTreeCommand() *cobra.Command {
+	opts := &diagnoseOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Print a ManagedResource with its secrets and objects as a tree",
+		Long: "Print a ManagedResource with its secrets in the source cluster and its objects in the target cluster as a tree, " +
+			"together with the live health of the objects.",
+		Args: cobra.NoArgs,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			sourceClient, targetClient, targetScheme, err := opts.clients()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+			defer cancel()
+
+			tree, err := diagnose.Tree(ctx, sourceClient, targetClient, targetScheme, opts.key())
+			if err != nil {
+				return err
+			}
+			return opts.print(cmd, tree, func() error { return tree.Write(cmd.OutOrStdout()) })
+		},
+	}
+
+	opts.addFlags(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsinstall "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterScopedGroupKinds are the cluster-scoped kinds of the standard APIs. As long as no target cluster is given, the
+// scope of all other kinds known to the scheme is assumed to be namespaced.
+var clusterScopedGroupKinds = []schema.GroupKind{
+	{Group: "", Kind: "ComponentStatus"},
+	{Group: "", Kind: "Namespace"},
+	{Group: "", Kind: "Node"},
+	{Group: "", Kind: "PersistentVolume"},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+	{Group: "apiregistration.k8s.io", Kind: "APIService"},
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"},
+	{Group: "extensions", Kind: "PodSecurityPolicy"},
+	{Group: "node.k8s.io", Kind: "RuntimeClass"},
+	{Group: "policy", Kind: "PodSecurityPolicy"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"},
+	{Group: "storage.k8s.io", Kind: "CSIDriver"},
+	{Group: "storage.k8s.io", Kind: "CSINode"},
+	{Group: "storage.k8s.io", Kind: "StorageClass"},
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"},
+}
+
+// newOfflineRESTMapper returns a mapper for the kinds of the given scheme which doesn't need a cluster.
+func newOfflineRESTMapper(s *runtime.Scheme) meta.RESTMapper {
+	clusterScoped := make(map[schema.GroupKind]struct{}, len(clusterScopedGroupKinds))
+	for _, groupKind := range clusterScopedGroupKinds {
+		clusterScoped[groupKind] = struct{}{}
+	}
+
+	mapper := meta.NewDefaultRESTMapper(s.PrioritizedVersionsAllGroups())
+	for gvk := range s.AllKnownTypes() {
+		if _, ok := clusterScoped[gvk.GroupKind()]; ok {
+			mapper.Add(gvk, meta.RESTScopeRoot)
+		} else {
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+	}
+	return mapper
+}
+
+// parseObjectKey parses the given key in the form `<namespace>/<name>`.
+func parseObjectKey(key string) (types.NamespacedName, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid key %q, expected <namespace>/<name>", key)
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+// readSecretFile reads a secret manifest from the given file. Its `stringData` is merged into its `data` like by the
+// API server.
+func readSecretFile(path string) (*corev1.Secret, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{}
+	if err := yaml.Unmarshal(data, secret); err != nil {
+		return nil, fmt.Errorf("could not decode secret from %s: %+v", path, err)
+	}
+	if secret.Kind != "Secret" {
+		return nil, fmt.Errorf("%s does not contain a Secret", path)
+	}
+
+	if len(secret.StringData) > 0 && secret.Data == nil {
+		secret.Data = make(map[string][]byte, len(secret.StringData))
+	}
+	for key, value := range secret.StringData {
+		secret.Data[key] = []byte(value)
+	}
+	secret.StringData = nil
+	return secret, nil
+}
+
+// newRenderCommand creates a new command printing the objects of ManagedResource secrets as they are applied.
+func newRenderCommand() *cobra.Command {
+	var (
+		opts            = &diagnoseOptions{}
+		secretKeys      []string
+		files           []string
+		managedResource string
+		class           string
+		injectLabels    map[string]string
+		clusterID       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Print the objects of ManagedResource secrets as they are applied",
+		Long: "Print the objects of ManagedResource secrets as they are created in the target cluster by the " +
+			"gardener-resource-manager, i.e. with defaulted namespaces, generated names, injected labels and the origin " +
+			"label and annotation. The secrets are read from the source cluster (--secret, or the secrets referenced by " +
+			"--managed-resource) or from files (--file). Without --target-kubeconfig, the scope of the kinds is looked up " +
+			"from the standard APIs, objects of other kinds are assumed to be namespaced.",
+		Args: cobra.NoArgs,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(secretKeys) == 0 && len(files) == 0 && managedResource == "" {
+				return fmt.Errorf("one of --secret, --file or --managed-resource must be set")
+			}
+			if opts.output != "" && opts.output != "json" {
+				return fmt.Errorf("--output must be empty or json")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+			defer cancel()
+
+			var secrets []*corev1.Secret
+			for _, path := range files {
+				secret, err := readSecretFile(path)
+				if err != nil {
+					return err
+				}
+				secrets = append(secrets, secret)
+			}
+
+			mr := &resourcesv1alpha1.ManagedResource{Spec: resourcesv1alpha1.ManagedResourceSpec{InjectLabels: injectLabels}}
+			if class != "" {
+				mr.Spec.Class = &class
+			}
+
+			var mapper meta.RESTMapper
+			if managedResource != "" || len(secretKeys) > 0 || opts.targetKubeconfigPath != "" {
+				sourceClient, _, _, err := opts.clients()
+				if err != nil {
+					return err
+				}
+
+				if managedResource != "" {
+					key, err := parseObjectKey(managedResource)
+					if err != nil {
+						return err
+					}
+					if err := sourceClient.Get(ctx, key, mr); err != nil {
+						return fmt.Errorf("could not get ManagedResource %s: %+v", key, err)
+					}
+					if len(secretKeys) == 0 && len(files) == 0 {
+						for _, ref := range mr.Spec.SecretRefs {
+							secretKeys = append(secretKeys, key.Namespace+"/"+ref.Name)
+						}
+					}
+				}
+
+				for _, secretKey := range secretKeys {
+					key, err := parseObjectKey(secretKey)
+					if err != nil {
+						return err
+					}
+					secret := &corev1.Secret{}
+					if err := sourceClient.Get(ctx, key, secret); err != nil {
+						return fmt.Errorf("could not read secret %s: %+v", key, err)
+					}
+					secrets = append(secrets, secret)
+				}
+			}
+
+			if opts.targetKubeconfigPath != "" {
+				targetConfig, err := getTargetConfig(opts.targetKubeconfigPath)
+				if err != nil {
+					return fmt.Errorf("unable to create REST config for target cluster: %+v", err)
+				}
+				if mapper, err = getTargetRESTMapper(targetConfig); err != nil {
+					return fmt.Errorf("unable to create REST mapper for target cluster: %+v", err)
+				}
+			} else {
+				targetScheme := runtime.NewScheme()
+				utilruntime.Must(scheme.AddToScheme(targetScheme))
+				apiextensionsinstall.Install(targetScheme)
+				mapper = newOfflineRESTMapper(targetScheme)
+			}
+
+			// decoding errors are returned, and unknown kinds are described in the help text
+			objs, err := managedresources.Render(runtimelog.NullLogger{}, mapper, mr, clusterID, secrets)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if opts.output == "json" {
+				data, err := json.MarshalIndent(objs, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(out, string(data))
+				return nil
+			}
+			for _, obj := range objs {
+				data, err := yaml.Marshal(obj.Object)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "---\n%s", data)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.kubeconfigPath, "kubeconfig", "", "path to the kubeconfig for the source cluster")
+	cmd.Flags().StringVar(&opts.targetKubeconfigPath, "target-kubeconfig", "", "path to the kubeconfig for the target cluster, used for looking up the scope of the kinds")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output format (empty for YAML, or json)")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", time.Minute, "timeout for reading the ManagedResource and its secrets")
+	cmd.Flags().StringArrayVar(&secretKeys, "secret", nil, "secret in the source cluster to render in the form <namespace>/<name> (can be given multiple times)")
+	cmd.Flags().StringArrayVarP(&files, "file", "f", nil, "file containing a secret manifest to render (can be given multiple times)")
+	cmd.Flags().StringVar(&managedResource, "managed-resource", "", "ManagedResource in the source cluster in the form <namespace>/<name> whose settings are used, and whose secrets are rendered if neither --secret nor --file are given")
+	cmd.Flags().StringVar(&class, "class", "", "resource class of the objects if --managed-resource is not given")
+	cmd.Flags().StringToStringVar(&injectLabels, "inject-labels", nil, "labels injected into the objects if --managed-resource is not given")
+	cmd.Flags().StringVar(&clusterID, "cluster-id", "", "ID of the source cluster used in the origin annotation")
+	return cmd
+}
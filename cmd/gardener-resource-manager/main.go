@@ -26,8 +26,6 @@ import (
 )
 
 func main() {
-	runtimelog.SetLogger(log.ZapLogger(false))
-
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
 		defer cancel()
@@ -37,6 +35,9 @@ func main() {
 	cmd := app.NewControllerManagerCommand(ctx)
 
 	if err := cmd.Execute(); err != nil {
+		// the logger is configured by the command according to the given flags, fall back to the default logger
+		// in case the command failed before doing so (no-op otherwise)
+		runtimelog.SetLogger(log.ZapLogger(false))
 		runtimelog.Log.Error(err, "error executing the main controller command")
 		os.Exit(1)
 	}
@@ -3,6 +3,9 @@ module github.com/gardener/gardener-resource-manager
 go 1.13
 
 require (
+	github.com/Masterminds/semver v1.5.0
+	github.com/evanphx/json-patch v4.5.0+incompatible
+	github.com/gardener/etcd-druid v0.1.15
 	github.com/gardener/gardener v1.4.1-0.20200519155656-a8ccc6cc779a
 	github.com/gardener/hvpa-controller v0.2.5
 	github.com/go-logr/logr v0.1.0
@@ -10,19 +13,30 @@ require (
 	github.com/hashicorp/go-multierror v1.0.0
 	github.com/onsi/ginkgo v1.10.1
 	github.com/onsi/gomega v1.7.0
+	github.com/prometheus/client_golang v1.3.0
+	github.com/prometheus/client_model v0.1.0
+	github.com/prometheus/common v0.7.0
 	github.com/spf13/cobra v0.0.6
 	github.com/spf13/pflag v1.0.5
+	go.opentelemetry.io/otel v0.13.0
+	go.opentelemetry.io/otel/exporters/otlp v0.13.0
+	go.opentelemetry.io/otel/sdk v0.13.0
 	go.uber.org/zap v1.13.0
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
+	gomodules.xyz/jsonpatch/v2 v2.0.1
 	k8s.io/api v0.17.0
 	k8s.io/apiextensions-apiserver v0.17.0
 	k8s.io/apimachinery v0.17.0
 	k8s.io/autoscaler v0.0.0-20190805135949-100e91ba756e
 	k8s.io/client-go v11.0.1-0.20190409021438-1a26190bd76a+incompatible
 	k8s.io/code-generator v0.17.0
+	k8s.io/component-base v0.16.8
+	k8s.io/helm v2.16.1+incompatible
 	k8s.io/kube-aggregator v0.16.8
 	k8s.io/kube-openapi v0.0.0-20191107075043-30be4d16710a
 	k8s.io/utils v0.0.0-20200327001022-6496210b90e8
 	sigs.k8s.io/controller-runtime v0.4.0
+	sigs.k8s.io/yaml v1.1.0
 )
 
 replace (
@@ -45,5 +45,5 @@ bash "${PROJECT_ROOT}"/vendor/k8s.io/code-generator/generate-groups.sh \
   "deepcopy" \
   github.com/gardener/gardener-resource-manager/pkg/client/resources \
   github.com/gardener/gardener-resource-manager/pkg/apis \
-  resources:v1alpha1 \
+  resources:v1alpha1,v1beta1 \
   -h <(headers)
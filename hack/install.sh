@@ -21,7 +21,9 @@ source "$DIRNAME/common.sh"
 
 header_text "Install"
 
-LD_FLAGS="-w -X github.com/gardener/gardener-resource-manager/pkg/version.Version=$VERSION"
+GIT_COMMIT="$(git rev-parse --verify HEAD 2>/dev/null || true)"
+BUILD_DATE="$(date -u '+%Y-%m-%dT%H:%M:%SZ')"
+LD_FLAGS="-w -X github.com/gardener/gardener-resource-manager/pkg/version.Version=$VERSION -X github.com/gardener/gardener-resource-manager/pkg/version.GitCommit=$GIT_COMMIT -X github.com/gardener/gardener-resource-manager/pkg/version.BuildDate=$BUILD_DATE"
 
 CGO_ENABLED=0 GOOS=linux GOARCH=amd64 GO111MODULE=on \
     go install \
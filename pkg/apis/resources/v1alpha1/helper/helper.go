@@ -15,9 +15,15 @@
 package helper
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // Now determines the current metav1.Time.
@@ -97,3 +103,54 @@ func MergeConditions(oldConditions []resourcesv1alpha1.ManagedResourceCondition,
 
 	return out
 }
+
+// Origin returns the value of the origin annotation for resources managed by the ManagedResource with the given key
+// in the source cluster with the given ID (may be empty).
+func Origin(clusterID string, managedResource types.NamespacedName) string {
+	if clusterID == "" {
+		return managedResource.String()
+	}
+	return clusterID + ":" + managedResource.String()
+}
+
+// ParseOrigin returns the cluster ID and the key of the ManagedResource contained in the given value of the origin
+// annotation.
+func ParseOrigin(origin string) (string, types.NamespacedName, error) {
+	var clusterID string
+	// namespaces and names cannot contain colons
+	if i := strings.LastIndex(origin, ":"); i >= 0 {
+		clusterID, origin = origin[:i], origin[i+1:]
+	}
+
+	parts := strings.Split(origin, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", types.NamespacedName{}, fmt.Errorf("origin %q must be of the form [<cluster-id>:]<namespace>/<name>", origin)
+	}
+	return clusterID, types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+// IsIgnored returns true if the given ManagedResource is annotated to be ignored, i.e. it is neither reconciled nor
+// health checked.
+func IsIgnored(mr *resourcesv1alpha1.ManagedResource) bool {
+	ignored, _ := strconv.ParseBool(mr.Annotations[resourcesv1alpha1.Ignore])
+	return ignored
+}
+
+// ReconcileTimeout returns the maximum duration of the reconciliations of the given ManagedResource, i.e. the value
+// of its reconcile timeout annotation or the given default if it is not annotated. A duration of `0` means that the
+// reconciliations are not bounded.
+func ReconcileTimeout(mr *resourcesv1alpha1.ManagedResource, defaultTimeout time.Duration) (time.Duration, error) {
+	value, ok := mr.Annotations[resourcesv1alpha1.ReconcileTimeout]
+	if !ok {
+		return defaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultTimeout, fmt.Errorf("invalid value %q of annotation %s: %+v", value, resourcesv1alpha1.ReconcileTimeout, err)
+	}
+	if timeout < 0 {
+		return defaultTimeout, fmt.Errorf("invalid value %q of annotation %s: must not be negative", value, resourcesv1alpha1.ReconcileTimeout)
+	}
+	return timeout, nil
+}
@@ -22,6 +22,7 @@ import (
 	helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -102,4 +103,74 @@ var _ = Describe("helper", func() {
 			Expect(helper.GetOrInitCondition(nil, "foo")).To(Equal(helper.InitCondition("foo")))
 		})
 	})
+
+	Describe("#Origin", func() {
+		key := types.NamespacedName{Namespace: "foo", Name: "bar"}
+
+		It("should contain the cluster ID and the key of the ManagedResource", func() {
+			Expect(helper.Origin("seed", key)).To(Equal("seed:foo/bar"))
+		})
+
+		It("should only contain the key of the ManagedResource without cluster ID", func() {
+			Expect(helper.Origin("", key)).To(Equal("foo/bar"))
+		})
+	})
+
+	Describe("#ParseOrigin", func() {
+		It("should parse origins with and without cluster ID", func() {
+			clusterID, key, err := helper.ParseOrigin("shoot:seed:foo/bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterID).To(Equal("shoot:seed"))
+			Expect(key).To(Equal(types.NamespacedName{Namespace: "foo", Name: "bar"}))
+
+			clusterID, key, err = helper.ParseOrigin("foo/bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterID).To(BeEmpty())
+			Expect(key).To(Equal(types.NamespacedName{Namespace: "foo", Name: "bar"}))
+		})
+
+		It("should fail for invalid origins", func() {
+			for _, origin := range []string{"", "seed", "seed:foo", "seed:/bar", "foo/bar/baz"} {
+				_, _, err := helper.ParseOrigin(origin)
+				Expect(err).To(HaveOccurred(), origin)
+			}
+		})
+	})
+	Describe("#IsIgnored", func() {
+		It("should return whether the ManagedResource is ignored", func() {
+			mr := &resourcesv1alpha1.ManagedResource{}
+			Expect(helper.IsIgnored(mr)).To(BeFalse())
+
+			mr.Annotations = map[string]string{resourcesv1alpha1.Ignore: "false"}
+			Expect(helper.IsIgnored(mr)).To(BeFalse())
+
+			mr.Annotations = map[string]string{resourcesv1alpha1.Ignore: "true"}
+			Expect(helper.IsIgnored(mr)).To(BeTrue())
+		})
+	})
+
+	Describe("#ReconcileTimeout", func() {
+		It("should return the default timeout if the ManagedResource is not annotated", func() {
+			Expect(helper.ReconcileTimeout(&resourcesv1alpha1.ManagedResource{}, time.Minute)).To(Equal(time.Minute))
+		})
+
+		It("should return the timeout of the annotation", func() {
+			mr := &resourcesv1alpha1.ManagedResource{}
+			mr.Annotations = map[string]string{resourcesv1alpha1.ReconcileTimeout: "10m"}
+			Expect(helper.ReconcileTimeout(mr, time.Minute)).To(Equal(10 * time.Minute))
+
+			mr.Annotations[resourcesv1alpha1.ReconcileTimeout] = "0"
+			Expect(helper.ReconcileTimeout(mr, time.Minute)).To(BeZero())
+		})
+
+		It("should return the default timeout and an error for invalid annotations", func() {
+			mr := &resourcesv1alpha1.ManagedResource{}
+			for _, value := range []string{"foo", "-1m"} {
+				mr.Annotations = map[string]string{resourcesv1alpha1.ReconcileTimeout: value}
+				timeout, err := helper.ReconcileTimeout(mr, time.Minute)
+				Expect(err).To(MatchError(ContainSubstring(resourcesv1alpha1.ReconcileTimeout)))
+				Expect(timeout).To(Equal(time.Minute))
+			}
+		})
+	})
 })
@@ -17,19 +17,120 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
 	// Ignore is an annotation that dictates whether a resources should be ignored during
-	// reconciliation.
+	// reconciliation. On a ManagedResource, it suspends its reconciliation and health checks completely.
 	Ignore = "resources.gardener.cloud/ignore"
 	// DeleteOnInvalidUpdate is a constant for an annotation on a resource managed by a ManagedResource. If set to
 	// true then the controller will delete the object in case it faces an "Invalid" response during an update operation.
 	DeleteOnInvalidUpdate = "resources.gardener.cloud/delete-on-invalid-update"
+	// PreserveAvailability is a constant for an annotation on a Deployment or StatefulSet managed by a ManagedResource.
+	// If set to true then the controller will not delete the object in case of an "Invalid" response during an update
+	// operation, if deleting its pods would violate a PodDisruptionBudget.
+	PreserveAvailability = "resources.gardener.cloud/preserve-availability"
+	// Mode is a constant for an annotation on a resource contained in the secrets of a ManagedResource. It controls
+	// how the resource is managed by the controller.
+	Mode = "resources.gardener.cloud/mode"
+	// ModeIgnore is a value for the Mode annotation. Resources in this mode are neither applied nor deleted by the
+	// controller, but they are still part of the ManagedResource, e.g. while they are managed by hand temporarily.
+	ModeIgnore = "Ignore"
+	// Hook is a constant for an annotation on a Job or Pod contained in the secrets of a ManagedResource. It makes the
+	// resource a hook which is run at the given phase of the reconciliation, the controller waits for its completion
+	// before continuing.
+	Hook = "resources.gardener.cloud/hook"
+	// HookPreApply is a value for the Hook annotation. Hooks of this phase are run before the other resources are applied.
+	HookPreApply = "pre-apply"
+	// HookPostApply is a value for the Hook annotation. Hooks of this phase are run after the other resources have been
+	// applied.
+	HookPostApply = "post-apply"
+	// HookPreDelete is a value for the Hook annotation. Hooks of this phase are run when the ManagedResource is deleted,
+	// before its resources are deleted.
+	HookPreDelete = "pre-delete"
+	// WaitForReady is a constant for an annotation on a resource contained in the secrets of a ManagedResource. If set
+	// to true then the controller waits (for a bounded time) until the resource is healthy before applying the
+	// resources following it.
+	WaitForReady = "resources.gardener.cloud/wait-for-ready"
+	// SkipIfAPIUnavailable is a constant for an annotation on a resource contained in the secrets of a ManagedResource.
+	// Its value is a comma-separated list of API group versions (e.g. `policy/v1beta1`), the resource is skipped if
+	// any of them is not served by the target cluster.
+	SkipIfAPIUnavailable = "resources.gardener.cloud/skip-if-api-unavailable"
+	// TargetVersionConstraint is a constant for an annotation on a resource contained in the secrets of a
+	// ManagedResource. Its value is a semantic version constraint (e.g. `>=1.21`), the resource is skipped if the
+	// Kubernetes version of the target cluster does not satisfy it.
+	TargetVersionConstraint = "resources.gardener.cloud/target-version-constraint"
+	// ExportStatus is a constant for an annotation on a resource contained in the secrets of a ManagedResource. Its
+	// value is a JSONPath expression (e.g. `{.status.succeeded}`) evaluated on the status of the resource in the target
+	// cluster, the result is exported into the status of the ManagedResource.
+	ExportStatus = "resources.gardener.cloud/export-status"
 	// KeepObject is a constant for an annotation on a resource managed by a ManagedResource. If set to
 	// true then the controller will not delete the object in case it is removed from the ManagedResource or the
 	// ManagedResource itself is deleted.
 	KeepObject = "resources.gardener.cloud/keep-object"
+	// ConfirmationDeletion is a constant for an annotation on a resource managed by a ManagedResource. Objects holding
+	// data or affecting the whole cluster (CustomResourceDefinitions, PersistentVolumeClaims and Namespaces) are only
+	// deleted by the controller if this annotation is set to true.
+	ConfirmationDeletion = "confirmation.gardener.cloud/deletion"
+	// ConfirmationClusterScoped is a constant for an annotation on a cluster-scoped resource contained in the secrets of
+	// a ManagedResource. If the policy for cluster-scoped objects of its resource class requires a confirmation, the
+	// resource is only applied if this annotation is set to true.
+	ConfirmationClusterScoped = "confirmation.gardener.cloud/cluster-scoped"
+	// OriginLabel is a constant for a label on a resource managed by a ManagedResource. Its value is the resource class
+	// of the gardener-resource-manager instance managing the resource.
+	OriginLabel = "resources.gardener.cloud/origin"
+	// OriginAnnotation is a constant for an annotation on a resource managed by a ManagedResource. Its value identifies
+	// the ManagedResource managing the resource in the form `[<cluster-id>:]<namespace>/<name>`, where the cluster ID
+	// identifies the source cluster of the ManagedResource if configured.
+	OriginAnnotation = "resources.gardener.cloud/origin"
+	// ExpiresAt is a constant for an annotation on a resource which was managed by a ManagedResource deleted with
+	// `.spec.keepObjects=true`. Its value is the time (RFC 3339) after which the resource is deleted by the garbage
+	// collector, unless it has been adopted by another ManagedResource in the meantime.
+	ExpiresAt = "resources.gardener.cloud/expires-at"
+	// PruneAfter is a constant for an annotation on a resource which has been removed from its ManagedResource and is
+	// pending prune. Its value is the time (RFC 3339) after which the resource is deleted, unless it is added to the
+	// ManagedResource again in the meantime.
+	PruneAfter = "resources.gardener.cloud/prune-after"
+	// OperationForceApply is a value for the `gardener.cloud/operation` annotation on a ManagedResource. It triggers an
+	// immediate reconciliation which updates all objects even if their desired state did not change. The annotation is
+	// removed once the reconciliation succeeded.
+	OperationForceApply = "force-apply"
+	// ReconcileTimeout is a constant for an annotation on a ManagedResource overriding the maximum duration of its
+	// reconciliations (e.g. 10m) configured for the gardener-resource-manager, `0` disables the timeout.
+	ReconcileTimeout = "resources.gardener.cloud/reconcile-timeout"
+	// ProtectionOverride is a constant for an annotation on a resource managed by a ManagedResource. If set to true
+	// then the protection webhook allows modifications and deletions of the resource by other users than the
+	// gardener-resource-manager.
+	ProtectionOverride = "resources.gardener.cloud/protection-override"
+
+	// ResourceManagerPurpose is a constant for a label on a secret in the source cluster describing the purpose for
+	// which the gardener-resource-manager maintains it.
+	ResourceManagerPurpose = "resources.gardener.cloud/purpose"
+	// LabelPurposeTokenRequest is a constant for the value of the ResourceManagerPurpose label of secrets into which
+	// the token requestor writes the tokens of a ServiceAccount in the target cluster.
+	LabelPurposeTokenRequest = "token-requestor"
+	// ServiceAccountName is a constant for an annotation on a token requestor secret specifying the name of the
+	// ServiceAccount in the target cluster whose tokens are requested.
+	ServiceAccountName = "serviceaccount.resources.gardener.cloud/name"
+	// ServiceAccountNamespace is a constant for an annotation on a token requestor secret specifying the namespace of
+	// the ServiceAccount in the target cluster whose tokens are requested.
+	ServiceAccountNamespace = "serviceaccount.resources.gardener.cloud/namespace"
+	// ServiceAccountTokenExpirationDuration is a constant for an annotation on a token requestor secret specifying the
+	// requested validity of the tokens (e.g. 12h).
+	ServiceAccountTokenExpirationDuration = "serviceaccount.resources.gardener.cloud/token-expiration-duration"
+	// ServiceAccountTokenRenewTimestamp is a constant for an annotation on a token requestor secret in which the token
+	// requestor records the time (RFC 3339) after which the token is renewed.
+	ServiceAccountTokenRenewTimestamp = "serviceaccount.resources.gardener.cloud/token-renew-timestamp"
+
+	// NetworkingFromPodSelector is a constant for an annotation on a Service in the target cluster containing a label
+	// selector (e.g. `app=prometheus`) for the pods which are allowed to send traffic to the Service's pods. If set,
+	// the network policy controller maintains an ingress NetworkPolicy for the Service.
+	NetworkingFromPodSelector = "networking.resources.gardener.cloud/from-pod-selector"
+	// NetworkingNamespaceSelectors is a constant for an annotation on a Service in the target cluster containing a JSON
+	// list of label selectors for the namespaces of the pods selected by NetworkingFromPodSelector. Only pods in the
+	// Service's namespace are allowed if it is not set.
+	NetworkingNamespaceSelectors = "networking.resources.gardener.cloud/namespace-selectors"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -72,6 +173,14 @@ type ManagedResourceSpec struct {
 	// ForceOverwriteAnnotations specifies that all existing annotations should be overwritten. Defaults to false.
 	// +optional
 	ForceOverwriteAnnotations *bool `json:"forceOverwriteAnnotations,omitempty"`
+	// ForceOverwriteOwnerReferences specifies that all existing owner references should be overwritten instead of
+	// being merged with the desired ones. Defaults to false.
+	// +optional
+	ForceOverwriteOwnerReferences *bool `json:"forceOverwriteOwnerReferences,omitempty"`
+	// ForceOverwriteFinalizers specifies that all existing finalizers should be overwritten instead of being merged
+	// with the desired ones. Defaults to false.
+	// +optional
+	ForceOverwriteFinalizers *bool `json:"forceOverwriteFinalizers,omitempty"`
 	// KeepObjects specifies whether the objects should be kept although the managed resource has already been deleted.
 	// Defaults to false.
 	// +optional
@@ -83,6 +192,62 @@ type ManagedResourceSpec struct {
 	// resource, should also be deleted when the corresponding StatefulSet is deleted (defaults to false).
 	// +optional
 	DeletePersistentVolumeClaims *bool `json:"deletePersistentVolumeClaims,omitempty"`
+	// ResyncPeriod specifies how often the objects are reconciled to enforce their desired state (defaults to the
+	// sync period of the gardener-resource-manager).
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+	// Prune configures the deletion of objects that are no longer part of the referenced secrets.
+	// +optional
+	Prune *Prune `json:"prune,omitempty"`
+	// Owner is an object in the target cluster which is set as owner of all objects, so that they are garbage
+	// collected by Kubernetes once it is deleted.
+	// +optional
+	Owner *Owner `json:"owner,omitempty"`
+	// DeletePolicy specifies how the objects are deleted when the managed resource is deleted (defaults to
+	// BestEffort).
+	// +optional
+	DeletePolicy DeletePolicy `json:"deletePolicy,omitempty"`
+}
+
+// DeletePolicy specifies how the objects of a managed resource are deleted.
+type DeletePolicy string
+
+const (
+	// DeletePolicyBestEffort deletes all objects, even if the deletion of some of them fails, and reports all errors.
+	DeletePolicyBestEffort DeletePolicy = "BestEffort"
+	// DeletePolicyFailFast deletes the objects one after another and stops at the first object whose deletion fails.
+	DeletePolicyFailFast DeletePolicy = "FailFast"
+)
+
+// Owner is an object in the target cluster owning the objects of a managed resource. A namespaced owner only owns the
+// objects in its namespace.
+type Owner struct {
+	// APIVersion is the API version of the owner.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the owner.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the owner, it must be empty for cluster-scoped owners.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the owner.
+	Name string `json:"name"`
+}
+
+// Prune configures the deletion of objects that are no longer part of the referenced secrets.
+type Prune struct {
+	// SkipKinds is a list of group/kinds whose objects are not deleted but released when they are no longer part of
+	// the referenced secrets, e.g. PersistentVolumeClaims or Namespaces holding data. They are still deleted when the
+	// managed resource is deleted (unless keepObjects is set).
+	// +optional
+	SkipKinds []metav1.GroupKind `json:"skipKinds,omitempty"`
+	// GracePeriod is the duration for which objects that are no longer part of the referenced secrets are kept before
+	// they are deleted (defaults to the prune grace period of the gardener-resource-manager).
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	// AfterHealthy specifies that objects that are no longer part of the referenced secrets are kept until the
+	// remaining objects are healthy, e.g. for replacing a component by a new one within the same ManagedResource.
+	// +optional
+	AfterHealthy *bool `json:"afterHealthy,omitempty"`
 }
 
 // ManagedResourceStatus is the status of a managed resource.
@@ -93,6 +258,40 @@ type ManagedResourceStatus struct {
 	// Resources is a list of objects that have been created.
 	// +optional
 	Resources []ObjectReference `json:"resources,omitempty"`
+	// ExportedStatus is a list of values exported from the status of the resources annotated with
+	// `resources.gardener.cloud/export-status`.
+	// +optional
+	ExportedStatus []ExportedStatus `json:"exportedStatus,omitempty"`
+	// LastAppliedRevision identifies the data of the secrets whose resources have last been applied successfully.
+	// +optional
+	LastAppliedRevision *AppliedRevision `json:"lastAppliedRevision,omitempty"`
+}
+
+// AppliedRevision identifies the data of the secrets of a ManagedResource.
+type AppliedRevision struct {
+	// Checksum is the checksum of the data of the secrets.
+	Checksum string `json:"checksum"`
+	// Secrets are the resource versions of the secrets.
+	// +optional
+	Secrets []SecretRevision `json:"secrets,omitempty"`
+}
+
+// SecretRevision is the resource version of a secret referenced by a ManagedResource.
+type SecretRevision struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+	// ResourceVersion is the resource version of the secret.
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// ExportedStatus is a value exported from the status of a resource in the target cluster.
+type ExportedStatus struct {
+	corev1.ObjectReference `json:",inline"`
+	// Value is the result of the JSONPath expression of the resource's `resources.gardener.cloud/export-status`
+	// annotation.
+	Value runtime.RawExtension `json:"value"`
+	// LastUpdateTime is the time the value last changed.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
 }
 
 type ObjectReference struct {
@@ -101,6 +300,9 @@ type ObjectReference struct {
 	Labels map[string]string `json:"labels,omitempty"`
 	// Annotations is a map of annotations that were used during last update of the resource.
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// PruneAfter is the time after which the resource is deleted as it is no longer part of the referenced secrets.
+	// +optional
+	PruneAfter *metav1.Time `json:"pruneAfter,omitempty"`
 }
 
 // ConditionType is the type of a condition.
@@ -137,7 +339,8 @@ const (
 	// because applying the resources failed.
 	ConditionApplyFailed = "ApplyFailed"
 	// ConditionDecodingFailed indicates that the `ResourcesApplied` condition is `False`,
-	// because decoding the resources of the ManagedResource failed.
+	// because decoding the resources of the ManagedResource failed. Hence, the resources which have last been applied
+	// successfully are still applied, or none of the resources is applied or deleted if they are unknown.
 	ConditionDecodingFailed = "DecodingFailed"
 	// ConditionApplyProgressing indicates that the `ResourcesApplied` condition is `Progressing`,
 	// because the resources are currently being reconciled.
@@ -151,6 +354,44 @@ const (
 	// ConditionHealthChecksPending indicates that the `ResourcesHealthy` condition is `Unknown`,
 	// because the health checks have not been completely executed yet for the current set of resources.
 	ConditionHealthChecksPending = "HealthChecksPending"
+	// ConditionManagedResourceIgnored indicates that the `ResourcesApplied` and `ResourcesHealthy` conditions are
+	// `Unknown`, because the ManagedResource is annotated to be ignored.
+	ConditionManagedResourceIgnored = "ManagedResourceIgnored"
+	// ConditionPolicyViolated indicates that the `ResourcesApplied` condition is `False`, because some resources
+	// violate the apply policy of the gardener-resource-manager, hence none of the resources is applied or deleted.
+	ConditionPolicyViolated = "PolicyViolated"
+	// ConditionDuplicateObjects indicates that the `ResourcesApplied` condition is `False`, because some objects are
+	// contained multiple times in the resources, hence none of the resources is applied or deleted.
+	ConditionDuplicateObjects = "DuplicateObjects"
+	// ConditionHookPending indicates that the `ResourcesApplied` condition is `Progressing`, because some hooks have
+	// not yet completed.
+	ConditionHookPending = "HookPending"
+	// ConditionHookFailed indicates that the `ResourcesApplied` condition is `False`, because some hooks failed.
+	ConditionHookFailed = "HookFailed"
+	// ConditionHandoverPending indicates that the `ResourcesApplied` condition is `Progressing`, because the class of
+	// the ManagedResource changed and the instance responsible for the new class has not yet taken it over.
+	ConditionHandoverPending = "HandoverPending"
+	// ConditionReconcileTimedOut indicates that the `ResourcesApplied` condition is `False`, because the
+	// reconciliation of the ManagedResource did not finish within its timeout.
+	ConditionReconcileTimedOut = "ReconcileTimedOut"
+)
+
+// ErrorClass classifies the errors of a condition by how they are retried.
+type ErrorClass string
+
+// These are valid error classes.
+const (
+	// ErrorClassTransientTarget means the target cluster could not serve a request but is expected to recover, e.g.
+	// because of a timeout or an unavailable API server. The reconciliation is retried with a backoff.
+	ErrorClassTransientTarget ErrorClass = "TransientTarget"
+	// ErrorClassConflict means an object has been changed concurrently. The reconciliation is retried with a backoff.
+	ErrorClassConflict ErrorClass = "Conflict"
+	// ErrorClassPermanentManifest means an object has been rejected by the target cluster, e.g. because it is invalid.
+	// The reconciliation is only retried once the ManagedResource or its resources change, or with the next sync.
+	ErrorClassPermanentManifest ErrorClass = "PermanentManifest"
+	// ErrorClassRBACDenied means the gardener-resource-manager is not allowed to apply an object. The reconciliation is
+	// only retried once the ManagedResource or its resources change, or with the next sync.
+	ErrorClassRBACDenied ErrorClass = "RBACDenied"
 )
 
 // ManagedResourceCondition describes the state of a deployment at a certain period.
@@ -167,4 +408,7 @@ type ManagedResourceCondition struct {
 	Reason string `json:"reason"`
 	// A human readable message indicating details about the transition.
 	Message string `json:"message"`
+	// ErrorClass classifies the errors the condition reports, if any.
+	// +optional
+	ErrorClass ErrorClass `json:"errorClass,omitempty"`
 }
@@ -0,0 +1,152 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// maxClassLength is the maximum length of a resource class. The class is appended to the finalizer
+// `resources.gardener.cloud/gardener-resource-manager-<class>`, whose name part must not exceed 63 characters.
+const maxClassLength = validation.DNS1123LabelMaxLength - len("gardener-resource-manager-")
+
+// minResyncPeriod is the minimum resync period of a ManagedResource, shorter periods would put too much load on the
+// source and target clusters.
+const minResyncPeriod = 30 * time.Second
+
+// ValidateManagedResource validates a ManagedResource. The object metadata is not validated as this is already done
+// by the API server.
+func ValidateManagedResource(mr *resourcesv1alpha1.ManagedResource) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateAnnotations(mr.Annotations, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, ValidateManagedResourceSpec(&mr.Spec, field.NewPath("spec"))...)
+
+	return allErrs
+}
+
+// ValidateManagedResourceUpdate validates an update of a ManagedResource. The class may be changed, which hands the
+// ManagedResource over to the instance responsible for the new class.
+func ValidateManagedResourceUpdate(newMR, _ *resourcesv1alpha1.ManagedResource) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateAnnotations(newMR.Annotations, field.NewPath("metadata", "annotations"))...)
+	allErrs = append(allErrs, ValidateManagedResourceSpec(&newMR.Spec, field.NewPath("spec"))...)
+
+	return allErrs
+}
+
+// ValidateManagedResourceSpec validates the specification of a ManagedResource.
+func ValidateManagedResourceSpec(spec *resourcesv1alpha1.ManagedResourceSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Class != nil {
+		allErrs = append(allErrs, ValidateClass(*spec.Class, fldPath.Child("class"))...)
+	}
+
+	if len(spec.SecretRefs) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("secretRefs"), "at least one secret reference is required"))
+	}
+
+	secretNames := sets.NewString()
+	for i, ref := range spec.SecretRefs {
+		idxPath := fldPath.Child("secretRefs").Index(i).Child("name")
+
+		if len(ref.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath, "secret name is required"))
+			continue
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(ref.Name) {
+			allErrs = append(allErrs, field.Invalid(idxPath, ref.Name, msg))
+		}
+		if secretNames.Has(ref.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, ref.Name))
+		}
+		secretNames.Insert(ref.Name)
+	}
+
+	if spec.ResyncPeriod != nil && spec.ResyncPeriod.Duration < minResyncPeriod {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("resyncPeriod"), spec.ResyncPeriod.Duration.String(), "must be at least "+minResyncPeriod.String()))
+	}
+
+	if spec.Prune != nil {
+		for i, groupKind := range spec.Prune.SkipKinds {
+			if len(groupKind.Kind) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("prune", "skipKinds").Index(i).Child("kind"), "kind is required"))
+			}
+		}
+		if spec.Prune.GracePeriod != nil && spec.Prune.GracePeriod.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("prune", "gracePeriod"), spec.Prune.GracePeriod.Duration.String(), "must not be negative"))
+		}
+	}
+
+	switch spec.DeletePolicy {
+	case "", resourcesv1alpha1.DeletePolicyBestEffort, resourcesv1alpha1.DeletePolicyFailFast:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("deletePolicy"), spec.DeletePolicy, []string{string(resourcesv1alpha1.DeletePolicyBestEffort), string(resourcesv1alpha1.DeletePolicyFailFast)}))
+	}
+
+	if spec.Owner != nil {
+		ownerPath := fldPath.Child("owner")
+		if len(spec.Owner.APIVersion) == 0 {
+			allErrs = append(allErrs, field.Required(ownerPath.Child("apiVersion"), "apiVersion is required"))
+		}
+		if len(spec.Owner.Kind) == 0 {
+			allErrs = append(allErrs, field.Required(ownerPath.Child("kind"), "kind is required"))
+		}
+		if len(spec.Owner.Name) == 0 {
+			allErrs = append(allErrs, field.Required(ownerPath.Child("name"), "name is required"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateClass validates a resource class. An empty class denotes the default class and is valid.
+func ValidateClass(class string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(class) == 0 {
+		return allErrs
+	}
+	for _, msg := range validation.IsDNS1123Label(class) {
+		allErrs = append(allErrs, field.Invalid(fldPath, class, msg))
+	}
+	if len(class) > maxClassLength {
+		allErrs = append(allErrs, field.TooLong(fldPath, class, maxClassLength))
+	}
+
+	return allErrs
+}
+
+func validateAnnotations(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if value, ok := annotations[resourcesv1alpha1.ReconcileTimeout]; ok {
+		if timeout, err := time.ParseDuration(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(resourcesv1alpha1.ReconcileTimeout), value, err.Error()))
+		} else if timeout < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(resourcesv1alpha1.ReconcileTimeout), value, "must not be negative"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation_test
+
+import (
+	"strings"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/validation"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ = Describe("Validation", func() {
+	var mr *resourcesv1alpha1.ManagedResource
+
+	BeforeEach(func() {
+		mr = &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				SecretRefs: []corev1.LocalObjectReference{{Name: "secret1"}, {Name: "secret2"}},
+			},
+		}
+	})
+
+	Describe("#ValidateManagedResource", func() {
+		It("should allow a valid ManagedResource", func() {
+			Expect(ValidateManagedResource(mr)).To(BeEmpty())
+		})
+
+		It("should allow a valid class", func() {
+			class := "seed"
+			mr.Spec.Class = &class
+			Expect(ValidateManagedResource(mr)).To(BeEmpty())
+		})
+
+		It("should forbid empty secret references", func() {
+			mr.Spec.SecretRefs = nil
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{"spec.secretRefs: " + string(field.ErrorTypeRequired)}))
+		})
+
+		It("should forbid invalid and duplicate secret names", func() {
+			mr.Spec.SecretRefs = []corev1.LocalObjectReference{{Name: "secret1"}, {Name: ""}, {Name: "Secret"}, {Name: "secret1"}}
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{
+				"spec.secretRefs[1].name: " + string(field.ErrorTypeRequired),
+				"spec.secretRefs[2].name: " + string(field.ErrorTypeInvalid),
+				"spec.secretRefs[3].name: " + string(field.ErrorTypeDuplicate),
+			}))
+		})
+
+		It("should forbid invalid classes", func() {
+			class := "Foo_Bar"
+			mr.Spec.Class = &class
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{"spec.class: " + string(field.ErrorTypeInvalid)}))
+		})
+
+		It("should forbid classes that exceed the finalizer length", func() {
+			class := strings.Repeat("a", 38)
+			mr.Spec.Class = &class
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{"spec.class: " + string(field.ErrorTypeTooLong)}))
+		})
+
+		It("should allow a valid resync period", func() {
+			mr.Spec.ResyncPeriod = &metav1.Duration{Duration: time.Hour}
+			Expect(ValidateManagedResource(mr)).To(BeEmpty())
+		})
+
+		It("should forbid too short resync periods", func() {
+			mr.Spec.ResyncPeriod = &metav1.Duration{Duration: 10 * time.Second}
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{"spec.resyncPeriod: " + string(field.ErrorTypeInvalid)}))
+		})
+
+		It("should allow skipping kinds from pruning", func() {
+			mr.Spec.Prune = &resourcesv1alpha1.Prune{SkipKinds: []metav1.GroupKind{{Kind: "Namespace"}, {Group: "apps", Kind: "StatefulSet"}}}
+			Expect(ValidateManagedResource(mr)).To(BeEmpty())
+		})
+
+		It("should forbid skipping kinds without kind", func() {
+			mr.Spec.Prune = &resourcesv1alpha1.Prune{SkipKinds: []metav1.GroupKind{{Group: "apps"}}}
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{"spec.prune.skipKinds[0].kind: " + string(field.ErrorTypeRequired)}))
+		})
+
+		It("should forbid negative prune grace periods", func() {
+			mr.Spec.Prune = &resourcesv1alpha1.Prune{GracePeriod: &metav1.Duration{Duration: -time.Minute}}
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{"spec.prune.gracePeriod: " + string(field.ErrorTypeInvalid)}))
+		})
+
+		It("should allow the supported delete policies", func() {
+			mr.Spec.DeletePolicy = resourcesv1alpha1.DeletePolicyFailFast
+			Expect(ValidateManagedResource(mr)).To(BeEmpty())
+		})
+
+		It("should forbid unsupported delete policies", func() {
+			mr.Spec.DeletePolicy = "Foo"
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{"spec.deletePolicy: " + string(field.ErrorTypeNotSupported)}))
+		})
+
+		It("should allow owners", func() {
+			mr.Spec.Owner = &resourcesv1alpha1.Owner{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "anchor"}
+			Expect(ValidateManagedResource(mr)).To(BeEmpty())
+		})
+
+		It("should forbid incomplete owners", func() {
+			mr.Spec.Owner = &resourcesv1alpha1.Owner{Namespace: "default"}
+			Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{
+				"spec.owner.apiVersion: " + string(field.ErrorTypeRequired),
+				"spec.owner.kind: " + string(field.ErrorTypeRequired),
+				"spec.owner.name: " + string(field.ErrorTypeRequired),
+			}))
+		})
+
+		It("should allow valid reconcile timeouts", func() {
+			mr.Annotations = map[string]string{resourcesv1alpha1.ReconcileTimeout: "10m"}
+			Expect(ValidateManagedResource(mr)).To(BeEmpty())
+		})
+
+		It("should forbid invalid and negative reconcile timeouts", func() {
+			for _, value := range []string{"foo", "-1m"} {
+				mr.Annotations = map[string]string{resourcesv1alpha1.ReconcileTimeout: value}
+				Expect(errorTypes(ValidateManagedResource(mr))).To(Equal([]string{"metadata.annotations[" + resourcesv1alpha1.ReconcileTimeout + "]: " + string(field.ErrorTypeInvalid)}))
+			}
+		})
+	})
+
+	Describe("#ValidateManagedResourceUpdate", func() {
+		It("should allow updates not changing the class", func() {
+			newMR := mr.DeepCopy()
+			newMR.Spec.SecretRefs = []corev1.LocalObjectReference{{Name: "secret3"}}
+			Expect(ValidateManagedResourceUpdate(newMR, mr)).To(BeEmpty())
+		})
+
+		It("should allow changing the class", func() {
+			class := "seed"
+			newMR := mr.DeepCopy()
+			newMR.Spec.Class = &class
+			Expect(ValidateManagedResourceUpdate(newMR, mr)).To(BeEmpty())
+		})
+
+		It("should forbid changing the class to an invalid class", func() {
+			class := strings.Repeat("a", 38)
+			newMR := mr.DeepCopy()
+			newMR.Spec.Class = &class
+			Expect(errorTypes(ValidateManagedResourceUpdate(newMR, mr))).To(Equal([]string{"spec.class: " + string(field.ErrorTypeTooLong)}))
+		})
+
+		It("should treat an empty class like an unset class", func() {
+			class := ""
+			newMR := mr.DeepCopy()
+			newMR.Spec.Class = &class
+			Expect(ValidateManagedResourceUpdate(newMR, mr)).To(BeEmpty())
+		})
+	})
+
+	Describe("#ValidateClass", func() {
+		It("should allow valid and empty classes", func() {
+			Expect(ValidateClass("seed", field.NewPath("class"))).To(BeEmpty())
+			Expect(ValidateClass("", field.NewPath("class"))).To(BeEmpty())
+		})
+
+		It("should forbid invalid classes with the given path", func() {
+			Expect(errorTypes(ValidateClass("Foo_Bar", field.NewPath("--resource-class")))).To(Equal([]string{"--resource-class: " + string(field.ErrorTypeInvalid)}))
+		})
+	})
+})
+
+func errorTypes(allErrs field.ErrorList) []string {
+	out := make([]string, 0, len(allErrs))
+	for _, err := range allErrs {
+		out = append(out, err.Field+": "+string(err.Type))
+	}
+	return out
+}
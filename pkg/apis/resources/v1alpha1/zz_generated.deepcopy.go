@@ -23,6 +23,46 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedRevision) DeepCopyInto(out *AppliedRevision) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]SecretRevision, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedRevision.
+func (in *AppliedRevision) DeepCopy() *AppliedRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportedStatus) DeepCopyInto(out *ExportedStatus) {
+	*out = *in
+	out.ObjectReference = in.ObjectReference
+	in.Value.DeepCopyInto(&out.Value)
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportedStatus.
+func (in *ExportedStatus) DeepCopy() *ExportedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedResource) DeepCopyInto(out *ManagedResource) {
 	*out = *in
@@ -132,6 +172,16 @@ func (in *ManagedResourceSpec) DeepCopyInto(out *ManagedResourceSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ForceOverwriteOwnerReferences != nil {
+		in, out := &in.ForceOverwriteOwnerReferences, &out.ForceOverwriteOwnerReferences
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForceOverwriteFinalizers != nil {
+		in, out := &in.ForceOverwriteFinalizers, &out.ForceOverwriteFinalizers
+		*out = new(bool)
+		**out = **in
+	}
 	if in.KeepObjects != nil {
 		in, out := &in.KeepObjects, &out.KeepObjects
 		*out = new(bool)
@@ -153,6 +203,21 @@ func (in *ManagedResourceSpec) DeepCopyInto(out *ManagedResourceSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Prune != nil {
+		in, out := &in.Prune, &out.Prune
+		*out = new(Prune)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(Owner)
+		**out = **in
+	}
 	return
 }
 
@@ -183,6 +248,18 @@ func (in *ManagedResourceStatus) DeepCopyInto(out *ManagedResourceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExportedStatus != nil {
+		in, out := &in.ExportedStatus, &out.ExportedStatus
+		*out = make([]ExportedStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastAppliedRevision != nil {
+		in, out := &in.LastAppliedRevision, &out.LastAppliedRevision
+		*out = new(AppliedRevision)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -214,6 +291,10 @@ func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
 			(*out)[key] = val
 		}
 	}
+	if in.PruneAfter != nil {
+		in, out := &in.PruneAfter, &out.PruneAfter
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -226,3 +307,66 @@ func (in *ObjectReference) DeepCopy() *ObjectReference {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Owner) DeepCopyInto(out *Owner) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Owner.
+func (in *Owner) DeepCopy() *Owner {
+	if in == nil {
+		return nil
+	}
+	out := new(Owner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Prune) DeepCopyInto(out *Prune) {
+	*out = *in
+	if in.SkipKinds != nil {
+		in, out := &in.SkipKinds, &out.SkipKinds
+		*out = make([]metav1.GroupKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AfterHealthy != nil {
+		in, out := &in.AfterHealthy, &out.AfterHealthy
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Prune.
+func (in *Prune) DeepCopy() *Prune {
+	if in == nil {
+		return nil
+	}
+	out := new(Prune)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretRevision) DeepCopyInto(out *SecretRevision) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRevision.
+func (in *SecretRevision) DeepCopy() *SecretRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretRevision)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &ManagedResource{}
+
+// ConvertTo converts this ManagedResource to the hub version (v1alpha1).
+func (in *ManagedResource) ConvertTo(hub conversion.Hub) error {
+	out, ok := hub.(*resourcesv1alpha1.ManagedResource)
+	if !ok {
+		return fmt.Errorf("unsupported conversion target %T", hub)
+	}
+
+	out.ObjectMeta = in.ObjectMeta
+
+	out.Spec = resourcesv1alpha1.ManagedResourceSpec{
+		Class:                         in.Spec.Class,
+		InjectLabels:                  in.Spec.InjectLabels,
+		ForceOverwriteLabels:          boolPtr(in.Spec.ForceOverwriteLabels),
+		ForceOverwriteAnnotations:     boolPtr(in.Spec.ForceOverwriteAnnotations),
+		ForceOverwriteOwnerReferences: boolPtr(in.Spec.ForceOverwriteOwnerReferences),
+		ForceOverwriteFinalizers:      boolPtr(in.Spec.ForceOverwriteFinalizers),
+		KeepObjects:                   boolPtr(in.Spec.KeepObjects),
+		Equivalences:                  in.Spec.Equivalences,
+		DeletePersistentVolumeClaims:  boolPtr(in.Spec.DeletePersistentVolumeClaims),
+		ResyncPeriod:                  in.Spec.ResyncPeriod,
+		DeletePolicy:                  resourcesv1alpha1.DeletePolicy(in.Spec.DeletePolicy),
+	}
+	if in.Spec.Prune != nil {
+		out.Spec.Prune = &resourcesv1alpha1.Prune{SkipKinds: in.Spec.Prune.SkipKinds, GracePeriod: in.Spec.Prune.GracePeriod, AfterHealthy: in.Spec.Prune.AfterHealthy}
+	}
+	if in.Spec.Owner != nil {
+		owner := resourcesv1alpha1.Owner(*in.Spec.Owner)
+		out.Spec.Owner = &owner
+	}
+	if in.Spec.SecretRefs != nil {
+		out.Spec.SecretRefs = make([]corev1.LocalObjectReference, 0, len(in.Spec.SecretRefs))
+		for _, ref := range in.Spec.SecretRefs {
+			out.Spec.SecretRefs = append(out.Spec.SecretRefs, corev1.LocalObjectReference{Name: ref.Name})
+		}
+	}
+
+	out.Status = resourcesv1alpha1.ManagedResourceStatus{ObservedGeneration: in.Status.ObservedGeneration}
+	for _, condition := range in.Status.Conditions {
+		out.Status.Conditions = append(out.Status.Conditions, resourcesv1alpha1.ManagedResourceCondition{
+			Type:               resourcesv1alpha1.ConditionType(condition.Type),
+			Status:             resourcesv1alpha1.ConditionStatus(condition.Status),
+			LastUpdateTime:     condition.LastUpdateTime,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			ErrorClass:         resourcesv1alpha1.ErrorClass(condition.ErrorClass),
+		})
+	}
+	for _, ref := range in.Status.Resources {
+		out.Status.Resources = append(out.Status.Resources, resourcesv1alpha1.ObjectReference{
+			ObjectReference: corev1.ObjectReference{
+				APIVersion: ref.APIVersion,
+				Kind:       ref.Kind,
+				Namespace:  ref.Namespace,
+				Name:       ref.Name,
+			},
+			Labels:      ref.Labels,
+			Annotations: ref.Annotations,
+			PruneAfter:  ref.PruneAfter,
+		})
+	}
+	for _, exported := range in.Status.ExportedStatus {
+		out.Status.ExportedStatus = append(out.Status.ExportedStatus, resourcesv1alpha1.ExportedStatus{
+			ObjectReference: corev1.ObjectReference{
+				APIVersion: exported.APIVersion,
+				Kind:       exported.Kind,
+				Namespace:  exported.Namespace,
+				Name:       exported.Name,
+			},
+			Value:          exported.Value,
+			LastUpdateTime: exported.LastUpdateTime,
+		})
+	}
+	if revision := in.Status.LastAppliedRevision; revision != nil {
+		out.Status.LastAppliedRevision = &resourcesv1alpha1.AppliedRevision{Checksum: revision.Checksum}
+		for _, secret := range revision.Secrets {
+			out.Status.LastAppliedRevision.Secrets = append(out.Status.LastAppliedRevision.Secrets, resourcesv1alpha1.SecretRevision{
+				Name:            secret.Name,
+				ResourceVersion: secret.ResourceVersion,
+			})
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the given ManagedResource of the hub version (v1alpha1) to this version. Unset boolean fields
+// are converted to false, which is their default. Only the fields of the object references in the status that are
+// set by the gardener-resource-manager are converted.
+func (in *ManagedResource) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*resourcesv1alpha1.ManagedResource)
+	if !ok {
+		return fmt.Errorf("unsupported conversion source %T", hub)
+	}
+
+	in.ObjectMeta = src.ObjectMeta
+
+	in.Spec = ManagedResourceSpec{
+		Class:                         src.Spec.Class,
+		InjectLabels:                  src.Spec.InjectLabels,
+		ForceOverwriteLabels:          boolValue(src.Spec.ForceOverwriteLabels),
+		ForceOverwriteAnnotations:     boolValue(src.Spec.ForceOverwriteAnnotations),
+		ForceOverwriteOwnerReferences: boolValue(src.Spec.ForceOverwriteOwnerReferences),
+		ForceOverwriteFinalizers:      boolValue(src.Spec.ForceOverwriteFinalizers),
+		KeepObjects:                   boolValue(src.Spec.KeepObjects),
+		Equivalences:                  src.Spec.Equivalences,
+		DeletePersistentVolumeClaims:  boolValue(src.Spec.DeletePersistentVolumeClaims),
+		ResyncPeriod:                  src.Spec.ResyncPeriod,
+		DeletePolicy:                  DeletePolicy(src.Spec.DeletePolicy),
+	}
+	if src.Spec.Prune != nil {
+		in.Spec.Prune = &Prune{SkipKinds: src.Spec.Prune.SkipKinds, GracePeriod: src.Spec.Prune.GracePeriod, AfterHealthy: src.Spec.Prune.AfterHealthy}
+	}
+	if src.Spec.Owner != nil {
+		owner := Owner(*src.Spec.Owner)
+		in.Spec.Owner = &owner
+	}
+	if src.Spec.SecretRefs != nil {
+		in.Spec.SecretRefs = make([]SecretReference, 0, len(src.Spec.SecretRefs))
+		for _, ref := range src.Spec.SecretRefs {
+			in.Spec.SecretRefs = append(in.Spec.SecretRefs, SecretReference{Name: ref.Name})
+		}
+	}
+
+	in.Status = ManagedResourceStatus{ObservedGeneration: src.Status.ObservedGeneration}
+	for _, condition := range src.Status.Conditions {
+		in.Status.Conditions = append(in.Status.Conditions, ManagedResourceCondition{
+			Type:               ConditionType(condition.Type),
+			Status:             ConditionStatus(condition.Status),
+			LastUpdateTime:     condition.LastUpdateTime,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			ErrorClass:         ErrorClass(condition.ErrorClass),
+		})
+	}
+	for _, ref := range src.Status.Resources {
+		in.Status.Resources = append(in.Status.Resources, ObjectReference{
+			APIVersion:  ref.APIVersion,
+			Kind:        ref.Kind,
+			Namespace:   ref.Namespace,
+			Name:        ref.Name,
+			Labels:      ref.Labels,
+			Annotations: ref.Annotations,
+			PruneAfter:  ref.PruneAfter,
+		})
+	}
+	for _, exported := range src.Status.ExportedStatus {
+		in.Status.ExportedStatus = append(in.Status.ExportedStatus, ExportedStatus{
+			APIVersion:     exported.APIVersion,
+			Kind:           exported.Kind,
+			Namespace:      exported.Namespace,
+			Name:           exported.Name,
+			Value:          exported.Value,
+			LastUpdateTime: exported.LastUpdateTime,
+		})
+	}
+	if revision := src.Status.LastAppliedRevision; revision != nil {
+		in.Status.LastAppliedRevision = &AppliedRevision{Checksum: revision.Checksum}
+		for _, secret := range revision.Secrets {
+			in.Status.LastAppliedRevision.Secrets = append(in.Status.LastAppliedRevision.Secrets, SecretRevision{
+				Name:            secret.Name,
+				ResourceVersion: secret.ResourceVersion,
+			})
+		}
+	}
+
+	return nil
+}
+
+func boolPtr(v bool) *bool {
+	if !v {
+		return nil
+	}
+	return &v
+}
+
+func boolValue(v *bool) bool {
+	return v != nil && *v
+}
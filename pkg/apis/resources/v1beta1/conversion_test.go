@@ -0,0 +1,153 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1_test
+
+import (
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1beta1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Conversion", func() {
+	var (
+		now   = metav1.Now()
+		alpha *resourcesv1alpha1.ManagedResource
+		beta  *ManagedResource
+	)
+
+	BeforeEach(func() {
+		alpha = &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar", Generation: 2},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				Class:                     pointer.StringPtr("seed"),
+				SecretRefs:                []corev1.LocalObjectReference{{Name: "secret1"}, {Name: "secret2"}},
+				InjectLabels:              map[string]string{"foo": "bar"},
+				ForceOverwriteAnnotations: pointer.BoolPtr(true),
+				ForceOverwriteFinalizers:  pointer.BoolPtr(true),
+				KeepObjects:               pointer.BoolPtr(true),
+				Equivalences:              [][]metav1.GroupKind{{{Group: "apps", Kind: "Deployment"}, {Group: "extensions", Kind: "Deployment"}}},
+				ResyncPeriod:              &metav1.Duration{Duration: time.Hour},
+				Prune:                     &resourcesv1alpha1.Prune{SkipKinds: []metav1.GroupKind{{Kind: "PersistentVolumeClaim"}}, GracePeriod: &metav1.Duration{Duration: time.Minute}, AfterHealthy: pointer.BoolPtr(true)},
+				Owner:                     &resourcesv1alpha1.Owner{APIVersion: "v1", Kind: "Namespace", Name: "foo"},
+				DeletePolicy:              resourcesv1alpha1.DeletePolicyFailFast,
+			},
+			Status: resourcesv1alpha1.ManagedResourceStatus{
+				ObservedGeneration: 1,
+				Conditions: []resourcesv1alpha1.ManagedResourceCondition{{
+					Type:               resourcesv1alpha1.ResourcesApplied,
+					Status:             resourcesv1alpha1.ConditionFalse,
+					LastUpdateTime:     now,
+					LastTransitionTime: now,
+					Reason:             resourcesv1alpha1.ConditionApplyFailed,
+					Message:            "Could not apply all new resources.",
+					ErrorClass:         resourcesv1alpha1.ErrorClassPermanentManifest,
+				}},
+				Resources: []resourcesv1alpha1.ObjectReference{{
+					ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "foo"},
+					Labels:          map[string]string{"foo": "bar"},
+					PruneAfter:      &now,
+				}},
+				ExportedStatus: []resourcesv1alpha1.ExportedStatus{{
+					ObjectReference: corev1.ObjectReference{APIVersion: "batch/v1", Kind: "Job", Namespace: "default", Name: "migrate"},
+					Value:           runtime.RawExtension{Raw: []byte(`1`)},
+					LastUpdateTime:  now,
+				}},
+				LastAppliedRevision: &resourcesv1alpha1.AppliedRevision{
+					Checksum: "1234",
+					Secrets:  []resourcesv1alpha1.SecretRevision{{Name: "secret1", ResourceVersion: "1"}, {Name: "secret2", ResourceVersion: "2"}},
+				},
+			},
+		}
+
+		beta = &ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar", Generation: 2},
+			Spec: ManagedResourceSpec{
+				Class:                     pointer.StringPtr("seed"),
+				SecretRefs:                []SecretReference{{Name: "secret1"}, {Name: "secret2"}},
+				InjectLabels:              map[string]string{"foo": "bar"},
+				ForceOverwriteAnnotations: true,
+				ForceOverwriteFinalizers:  true,
+				KeepObjects:               true,
+				Equivalences:              [][]metav1.GroupKind{{{Group: "apps", Kind: "Deployment"}, {Group: "extensions", Kind: "Deployment"}}},
+				ResyncPeriod:              &metav1.Duration{Duration: time.Hour},
+				Prune:                     &Prune{SkipKinds: []metav1.GroupKind{{Kind: "PersistentVolumeClaim"}}, GracePeriod: &metav1.Duration{Duration: time.Minute}, AfterHealthy: pointer.BoolPtr(true)},
+				Owner:                     &Owner{APIVersion: "v1", Kind: "Namespace", Name: "foo"},
+				DeletePolicy:              DeletePolicyFailFast,
+			},
+			Status: ManagedResourceStatus{
+				ObservedGeneration: 1,
+				Conditions: []ManagedResourceCondition{{
+					Type:               ResourcesApplied,
+					Status:             ConditionFalse,
+					LastUpdateTime:     now,
+					LastTransitionTime: now,
+					Reason:             resourcesv1alpha1.ConditionApplyFailed,
+					Message:            "Could not apply all new resources.",
+					ErrorClass:         ErrorClassPermanentManifest,
+				}},
+				Resources: []ObjectReference{{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Namespace:  "default",
+					Name:       "foo",
+					Labels:     map[string]string{"foo": "bar"},
+					PruneAfter: &now,
+				}},
+				ExportedStatus: []ExportedStatus{{
+					APIVersion:     "batch/v1",
+					Kind:           "Job",
+					Namespace:      "default",
+					Name:           "migrate",
+					Value:          runtime.RawExtension{Raw: []byte(`1`)},
+					LastUpdateTime: now,
+				}},
+				LastAppliedRevision: &AppliedRevision{
+					Checksum: "1234",
+					Secrets:  []SecretRevision{{Name: "secret1", ResourceVersion: "1"}, {Name: "secret2", ResourceVersion: "2"}},
+				},
+			},
+		}
+	})
+
+	It("should convert from v1alpha1", func() {
+		converted := &ManagedResource{}
+		Expect(converted.ConvertFrom(alpha)).To(Succeed())
+		Expect(converted).To(Equal(beta))
+	})
+
+	It("should convert to v1alpha1", func() {
+		converted := &resourcesv1alpha1.ManagedResource{}
+		Expect(beta.ConvertTo(converted)).To(Succeed())
+		Expect(converted).To(Equal(alpha))
+	})
+
+	It("should convert unset boolean fields to false", func() {
+		alpha.Spec.ForceOverwriteAnnotations = nil
+		alpha.Spec.KeepObjects = pointer.BoolPtr(false)
+
+		converted := &ManagedResource{}
+		Expect(converted.ConvertFrom(alpha)).To(Succeed())
+		Expect(converted.Spec.ForceOverwriteAnnotations).To(BeFalse())
+		Expect(converted.Spec.KeepObjects).To(BeFalse())
+	})
+})
@@ -0,0 +1,278 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ManagedResource describes a list of managed resources.
+type ManagedResource struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec contains the specification of this managed resource.
+	Spec ManagedResourceSpec `json:"spec,omitempty"`
+	// Status contains the status of this managed resource.
+	Status ManagedResourceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ManagedResourceList is a list of ManagedResource resources.
+type ManagedResourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of ManagedResource.
+	Items []ManagedResource `json:"items"`
+}
+
+// ManagedResourceSpec is the specification of a managed resource.
+type ManagedResourceSpec struct {
+	// Class holds the resource class used to control the responsibility for multiple resource manager instances.
+	// +optional
+	Class *string `json:"class,omitempty"`
+	// SecretRefs is a list of references to secrets in the namespace of the ManagedResource containing the objects.
+	SecretRefs []SecretReference `json:"secretRefs"`
+	// InjectLabels injects the provided labels into every object that is part of the referenced secrets as well as into
+	// their pod templates and volume claim templates.
+	// +optional
+	InjectLabels map[string]string `json:"injectLabels,omitempty"`
+	// ForceOverwriteLabels specifies that all existing labels should be overwritten.
+	// +optional
+	ForceOverwriteLabels bool `json:"forceOverwriteLabels,omitempty"`
+	// ForceOverwriteAnnotations specifies that all existing annotations should be overwritten.
+	// +optional
+	ForceOverwriteAnnotations bool `json:"forceOverwriteAnnotations,omitempty"`
+	// ForceOverwriteOwnerReferences specifies that all existing owner references should be overwritten instead of
+	// being merged with the desired ones.
+	// +optional
+	ForceOverwriteOwnerReferences bool `json:"forceOverwriteOwnerReferences,omitempty"`
+	// ForceOverwriteFinalizers specifies that all existing finalizers should be overwritten instead of being merged
+	// with the desired ones.
+	// +optional
+	ForceOverwriteFinalizers bool `json:"forceOverwriteFinalizers,omitempty"`
+	// KeepObjects specifies whether the objects should be kept although the managed resource has already been deleted.
+	// +optional
+	KeepObjects bool `json:"keepObjects,omitempty"`
+	// Equivalences specifies possible group/kind equivalences for objects.
+	// +optional
+	Equivalences [][]metav1.GroupKind `json:"equivalences,omitempty"`
+	// DeletePersistentVolumeClaims specifies if PersistentVolumeClaims created by StatefulSets, which are managed by this
+	// resource, should also be deleted when the corresponding StatefulSet is deleted.
+	// +optional
+	DeletePersistentVolumeClaims bool `json:"deletePersistentVolumeClaims,omitempty"`
+	// ResyncPeriod specifies how often the objects are reconciled to enforce their desired state (defaults to the
+	// sync period of the gardener-resource-manager).
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+	// Prune configures the deletion of objects that are no longer part of the referenced secrets.
+	// +optional
+	Prune *Prune `json:"prune,omitempty"`
+	// Owner is an object in the target cluster which is set as owner of all objects, so that they are garbage
+	// collected by Kubernetes once it is deleted.
+	// +optional
+	Owner *Owner `json:"owner,omitempty"`
+	// DeletePolicy specifies how the objects are deleted when the managed resource is deleted (defaults to
+	// BestEffort).
+	// +optional
+	DeletePolicy DeletePolicy `json:"deletePolicy,omitempty"`
+}
+
+// DeletePolicy specifies how the objects of a managed resource are deleted.
+type DeletePolicy string
+
+const (
+	// DeletePolicyBestEffort deletes all objects, even if the deletion of some of them fails, and reports all errors.
+	DeletePolicyBestEffort DeletePolicy = "BestEffort"
+	// DeletePolicyFailFast deletes the objects one after another and stops at the first object whose deletion fails.
+	DeletePolicyFailFast DeletePolicy = "FailFast"
+)
+
+// Owner is an object in the target cluster owning the objects of a managed resource. A namespaced owner only owns the
+// objects in its namespace.
+type Owner struct {
+	// APIVersion is the API version of the owner.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the owner.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the owner, it must be empty for cluster-scoped owners.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the owner.
+	Name string `json:"name"`
+}
+
+// Prune configures the deletion of objects that are no longer part of the referenced secrets.
+type Prune struct {
+	// SkipKinds is a list of group/kinds whose objects are not deleted but released when they are no longer part of
+	// the referenced secrets, e.g. PersistentVolumeClaims or Namespaces holding data. They are still deleted when the
+	// managed resource is deleted (unless keepObjects is set).
+	// +optional
+	SkipKinds []metav1.GroupKind `json:"skipKinds,omitempty"`
+	// GracePeriod is the duration for which objects that are no longer part of the referenced secrets are kept before
+	// they are deleted (defaults to the prune grace period of the gardener-resource-manager).
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	// AfterHealthy specifies that objects that are no longer part of the referenced secrets are kept until the
+	// remaining objects are healthy, e.g. for replacing a component by a new one within the same ManagedResource.
+	// +optional
+	AfterHealthy *bool `json:"afterHealthy,omitempty"`
+}
+
+// SecretReference is a reference to a secret in the namespace of the ManagedResource.
+type SecretReference struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+}
+
+// ManagedResourceStatus is the status of a managed resource.
+type ManagedResourceStatus struct {
+	Conditions []ManagedResourceCondition `json:"conditions,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this resource.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Resources is a list of objects that have been created.
+	// +optional
+	Resources []ObjectReference `json:"resources,omitempty"`
+	// ExportedStatus is a list of values exported from the status of the objects annotated with
+	// `resources.gardener.cloud/export-status`.
+	// +optional
+	ExportedStatus []ExportedStatus `json:"exportedStatus,omitempty"`
+	// LastAppliedRevision identifies the data of the secrets whose objects have last been applied successfully.
+	// +optional
+	LastAppliedRevision *AppliedRevision `json:"lastAppliedRevision,omitempty"`
+}
+
+// AppliedRevision identifies the data of the secrets of a ManagedResource.
+type AppliedRevision struct {
+	// Checksum is the checksum of the data of the secrets.
+	Checksum string `json:"checksum"`
+	// Secrets are the resource versions of the secrets.
+	// +optional
+	Secrets []SecretRevision `json:"secrets,omitempty"`
+}
+
+// SecretRevision is the resource version of a secret referenced by a ManagedResource.
+type SecretRevision struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+	// ResourceVersion is the resource version of the secret.
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// ExportedStatus is a value exported from the status of an object in the target cluster.
+type ExportedStatus struct {
+	// APIVersion is the API version of the object.
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind is the kind of the object.
+	Kind string `json:"kind,omitempty"`
+	// Namespace is the namespace of the object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object.
+	Name string `json:"name,omitempty"`
+	// Value is the result of the JSONPath expression of the object's `resources.gardener.cloud/export-status`
+	// annotation.
+	Value runtime.RawExtension `json:"value"`
+	// LastUpdateTime is the time the value last changed.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
+// ObjectReference is a reference to an object managed by a ManagedResource.
+type ObjectReference struct {
+	// APIVersion is the API version of the object.
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind is the kind of the object.
+	Kind string `json:"kind,omitempty"`
+	// Namespace is the namespace of the object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object.
+	Name string `json:"name,omitempty"`
+	// Labels is a map of labels that were used during last update of the resource.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations is a map of annotations that were used during last update of the resource.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// PruneAfter is the time after which the object is deleted as it is no longer part of the referenced secrets.
+	// +optional
+	PruneAfter *metav1.Time `json:"pruneAfter,omitempty"`
+}
+
+// ConditionType is the type of a condition.
+type ConditionType string
+
+const (
+	// ResourcesApplied is a condition type that indicates whether all resources are applied to the target cluster.
+	ResourcesApplied ConditionType = "ResourcesApplied"
+	// ResourcesHealthy is a condition type that indicates whether all resources are present and healthy.
+	ResourcesHealthy ConditionType = "ResourcesHealthy"
+)
+
+// ConditionStatus is the status of a condition.
+type ConditionStatus string
+
+// These are valid condition statuses.
+const (
+	// ConditionTrue means a resource is in the condition.
+	ConditionTrue ConditionStatus = "True"
+	// ConditionFalse means a resource is not in the condition.
+	ConditionFalse ConditionStatus = "False"
+	// ConditionUnknown means that the controller can't decide if a resource is in the condition or not
+	ConditionUnknown ConditionStatus = "Unknown"
+	// ConditionProgressing means that the controller is currently acting on the resource and the condition is therefore progressing.
+	ConditionProgressing ConditionStatus = "Progressing"
+)
+
+// ErrorClass classifies the errors of a condition by how they are retried.
+type ErrorClass string
+
+// These are valid error classes.
+const (
+	// ErrorClassTransientTarget means the target cluster could not serve a request but is expected to recover, e.g.
+	// because of a timeout or an unavailable API server. The reconciliation is retried with a backoff.
+	ErrorClassTransientTarget ErrorClass = "TransientTarget"
+	// ErrorClassConflict means an object has been changed concurrently. The reconciliation is retried with a backoff.
+	ErrorClassConflict ErrorClass = "Conflict"
+	// ErrorClassPermanentManifest means an object has been rejected by the target cluster, e.g. because it is invalid.
+	// The reconciliation is only retried once the ManagedResource or its resources change, or with the next sync.
+	ErrorClassPermanentManifest ErrorClass = "PermanentManifest"
+	// ErrorClassRBACDenied means the gardener-resource-manager is not allowed to apply an object. The reconciliation is
+	// only retried once the ManagedResource or its resources change, or with the next sync.
+	ErrorClassRBACDenied ErrorClass = "RBACDenied"
+)
+
+// ManagedResourceCondition describes the state of a deployment at a certain period.
+type ManagedResourceCondition struct {
+	// Type of the ManagedResource condition.
+	Type ConditionType `json:"type"`
+	// Status of the ManagedResource condition.
+	Status ConditionStatus `json:"status"`
+	// Last time the condition was updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// The reason for the condition's last transition.
+	Reason string `json:"reason"`
+	// A human readable message indicating details about the transition.
+	Message string `json:"message"`
+	// ErrorClass classifies the errors the condition reports, if any.
+	// +optional
+	ErrorClass ErrorClass `json:"errorClass,omitempty"`
+}
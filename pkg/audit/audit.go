@@ -0,0 +1,194 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+// Operation is a mutating operation performed in the target cluster.
+type Operation string
+
+const (
+	// OperationCreate is the creation of an object.
+	OperationCreate Operation = "create"
+	// OperationUpdate is the update of an object.
+	OperationUpdate Operation = "update"
+	// OperationDelete is the deletion of an object.
+	OperationDelete Operation = "delete"
+)
+
+// eventReasons are the reasons of the events recorded on the mutated objects per operation.
+var eventReasons = map[Operation]string{
+	OperationCreate: "Created",
+	OperationUpdate: "Updated",
+	OperationDelete: "Deleted",
+}
+
+// maxChangeDepth is the maximum depth of the field paths reported as changes of an update.
+const maxChangeDepth = 3
+
+// ignoredChanges are field paths which are maintained by the API server and are therefore not reported as changes.
+var ignoredChanges = map[string]bool{
+	"metadata.resourceVersion": true,
+	"metadata.generation":      true,
+	"metadata.managedFields":   true,
+	"status":                   true,
+}
+
+// now is the function used to determine the current time (overwritten in tests).
+var now = time.Now
+
+// Entry is a single record of the audit log.
+type Entry struct {
+	// Time is the time when the operation was performed.
+	Time time.Time `json:"time"`
+	// Operation is the performed operation.
+	Operation Operation `json:"operation"`
+	// APIVersion is the API version of the mutated object.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the mutated object.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the mutated object.
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the mutated object.
+	Name string `json:"name"`
+	// ManagedResource is the ManagedResource (`<namespace>/<name>`) on behalf of which the operation was performed.
+	ManagedResource string `json:"managedResource"`
+	// ReconcileID is the ID of the reconciliation which performed the operation.
+	ReconcileID string `json:"reconcileID,omitempty"`
+	// Reason describes why the operation was performed.
+	Reason string `json:"reason"`
+	// Changes contains the paths of the fields changed by an update.
+	Changes []string `json:"changes,omitempty"`
+}
+
+// Sink receives the entries of the audit log.
+type Sink interface {
+	// Record writes the given entry to the audit log.
+	Record(entry Entry)
+}
+
+// Object is an object in the target cluster.
+type Object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// Recorder records the operations performed on behalf of a single ManagedResource in an audit sink and/or as events
+// on the mutated objects. A nil Recorder is valid and discards all operations.
+type Recorder struct {
+	sink            Sink
+	eventRecorder   record.EventRecorder
+	managedResource string
+	reconcileID     string
+}
+
+// NewRecorder creates a new Recorder writing to the given sink and event recorder (both may be nil) for the
+// ManagedResource with the given key and the reconciliation with the given ID. It returns nil if both the sink and
+// the event recorder are nil.
+func NewRecorder(sink Sink, eventRecorder record.EventRecorder, managedResource types.NamespacedName, reconcileID string) *Recorder {
+	if sink == nil && eventRecorder == nil {
+		return nil
+	}
+	return &Recorder{sink: sink, eventRecorder: eventRecorder, managedResource: managedResource.String(), reconcileID: reconcileID}
+}
+
+// Record records an operation on the given object. The object's kind has to be set.
+func (r *Recorder) Record(operation Operation, obj Object, reason string, changes []string) {
+	if r == nil {
+		return
+	}
+
+	if r.sink != nil {
+		apiVersion, kind := obj.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+		r.sink.Record(Entry{
+			Time:            now().UTC(),
+			Operation:       operation,
+			APIVersion:      apiVersion,
+			Kind:            kind,
+			Namespace:       obj.GetNamespace(),
+			Name:            obj.GetName(),
+			ManagedResource: r.managedResource,
+			ReconcileID:     r.reconcileID,
+			Reason:          reason,
+			Changes:         changes,
+		})
+	}
+
+	if r.eventRecorder != nil {
+		message := fmt.Sprintf("Object %sd on behalf of ManagedResource %s (reconcile %s): %s", operation, r.managedResource, r.reconcileID, reason)
+		if len(changes) > 0 {
+			message += fmt.Sprintf(" (changed fields: %s)", strings.Join(changes, ", "))
+		}
+		r.eventRecorder.Event(obj, corev1.EventTypeNormal, eventReasons[operation], message)
+	}
+}
+
+// Changes returns the sorted paths of all fields that differ between the given unstructured contents of an object.
+// Paths are truncated after a few levels to keep the summary short. Fields which are maintained by the API server
+// (e.g. `metadata.resourceVersion` or `status`) are ignored.
+func Changes(old, new map[string]interface{}) []string {
+	paths := make(map[string]bool)
+	collectChanges(paths, nil, old, new)
+
+	changes := make([]string, 0, len(paths))
+	for path := range paths {
+		changes = append(changes, path)
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+func collectChanges(paths map[string]bool, prefix []string, old, new interface{}) {
+	path := strings.Join(prefix, ".")
+	if ignoredChanges[path] {
+		return
+	}
+
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if !oldIsMap || !newIsMap || len(prefix) == maxChangeDepth {
+		if !equality.Semantic.DeepEqual(old, new) {
+			paths[path] = true
+		}
+		return
+	}
+
+	for key, oldValue := range oldMap {
+		collectChanges(paths, appendPath(prefix, key), oldValue, newMap[key])
+	}
+	for key, newValue := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			collectChanges(paths, appendPath(prefix, key), nil, newValue)
+		}
+	}
+}
+
+func appendPath(prefix []string, key string) []string {
+	path := make([]string, len(prefix), len(prefix)+1)
+	copy(path, prefix)
+	return append(path, key)
+}
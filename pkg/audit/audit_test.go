@@ -0,0 +1,132 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/audit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Audit", func() {
+	Describe("Recorder", func() {
+		var deployment *appsv1.Deployment
+
+		BeforeEach(func() {
+			deployment = &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "dep"},
+			}
+		})
+
+		It("should write entries to the sink", func() {
+			var (
+				buf      = &bytes.Buffer{}
+				recorder = NewRecorder(NewWriterSink(runtimelog.NullLogger{}, buf), nil, types.NamespacedName{Namespace: "foo", Name: "bar"}, "1234")
+			)
+
+			recorder.Record(OperationUpdate, deployment, "some reason", []string{"spec.replicas"})
+
+			entry := Entry{}
+			Expect(json.Unmarshal(buf.Bytes(), &entry)).To(Succeed())
+			Expect(entry.Time.IsZero()).To(BeFalse())
+			entry.Time = entry.Time.UTC()
+			Expect(entry).To(Equal(Entry{
+				Time:            entry.Time,
+				Operation:       OperationUpdate,
+				APIVersion:      "apps/v1",
+				Kind:            "Deployment",
+				Namespace:       "kube-system",
+				Name:            "dep",
+				ManagedResource: "foo/bar",
+				ReconcileID:     "1234",
+				Reason:          "some reason",
+				Changes:         []string{"spec.replicas"},
+			}))
+		})
+
+		It("should record events on the object", func() {
+			var (
+				eventRecorder = record.NewFakeRecorder(1)
+				recorder      = NewRecorder(nil, eventRecorder, types.NamespacedName{Namespace: "foo", Name: "bar"}, "1234")
+			)
+
+			recorder.Record(OperationUpdate, deployment, "some reason", []string{"spec.replicas"})
+
+			Expect(eventRecorder.Events).To(Receive(Equal("Normal Updated Object updated on behalf of ManagedResource foo/bar (reconcile 1234): some reason (changed fields: spec.replicas)")))
+		})
+
+		It("should discard entries if neither sink nor event recorder is given", func() {
+			recorder := NewRecorder(nil, nil, types.NamespacedName{Namespace: "foo", Name: "bar"}, "1234")
+			Expect(recorder).To(BeNil())
+			Expect(func() {
+				recorder.Record(OperationDelete, deployment, "some reason", nil)
+			}).NotTo(Panic())
+		})
+	})
+
+	Describe("#Changes", func() {
+		It("should return no changes for equal objects", func() {
+			obj := map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1)}}
+			Expect(Changes(obj, obj)).To(BeEmpty())
+		})
+
+		It("should return the paths of changed, added and removed fields", func() {
+			old := map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels":          map[string]interface{}{"foo": "bar", "removed": "true"},
+					"resourceVersion": "1",
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(1),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{"containers": []interface{}{"a"}},
+					},
+				},
+				"status": map[string]interface{}{"replicas": int64(1)},
+			}
+			new := map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels":          map[string]interface{}{"foo": "baz", "added": "true"},
+					"resourceVersion": "2",
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{"containers": []interface{}{"b"}},
+					},
+				},
+				"status": map[string]interface{}{"replicas": int64(2)},
+			}
+
+			Expect(Changes(old, new)).To(Equal([]string{
+				"metadata.labels.added",
+				"metadata.labels.foo",
+				"metadata.labels.removed",
+				"spec.replicas",
+				"spec.template.spec",
+			}))
+		})
+	})
+})
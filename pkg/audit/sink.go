@@ -0,0 +1,96 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+type logSink struct {
+	log logr.Logger
+}
+
+// NewLogSink returns a sink writing all entries as structured log messages to the given logger.
+func NewLogSink(log logr.Logger) Sink {
+	return &logSink{log: log}
+}
+
+func (s *logSink) Record(entry Entry) {
+	keysAndValues := []interface{}{
+		"operation", entry.Operation,
+		"apiVersion", entry.APIVersion,
+		"kind", entry.Kind,
+		"namespace", entry.Namespace,
+		"name", entry.Name,
+		"managedResource", entry.ManagedResource,
+		"reconcileID", entry.ReconcileID,
+		"reason", entry.Reason,
+	}
+	if len(entry.Changes) > 0 {
+		keysAndValues = append(keysAndValues, "changes", entry.Changes)
+	}
+	s.log.Info("Audit", keysAndValues...)
+}
+
+// WriterSink is a sink writing all entries as JSON lines to a writer.
+type WriterSink struct {
+	lock    sync.Mutex
+	writer  io.Writer
+	encoder *json.Encoder
+	log     logr.Logger
+}
+
+// NewWriterSink returns a sink writing all entries as JSON lines to the given writer. Errors during writing are
+// logged with the given logger.
+func NewWriterSink(log logr.Logger, writer io.Writer) *WriterSink {
+	return &WriterSink{writer: writer, encoder: json.NewEncoder(writer), log: log}
+}
+
+// NewFileSink returns a sink appending all entries as JSON lines to the file at the given path. The file is created
+// if it does not exist.
+func NewFileSink(log logr.Logger, path string) (*WriterSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open audit log file: %w", err)
+	}
+	return NewWriterSink(log, file), nil
+}
+
+// Record implements `Sink`.
+func (s *WriterSink) Record(entry Entry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.encoder.Encode(entry); err != nil {
+		s.log.Error(err, "Could not write audit log entry", "entry", entry)
+	}
+}
+
+// Close closes the underlying writer if it is an `io.Closer`.
+func (s *WriterSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if closer, ok := s.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chartrenderer packages rendered Helm charts into ManagedResources, so that components deploying charts via
+// the gardener-resource-manager (e.g. Gardener extensions) don't have to maintain their own conversion code.
+package chartrenderer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/manager"
+
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// DefaultMaxSecretSize is the default maximum size of the data of a single secret. The API server rejects secrets
+// larger than 1 MiB, the remaining space is left for the metadata and the base64 encoding overhead of the request.
+const DefaultMaxSecretSize = 768 * 1024
+
+// Options configures the ManagedResource and the secrets of a Bundle.
+type Options struct {
+	// Class is the class of the ManagedResource.
+	Class string
+	// InjectLabels are the labels injected into all objects of the ManagedResource.
+	InjectLabels map[string]string
+	// KeepObjects keeps the objects in the target cluster when the ManagedResource is deleted.
+	KeepObjects bool
+	// ForceOverwriteLabels overwrites the labels of existing objects.
+	ForceOverwriteLabels bool
+	// ForceOverwriteAnnotations overwrites the annotations of existing objects.
+	ForceOverwriteAnnotations bool
+	// MaxSecretSize is the maximum size of the data of a single secret, DefaultMaxSecretSize if not set.
+	MaxSecretSize int
+}
+
+// Bundle is a ManagedResource together with the secrets containing its objects.
+type Bundle struct {
+	ManagedResource *resourcesv1alpha1.ManagedResource
+	Secrets         []*corev1.Secret
+}
+
+// Render renders the chart at the given path in-process and packages the result into a Bundle with the given
+// namespace and name, see Package.
+func Render(renderer chartrenderer.Interface, chartPath, releaseName, chartNamespace string, values interface{}, namespace, name string, opts Options) (*Bundle, error) {
+	chart, err := renderer.Render(chartPath, releaseName, chartNamespace, values)
+	if err != nil {
+		return nil, fmt.Errorf("could not render chart %q: %w", chartPath, err)
+	}
+	return Package(chart, namespace, name, opts)
+}
+
+// RenderArchive renders the given chart archive in-process and packages the result into a Bundle with the given
+// namespace and name, see Package.
+func RenderArchive(renderer chartrenderer.Interface, archive []byte, releaseName, chartNamespace string, values interface{}, namespace, name string, opts Options) (*Bundle, error) {
+	chart, err := renderer.RenderArchive(archive, releaseName, chartNamespace, values)
+	if err != nil {
+		return nil, fmt.Errorf("could not render chart archive: %w", err)
+	}
+	return Package(chart, namespace, name, opts)
+}
+
+// Package packages the manifests of the given rendered chart into as few secrets as possible, each with at most
+// opts.MaxSecretSize bytes of data, and a ManagedResource referencing them. The first secret has the name of the
+// ManagedResource, the following ones are suffixed with their index (`<name>-1`, `<name>-2`, ...). Every template is
+// stored in its own key, templates exceeding the maximum size are split into their documents. Empty templates are
+// skipped.
+func Package(chart *chartrenderer.RenderedChart, namespace, name string, opts Options) (*Bundle, error) {
+	maxSize := opts.MaxSecretSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSecretSize
+	}
+
+	entries, err := entriesOf(chart, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		secrets []*corev1.Secret
+		size    int
+	)
+	for _, e := range entries {
+		if len(secrets) == 0 || size+len(e.value) > maxSize {
+			secrets = append(secrets, &corev1.Secret{})
+			size = 0
+		}
+		secret := secrets[len(secrets)-1]
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[e.key] = e.value
+		size += len(e.value)
+	}
+
+	mr := &resourcesv1alpha1.ManagedResource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: resourcesv1alpha1.ManagedResourceSpec{
+			InjectLabels:              opts.InjectLabels,
+			KeepObjects:               &opts.KeepObjects,
+			ForceOverwriteLabels:      &opts.ForceOverwriteLabels,
+			ForceOverwriteAnnotations: &opts.ForceOverwriteAnnotations,
+		},
+	}
+	if opts.Class != "" {
+		mr.Spec.Class = &opts.Class
+	}
+
+	for i, secret := range secrets {
+		secret.Namespace = namespace
+		secret.Name = secretName(name, i)
+		secret.Type = corev1.SecretTypeOpaque
+		mr.Spec.SecretRefs = append(mr.Spec.SecretRefs, corev1.LocalObjectReference{Name: secret.Name})
+	}
+
+	return &Bundle{ManagedResource: mr, Secrets: secrets}, nil
+}
+
+func secretName(name string, index int) string {
+	if index == 0 {
+		return name
+	}
+	return name + "-" + strconv.Itoa(index)
+}
+
+type entry struct {
+	key   string
+	value []byte
+}
+
+var documentSeparator = regexp.MustCompile(`(?m)^---.*$`)
+
+// entriesOf returns the secret data entries of the given chart sorted by key, so that the bundles of equal charts
+// are equal as well.
+func entriesOf(chart *chartrenderer.RenderedChart, maxSize int) ([]entry, error) {
+	var entries []entry
+	for _, m := range chart.Manifests {
+		if strings.TrimSpace(m.Content) == "" {
+			continue
+		}
+
+		key := strings.ReplaceAll(m.Name, "/", "_")
+		if len(m.Content) <= maxSize {
+			entries = append(entries, entry{key, []byte(m.Content)})
+			continue
+		}
+
+		var index int
+		for _, doc := range documentSeparator.Split(m.Content, -1) {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+			if len(doc) > maxSize {
+				return nil, fmt.Errorf("template %q contains a document of %d bytes exceeding the maximum secret size of %d bytes", m.Name, len(doc), maxSize)
+			}
+			entries = append(entries, entry{key + "_" + strconv.Itoa(index), []byte(doc)})
+			index++
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	return entries, nil
+}
+
+// Reconcile creates or updates the secrets of the bundle and afterwards its ManagedResource. Secrets referenced by a
+// previous version of the ManagedResource that are not part of the bundle anymore are deleted.
+func (b *Bundle) Reconcile(ctx context.Context, c client.Client) error {
+	var (
+		mr    = b.ManagedResource
+		stale = sets.NewString()
+	)
+
+	existing := &resourcesv1alpha1.ManagedResource{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}, existing); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("could not get managed resource '%s/%s': %w", mr.Namespace, mr.Name, err)
+	}
+	for _, ref := range existing.Spec.SecretRefs {
+		stale.Insert(ref.Name)
+	}
+
+	for _, secret := range b.Secrets {
+		if err := manager.NewSecret(c).
+			WithNamespacedName(secret.Namespace, secret.Name).
+			WithLabels(secret.Labels).
+			WithAnnotations(secret.Annotations).
+			WithKeyValues(secret.Data).
+			Reconcile(ctx); err != nil {
+			return fmt.Errorf("could not create or update secret '%s/%s' of managed resource: %w", secret.Namespace, secret.Name, err)
+		}
+		stale.Delete(secret.Name)
+	}
+
+	current := &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{Namespace: mr.Namespace, Name: mr.Name}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, current, func() error {
+		current.Labels = mr.Labels
+		current.Annotations = mr.Annotations
+		current.Spec = mr.Spec
+		return nil
+	}); err != nil {
+		return fmt.Errorf("could not create or update managed resource '%s/%s': %w", mr.Namespace, mr.Name, err)
+	}
+
+	for _, name := range stale.List() {
+		if err := manager.NewSecret(c).WithNamespacedName(mr.Namespace, name).Delete(ctx); err != nil {
+			return fmt.Errorf("could not delete stale secret '%s/%s' of managed resource: %w", mr.Namespace, name, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the ManagedResource of the bundle and afterwards its secrets.
+func (b *Bundle) Delete(ctx context.Context, c client.Client) error {
+	mr := b.ManagedResource
+	if err := manager.NewManagedResource(c).WithNamespacedName(mr.Namespace, mr.Name).Delete(ctx); err != nil {
+		return fmt.Errorf("could not delete managed resource '%s/%s': %w", mr.Namespace, mr.Name, err)
+	}
+
+	for _, secret := range b.Secrets {
+		if err := manager.NewSecret(c).WithNamespacedName(secret.Namespace, secret.Name).Delete(ctx); err != nil {
+			return fmt.Errorf("could not delete secret '%s/%s' of managed resource: %w", secret.Namespace, secret.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartrenderer_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/chartrenderer"
+	"github.com/gardener/gardener-resource-manager/pkg/test/fake"
+
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/manifest"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ChartRenderer", func() {
+	configMap := func(name string) string {
+		return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+	}
+
+	Describe("#Render", func() {
+		var chartPath string
+
+		BeforeEach(func() {
+			var err error
+			chartPath, err = ioutil.TempDir("", "chart")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.Mkdir(filepath.Join(chartPath, "templates"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(chartPath, "Chart.yaml"), []byte("name: test\nversion: 0.1.0\n"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(chartPath, "templates", "configmap.yaml"), []byte(configMap("{{ .Values.name }}")), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(chartPath, "templates", "empty.yaml"), []byte("{{- if false }}\n{{- end }}\n"), 0644)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(chartPath)).To(Succeed())
+		})
+
+		It("should render the chart into a ManagedResource and a secret", func() {
+			renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{})
+
+			bundle, err := Render(renderer, chartPath, "test", "kube-system", map[string]interface{}{"name": "foo"}, "shoot--foo--bar", "test", Options{Class: "seed"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(bundle.Secrets).To(HaveLen(1))
+			Expect(bundle.Secrets[0].Namespace).To(Equal("shoot--foo--bar"))
+			Expect(bundle.Secrets[0].Name).To(Equal("test"))
+			Expect(bundle.Secrets[0].Data).To(Equal(map[string][]byte{"test_templates_configmap.yaml": []byte(configMap("foo"))}))
+
+			Expect(bundle.ManagedResource.Namespace).To(Equal("shoot--foo--bar"))
+			Expect(bundle.ManagedResource.Name).To(Equal("test"))
+			Expect(bundle.ManagedResource.Spec.Class).To(Equal(pointer.StringPtr("seed")))
+			Expect(bundle.ManagedResource.Spec.SecretRefs).To(Equal([]corev1.LocalObjectReference{{Name: "test"}}))
+		})
+	})
+
+	Describe("#Package", func() {
+		It("should split the templates into secrets of the maximum size", func() {
+			chart := &chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []manifest.Manifest{
+					{Name: "test/templates/c.yaml", Content: configMap("c")},
+					{Name: "test/templates/a.yaml", Content: configMap("a")},
+					{Name: "test/templates/b.yaml", Content: configMap("b")},
+				},
+			}
+
+			bundle, err := Package(chart, "default", "test", Options{MaxSecretSize: 2 * len(configMap("a"))})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(bundle.Secrets).To(HaveLen(2))
+			Expect(bundle.Secrets[0].Name).To(Equal("test"))
+			Expect(bundle.Secrets[0].Data).To(Equal(map[string][]byte{
+				"test_templates_a.yaml": []byte(configMap("a")),
+				"test_templates_b.yaml": []byte(configMap("b")),
+			}))
+			Expect(bundle.Secrets[1].Name).To(Equal("test-1"))
+			Expect(bundle.Secrets[1].Data).To(Equal(map[string][]byte{
+				"test_templates_c.yaml": []byte(configMap("c")),
+			}))
+			Expect(bundle.ManagedResource.Spec.SecretRefs).To(Equal([]corev1.LocalObjectReference{{Name: "test"}, {Name: "test-1"}}))
+		})
+
+		It("should split templates exceeding the maximum size into their documents", func() {
+			chart := &chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []manifest.Manifest{
+					{Name: "test/templates/a.yaml", Content: configMap("a") + "---\n" + configMap("b")},
+				},
+			}
+
+			bundle, err := Package(chart, "default", "test", Options{MaxSecretSize: len(configMap("a")) + 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(bundle.Secrets).To(HaveLen(2))
+			Expect(bundle.Secrets[0].Data).To(HaveKey("test_templates_a.yaml_0"))
+			Expect(bundle.Secrets[1].Data).To(HaveKey("test_templates_a.yaml_1"))
+			Expect(strings.TrimSpace(string(bundle.Secrets[1].Data["test_templates_a.yaml_1"]))).To(Equal(strings.TrimSpace(configMap("b"))))
+		})
+
+		It("should fail if a single document exceeds the maximum size", func() {
+			chart := &chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []manifest.Manifest{{Name: "test/templates/a.yaml", Content: configMap("a")}},
+			}
+
+			_, err := Package(chart, "default", "test", Options{MaxSecretSize: 10})
+			Expect(err).To(MatchError(ContainSubstring("exceeding the maximum secret size")))
+		})
+	})
+
+	Describe("Bundle", func() {
+		var (
+			ctx = context.TODO()
+			c   *fake.Client
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(kubernetesscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+			c = fake.NewClient(scheme)
+		})
+
+		newBundle := func(maxSecretSize int) *Bundle {
+			chart := &chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []manifest.Manifest{
+					{Name: "test/templates/a.yaml", Content: configMap("a")},
+					{Name: "test/templates/b.yaml", Content: configMap("b")},
+				},
+			}
+			bundle, err := Package(chart, "default", "test", Options{MaxSecretSize: maxSecretSize})
+			Expect(err).NotTo(HaveOccurred())
+			return bundle
+		}
+
+		It("should create the secrets before the ManagedResource and delete stale secrets", func() {
+			Expect(newBundle(len(configMap("a"))).Reconcile(ctx, c)).To(Succeed())
+			Expect(c.OperationStrings()).To(Equal([]string{
+				"create v1 Secret default/test",
+				"create v1 Secret default/test-1",
+				"create resources.gardener.cloud/v1alpha1 ManagedResource default/test",
+			}))
+
+			c.Reset()
+			Expect(newBundle(0).Reconcile(ctx, c)).To(Succeed())
+			Expect(c.OperationStrings()).To(Equal([]string{
+				"update v1 Secret default/test",
+				"update resources.gardener.cloud/v1alpha1 ManagedResource default/test",
+				"delete v1 Secret default/test-1",
+			}))
+
+			mr := &resourcesv1alpha1.ManagedResource{}
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test"}, mr)).To(Succeed())
+			Expect(mr.Spec.SecretRefs).To(Equal([]corev1.LocalObjectReference{{Name: "test"}}))
+		})
+
+		It("should delete the ManagedResource and its secrets", func() {
+			bundle := newBundle(0)
+			Expect(bundle.Reconcile(ctx, c)).To(Succeed())
+
+			Expect(bundle.Delete(ctx, c)).To(Succeed())
+			Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test"}, &resourcesv1alpha1.ManagedResource{}))).To(BeTrue())
+			Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test"}, &corev1.Secret{}))).To(BeTrue())
+		})
+	})
+})
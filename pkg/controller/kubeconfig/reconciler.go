@@ -0,0 +1,174 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gardener/gardener-resource-manager/pkg/metrics"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// EventReasonRotated is the reason of the events recorded on the secret when its kubeconfig has been rotated.
+	EventReasonRotated = "TargetKubeconfigRotated"
+	// EventReasonValid is the reason of the events recorded on the secret when its rotated kubeconfig could connect to
+	// the target cluster.
+	EventReasonValid = "TargetKubeconfigValid"
+	// EventReasonInvalid is the reason of the events recorded on the secret when its rotated kubeconfig could not
+	// connect to the target cluster.
+	EventReasonInvalid = "TargetKubeconfigInvalid"
+
+	// RetryInterval is the interval after which a rotated kubeconfig which could not connect to the target cluster is
+	// validated again.
+	RetryInterval = time.Minute
+
+	// validationTimeout is the timeout of the requests validating a rotated kubeconfig.
+	validationTimeout = 10 * time.Second
+)
+
+// Reconciler observes the secret containing the kubeconfig of the target cluster. When the kubeconfig differs from
+// the one the gardener-resource-manager has been started with, the rotation is reported with an event on the secret
+// and the `gardener_resource_manager_target_kubeconfig_rotations_total` metric, and the new kubeconfig is validated
+// by connecting to the target cluster with it. This way, broken credentials are noticed before the old ones are
+// revoked and all reconciliations start failing.
+// The reconciler keeps the state of the observed kubeconfig, hence it must only reconcile this single secret.
+type Reconciler struct {
+	log      logr.Logger
+	client   client.Client
+	ctx      context.Context
+	recorder record.EventRecorder
+	key      types.NamespacedName
+	dataKey  string
+
+	validate func(kubeconfig []byte) error
+
+	checksum string
+	valid    bool
+}
+
+// InjectClient injects a client into the reconciler.
+func (r *Reconciler) InjectClient(client client.Client) error {
+	r.client = client
+	return nil
+}
+
+// NewReconciler creates a new reconciler for the secret with the given key, which contains the kubeconfig of the
+// target cluster in the given data key. The given kubeconfig is the one currently used for the target cluster, only
+// deviating kubeconfigs are reported as rotations. All requests of its reconciliations are made with (children of)
+// the given context.
+func NewReconciler(ctx context.Context, log logr.Logger, recorder record.EventRecorder, key types.NamespacedName, dataKey string, kubeconfig []byte) *Reconciler {
+	// the gardener-resource-manager has been able to start with the current kubeconfig
+	metrics.RecordTargetKubeconfigValidation(key.Namespace, key.Name, true)
+
+	return &Reconciler{
+		log:      log,
+		ctx:      ctx,
+		recorder: recorder,
+		key:      key,
+		dataKey:  dataKey,
+		validate: validateKubeconfig,
+		checksum: checksum(kubeconfig),
+		valid:    true,
+	}
+}
+
+// Reconcile implements `reconcile.Reconciler`.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	if req.NamespacedName != r.key {
+		return reconcile.Result{}, nil
+	}
+
+	ctx := r.ctx
+	log := r.log.WithValues("secret", req)
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, req.NamespacedName, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("Secret containing the kubeconfig of the target cluster has been deleted")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Secret: %+v", err)
+	}
+
+	kubeconfig, ok := secret.Data[r.dataKey]
+	if sum := checksum(kubeconfig); sum != r.checksum {
+		r.checksum = sum
+		r.valid = false
+		metrics.TargetKubeconfigRotations.Inc()
+		log.Info("Kubeconfig of the target cluster has been rotated, validating it")
+		r.recorder.Event(secret, corev1.EventTypeNormal, EventReasonRotated, "Kubeconfig of the target cluster has been rotated")
+	} else if r.valid {
+		return reconcile.Result{}, nil
+	}
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("secret has no data key %q", r.dataKey)
+	} else if err = ctx.Err(); err != nil {
+		return reconcile.Result{}, err
+	} else {
+		err = r.validate(kubeconfig)
+	}
+
+	metrics.RecordTargetKubeconfigValidation(r.key.Namespace, r.key.Name, err == nil)
+	if err != nil {
+		log.Error(err, "Rotated kubeconfig cannot connect to the target cluster", "retryAfter", RetryInterval.String())
+		r.recorder.Eventf(secret, corev1.EventTypeWarning, EventReasonInvalid, "Rotated kubeconfig cannot connect to the target cluster: %v", err)
+		return reconcile.Result{RequeueAfter: RetryInterval}, nil
+	}
+
+	r.valid = true
+	log.Info("Rotated kubeconfig can connect to the target cluster")
+	r.recorder.Event(secret, corev1.EventTypeNormal, EventReasonValid, "Rotated kubeconfig can connect to the target cluster, it is used after the next restart")
+	return reconcile.Result{}, nil
+}
+
+// validateKubeconfig connects to the cluster of the given kubeconfig. In contrast to the version endpoint, the
+// discovery endpoints are not served to anonymous users by default, hence the credentials are validated as well.
+func validateKubeconfig(kubeconfig []byte) error {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("could not parse kubeconfig: %+v", err)
+	}
+	config.Timeout = validationTimeout
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("could not create discovery client: %+v", err)
+	}
+	if _, err := discoveryClient.ServerGroups(); err != nil {
+		return fmt.Errorf("could not discover API groups: %+v", err)
+	}
+	return nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gardener/gardener-resource-manager/pkg/metrics"
+	"github.com/gardener/gardener-resource-manager/pkg/test/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+func metricValue(metric prometheus.Metric) float64 {
+	m := &dto.Metric{}
+	Expect(metric.Write(m)).To(Succeed())
+	if m.Counter != nil {
+		return m.GetCounter().GetValue()
+	}
+	return m.GetGauge().GetValue()
+}
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx      = context.TODO()
+		key      = types.NamespacedName{Namespace: "garden", Name: "target-kubeconfig"}
+		req      = reconcile.Request{NamespacedName: key}
+		recorder *record.FakeRecorder
+
+		r           *Reconciler
+		secret      *corev1.Secret
+		validateErr error
+		validated   [][]byte
+		rotations   float64
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		validateErr = nil
+		validated = nil
+		rotations = metricValue(metrics.TargetKubeconfigRotations)
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data:       map[string][]byte{"kubeconfig.yaml": []byte("old")},
+		}
+	})
+
+	newReconciler := func() {
+		r = NewReconciler(ctx, runtimelog.NullLogger{}, recorder, key, "kubeconfig.yaml", []byte("old"))
+		r.validate = func(kubeconfig []byte) error {
+			validated = append(validated, kubeconfig)
+			return validateErr
+		}
+		Expect(inject.ClientInto(fake.NewClient(scheme.Scheme, secret), r)).To(BeTrue())
+	}
+
+	It("should not report the kubeconfig the reconciler has been started with", func() {
+		newReconciler()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+		Expect(validated).To(BeEmpty())
+		Expect(recorder.Events).To(BeEmpty())
+		Expect(metricValue(metrics.TargetKubeconfigRotations)).To(Equal(rotations))
+	})
+
+	It("should report and validate a rotated kubeconfig once", func() {
+		secret.Data["kubeconfig.yaml"] = []byte("new")
+		newReconciler()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+		Expect(validated).To(Equal([][]byte{[]byte("new")}))
+		Expect(recorder.Events).To(Receive(Equal("Normal TargetKubeconfigRotated Kubeconfig of the target cluster has been rotated")))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal TargetKubeconfigValid ")))
+		Expect(metricValue(metrics.TargetKubeconfigRotations)).To(Equal(rotations + 1))
+		Expect(metricValue(metrics.TargetKubeconfigValid.WithLabelValues(key.Namespace, key.Name))).To(Equal(1.0))
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+		Expect(validated).To(HaveLen(1))
+		Expect(recorder.Events).To(BeEmpty())
+		Expect(metricValue(metrics.TargetKubeconfigRotations)).To(Equal(rotations + 1))
+	})
+
+	It("should validate an invalid rotated kubeconfig again until it can connect", func() {
+		secret.Data["kubeconfig.yaml"] = []byte("new")
+		validateErr = fmt.Errorf("fake")
+		newReconciler()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: RetryInterval}))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal TargetKubeconfigRotated ")))
+		Expect(recorder.Events).To(Receive(Equal("Warning TargetKubeconfigInvalid Rotated kubeconfig cannot connect to the target cluster: fake")))
+		Expect(metricValue(metrics.TargetKubeconfigValid.WithLabelValues(key.Namespace, key.Name))).To(Equal(0.0))
+
+		validateErr = nil
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+		Expect(validated).To(HaveLen(2))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal TargetKubeconfigValid ")))
+		Expect(metricValue(metrics.TargetKubeconfigRotations)).To(Equal(rotations + 1))
+		Expect(metricValue(metrics.TargetKubeconfigValid.WithLabelValues(key.Namespace, key.Name))).To(Equal(1.0))
+	})
+
+	It("should report a secret without the data key as invalid", func() {
+		secret.Data = nil
+		newReconciler()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: RetryInterval}))
+		Expect(validated).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal TargetKubeconfigRotated ")))
+		Expect(recorder.Events).To(Receive(Equal(`Warning TargetKubeconfigInvalid Rotated kubeconfig cannot connect to the target cluster: secret has no data key "kubeconfig.yaml"`)))
+	})
+
+	It("should ignore other secrets", func() {
+		secret.Data["kubeconfig.yaml"] = []byte("new")
+		newReconciler()
+
+		Expect(r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: key.Namespace, Name: "other"}})).To(Equal(reconcile.Result{}))
+		Expect(validated).To(BeEmpty())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should ignore the deletion of the secret", func() {
+		newReconciler()
+		Expect(inject.ClientInto(fake.NewClient(scheme.Scheme), r)).To(BeTrue())
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+		Expect(validated).To(BeEmpty())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	Describe("#validateKubeconfig", func() {
+		kubeconfigFor := func(server string) []byte {
+			return []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: target
+  cluster:
+    server: ` + server + `
+contexts:
+- name: target
+  context:
+    cluster: target
+    user: target
+current-context: target
+users:
+- name: target
+  user:
+    token: foo
+`)
+		}
+
+		It("should succeed if the API groups can be discovered", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/api":
+					fmt.Fprint(w, `{"kind":"APIVersions","versions":["v1"]}`)
+				case "/apis":
+					fmt.Fprint(w, `{"kind":"APIGroupList","groups":[]}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			Expect(validateKubeconfig(kubeconfigFor(server.URL))).To(Succeed())
+		})
+
+		It("should fail if the credentials are rejected", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+			defer server.Close()
+
+			Expect(validateKubeconfig(kubeconfigFor(server.URL))).To(MatchError(ContainSubstring("could not discover API groups")))
+		})
+
+		It("should fail if the kubeconfig cannot be parsed", func() {
+			Expect(validateKubeconfig([]byte("{"))).To(MatchError(ContainSubstring("could not parse kubeconfig")))
+		})
+	})
+})
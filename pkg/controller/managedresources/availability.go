@@ -0,0 +1,95 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+	"fmt"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// availableReplicasFields are the fields of the status of the kinds supporting the PreserveAvailability annotation
+// containing the number of their pods counted as healthy by PodDisruptionBudgets.
+var availableReplicasFields = map[schema.GroupKind][]string{
+	{Group: "apps", Kind: "Deployment"}:  {"status", "availableReplicas"},
+	{Group: "apps", Kind: "StatefulSet"}: {"status", "readyReplicas"},
+}
+
+func preserveAvailability(meta metav1.Object) bool {
+	return annotationExistsAndValueTrue(meta, resourcesv1alpha1.PreserveAvailability)
+}
+
+// checkAvailability returns an error if deleting the given Deployment or StatefulSet (as it exists in the target
+// cluster) would remove more of its available pods than a PodDisruptionBudget selecting them allows to be disrupted.
+// Objects of other kinds are not checked.
+func (r *Reconciler) checkAvailability(ctx context.Context, obj *unstructured.Unstructured) error {
+	fields, ok := availableReplicasFields[obj.GroupVersionKind().GroupKind()]
+	if !ok {
+		return nil
+	}
+
+	available, _, err := unstructured.NestedInt64(obj.Object, fields...)
+	if err != nil {
+		return err
+	}
+	if available == 0 {
+		return nil
+	}
+	podLabels, _, err := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+	if err != nil {
+		return err
+	}
+
+	// the PodDisruptionBudgets are read from the API server to not start an informer for all of them
+	pdbList := &unstructured.UnstructuredList{}
+	pdbList.SetGroupVersionKind(policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudgetList"))
+	if err := r.targetClient.List(ctx, pdbList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return fmt.Errorf("could not list PodDisruptionBudgets: %w", err)
+	}
+
+	for _, item := range pdbList.Items {
+		pdb := &policyv1beta1.PodDisruptionBudget{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, pdb); err != nil {
+			return err
+		}
+
+		// an empty selector selects no pods in policy/v1beta1
+		if pdb.Spec.Selector == nil || len(pdb.Spec.Selector.MatchLabels)+len(pdb.Spec.Selector.MatchExpressions) == 0 {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return fmt.Errorf("invalid selector of PodDisruptionBudget %s/%s: %w", pdb.Namespace, pdb.Name, err)
+		}
+		if !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+
+		if int64(pdb.Status.PodDisruptionsAllowed) < available {
+			return fmt.Errorf("deleting its %d available pods would violate PodDisruptionBudget %s/%s, which allows %d disruptions", available, pdb.Namespace, pdb.Name, pdb.Status.PodDisruptionsAllowed)
+		}
+	}
+
+	return nil
+}
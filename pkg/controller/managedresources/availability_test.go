@@ -0,0 +1,84 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-resource-manager/pkg/test/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("Availability", func() {
+	Describe("#checkAvailability", func() {
+		var (
+			scheme     *runtime.Scheme
+			deployment *unstructured.Unstructured
+		)
+
+		newPDB := func(name string, matchLabels map[string]string, disruptionsAllowed int32) *policyv1beta1.PodDisruptionBudget {
+			return &policyv1beta1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+				Spec:       policyv1beta1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: matchLabels}},
+				Status:     policyv1beta1.PodDisruptionBudgetStatus{PodDisruptionsAllowed: disruptionsAllowed},
+			}
+		}
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(kubernetesscheme.AddToScheme(scheme)).To(Succeed())
+
+			deployment = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"namespace": "default", "name": "foo"},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "foo"}},
+					},
+				},
+				"status": map[string]interface{}{"availableReplicas": int64(2)},
+			}}
+		})
+
+		It("should allow deleting objects whose pods may be disrupted", func() {
+			r := &Reconciler{targetClient: fake.NewClient(scheme, newPDB("foo", map[string]string{"app": "foo"}, 2), newPDB("bar", map[string]string{"app": "bar"}, 0))}
+			Expect(r.checkAvailability(context.TODO(), deployment)).To(Succeed())
+		})
+
+		It("should forbid deleting objects whose pods must not be disrupted", func() {
+			r := &Reconciler{targetClient: fake.NewClient(scheme, newPDB("foo", map[string]string{"app": "foo"}, 1))}
+			Expect(r.checkAvailability(context.TODO(), deployment)).To(MatchError("deleting its 2 available pods would violate PodDisruptionBudget default/foo, which allows 1 disruptions"))
+		})
+
+		It("should allow deleting objects without available pods or of other kinds", func() {
+			r := &Reconciler{targetClient: fake.NewClient(scheme, newPDB("foo", map[string]string{"app": "foo"}, 0))}
+
+			Expect(unstructured.SetNestedField(deployment.Object, int64(0), "status", "availableReplicas")).To(Succeed())
+			Expect(r.checkAvailability(context.TODO(), deployment)).To(Succeed())
+
+			deployment.SetKind("ReplicaSet")
+			Expect(unstructured.SetNestedField(deployment.Object, int64(2), "status", "availableReplicas")).To(Succeed())
+			Expect(r.checkAvailability(context.TODO(), deployment)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,153 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The benchmarks measure the CPU-bound parts of a reconciliation for a ManagedResource with 100 ConfigMaps of 1 KiB
+// each, run them with `go test -run=^$ -bench=. -benchmem ./pkg/controller/managedresources`. See
+// docs/development/load-test.md for measuring the gardener-resource-manager as a whole.
+
+func benchmarkSecrets(objects, size int) []*corev1.Secret {
+	var b strings.Builder
+	for i := 0; i < objects; i++ {
+		fmt.Fprintf(&b, "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: foo-%d\ndata:\n  value: %q\n", i, strings.Repeat("x", size))
+	}
+	return []*corev1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Data:       map[string][]byte{"objects.yaml": []byte(b.String())},
+	}}
+}
+
+// configMapRESTMapper returns a RESTMapper discovering ConfigMaps from a fake API server.
+func configMapRESTMapper(t interface{ Fatal(args ...interface{}) }) (*restmapper.DeferredDiscoveryRESTMapper, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body interface{}
+		switch req.URL.Path {
+		case "/api":
+			body = &metav1.APIVersions{Versions: []string{"v1"}}
+		case "/apis":
+			body = &metav1.APIGroupList{}
+		case "/api/v1":
+			body = &metav1.APIResourceList{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"}},
+			}
+		default:
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)), server.Close
+}
+
+func BenchmarkDecodeSecrets(b *testing.B) {
+	mapper, stop := configMapRESTMapper(b)
+	defer stop()
+
+	secrets := benchmarkSecrets(100, 1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if objs, _, _, complete := decodeSecrets(runtimelog.NullLogger{}, mapper, nil, secrets); len(objs) != 100 || !complete {
+			b.Fatalf("unexpected result of decoding: %d objects, complete %t", len(objs), complete)
+		}
+	}
+}
+
+func BenchmarkChecksumOfSecrets(b *testing.B) {
+	secrets := benchmarkSecrets(100, 1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checksumOfSecrets(secrets)
+	}
+}
+
+func BenchmarkDecodeCacheGet(b *testing.B) {
+	mapper, stop := configMapRESTMapper(b)
+	defer stop()
+
+	var (
+		secrets  = benchmarkSecrets(100, 1024)
+		cache    = NewDecodeCache()
+		key      = client.ObjectKey{Namespace: "foo", Name: "bar"}
+		checksum = checksumOfSecrets(secrets)
+	)
+	objs, _, _, _ := decodeSecrets(runtimelog.NullLogger{}, mapper, nil, secrets)
+	cache.Set(key, checksum, objs)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := cache.Get(key, checksumOfSecrets(secrets)); !ok {
+			b.Fatal("expected cache hit")
+		}
+	}
+}
+
+func BenchmarkMerge(b *testing.B) {
+	newConfigMap := func(value string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace("foo")
+		obj.SetName("bar")
+		obj.SetLabels(map[string]string{"foo": "bar"})
+		obj.SetAnnotations(map[string]string{"foo": "bar"})
+		obj.Object["data"] = map[string]interface{}{"value": value}
+		return obj
+	}
+
+	var (
+		desired = newConfigMap(strings.Repeat("x", 1024))
+		current = newConfigMap(strings.Repeat("y", 1024))
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := merge(desired.DeepCopy(), current.DeepCopy(), false, nil, false, nil, false, false, false, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
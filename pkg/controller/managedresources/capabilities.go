@@ -0,0 +1,151 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"github.com/Masterminds/semver"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+)
+
+// Capabilities looks up the API group versions served by the target cluster and its Kubernetes version, on which
+// the objects of ManagedResources can be made conditional with the `resources.gardener.cloud/skip-if-api-unavailable`
+// and `resources.gardener.cloud/target-version-constraint` annotations. Both are cached until they are reset.
+type Capabilities struct {
+	discovery discovery.DiscoveryInterface
+
+	lock          sync.Mutex
+	groupVersions sets.String
+	version       *semver.Version
+}
+
+// NewCapabilities creates new Capabilities looking up the target cluster with the given discovery client.
+func NewCapabilities(discovery discovery.DiscoveryInterface) *Capabilities {
+	return &Capabilities{discovery: discovery}
+}
+
+// Reset implements `utils.Resetter`. The discovery information is looked up again with the next check.
+func (c *Capabilities) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.groupVersions = nil
+	c.version = nil
+}
+
+// unsupportedReason returns why the given object must not be applied to the target cluster according to its
+// annotations, or an empty string if it is supported. All objects are supported if no capabilities are given.
+func (c *Capabilities) unsupportedReason(obj *unstructured.Unstructured) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	annotations := obj.GetAnnotations()
+
+	if value, ok := annotations[resourcesv1alpha1.SkipIfAPIUnavailable]; ok {
+		groupVersions, err := c.servedGroupVersions()
+		if err != nil {
+			return "", err
+		}
+
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+			groupVersion, err := schema.ParseGroupVersion(v)
+			if err != nil {
+				return "", fmt.Errorf("invalid value %q of annotation %s: %+v", value, resourcesv1alpha1.SkipIfAPIUnavailable, err)
+			}
+			if !groupVersions.Has(groupVersion.String()) {
+				return fmt.Sprintf("API %s is not served by the target cluster", groupVersion), nil
+			}
+		}
+	}
+
+	if value, ok := annotations[resourcesv1alpha1.TargetVersionConstraint]; ok {
+		constraint, err := semver.NewConstraint(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid value %q of annotation %s: %+v", value, resourcesv1alpha1.TargetVersionConstraint, err)
+		}
+
+		version, err := c.serverVersion()
+		if err != nil {
+			return "", err
+		}
+		if !constraint.Check(version) {
+			return fmt.Sprintf("version %s of the target cluster does not satisfy %q", version, value), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (c *Capabilities) servedGroupVersions() (sets.String, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.groupVersions != nil {
+		return c.groupVersions, nil
+	}
+
+	groups, err := c.discovery.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("could not discover the APIs served by the target cluster: %+v", err)
+	}
+	groupVersions := sets.NewString()
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			groupVersions.Insert(version.GroupVersion)
+		}
+	}
+
+	c.groupVersions = groupVersions
+	return groupVersions, nil
+}
+
+func (c *Capabilities) serverVersion() (*semver.Version, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.version != nil {
+		return c.version, nil
+	}
+
+	info, err := c.discovery.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("could not discover the version of the target cluster: %+v", err)
+	}
+	version, err := semver.NewVersion(info.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the version %q of the target cluster: %+v", info.GitVersion, err)
+	}
+	// Versions of providers like `v1.21.5-gke.1302` are pre-releases for semver, which don't satisfy constraints
+	// without pre-release (e.g. `>=1.21`), hence only the release is compared.
+	if version.Prerelease() != "" || version.Metadata() != "" {
+		if version, err = semver.NewVersion(fmt.Sprintf("%d.%d.%d", version.Major(), version.Minor(), version.Patch())); err != nil {
+			return nil, err
+		}
+	}
+
+	c.version = version
+	return version, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+)
+
+type fakeCapabilitiesDiscovery struct {
+	discovery.DiscoveryInterface
+	groups  *metav1.APIGroupList
+	version *version.Info
+	calls   int
+}
+
+func (d *fakeCapabilitiesDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	d.calls++
+	return d.groups, nil
+}
+
+func (d *fakeCapabilitiesDiscovery) ServerVersion() (*version.Info, error) {
+	d.calls++
+	return d.version, nil
+}
+
+var _ = Describe("Capabilities", func() {
+	var (
+		disc *fakeCapabilitiesDiscovery
+		caps *Capabilities
+		obj  *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		disc = &fakeCapabilitiesDiscovery{
+			groups: &metav1.APIGroupList{Groups: []metav1.APIGroup{
+				{Name: "", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "v1", Version: "v1"}}},
+				{Name: "policy", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "policy/v1", Version: "v1"}}},
+			}},
+			version: &version.Info{GitVersion: "v1.21.5-gke.1302"},
+		}
+		caps = NewCapabilities(disc)
+
+		obj = &unstructured.Unstructured{}
+		obj.SetAPIVersion("policy/v1beta1")
+		obj.SetKind("PodDisruptionBudget")
+	})
+
+	It("should support all objects if no capabilities are given", func() {
+		obj.SetAnnotations(map[string]string{resourcesv1alpha1.SkipIfAPIUnavailable: "policy/v1beta1"})
+		Expect((*Capabilities)(nil).unsupportedReason(obj)).To(BeEmpty())
+	})
+
+	It("should support objects without annotations without discovering the target cluster", func() {
+		Expect(caps.unsupportedReason(obj)).To(BeEmpty())
+		Expect(disc.calls).To(BeZero())
+	})
+
+	It("should skip objects requiring APIs which are not served", func() {
+		obj.SetAnnotations(map[string]string{resourcesv1alpha1.SkipIfAPIUnavailable: "v1, policy/v1beta1"})
+		Expect(caps.unsupportedReason(obj)).To(Equal("API policy/v1beta1 is not served by the target cluster"))
+
+		obj.SetAnnotations(map[string]string{resourcesv1alpha1.SkipIfAPIUnavailable: "v1,policy/v1"})
+		Expect(caps.unsupportedReason(obj)).To(BeEmpty())
+	})
+
+	It("should compare the release of the target cluster with version constraints", func() {
+		obj.SetAnnotations(map[string]string{resourcesv1alpha1.TargetVersionConstraint: ">= 1.21"})
+		Expect(caps.unsupportedReason(obj)).To(BeEmpty())
+
+		obj.SetAnnotations(map[string]string{resourcesv1alpha1.TargetVersionConstraint: "< 1.21"})
+		Expect(caps.unsupportedReason(obj)).To(Equal(`version 1.21.5 of the target cluster does not satisfy "< 1.21"`))
+	})
+
+	It("should cache the discovery information until it is reset", func() {
+		obj.SetAnnotations(map[string]string{
+			resourcesv1alpha1.SkipIfAPIUnavailable:    "policy/v1",
+			resourcesv1alpha1.TargetVersionConstraint: ">= 1.21",
+		})
+		Expect(caps.unsupportedReason(obj)).To(BeEmpty())
+		Expect(caps.unsupportedReason(obj)).To(BeEmpty())
+		Expect(disc.calls).To(Equal(2))
+
+		caps.Reset()
+		Expect(caps.unsupportedReason(obj)).To(BeEmpty())
+		Expect(disc.calls).To(Equal(4))
+	})
+
+	It("should fail for invalid annotations", func() {
+		obj.SetAnnotations(map[string]string{resourcesv1alpha1.SkipIfAPIUnavailable: "a/b/c"})
+		_, err := caps.unsupportedReason(obj)
+		Expect(err).To(MatchError(ContainSubstring(resourcesv1alpha1.SkipIfAPIUnavailable)))
+
+		obj.SetAnnotations(map[string]string{resourcesv1alpha1.TargetVersionConstraint: "foo"})
+		_, err = caps.unsupportedReason(obj)
+		Expect(err).To(MatchError(ContainSubstring(resourcesv1alpha1.TargetVersionConstraint)))
+	})
+})
@@ -18,27 +18,48 @@ import (
 	"context"
 	"fmt"
 
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/audit"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // cleanup tries to cleanup any resources created by the given object, that are left in the target cluster. It returns a
 // bool indicating whether there are still some deletions pending and an error if any occurred.
-func cleanup(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj *unstructured.Unstructured, deletePVCs bool) error {
+func cleanup(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj *unstructured.Unstructured, deletePVCs bool, auditRecorder *audit.Recorder) error {
 	switch obj.GroupVersionKind().GroupKind() {
 	case appsv1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind(), extensionsv1beta1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind():
-		return cleanupStatefulSet(ctx, c, scheme, obj, deletePVCs)
+		return cleanupStatefulSet(ctx, c, scheme, obj, deletePVCs, auditRecorder)
 	}
 
 	return nil
 }
 
+// deletionRequiresConfirmationGroupKinds contains the kinds of objects that hold data or affect the whole cluster, so
+// that deleting them by accident would be fatal.
+var deletionRequiresConfirmationGroupKinds = map[schema.GroupKind]struct{}{
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: {},
+	{Group: corev1.GroupName, Kind: "PersistentVolumeClaim"}:          {},
+	{Group: corev1.GroupName, Kind: "Namespace"}:                      {},
+}
+
+// DeletionConfirmed returns true if the given object may be deleted, i.e. if it is not of a kind requiring a
+// confirmation for its deletion or if it is annotated with the deletion confirmation annotation.
+func DeletionConfirmed(obj *unstructured.Unstructured) bool {
+	if _, ok := deletionRequiresConfirmationGroupKinds[obj.GroupVersionKind().GroupKind()]; !ok {
+		return true
+	}
+	return annotationExistsAndValueTrue(obj, resourcesv1alpha1.ConfirmationDeletion)
+}
+
 // cleanupStatefulSet tries to delete all PVCs created by this StatefulSet if the ManagedResource is configured accordingly.
-func cleanupStatefulSet(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj runtime.Object, deletePVCs bool) error {
+func cleanupStatefulSet(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj runtime.Object, deletePVCs bool, auditRecorder *audit.Recorder) error {
 	if !deletePVCs {
 		return nil
 	}
@@ -72,5 +93,12 @@ func cleanupStatefulSet(ctx context.Context, c client.Client, scheme *runtime.Sc
 		return fmt.Errorf("%s: %v", errMsg, err)
 	}
 
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		pvc.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+		auditRecorder.Record(audit.OperationDelete, pvc,
+			fmt.Sprintf("StatefulSet %s/%s is deleted and ManagedResource requests deletion of its PersistentVolumeClaims", statefulSet.Namespace, statefulSet.Name), nil)
+	}
+
 	return nil
 }
@@ -18,17 +18,21 @@ import (
 	"context"
 	"fmt"
 
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/audit"
 	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
 
 	"github.com/golang/mock/gomock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -79,21 +83,21 @@ var _ = Describe("cleaner", func() {
 		})
 
 		It("should do nothing if deletePVCs is false", func() {
-			err := cleanupStatefulSet(ctx, c, s, sts, false)
+			err := cleanupStatefulSet(ctx, c, s, sts, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should do nothing if conversion to appsv1.StatefulSet fails", func() {
 			s = runtime.NewScheme()
 
-			err := cleanupStatefulSet(ctx, c, s, sts, true)
+			err := cleanupStatefulSet(ctx, c, s, sts, true, nil)
 			Expect(err).To(MatchError(ContainSubstring("failed cleaning up PersistentVolumeClaims of StatefulSet: could not convert object to StatefulSet")))
 		})
 
 		It("should do nothing if .spec.volumeClaimTemplate is not set", func() {
 			sts.Spec.VolumeClaimTemplates = nil
 
-			err := cleanupStatefulSet(ctx, c, s, sts, true)
+			err := cleanupStatefulSet(ctx, c, s, sts, true, nil)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -105,7 +109,7 @@ var _ = Describe("cleaner", func() {
 					return fakeErr
 				})
 
-			err := cleanupStatefulSet(ctx, c, s, sts, true)
+			err := cleanupStatefulSet(ctx, c, s, sts, true, nil)
 			Expect(err).To(MatchError(ContainSubstring(fakeErr.Error())))
 		})
 
@@ -115,7 +119,7 @@ var _ = Describe("cleaner", func() {
 					return nil
 				})
 
-			err := cleanupStatefulSet(ctx, c, s, sts, true)
+			err := cleanupStatefulSet(ctx, c, s, sts, true, nil)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -142,7 +146,7 @@ var _ = Describe("cleaner", func() {
 					}),
 			)
 
-			err := cleanupStatefulSet(ctx, c, s, sts, true)
+			err := cleanupStatefulSet(ctx, c, s, sts, true, nil)
 			Expect(err).To(MatchError(ContainSubstring(fakeErr.Error())))
 		})
 
@@ -167,8 +171,64 @@ var _ = Describe("cleaner", func() {
 					}),
 			)
 
-			err := cleanupStatefulSet(ctx, c, s, sts, true)
+			err := cleanupStatefulSet(ctx, c, s, sts, true, nil)
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should record the deletion of all PVCs of the StatefulSet in the audit log", func() {
+			gomock.InOrder(
+				c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaimList{}), client.InNamespace(sts.Namespace), client.MatchingLabels(sts.Spec.Selector.MatchLabels)).
+					DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+						list.(*corev1.PersistentVolumeClaimList).Items = []corev1.PersistentVolumeClaim{
+							{ObjectMeta: metav1.ObjectMeta{Namespace: sts.Namespace, Name: "pvc-foo-0"}},
+						}
+						return nil
+					}),
+				c.EXPECT().DeleteAllOf(ctx, gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaim{}), client.InNamespace(sts.Namespace), client.MatchingLabels(sts.Spec.Selector.MatchLabels)),
+			)
+
+			sink := &fakeAuditSink{}
+			err := cleanupStatefulSet(ctx, c, s, sts, true, audit.NewRecorder(sink, nil, client.ObjectKey{Namespace: "garden", Name: "mr"}, "1234"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sink.entries).To(HaveLen(1))
+			Expect(sink.entries[0].Operation).To(Equal(audit.OperationDelete))
+			Expect(sink.entries[0].APIVersion).To(Equal("v1"))
+			Expect(sink.entries[0].Kind).To(Equal("PersistentVolumeClaim"))
+			Expect(sink.entries[0].Namespace).To(Equal(sts.Namespace))
+			Expect(sink.entries[0].Name).To(Equal("pvc-foo-0"))
+			Expect(sink.entries[0].ManagedResource).To(Equal("garden/mr"))
+		})
+	})
+
+	Describe("#DeletionConfirmed", func() {
+		newObject := func(apiVersion, kind string, annotations map[string]string) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(apiVersion)
+			obj.SetKind(kind)
+			obj.SetName("foo")
+			obj.SetAnnotations(annotations)
+			return obj
+		}
+
+		DescribeTable("should return the expected result",
+			func(obj *unstructured.Unstructured, expected bool) {
+				Expect(DeletionConfirmed(obj)).To(Equal(expected))
+			},
+			Entry("deployment", newObject("apps/v1", "Deployment", nil), true),
+			Entry("unconfirmed custom resource definition", newObject("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", nil), false),
+			Entry("unconfirmed persistent volume claim", newObject("v1", "PersistentVolumeClaim", nil), false),
+			Entry("unconfirmed namespace", newObject("v1", "Namespace", map[string]string{resourcesv1alpha1.ConfirmationDeletion: "false"}), false),
+			Entry("confirmed custom resource definition", newObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", map[string]string{resourcesv1alpha1.ConfirmationDeletion: "true"}), true),
+			Entry("confirmed persistent volume claim", newObject("v1", "PersistentVolumeClaim", map[string]string{resourcesv1alpha1.ConfirmationDeletion: "true"}), true),
+			Entry("confirmed namespace", newObject("v1", "Namespace", map[string]string{resourcesv1alpha1.ConfirmationDeletion: "true"}), true),
+		)
 	})
 })
+
+type fakeAuditSink struct {
+	entries []audit.Entry
+}
+
+func (f *fakeAuditSink) Record(entry audit.Entry) {
+	f.entries = append(f.entries, entry)
+}
@@ -20,17 +20,26 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
 	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+	"github.com/gardener/gardener-resource-manager/pkg/audit"
 	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	"github.com/gardener/gardener-resource-manager/pkg/metrics"
+	"github.com/gardener/gardener-resource-manager/pkg/tracing"
+	"github.com/gardener/gardener-resource-manager/pkg/update"
 
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	hvpav1alpha1 "github.com/gardener/hvpa-controller/api/v1alpha1"
 	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-multierror"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -41,11 +50,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -57,6 +68,17 @@ const (
 	// FinalizerName is the finalizer base name that is injected into ManagedResources.
 	// The concrete finalizer is finally containing this base name and the resource class.
 	FinalizerName = "resources.gardener.cloud/gardener-resource-manager"
+
+	// DefaultReconcileTimeout is the default maximum duration of a single reconciliation of a ManagedResource.
+	DefaultReconcileTimeout = 10 * time.Minute
+
+	// timedOutStatusUpdateTimeout is the maximum duration of the status update of a ManagedResource whose
+	// reconciliation timed out.
+	timedOutStatusUpdateTimeout = 30 * time.Second
+
+	// pruneAfterHealthyPollInterval is the interval in which ManagedResources with objects waiting for the remaining
+	// ones to become healthy before they are pruned are reconciled.
+	pruneAfterHealthyPollInterval = 10 * time.Second
 )
 
 var (
@@ -73,54 +95,272 @@ type Reconciler struct {
 	targetClient     client.Client
 	targetRESTMapper *restmapper.DeferredDiscoveryRESTMapper
 	targetScheme     *runtime.Scheme
+	targetCaps       *Capabilities
+
+	upgradeDeprecatedAPIs bool
+	strictDecoding        bool
+	duplicateObjects      DuplicateObjectPolicy
+
+	class                *ClassFilter
+	clusterID            string
+	alwaysUpdate         bool
+	syncPeriod           time.Duration
+	maxConcurrentApplies int
+	keepObjectsTTL       time.Duration
+	pruneSkipKinds       map[schema.GroupKind]struct{}
+	pruneGracePeriod     time.Duration
+	deletionSelector     labels.Selector
+	applyPolicy          *ApplyPolicy
+
+	stripFinalizers        sets.String
+	stripFinalizersTimeout time.Duration
+	waitForReadyTimeout    time.Duration
+	reconcileTimeout       time.Duration
+
+	auditSink           audit.Sink
+	eventRecorder       record.EventRecorder
+	targetEventRecorder record.EventRecorder
+	statusDebouncer     *StatusDebouncer
+	decodeCache         *DecodeCache
+}
 
-	class        *ClassFilter
-	alwaysUpdate bool
-	syncPeriod   time.Duration
+// ReconcilerOptions configures the `Reconciler`.
+type ReconcilerOptions struct {
+	// TargetRESTMapper maps the kinds of the managed objects to resources of the target cluster.
+	TargetRESTMapper *restmapper.DeferredDiscoveryRESTMapper
+	// TargetScheme is used to decode the managed objects and to convert them to typed objects.
+	TargetScheme *runtime.Scheme
+	// TargetCapabilities are the capabilities of the target cluster. Objects annotated with requirements on the target
+	// cluster are only applied if they are satisfied (all objects are applied if nil).
+	TargetCapabilities *Capabilities
+	// UpgradeDeprecatedAPIs applies objects of deprecated API versions in the newest version of their kind served by
+	// the target cluster.
+	UpgradeDeprecatedAPIs bool
+	// StrictDecoding fails decoding objects of kinds known to the target scheme if they contain unknown fields.
+	StrictDecoding bool
+	// DuplicateObjects defines how ManagedResources containing the same object multiple times are handled (they fail
+	// unless it is Warn).
+	DuplicateObjects DuplicateObjectPolicy
+	// Class filters the ManagedResources which are handled by the Reconciler.
+	Class *ClassFilter
+	// ClusterID is part of the origin annotation of the managed objects (may be empty).
+	ClusterID string
+	// AlwaysUpdate updates the managed objects even if they did not change.
+	AlwaysUpdate bool
+	// SyncPeriod is the duration after which ManagedResources are reconciled again.
+	SyncPeriod time.Duration
+	// MaxConcurrentApplies is the maximum number of objects of a ManagedResource applied in parallel.
+	MaxConcurrentApplies int
+	// KeepObjectsTTL is the duration after which the objects of ManagedResources deleted with
+	// `.spec.keepObjects=true` expire (they are kept forever if it is not positive).
+	KeepObjectsTTL time.Duration
+	// PruneSkipKinds are the kinds of objects which are released instead of deleted when they are removed from a
+	// ManagedResource, in addition to the kinds listed in its `.spec.prune.skipKinds`.
+	PruneSkipKinds []schema.GroupKind
+	// PruneGracePeriod is the duration after which removed objects are deleted (unless overridden by
+	// `.spec.prune.gracePeriod`).
+	PruneGracePeriod time.Duration
+	// DeletionSelector restricts the objects which are deleted (all if nil).
+	DeletionSelector labels.Selector
+	// ApplyPolicy is the policy ManagedResources have to comply with to be applied and pruned (may be nil).
+	ApplyPolicy *ApplyPolicy
+	// StripFinalizers are the finalizers removed from the objects of deleted ManagedResources whose deletion has been
+	// blocked for longer than StripFinalizersTimeout.
+	StripFinalizers        []string
+	StripFinalizersTimeout time.Duration
+	// WaitForReadyTimeout is the maximum duration readiness gates block the objects following them.
+	WaitForReadyTimeout time.Duration
+	// ReconcileTimeout is the duration after which reconciliations are aborted (unless overridden by the reconcile
+	// timeout annotation of the ManagedResource, not bounded if 0).
+	ReconcileTimeout time.Duration
+	// AuditSink records all mutations performed in the target cluster (may be nil).
+	AuditSink audit.Sink
+	// EventRecorder records events concerning the ManagedResources (may be nil).
+	EventRecorder record.EventRecorder
+	// TargetEventRecorder records events on the mutated objects in the target cluster (may be nil).
+	TargetEventRecorder record.EventRecorder
+	// StatusDebouncer defers intermediate conditions of the ManagedResources.
+	StatusDebouncer *StatusDebouncer
+	// DecodeCache caches the objects decoded from the secrets of ManagedResources (may be nil).
+	DecodeCache *DecodeCache
 }
 
-// NewReconciler creates a new reconciler with the given target client.
-func NewReconciler(ctx context.Context, log logr.Logger, c, targetClient client.Client, targetRESTMapper *restmapper.DeferredDiscoveryRESTMapper, targetScheme *runtime.Scheme, class *ClassFilter, alwaysUpdate bool, syncPeriod time.Duration) *Reconciler {
-	return &Reconciler{ctx, log, c, targetClient, targetRESTMapper, targetScheme, class, alwaysUpdate, syncPeriod}
+// NewReconciler creates a new reconciler applying the objects of ManagedResources with the given target client.
+func NewReconciler(ctx context.Context, log logr.Logger, c, targetClient client.Client, options ReconcilerOptions) *Reconciler {
+	skipKinds := make(map[schema.GroupKind]struct{}, len(options.PruneSkipKinds))
+	for _, groupKind := range options.PruneSkipKinds {
+		skipKinds[groupKind] = struct{}{}
+	}
+	return &Reconciler{
+		ctx:                    ctx,
+		log:                    log,
+		client:                 c,
+		targetClient:           targetClient,
+		targetRESTMapper:       options.TargetRESTMapper,
+		targetScheme:           options.TargetScheme,
+		targetCaps:             options.TargetCapabilities,
+		upgradeDeprecatedAPIs:  options.UpgradeDeprecatedAPIs,
+		strictDecoding:         options.StrictDecoding,
+		duplicateObjects:       options.DuplicateObjects,
+		class:                  options.Class,
+		clusterID:              options.ClusterID,
+		alwaysUpdate:           options.AlwaysUpdate,
+		syncPeriod:             options.SyncPeriod,
+		maxConcurrentApplies:   options.MaxConcurrentApplies,
+		keepObjectsTTL:         options.KeepObjectsTTL,
+		pruneSkipKinds:         skipKinds,
+		pruneGracePeriod:       options.PruneGracePeriod,
+		deletionSelector:       options.DeletionSelector,
+		applyPolicy:            options.ApplyPolicy,
+		stripFinalizers:        sets.NewString(options.StripFinalizers...),
+		stripFinalizersTimeout: options.StripFinalizersTimeout,
+		waitForReadyTimeout:    options.WaitForReadyTimeout,
+		reconcileTimeout:       options.ReconcileTimeout,
+		auditSink:              options.AuditSink,
+		eventRecorder:          options.EventRecorder,
+		targetEventRecorder:    options.TargetEventRecorder,
+		statusDebouncer:        options.StatusDebouncer,
+		decodeCache:            options.DecodeCache,
+	}
 }
 
 // Reconcile implements `reconcile.Reconciler`.
 func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	log := r.log.WithValues("object", req)
+	ctx, reconcileID := utils.WithReconcileID(r.ctx)
+	log := r.log.WithValues("object", req, utils.LogKeyReconcileID, reconcileID)
+
+	ctx, span := tracing.Tracer().Start(ctx, "reconcile ManagedResource", trace.WithAttributes(
+		append(tracing.ObjectAttributes(req.Namespace, req.Name), label.String(utils.LogKeyReconcileID, reconcileID))...,
+	))
+	defer span.End()
 
 	mr := &resourcesv1alpha1.ManagedResource{}
-	if err := r.client.Get(r.ctx, req.NamespacedName, mr); err != nil {
+	if err := r.client.Get(ctx, req.NamespacedName, mr); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Stopping reconciliation of ManagedResource, as it has been deleted")
+			metrics.ForgetManagedResource(req.Namespace, req.Name)
+			r.decodeCache.Forget(req.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, fmt.Errorf("could not fetch ManagedResource: %+v", err)
 	}
 
+	// A single ManagedResource whose target cluster does not respond must not occupy a worker indefinitely.
+	timeout, err := resourcesv1alpha1helper.ReconcileTimeout(mr, r.reconcileTimeout)
+	if err != nil {
+		log.Error(err, "Falling back to the default reconcile timeout", "timeout", timeout.String())
+	}
+	reconcileCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		reconcileCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := r.reconcileOrDelete(reconcileCtx, mr, log)
+	if err != nil && reconcileCtx.Err() == context.DeadlineExceeded {
+		return r.reconcileTimedOut(ctx, mr, log, timeout, err)
+	}
+	return result, err
+}
+
+// reconcileOrDelete applies or deletes the objects of the given ManagedResource, or hands it over, depending on its
+// state and responsibility.
+func (r *Reconciler) reconcileOrDelete(ctx context.Context, mr *resourcesv1alpha1.ManagedResource, log logr.Logger) (ctrl.Result, error) {
 	action, responsible := r.class.Active(mr)
 	log.Info(fmt.Sprintf("reconcile: action required: %t, responsible: %t", action, responsible))
 
-	// If the object should be deleted or the responsibility changed
-	// the actual deployments have to be deleted
-	if mr.DeletionTimestamp != nil || (action && !responsible) {
-		return r.delete(mr, log)
+	// The objects of ignored ManagedResources are neither applied nor deleted.
+	if resourcesv1alpha1helper.IsIgnored(mr) && (action || responsible) {
+		return r.reconcileIgnored(ctx, mr, log, mr.DeletionTimestamp != nil || !responsible)
+	}
+
+	// If the responsibility changed, the ManagedResource is handed over to the responsible controller without deleting
+	// the actual deployments. They are only deleted if the ManagedResource is deleted before it has been taken over.
+	if action && !responsible && (mr.DeletionTimestamp == nil || takenOver(r.class, mr)) {
+		return r.handOver(ctx, mr, log)
+	}
+
+	// If the object should be deleted the actual deployments have to be deleted
+	if mr.DeletionTimestamp != nil {
+		return r.delete(ctx, mr, log)
+	}
+
+	// If the responsibility changed, the responsible controller takes over the ManagedResource by adding its finalizer
+	// while reconciling it, even if the previous controller has not yet released it.
+	return r.reconcile(ctx, mr, log)
+}
+
+// reconcileTimedOut explains in the conditions of the given ManagedResource that its reconciliation has been aborted
+// after the given timeout. The error is returned, so that the ManagedResource is retried with backoff.
+func (r *Reconciler) reconcileTimedOut(ctx context.Context, mr *resourcesv1alpha1.ManagedResource, log logr.Logger, timeout time.Duration, err error) (ctrl.Result, error) {
+	log.Info("Aborted reconciliation of ManagedResource, as it did not finish in time", "timeout", timeout.String())
+
+	// the context of the reconciliation has expired already
+	statusCtx, cancel := context.WithTimeout(ctx, timedOutStatusUpdateTimeout)
+	defer cancel()
+
+	msg := fmt.Sprintf("The reconciliation did not finish within %s: %+v", timeout, err)
+	conditionResourcesApplied := resourcesv1alpha1helper.GetOrInitCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
+	conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionReconcileTimedOut, msg)
+	if err := tryUpdateManagedResourceConditions(statusCtx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
+		log.Error(err, "Could not update the ManagedResource status")
+	}
+
+	return ctrl.Result{}, fmt.Errorf("reconciliation did not finish within %s: %+v", timeout, err)
+}
+
+// reconcileIgnored reflects the ignored state in the conditions of the given ManagedResource. If it is released (i.e.
+// deleted or not handled by this resource class anymore), its finalizers are removed without deleting its objects.
+func (r *Reconciler) reconcileIgnored(ctx context.Context, mr *resourcesv1alpha1.ManagedResource, log logr.Logger, released bool) (ctrl.Result, error) {
+	if released {
+		log.Info("Removing finalizer without deleting the resources, as the ManagedResource is ignored")
+		for _, finalizer := range sets.NewString(mr.Finalizers...).List() {
+			if r.class.OwnsFinalizer(finalizer) {
+				if err := utils.DeleteFinalizer(ctx, r.client, finalizer, mr); err != nil {
+					return ctrl.Result{}, fmt.Errorf("error removing finalizer from ManagedResource: %+v", err)
+				}
+			}
+		}
+		return ctrl.Result{}, nil
 	}
 
-	// If the deletion after a change of responsibility is still
-	// pending, the handling of the object by the responsible controller
-	// must be delayed, until the deletion is finished.
-	if responsible && !action {
-		return ctrl.Result{Requeue: true}, nil
+	log.Info("Skipping reconciliation of ManagedResource, as it is ignored")
+	var (
+		msg                       = fmt.Sprintf("The ManagedResource is ignored as it is annotated with %s=true.", resourcesv1alpha1.Ignore)
+		conditionResourcesApplied = resourcesv1alpha1helper.GetOrInitCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
+		conditionResourcesHealthy = resourcesv1alpha1helper.GetOrInitCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesHealthy)
+	)
+	conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionUnknown, resourcesv1alpha1.ConditionManagedResourceIgnored, msg)
+	conditionResourcesHealthy = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesHealthy, resourcesv1alpha1.ConditionUnknown, resourcesv1alpha1.ConditionManagedResourceIgnored, msg)
+	if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied, conditionResourcesHealthy)...); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 	}
-	return r.reconcile(mr, log)
+
+	// the ManagedResource is reconciled again once the annotation is removed
+	return ctrl.Result{}, nil
 }
 
-func (r *Reconciler) reconcile(mr *resourcesv1alpha1.ManagedResource, log logr.Logger) (ctrl.Result, error) {
+func (r *Reconciler) reconcile(ctx context.Context, mr *resourcesv1alpha1.ManagedResource, log logr.Logger) (ctrl.Result, error) {
 	log.Info("Starting to reconcile ManagedResource")
 
-	if err := utils.EnsureFinalizer(r.ctx, r.client, r.class.FinalizerName(), mr); err != nil {
+	finalizer := r.class.FinalizerNameFor(mr)
+	if err := utils.EnsureFinalizer(ctx, r.client, finalizer, mr); err != nil {
 		return reconcile.Result{}, err
 	}
+	// the class might have changed to another class of this instance, hence remove the finalizers of the other classes
+	for _, f := range sets.NewString(mr.Finalizers...).List() {
+		if f != finalizer && r.class.OwnsFinalizer(f) {
+			if err := utils.DeleteFinalizer(ctx, r.client, f, mr); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
+	forceApply := mr.Annotations[v1beta1constants.GardenerOperation] == resourcesv1alpha1.OperationForceApply
+	if forceApply {
+		log.Info("Forcing the update of all resources as requested by the " + v1beta1constants.GardenerOperation + " annotation")
+	}
 
 	var (
 		newResourcesObjects          []object
@@ -129,10 +369,17 @@ func (r *Reconciler) reconcile(mr *resourcesv1alpha1.ManagedResource, log logr.L
 		equivalences           = NewEquivalences(mr.Spec.Equivalences...)
 		existingResourcesIndex = NewObjectIndex(mr.Status.Resources, equivalences)
 
-		forceOverwriteLabels      bool
-		forceOverwriteAnnotations bool
+		forceOverwriteLabels          bool
+		forceOverwriteAnnotations     bool
+		forceOverwriteOwnerReferences bool
+		forceOverwriteFinalizers      bool
 
 		decodingErrors []*decodingError
+		// keepOldResources is set if some of the new resources cannot be decoded and the last applied resources are
+		// unknown, the resources which could be decoded are applied and none of the old resources are deleted then
+		keepOldResources bool
+
+		bundleSize, largestSecretSize int
 	)
 
 	if v := mr.Spec.ForceOverwriteLabels; v != nil {
@@ -141,127 +388,235 @@ func (r *Reconciler) reconcile(mr *resourcesv1alpha1.ManagedResource, log logr.L
 	if v := mr.Spec.ForceOverwriteAnnotations; v != nil {
 		forceOverwriteAnnotations = *v
 	}
+	if v := mr.Spec.ForceOverwriteOwnerReferences; v != nil {
+		forceOverwriteOwnerReferences = *v
+	}
+	if v := mr.Spec.ForceOverwriteFinalizers; v != nil {
+		forceOverwriteFinalizers = *v
+	}
 
 	// Initialize condition based on the current status.
 	conditionResourcesApplied := resourcesv1alpha1helper.GetOrInitCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
 
+	decodeCtx, decodeSpan := tracing.Tracer().Start(ctx, "decode resources")
+	secrets := make([]*corev1.Secret, 0, len(mr.Spec.SecretRefs))
 	for _, ref := range mr.Spec.SecretRefs {
 		secret := &corev1.Secret{}
-		if err := r.client.Get(r.ctx, client.ObjectKey{Namespace: mr.Namespace, Name: ref.Name}, secret); err != nil {
+		if err := r.client.Get(decodeCtx, client.ObjectKey{Namespace: mr.Namespace, Name: ref.Name}, secret); err != nil {
+			tracing.EndSpan(decodeCtx, decodeSpan, err)
+
 			conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, "CannotReadSecret", err.Error())
-			if err := tryUpdateManagedResourceConditions(r.ctx, r.client, mr, conditionResourcesApplied); err != nil {
+			if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
 				return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v ", err)
 			}
 
 			return reconcile.Result{}, fmt.Errorf("could not read secret '%s': %+v", secret.Name, err)
 		}
 
-		for key, value := range secret.Data {
-			var (
-				decoder    = yaml.NewYAMLOrJSONDecoder(bytes.NewReader(value), 1024)
-				decodedObj map[string]interface{}
-			)
+		secretSize := 0
+		for _, value := range secret.Data {
+			secretSize += len(value)
+		}
+		bundleSize += secretSize
+		if secretSize > largestSecretSize {
+			largestSecretSize = secretSize
+		}
 
-			for i := 0; true; i++ {
-				err := decoder.Decode(&decodedObj)
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					decodingError := &decodingError{
-						err:               err,
-						secret:            fmt.Sprintf("%s/%s", secret.Namespace, secret.Name),
-						secretKey:         key,
-						objectIndexInFile: i,
-					}
-					decodingErrors = append(decodingErrors, decodingError)
-					log.Error(decodingError.err, decodingError.StringShort())
-					continue
-				}
+		secrets = append(secrets, secret)
+	}
 
-				if decodedObj == nil {
-					continue
+	// The decoded objects only depend on the data of the secrets, hence they are decoded again only if it changed.
+	var (
+		mrKey               = client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}
+		checksum            = checksumOfSecrets(secrets)
+		decodedObjs, cached = r.decodeCache.Get(mrKey, checksum)
+	)
+	if !cached || forceApply {
+		var (
+			duplicates []*duplicateObject
+			complete   bool
+		)
+		decodedObjs, decodingErrors, duplicates, complete = decodeSecrets(log, r.targetRESTMapper, r.strictDecodingScheme(), secrets)
+		if complete {
+			r.decodeCache.Set(mrKey, checksum, decodedObjs)
+		}
+
+		// Which of the duplicates wins depends on the order of the secrets and their keys, hence ManagedResources
+		// containing duplicates are not applied partially either (unless configured otherwise).
+		if len(duplicates) > 0 {
+			message := fmt.Sprintf("The resources contain duplicate objects: %s", duplicateObjectsMessage(duplicates))
+			if r.duplicateObjects == DuplicateObjectPolicyWarn {
+				log.Info("Applying resources containing duplicate objects, the last occurrence of each of them wins", "duplicates", duplicateObjectsMessage(duplicates))
+				r.recordEvent(mr, corev1.EventTypeWarning, resourcesv1alpha1.ConditionDuplicateObjects, message)
+			} else {
+				decodeSpan.End()
+				log.Info("Not applying resources, as they contain duplicate objects", "duplicates", duplicateObjectsMessage(duplicates))
+
+				conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionDuplicateObjects, message)
+				if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
+					return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 				}
 
-				obj := &unstructured.Unstructured{Object: decodedObj}
+				// the ManagedResource is reconciled again once it or its secrets change
+				return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+			}
+		}
+	}
 
-				// look up scope of objects' kind to check, if we should default the namespace field
-				mapping, err := r.targetRESTMapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
-				if err != nil || mapping == nil {
-					// Don't reset RESTMapper in case of cache misses. Most probably indicates, that the corresponding CRD is not yet applied.
-					// CRD might be applied later as part of the ManagedResource reconciliation
-					log.Info(fmt.Sprintf("could not get rest mapping for %s '%s/%s': %v", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err),
-						"secret", fmt.Sprintf("%s/%s", secret.Namespace, secret.Name), "secretKey", key, "objectIndexInFile", i)
+	// The new resources are not applied partially if some of them cannot be decoded, as the objects which cannot be
+	// decoded would be pruned. Instead, the resources which have last been applied successfully are still applied.
+	// The cached resources are only applied if they are the ones recorded in the status, e.g. not if another instance
+	// applied newer resources in the meantime. Otherwise (e.g. after a restart or without decode cache), the resources
+	// which could be decoded are applied, but none of the old resources are deleted.
+	if len(decodingErrors) > 0 {
+		lastAppliedObjs, lastAppliedChecksum, ok := r.decodeCache.LastApplied(mrKey)
+		if ok && mr.Status.LastAppliedRevision != nil && mr.Status.LastAppliedRevision.Checksum == lastAppliedChecksum {
+			log.Info("Applying the last applied resources, as some of the new resources could not be decoded", "checksum", lastAppliedChecksum)
+			decodedObjs = lastAppliedObjs
+		} else {
+			log.Info("Applying the resources which could be decoded and keeping the old resources, as some of the new resources could not be decoded and the last applied resources are unknown")
+			keepOldResources = true
+		}
+	}
 
-					// default namespace on a best effort basis
-					if obj.GetKind() != "Namespace" && obj.GetNamespace() == "" {
-						obj.SetNamespace(metav1.NamespaceDefault)
-					}
-				} else {
-					if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-						// default namespace field to `default` in case of namespaced kinds
-						if obj.GetNamespace() == "" {
-							obj.SetNamespace(metav1.NamespaceDefault)
-						}
-					} else {
-						// unset namespace field in case of non-namespaced kinds
-						obj.SetNamespace("")
-					}
+	// Policy violations are not applied partially, as pruning the violating objects could delete objects which have
+	// been applied before the policy was configured.
+	if violations := r.applyPolicy.Violations(mr, decodedObjs); len(violations) > 0 {
+		decodeSpan.End()
+		log.Info("Not applying resources, as they violate the apply policy", "violations", violations)
+
+		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionPolicyViolated, fmt.Sprintf("The resources violate the apply policy: %s", strings.Join(violations, "; ")))
+		if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
+		}
+
+		// the ManagedResource is reconciled again once it or its secrets change
+		return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+	}
+
+	for _, obj := range decodedObjs {
+		// pre-delete hooks are only applied when the ManagedResource is deleted
+		if hookOf(obj) == resourcesv1alpha1.HookPreDelete {
+			continue
+		}
+
+		// objects not supported by the target cluster are skipped (and pruned if they have been applied before)
+		reason, err := r.targetCaps.unsupportedReason(obj)
+		if err == nil && reason == "" && r.upgradeDeprecatedAPIs {
+			obj, err = r.targetCaps.upgradeAPIVersion(log, obj)
+		}
+		if err != nil {
+			decodeSpan.End()
+
+			conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionApplyFailed, fmt.Sprintf("Could not check whether %s is supported by the target cluster: %+v", objectKeyFromUnstructured(obj), err))
+			if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
+				return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
+			}
+
+			return ctrl.Result{}, err
+		}
+		if reason != "" {
+			log.Info("Skipping object, as it is not supported by the target cluster", "object", objectKeyFromUnstructured(obj), "reason", reason)
+			continue
+		}
+
+		// the status is exported by the health controller, but invalid expressions are reported as early as possible
+		if expression, ok := obj.GetAnnotations()[resourcesv1alpha1.ExportStatus]; ok {
+			if _, err := parseExportStatus(expression); err != nil {
+				decodeSpan.End()
+
+				conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionApplyFailed, fmt.Sprintf("Could not apply %s: %v", objectKeyFromUnstructured(obj), err))
+				if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
+					return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 				}
 
-				var (
-					newObj = object{
-						obj:                       obj,
-						forceOverwriteLabels:      forceOverwriteLabels,
-						forceOverwriteAnnotations: forceOverwriteAnnotations,
-					}
-					objectReference = resourcesv1alpha1.ObjectReference{
-						ObjectReference: corev1.ObjectReference{
-							APIVersion: newObj.obj.GetAPIVersion(),
-							Kind:       newObj.obj.GetKind(),
-							Name:       newObj.obj.GetName(),
-							Namespace:  newObj.obj.GetNamespace(),
-						},
-						Labels:      mergeMaps(newObj.obj.GetLabels(), mr.Spec.InjectLabels),
-						Annotations: newObj.obj.GetAnnotations(),
-					}
-				)
+				// the ManagedResource is reconciled again once it or its secrets change
+				return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+			}
+		}
 
-				newObj.oldInformation, _ = existingResourcesIndex.Lookup(objectReference)
-				decodedObj = nil
+		// values are written and defaulted like the API server does, so that they do not cause updates of unchanged objects
+		obj = withDefaults(r.targetScheme, normalize(r.targetScheme, obj))
+
+		var (
+			newObj = object{
+				obj:                           obj,
+				forceOverwriteLabels:          forceOverwriteLabels,
+				forceOverwriteAnnotations:     forceOverwriteAnnotations,
+				forceOverwriteOwnerReferences: forceOverwriteOwnerReferences,
+				forceOverwriteFinalizers:      forceOverwriteFinalizers,
+			}
+			objectReference = newObjectReference(obj, mr.Spec.InjectLabels)
+		)
 
-				newResourcesObjects = append(newResourcesObjects, newObj)
-				newResourcesObjectReferences = append(newResourcesObjectReferences, objectReference)
+		newObj.oldInformation, _ = existingResourcesIndex.Lookup(objectReference)
+
+		newResourcesObjects = append(newResourcesObjects, newObj)
+		newResourcesObjectReferences = append(newResourcesObjectReferences, objectReference)
+	}
+	// the old resources which are not part of the new resources are kept in the status, so that they are still deleted
+	// once the new resources can be decoded
+	var keptPendingPrune []resourcesv1alpha1.ObjectReference
+	if keepOldResources {
+		for _, ref := range existingResourcesIndex.Objects() {
+			if existingResourcesIndex.Found(ref) {
+				continue
 			}
+			existingResourcesIndex.Lookup(ref)
+			if ref.PruneAfter != nil {
+				keptPendingPrune = append(keptPendingPrune, ref)
+				continue
+			}
+			newResourcesObjectReferences = append(newResourcesObjectReferences, ref)
 		}
 	}
+	decodeSpan.SetAttributes(label.Int("objects", len(newResourcesObjects)), label.Int("decodingErrors", len(decodingErrors)), label.Bool("cached", cached))
+	decodeSpan.End()
+
+	metrics.RecordBundle(mr.Namespace, mr.Name, bundleSize, largestSecretSize, len(newResourcesObjects))
 
 	// sort object references before updating status, to keep consistent ordering
 	// (otherwise, the order will be different on each update)
 	sortObjectReferences(newResourcesObjectReferences)
 
 	// invalidate conditions, if resources have been added/removed from the managed resource
-	if len(mr.Status.Resources) == 0 || !apiequality.Semantic.DeepEqual(mr.Status.Resources, newResourcesObjectReferences) {
+	// (objects pending prune have already been removed before)
+	resourcesChanged := len(mr.Status.Resources) == 0 || !apiequality.Semantic.DeepEqual(withoutPendingPrune(mr.Status.Resources), newResourcesObjectReferences)
+	if resourcesChanged {
 		conditionResourcesHealthy := resourcesv1alpha1helper.GetOrInitCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesHealthy)
 		conditionResourcesHealthy = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesHealthy, resourcesv1alpha1.ConditionUnknown,
 			resourcesv1alpha1.ConditionHealthChecksPending, "The health checks have not yet been executed for the current set of resources.")
 
 		reason := resourcesv1alpha1.ConditionApplyProgressing
 		msg := "The resources are currently being reconciled."
+		var errorClass resourcesv1alpha1.ErrorClass
 		switch conditionResourcesApplied.Reason {
 		case resourcesv1alpha1.ConditionApplyFailed, resourcesv1alpha1.ConditionDeletionFailed, resourcesv1alpha1.ConditionDeletionPending:
 			// keep condition reason and message if last reconciliation failed
 			reason = conditionResourcesApplied.Reason
 			msg = conditionResourcesApplied.Message
+			errorClass = conditionResourcesApplied.ErrorClass
 		}
 		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionProgressing, reason, msg)
+		conditionResourcesApplied.ErrorClass = errorClass
 
-		if err := tryUpdateManagedResourceConditions(r.ctx, r.client, mr, conditionResourcesHealthy, conditionResourcesApplied); err != nil {
+		// The progressing conditions are usually replaced within a few seconds, hence they are deferred to save a write.
+		if err := r.statusDebouncer.Defer(ctx, mr, conditionResourcesHealthy, conditionResourcesApplied); err != nil {
 			return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 		}
 	}
 
-	if deletionPending, err := r.cleanOldResources(existingResourcesIndex, mr); err != nil {
+	auditRecorder := audit.NewRecorder(r.auditSink, r.targetEventRecorder, client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}, utils.ReconcileIDFromContext(ctx))
+
+	waitForHealthy := pruneAfterHealthy(mr) && (resourcesChanged || !resourcesHealthy(mr))
+	pendingPrune, deletionPending, err := r.cleanOldResources(ctx, log, existingResourcesIndex, mr, cleanOptions{
+		skipKinds:      r.skippedPruneKinds(mr),
+		gracePeriod:    r.pruneGracePeriodOf(mr),
+		waitForHealthy: waitForHealthy,
+		auditRecorder:  auditRecorder,
+		reason:         "object is no longer part of the ManagedResource",
+	})
+	if err != nil {
 		var (
 			reason string
 			status resourcesv1alpha1.ConditionStatus
@@ -277,7 +632,7 @@ func (r *Reconciler) reconcile(mr *resourcesv1alpha1.ManagedResource, log logr.L
 		}
 
 		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, status, reason, err.Error())
-		if err := tryUpdateManagedResourceConditions(r.ctx, r.client, mr, conditionResourcesApplied); err != nil {
+		if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
 			return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 		}
 
@@ -288,37 +643,257 @@ func (r *Reconciler) reconcile(mr *resourcesv1alpha1.ManagedResource, log logr.L
 		}
 	}
 
-	if err := r.applyNewResources(newResourcesObjects, mr.Spec.InjectLabels, equivalences); err != nil {
+	// the objects are not applied without their owner, as the garbage collector would delete them immediately
+	owner, err := r.ownerOf(ctx, mr)
+	if err != nil {
 		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionApplyFailed, err.Error())
-		if err := tryUpdateManagedResourceConditions(r.ctx, r.client, mr, conditionResourcesApplied); err != nil {
+		if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
 			return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 		}
 
-		return ctrl.Result{}, fmt.Errorf("could not apply all new resources: %+v", err)
+		return ctrl.Result{}, err
 	}
 
-	if len(decodingErrors) != 0 {
-		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionDecodingFailed, fmt.Sprintf("Could not decode all new resources: %v", decodingErrors))
-	} else {
+	// objects pending prune are kept in the status until they are deleted
+	statusResources := append(append(newResourcesObjectReferences, pendingPrune...), keptPendingPrune...)
+	sortObjectReferences(statusResources)
+
+	var (
+		origin                                        = resourcesv1alpha1helper.Origin(r.clusterID, client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name})
+		regularObjects, preApplyHooks, postApplyHooks = groupByHook(newResourcesObjects)
+	)
+
+	// hooks are applied and awaited before (or after) the regular objects
+	for _, step := range []struct {
+		hook    string
+		objects []object
+	}{
+		{hook: resourcesv1alpha1.HookPreApply, objects: preApplyHooks},
+		{objects: regularObjects},
+		{hook: resourcesv1alpha1.HookPostApply, objects: postApplyHooks},
+	} {
+		if step.hook != "" && len(step.objects) == 0 {
+			continue
+		}
+
+		var pending, failed []string
+		err := r.applyNewResources(ctx, log, step.objects, applyOptions{
+			class:          ResourceClassOf(mr),
+			origin:         origin,
+			owner:          owner,
+			labelsToInject: mr.Spec.InjectLabels,
+			equivalences:   equivalences,
+			alwaysUpdate:   r.alwaysUpdate || forceApply,
+			auditRecorder:  auditRecorder,
+		})
+		if err == nil && step.hook != "" {
+			pending, failed, err = r.checkHooks(ctx, step.objects, false)
+		}
+		if err != nil {
+			errorClass := ErrorClassOf(err)
+			conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionApplyFailed, err.Error())
+			conditionResourcesApplied.ErrorClass = errorClass
+			if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
+				return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
+			}
+
+			if !retriable(errorClass) {
+				// the ManagedResource is reconciled again once it or its secrets change
+				log.Error(err, "Could not apply all new resources, waiting for changes", "errorClass", errorClass)
+				return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("could not apply all new resources: %+v", err)
+		}
+
+		if done, result, err := r.reconcileHooks(ctx, log, mr, step.hook, pending, failed, conditionResourcesApplied, statusResources); !done {
+			return result, err
+		}
+	}
+
+	var lastAppliedRevision *resourcesv1alpha1.AppliedRevision
+	switch {
+	case keepOldResources:
+		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionDecodingFailed, fmt.Sprintf("Could not decode all new resources, the others are applied and no old resources are deleted: %v", decodingErrors))
+	case len(decodingErrors) != 0:
+		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionDecodingFailed, fmt.Sprintf("Could not decode all new resources, the last applied resources are still applied: %v", decodingErrors))
+	default:
 		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionTrue, resourcesv1alpha1.ConditionApplySucceeded, "All resources are applied.")
+		r.decodeCache.SetApplied(mrKey, checksum)
+		lastAppliedRevision = appliedRevisionOf(secrets, checksum)
 	}
 
-	if err := tryUpdateManagedResourceStatus(r.ctx, r.client, mr, newResourcesObjectReferences, conditionResourcesApplied); err != nil {
+	statusCtx, statusSpan := tracing.Tracer().Start(ctx, "update status")
+	err = tryUpdateManagedResourceStatus(statusCtx, r.client, mr, statusResources, lastAppliedRevision, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...)
+	tracing.EndSpan(statusCtx, statusSpan, err)
+	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 	}
 
+	if forceApply {
+		if err := update.Try(ctx, r.client, mr, func() error {
+			delete(mr.Annotations, v1beta1constants.GardenerOperation)
+			return nil
+		}, update.Options{Strategy: update.StrategyMergePatch}); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("could not remove the %s annotation: %+v", v1beta1constants.GardenerOperation, err)
+		}
+	}
+
 	log.Info("Finished to reconcile ManagedResource")
-	return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+	requeueAfter := r.syncPeriod
+	if mr.Spec.ResyncPeriod != nil && mr.Spec.ResyncPeriod.Duration > 0 {
+		requeueAfter = mr.Spec.ResyncPeriod.Duration
+	}
+	// reconcile again as soon as the first object pending prune can be deleted
+	for _, ref := range pendingPrune {
+		d := time.Until(ref.PruneAfter.Time) + time.Second
+		if waitForHealthy && d < pruneAfterHealthyPollInterval {
+			// the health of the remaining objects is checked by the health controller
+			d = pruneAfterHealthyPollInterval
+		}
+		if d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// decodeSecrets decodes the objects contained in the data of the given secrets, and defaults or unsets their namespace
+// depending on the scope of their kind as known by the given mapper. If a strict scheme is given, objects of kinds
+// known to it fail to decode if they contain unknown fields. Objects which are contained multiple times are returned
+// in all of their occurrences and additionally as duplicates. The returned objects are complete (i.e. they can be
+// cached) if all of them could be decoded, the scope of all their kinds is known and none of them is a duplicate.
+func decodeSecrets(log logr.Logger, mapper meta.RESTMapper, strictScheme *runtime.Scheme, secrets []*corev1.Secret) ([]*unstructured.Unstructured, []*decodingError, []*duplicateObject, bool) {
+	var (
+		objs           []*unstructured.Unstructured
+		decodingErrors []*decodingError
+		complete       = true
+
+		objectKeys    []string
+		objectSources = map[string][]objectSource{}
+	)
+
+	for _, secret := range secrets {
+		// the keys are decoded in a stable order, as the order of the objects matters for readiness gates
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var (
+				decoder    = yaml.NewYAMLOrJSONDecoder(bytes.NewReader(secret.Data[key]), 1024)
+				decodedObj map[string]interface{}
+			)
+
+			for i := 0; true; i++ {
+				err := decoder.Decode(&decodedObj)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					decodingError := &decodingError{
+						err:               err,
+						secret:            fmt.Sprintf("%s/%s", secret.Namespace, secret.Name),
+						secretKey:         key,
+						objectIndexInFile: i,
+					}
+					decodingErrors = append(decodingErrors, decodingError)
+					log.Error(decodingError.err, decodingError.StringShort())
+					complete = false
+					continue
+				}
+
+				if decodedObj == nil {
+					continue
+				}
+
+				obj := &unstructured.Unstructured{Object: decodedObj}
+				decodedObj = nil
+
+				if err := checkUnknownFields(strictScheme, obj); err != nil {
+					decodingError := &decodingError{
+						err:               err,
+						secret:            fmt.Sprintf("%s/%s", secret.Namespace, secret.Name),
+						secretKey:         key,
+						objectIndexInFile: i,
+					}
+					decodingErrors = append(decodingErrors, decodingError)
+					log.Error(decodingError.err, decodingError.StringShort())
+					complete = false
+					continue
+				}
+
+				// look up scope of objects' kind to check, if we should default the namespace field
+				mapping, err := mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+				if err != nil || mapping == nil {
+					// Don't reset RESTMapper in case of cache misses. Most probably indicates, that the corresponding CRD is not yet applied.
+					// CRD might be applied later as part of the ManagedResource reconciliation
+					log.Info(fmt.Sprintf("could not get rest mapping for %s '%s/%s': %v", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err),
+						"secret", fmt.Sprintf("%s/%s", secret.Namespace, secret.Name), "secretKey", key, "objectIndexInFile", i)
+
+					// default namespace on a best effort basis
+					if obj.GetKind() != "Namespace" && obj.GetNamespace() == "" {
+						obj.SetNamespace(metav1.NamespaceDefault)
+					}
+					// the namespace may be defaulted differently once the scope is known
+					complete = false
+				} else {
+					if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+						// default namespace field to `default` in case of namespaced kinds
+						if obj.GetNamespace() == "" {
+							obj.SetNamespace(metav1.NamespaceDefault)
+						}
+					} else {
+						// unset namespace field in case of non-namespaced kinds
+						obj.SetNamespace("")
+					}
+				}
+
+				// objects with generateName get a name derived from their template
+				if err := setGeneratedName(obj); err != nil {
+					decodingError := &decodingError{
+						err:               err,
+						secret:            fmt.Sprintf("%s/%s", secret.Namespace, secret.Name),
+						secretKey:         key,
+						objectIndexInFile: i,
+					}
+					decodingErrors = append(decodingErrors, decodingError)
+					log.Error(decodingError.err, decodingError.StringShort())
+					complete = false
+					continue
+				}
+
+				objKey := objectKeyFromUnstructured(obj)
+				if _, ok := objectSources[objKey]; !ok {
+					objectKeys = append(objectKeys, objKey)
+				}
+				objectSources[objKey] = append(objectSources[objKey], objectSource{
+					secret:            fmt.Sprintf("%s/%s", secret.Namespace, secret.Name),
+					secretKey:         key,
+					objectIndexInFile: i,
+				})
+
+				objs = append(objs, obj)
+			}
+		}
+	}
+
+	duplicates := duplicateObjects(objectKeys, objectSources)
+	if len(duplicates) > 0 {
+		// duplicates are reported on every reconciliation, hence the objects are not cached
+		complete = false
+	}
+
+	return objs, decodingErrors, duplicates, complete
 }
 
-func (r *Reconciler) delete(mr *resourcesv1alpha1.ManagedResource, log logr.Logger) (ctrl.Result, error) {
+func (r *Reconciler) delete(ctx context.Context, mr *resourcesv1alpha1.ManagedResource, log logr.Logger) (ctrl.Result, error) {
 	log.Info("Starting to delete ManagedResource")
 
 	conditionResourcesApplied := resourcesv1alpha1helper.GetOrInitCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
 
 	if keepObjects := mr.Spec.KeepObjects; keepObjects == nil || !*keepObjects {
-		existingResourcesIndex := NewObjectIndex(mr.Status.Resources, nil)
-
 		msg := "The resources are currently being deleted."
 		switch conditionResourcesApplied.Reason {
 		case resourcesv1alpha1.ConditionDeletionPending, resourcesv1alpha1.ConditionDeletionFailed:
@@ -326,11 +901,25 @@ func (r *Reconciler) delete(mr *resourcesv1alpha1.ManagedResource, log logr.Logg
 			msg = conditionResourcesApplied.Message
 		}
 		conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionProgressing, resourcesv1alpha1.ConditionDeletionPending, msg)
-		if err := tryUpdateManagedResourceConditions(r.ctx, r.client, mr, conditionResourcesApplied); err != nil {
+		if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
 			return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 		}
 
-		if deletionPending, err := r.cleanOldResources(existingResourcesIndex, mr); err != nil {
+		auditRecorder := audit.NewRecorder(r.auditSink, r.targetEventRecorder, client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}, utils.ReconcileIDFromContext(ctx))
+
+		if done, result, err := r.reconcilePreDeleteHooks(ctx, log, mr, conditionResourcesApplied, auditRecorder); !done {
+			return result, err
+		}
+
+		// the index contains the pre-delete hooks as well, they are deleted with all other objects
+		existingResourcesIndex := NewObjectIndex(mr.Status.Resources, nil)
+		failFast := mr.Spec.DeletePolicy == resourcesv1alpha1.DeletePolicyFailFast
+		if _, deletionPending, err := r.cleanOldResources(ctx, log, existingResourcesIndex, mr, cleanOptions{
+			stripFinalizers: r.stripFinalizers,
+			failFast:        failFast,
+			auditRecorder:   auditRecorder,
+			reason:          "ManagedResource is deleted or not handled by this resource class anymore",
+		}); err != nil {
 			var (
 				reason string
 				status resourcesv1alpha1.ConditionStatus
@@ -346,7 +935,7 @@ func (r *Reconciler) delete(mr *resourcesv1alpha1.ManagedResource, log logr.Logg
 			}
 
 			conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, status, reason, err.Error())
-			if err := tryUpdateManagedResourceConditions(r.ctx, r.client, mr, conditionResourcesApplied); err != nil {
+			if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
 				return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
 			}
 
@@ -358,105 +947,202 @@ func (r *Reconciler) delete(mr *resourcesv1alpha1.ManagedResource, log logr.Logg
 		}
 	} else {
 		log.Info(fmt.Sprintf("Do not delete any resources of %s because .spec.keepObjects=true", mr.Name))
+
+		auditRecorder := audit.NewRecorder(r.auditSink, r.targetEventRecorder, client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}, utils.ReconcileIDFromContext(ctx))
+		if err := r.releaseResources(ctx, log, mr, auditRecorder); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	log.Info("All resources have been deleted, removing finalizers from ManagedResource")
 
-	if err := utils.DeleteFinalizer(r.ctx, r.client, r.class.FinalizerName(), mr); err != nil {
-		return reconcile.Result{}, fmt.Errorf("error removing finalizer from ManagedResource: %+v", err)
+	for _, finalizer := range sets.NewString(mr.Finalizers...).List() {
+		if r.class.OwnsFinalizer(finalizer) {
+			if err := utils.DeleteFinalizer(ctx, r.client, finalizer, mr); err != nil {
+				return reconcile.Result{}, fmt.Errorf("error removing finalizer from ManagedResource: %+v", err)
+			}
+		}
 	}
+	metrics.ForgetManagedResource(mr.Namespace, mr.Name)
 
 	log.Info("Finished to delete ManagedResource")
 	return ctrl.Result{}, nil
 }
 
-func (r *Reconciler) applyNewResources(newResourcesObjects []object, labelsToInject map[string]string, equivalences Equivalences) error {
+// applyOptions configures `applyNewResources`.
+type applyOptions struct {
+	// class and origin are set as origin label and annotation on the applied objects.
+	class, origin  string
+	owner          *owner
+	labelsToInject map[string]string
+	equivalences   Equivalences
+	alwaysUpdate   bool
+	auditRecorder  *audit.Recorder
+}
+
+func (r *Reconciler) applyNewResources(ctx context.Context, log logr.Logger, newResourcesObjects []object, opts applyOptions) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "apply resources", trace.WithAttributes(label.Int("objects", len(newResourcesObjects))))
+	defer func() { tracing.EndSpan(ctx, span, err) }()
+
 	var (
-		results   = make(chan error)
-		wg        sync.WaitGroup
 		errorList = &multierror.Error{
 			ErrorFormat: utils.NewErrorFormatFuncWithPrefix("Could not apply all new resources"),
 		}
+		workers                 = make(chan struct{}, r.maxConcurrentApplies)
+		noMatchErrorLock        sync.Mutex
 		encounteredNoMatchError = false
 	)
 
 	// get all HPA and HVPA targetRefs to check if we should prevent overwriting replicas and/or resource requirements.
 	// VPAs don't have to be checked, as they don't update the spec directly and only mutate Pods via a MutatingWebhook
 	// and therefore don't interfere with the resource manager.
-	horizontallyScaledObjects, verticallyScaledObjects, err := computeAllScaledObjectKeys(r.ctx, r.targetClient)
+	horizontallyScaledObjects, verticallyScaledObjects, err := computeAllScaledObjectKeys(ctx, r.targetClient)
 	if err != nil {
-		return fmt.Errorf("failed to compute all HPA and HVPA target ref object keys: %w", err)
+		return classifyError(fmt.Errorf("failed to compute all HPA and HVPA target ref object keys: %w", err))
 	}
 
-	for _, o := range newResourcesObjects {
-		wg.Add(1)
+	// Objects of later phases may depend on objects of earlier phases, so the phases are applied one after another.
+	// Errors don't stop the subsequent phases, the failed objects are retried with the next reconciliation anyway.
+	// Only waves ending with a readiness gate stop the subsequent waves if they could not be applied or the gate does
+	// not become ready.
+	for _, wave := range groupByApplyWave(newResourcesObjects) {
+		var (
+			results = make(chan error)
+			wg      sync.WaitGroup
+		)
 
-		go func(obj object) {
-			defer wg.Done()
+		for _, o := range wave {
+			if ignoreMode(o.obj.GetAnnotations()) {
+				log.Info("Skipping object as it is in mode "+resourcesv1alpha1.ModeIgnore, "resource", unstructuredToString(o.obj))
+				continue
+			}
 
-			var (
-				current            = obj.obj.DeepCopy()
-				resource           = unstructuredToString(obj.obj)
-				scaledHorizontally = isScaled(obj.obj, horizontallyScaledObjects, equivalences)
-				scaledVertically   = isScaled(obj.obj, verticallyScaledObjects, equivalences)
-			)
+			wg.Add(1)
 
-			r.log.Info("Applying", "resource", resource)
+			go func(obj object) {
+				defer wg.Done()
 
-			results <- retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-				if operationResult, err := utils.TypedCreateOrUpdate(r.ctx, r.targetClient, r.targetScheme, current, r.alwaysUpdate, func() error {
-					metadata, err := meta.Accessor(obj.obj)
-					if err != nil {
-						return fmt.Errorf("error getting metadata of object %q: %s", resource, err)
-					}
+				workers <- struct{}{}
+				defer func() { <-workers }()
 
-					// if the ignore annotation is set to false, do nothing (ignore the resource)
-					if ignore(metadata) {
-						annotations := current.GetAnnotations()
-						delete(annotations, descriptionAnnotation)
-						current.SetAnnotations(annotations)
+				var (
+					current            = obj.obj.DeepCopy()
+					resource           = unstructuredToString(obj.obj)
+					scaledHorizontally = isScaled(obj.obj, horizontallyScaledObjects, opts.equivalences)
+					scaledVertically   = isScaled(obj.obj, verticallyScaledObjects, opts.equivalences)
+				)
+
+				log.Info("Applying", "resource", resource)
+
+				objCtx, objSpan := tracing.Tracer().Start(ctx, "apply object", trace.WithAttributes(label.String("resource", resource)))
+
+				// update.Try is not used, as it only updates existing objects, whereas missing objects are created here and
+				// the result of the write decides about deleting objects on invalid updates and auditing
+				err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+					// existing is the state of the object before it is mutated, it is used for summarizing the changes of an update
+					var existing *unstructured.Unstructured
+
+					operationResult, err := utils.TypedCreateOrUpdate(objCtx, r.targetClient, r.targetScheme, current, opts.alwaysUpdate, func() error {
+						existing = current.DeepCopy()
+
+						metadata, err := meta.Accessor(obj.obj)
+						if err != nil {
+							return &PermanentManifestError{Err: fmt.Errorf("error getting metadata of object %q: %s", resource, err)}
+						}
+
+						// if the ignore annotation is set to false, do nothing (ignore the resource)
+						if ignore(metadata) {
+							annotations := current.GetAnnotations()
+							delete(annotations, descriptionAnnotation)
+							current.SetAnnotations(annotations)
+							return nil
+						}
+
+						if err := injectLabels(obj.obj, opts.labelsToInject); err != nil {
+							return &PermanentManifestError{Err: fmt.Errorf("error injecting labels into object %q: %s", resource, err)}
+						}
+
+						if err := merge(obj.obj, current, obj.forceOverwriteLabels, obj.oldInformation.Labels, obj.forceOverwriteAnnotations, obj.oldInformation.Annotations, obj.forceOverwriteOwnerReferences, obj.forceOverwriteFinalizers, scaledHorizontally, scaledVertically); err != nil {
+							return &PermanentManifestError{Err: err}
+						}
+
+						setOriginLabel(current, opts.class)
+						setOriginAnnotation(current, opts.origin)
+						opts.owner.setOn(current)
 						return nil
-					}
+					})
+					if err != nil {
+						if meta.IsNoMatchError(err) {
+							noMatchErrorLock.Lock()
+							encounteredNoMatchError = true
+							noMatchErrorLock.Unlock()
+						}
 
-					if err := injectLabels(obj.obj, labelsToInject); err != nil {
-						return fmt.Errorf("error injecting labels into object %q: %s", resource, err)
-					}
+						if apierrors.IsConflict(err) {
+							log.Info(fmt.Sprintf("conflict during apply of object %q: %s", resource, err))
+							// return conflict error directly, so that the update will be retried
+							return err
+						}
 
-					return merge(obj.obj, current, obj.forceOverwriteLabels, obj.oldInformation.Labels, obj.forceOverwriteAnnotations, obj.oldInformation.Annotations, scaledHorizontally, scaledVertically)
-				}); err != nil {
-					if meta.IsNoMatchError(err) {
-						encounteredNoMatchError = true
-					}
+						if apierrors.IsInvalid(err) && operationResult == controllerutil.OperationResultUpdated && deleteOnInvalidUpdate(current) && DeletionConfirmed(current) && r.inDeletionScope(existing) {
+							if preserveAvailability(current) {
+								if availabilityErr := r.checkAvailability(objCtx, current); availabilityErr != nil {
+									return fmt.Errorf("not deleting object %q after 'invalid' update error as %v (%s)", resource, availabilityErr, err)
+								}
+							}
+							if deleteErr := r.targetClient.Delete(objCtx, current); client.IgnoreNotFound(deleteErr) != nil {
+								return fmt.Errorf("error deleting object %q after 'invalid' update error: %s", resource, deleteErr)
+							}
+							opts.auditRecorder.Record(audit.OperationDelete, current,
+								"update was rejected as invalid and object is annotated with "+resourcesv1alpha1.DeleteOnInvalidUpdate, nil)
+							// return error directly, so that the create after delete will be retried
+							return fmt.Errorf("deleted object %q because of 'invalid' update error and 'delete-on-invalid-update' annotation on object (%s)", resource, err)
+						}
 
-					if apierrors.IsConflict(err) {
-						r.log.Info(fmt.Sprintf("conflict during apply of object %q: %s", resource, err))
-						// return conflict error directly, so that the update will be retried
-						return err
+						return fmt.Errorf("error during apply of object %q: %w", resource, err)
 					}
 
-					if apierrors.IsInvalid(err) && operationResult == controllerutil.OperationResultUpdated && deleteOnInvalidUpdate(current) {
-						if deleteErr := r.targetClient.Delete(r.ctx, current); client.IgnoreNotFound(deleteErr) != nil {
-							return fmt.Errorf("error deleting object %q after 'invalid' update error: %s", resource, deleteErr)
-						}
-						// return error directly, so that the create after delete will be retried
-						return fmt.Errorf("deleted object %q because of 'invalid' update error and 'delete-on-invalid-update' annotation on object (%s)", resource, err)
+					switch operationResult {
+					case controllerutil.OperationResultCreated:
+						opts.auditRecorder.Record(audit.OperationCreate, current,
+							"object is part of the ManagedResource but does not exist", nil)
+					case controllerutil.OperationResultUpdated:
+						changes := audit.Changes(existing.Object, current.Object)
+						log.V(1).Info("Updated object to the desired state", "resource", resource, "changes", changes)
+						opts.auditRecorder.Record(audit.OperationUpdate, current,
+							"object differs from the desired state in the ManagedResource", changes)
 					}
+					return nil
+				})
 
-					return fmt.Errorf("error during apply of object %q: %s", resource, err)
-				}
-				return nil
-			})
-		}(o)
-	}
+				tracing.EndSpan(objCtx, objSpan, err)
+				results <- classifyError(err)
+			}(o)
+		}
 
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		var waveFailed bool
+		for err := range results {
+			if err != nil {
+				metrics.ApplyErrors.WithLabelValues(string(ErrorClassOf(err))).Inc()
+				errorList = multierror.Append(errorList, err)
+				waveFailed = true
+			}
+		}
 
-	for err := range results {
-		if err != nil {
-			errorList = multierror.Append(errorList, err)
+		if gate := readinessGateOf(wave); gate != nil {
+			if waveFailed {
+				errorList = multierror.Append(errorList, fmt.Errorf("skipped all objects following %q as not all objects up to it could be applied", unstructuredToString(gate)))
+				break
+			}
+			if err := r.waitForReady(ctx, log, gate); err != nil {
+				errorList = multierror.Append(errorList, &TransientTargetError{Err: err})
+				break
+			}
 		}
 	}
 
@@ -558,14 +1244,132 @@ func objectKeyFromUnstructured(o *unstructured.Unstructured) string {
 	return objectKey(o.GroupVersionKind().Group, o.GetKind(), o.GetNamespace(), o.GetName())
 }
 
+// ignoreMode returns true if the given annotations of an object put it into mode Ignore, i.e. the object is neither
+// applied nor deleted.
+func ignoreMode(annotations map[string]string) bool {
+	return annotations[resourcesv1alpha1.Mode] == resourcesv1alpha1.ModeIgnore
+}
+
 func ignore(meta metav1.Object) bool {
 	return annotationExistsAndValueTrue(meta, resourcesv1alpha1.Ignore)
 }
 
+// releaseResources removes the origin label and annotation from the kept resources of the given ManagedResource, so
+// that they are neither protected nor garbage collected anymore. If a TTL for kept objects is configured, the resources
+// are annotated with their expiration time instead, so that they are garbage collected after the TTL unless they are
+// adopted by another ManagedResource.
+func (r *Reconciler) releaseResources(ctx context.Context, log logr.Logger, mr *resourcesv1alpha1.ManagedResource, auditRecorder *audit.Recorder) error {
+	var (
+		errorList = &multierror.Error{
+			ErrorFormat: utils.NewErrorFormatFuncWithPrefix("Could not release all resources"),
+		}
+		expiresAt = time.Now().Add(r.keepObjectsTTL).UTC().Format(time.RFC3339)
+	)
+
+	for _, ref := range mr.Status.Resources {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(ref.APIVersion)
+		obj.SetKind(ref.Kind)
+
+		resource := objectKey(ref.GroupVersionKind().Group, ref.Kind, ref.Namespace, ref.Name)
+		if err := r.targetClient.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+			if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				errorList = multierror.Append(errorList, fmt.Errorf("error getting resource %q: %w", resource, err))
+			}
+			continue
+		}
+
+		_, hasLabel := obj.GetLabels()[resourcesv1alpha1.OriginLabel]
+		_, hasAnnotation := obj.GetAnnotations()[resourcesv1alpha1.OriginAnnotation]
+		if !hasLabel && !hasAnnotation {
+			continue
+		}
+
+		var (
+			patch              = client.MergeFrom(obj.DeepCopy())
+			annotations        = obj.GetAnnotations()
+			reason             = "ManagedResource is deleted with .spec.keepObjects=true"
+			changes            []string
+			_, alreadyExpiring = annotations[resourcesv1alpha1.ExpiresAt]
+		)
+
+		// objects annotated with keep-object are kept forever
+		if r.keepObjectsTTL > 0 && !keepObject(obj) {
+			if alreadyExpiring {
+				continue
+			}
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[resourcesv1alpha1.ExpiresAt] = expiresAt
+			reason += ", the object expires at " + expiresAt
+			changes = []string{"metadata.annotations." + resourcesv1alpha1.ExpiresAt}
+			obj.SetAnnotations(annotations)
+		} else {
+			changes = removeOrigin(obj)
+		}
+
+		log.Info("Releasing", "resource", unstructuredToString(obj))
+		if err := r.targetClient.Patch(ctx, obj, patch); client.IgnoreNotFound(err) != nil {
+			errorList = multierror.Append(errorList, fmt.Errorf("error releasing resource %q: %w", resource, err))
+			continue
+		}
+		auditRecorder.Record(audit.OperationUpdate, obj, reason, changes)
+	}
+
+	return errorList.ErrorOrNil()
+}
+
+// removeOrigin removes the origin label and annotation from the given object and returns the paths of the removed
+// fields.
+func removeOrigin(obj metav1.Object) []string {
+	var (
+		labels, annotations = obj.GetLabels(), obj.GetAnnotations()
+		changes             []string
+	)
+
+	if _, ok := labels[resourcesv1alpha1.OriginLabel]; ok {
+		delete(labels, resourcesv1alpha1.OriginLabel)
+		obj.SetLabels(labels)
+		changes = append(changes, "metadata.labels."+resourcesv1alpha1.OriginLabel)
+	}
+	if _, ok := annotations[resourcesv1alpha1.OriginAnnotation]; ok {
+		delete(annotations, resourcesv1alpha1.OriginAnnotation)
+		obj.SetAnnotations(annotations)
+		changes = append(changes, "metadata.annotations."+resourcesv1alpha1.OriginAnnotation)
+	}
+	return changes
+}
+
+// removeFinalizers removes the given finalizers from the object and returns the removed ones.
+func removeFinalizers(obj metav1.Object, finalizers sets.String) []string {
+	var (
+		kept    []string
+		removed []string
+	)
+
+	for _, finalizer := range obj.GetFinalizers() {
+		if finalizers.Has(finalizer) {
+			removed = append(removed, finalizer)
+			continue
+		}
+		kept = append(kept, finalizer)
+	}
+	if len(removed) > 0 {
+		obj.SetFinalizers(kept)
+	}
+	return removed
+}
+
 func deleteOnInvalidUpdate(meta metav1.Object) bool {
 	return annotationExistsAndValueTrue(meta, resourcesv1alpha1.DeleteOnInvalidUpdate)
 }
 
+// inDeletionScope returns whether the given object matches the deletion label selector, i.e. whether it may be deleted.
+func (r *Reconciler) inDeletionScope(meta metav1.Object) bool {
+	return r.deletionSelector == nil || r.deletionSelector.Matches(labels.Set(meta.GetLabels()))
+}
+
 func keepObject(meta metav1.Object) bool {
 	return annotationExistsAndValueTrue(meta, resourcesv1alpha1.KeepObject)
 }
@@ -580,19 +1384,74 @@ func annotationExistsAndValueTrue(meta metav1.Object, key string) bool {
 	return annotationExists && valueTrue
 }
 
-func (r *Reconciler) cleanOldResources(index *ObjectIndex, mr *resourcesv1alpha1.ManagedResource) (bool, error) {
+// skippedPruneKinds returns the kinds of objects that are released instead of deleted when they are removed from the
+// given ManagedResource.
+func (r *Reconciler) skippedPruneKinds(mr *resourcesv1alpha1.ManagedResource) map[schema.GroupKind]struct{} {
+	if mr.Spec.Prune == nil || len(mr.Spec.Prune.SkipKinds) == 0 {
+		return r.pruneSkipKinds
+	}
+
+	skipKinds := make(map[schema.GroupKind]struct{}, len(r.pruneSkipKinds)+len(mr.Spec.Prune.SkipKinds))
+	for groupKind := range r.pruneSkipKinds {
+		skipKinds[groupKind] = struct{}{}
+	}
+	for _, groupKind := range mr.Spec.Prune.SkipKinds {
+		skipKinds[schema.GroupKind{Group: groupKind.Group, Kind: groupKind.Kind}] = struct{}{}
+	}
+	return skipKinds
+}
+
+// pruneGracePeriodOf returns the duration for which objects removed from the given ManagedResource are kept before they
+// are deleted.
+func (r *Reconciler) pruneGracePeriodOf(mr *resourcesv1alpha1.ManagedResource) time.Duration {
+	if mr.Spec.Prune != nil && mr.Spec.Prune.GracePeriod != nil {
+		return mr.Spec.Prune.GracePeriod.Duration
+	}
+	return r.pruneGracePeriod
+}
+
+// cleanOptions configures `cleanOldResources`.
+type cleanOptions struct {
+	// skipKinds are the kinds of objects which are released instead of deleted.
+	skipKinds map[schema.GroupKind]struct{}
+	// gracePeriod is the duration for which objects are annotated as pending prune before they are deleted.
+	gracePeriod time.Duration
+	// waitForHealthy keeps the objects pending prune even after the grace period.
+	waitForHealthy bool
+	// stripFinalizers are removed from objects whose deletion has been blocked for too long.
+	stripFinalizers sets.String
+	// failFast deletes the objects one after another and skips the remaining ones as soon as one deletion failed.
+	failFast      bool
+	auditRecorder *audit.Recorder
+	// reason is recorded in the audit log for all mutations.
+	reason string
+}
+
+// cleanOldResources deletes all objects of the index that have not been found. Objects of the skipped kinds are
+// released instead. If the grace period is positive, objects are annotated as pending prune first and only deleted
+// after the grace period, the returned references of the objects pending prune have to be kept in the status.
+func (r *Reconciler) cleanOldResources(ctx context.Context, log logr.Logger, index *ObjectIndex, mr *resourcesv1alpha1.ManagedResource, opts cleanOptions) (pendingPrune []resourcesv1alpha1.ObjectReference, deletionPending bool, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "clean old resources")
+	defer func() { tracing.EndSpan(ctx, span, err) }()
+
 	type output struct {
 		resource        string
 		deletionPending bool
 		err             error
+		pendingPrune    *resourcesv1alpha1.ObjectReference
 	}
 
 	var (
-		results         = make(chan *output)
-		wg              sync.WaitGroup
-		deletePVCs      = mr.Spec.DeletePersistentVolumeClaims != nil && *mr.Spec.DeletePersistentVolumeClaims
-		deletionPending = false
-		errorList       = &multierror.Error{
+		results    = make(chan *output)
+		wg         sync.WaitGroup
+		deletePVCs = mr.Spec.DeletePersistentVolumeClaims != nil && *mr.Spec.DeletePersistentVolumeClaims
+		// the discovery information is refreshed at most once, even if the kinds of several objects are missing
+		resetRESTMapper sync.Once
+		// if failFast is set, the objects are deleted one after another and the remaining ones are skipped as soon as
+		// the deletion of one of them failed
+		sequential sync.Mutex
+		failed     bool
+		errorList  = &multierror.Error{
 			ErrorFormat: utils.NewErrorFormatFuncWithPrefix("Could not clean all old resources"),
 		}
 	)
@@ -610,30 +1469,153 @@ func (r *Reconciler) cleanOldResources(index *ObjectIndex, mr *resourcesv1alpha1
 				obj.SetName(ref.Name)
 
 				resource := unstructuredToString(obj)
-				r.log.Info("Deleting", "resource", resource)
+				send := func(out *output) {
+					if opts.failFast && out.err != nil {
+						failed = true
+					}
+					results <- out
+				}
+
+				if opts.failFast {
+					sequential.Lock()
+					defer sequential.Unlock()
+
+					if failed {
+						send(&output{resource: resource, deletionPending: true, err: errors.New("skipped as the deletion of another object failed")})
+						return
+					}
+				}
+
+				if ignoreMode(ref.Annotations) {
+					log.Info("Not deleting object as it is in mode "+resourcesv1alpha1.ModeIgnore, "resource", resource)
+					send(&output{resource: resource})
+					return
+				}
+				log.Info("Deleting", "resource", resource)
 
 				// get object before deleting to be able to do cleanup work for it
-				if err := r.targetClient.Get(r.ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
-					if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
-						r.log.Error(err, "Error during deletion", "resource", resource)
-						results <- &output{resource, true, err}
+				err := r.targetClient.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj)
+				if meta.IsNoMatchError(err) {
+					// the kind might only be missing in the cached discovery information
+					resetRESTMapper.Do(r.targetRESTMapper.Reset)
+					err = r.targetClient.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj)
+				}
+				if err != nil {
+					if meta.IsNoMatchError(err) {
+						// the kind has been removed from the target cluster (e.g. its CustomResourceDefinition has been
+						// deleted), hence the object cannot exist anymore and is dropped from the ManagedResource
+						log.Info("Dropping object as its kind does not exist anymore", "resource", resource)
+						r.recordEvent(mr, corev1.EventTypeWarning, "KindRemoved", fmt.Sprintf("Dropped %s as its kind does not exist in the target cluster anymore.", resource))
+						send(&output{resource: resource})
+						return
+					}
+					if !apierrors.IsNotFound(err) {
+						log.Error(err, "Error during deletion", "resource", resource)
+						send(&output{resource: resource, deletionPending: true, err: err})
 						return
 					}
 
 					// resource already deleted, nothing to do here
-					results <- &output{resource, false, nil}
+					send(&output{resource: resource})
+					return
+				}
+
+				if !r.inDeletionScope(obj) {
+					log.Info("Not touching object as it does not match the deletion label selector", "resource", resource, "selector", r.deletionSelector.String())
+					r.recordEvent(mr, corev1.EventTypeWarning, "DeletionOutOfScope", fmt.Sprintf("Did not delete %s as it does not match the deletion label selector %q.", resource, r.deletionSelector.String()))
+					send(&output{resource: resource})
+					return
+				}
+
+				if deletionTimestamp := obj.GetDeletionTimestamp(); deletionTimestamp != nil && opts.stripFinalizers.Len() > 0 && time.Since(deletionTimestamp.Time) > r.stripFinalizersTimeout {
+					// the object is updated instead of patched to not remove finalizers added concurrently
+					var removed []string
+					if err := update.Try(ctx, r.targetClient, obj, func() error {
+						removed = removeFinalizers(obj, opts.stripFinalizers)
+						return nil
+					}, update.Options{}); err != nil {
+						if apierrors.IsNotFound(err) {
+							send(&output{resource: resource})
+							return
+						}
+						log.Error(err, "Error during removal of finalizers", "resource", resource)
+						send(&output{resource: resource, deletionPending: true, err: err})
+						return
+					}
+					if len(removed) > 0 {
+						log.Info("Removed finalizers from object as its deletion is blocked for too long", "resource", resource, "finalizers", removed)
+						opts.auditRecorder.Record(audit.OperationUpdate, obj, fmt.Sprintf("%s, the finalizers %s are removed as the deletion has been blocked for longer than %s", opts.reason, strings.Join(removed, ", "), r.stripFinalizersTimeout), []string{"metadata.finalizers"})
+					}
+					send(&output{resource: resource, deletionPending: true})
 					return
 				}
 
 				if keepObject(obj) {
-					r.log.Info("Keeping object in the system as "+resourcesv1alpha1.KeepObject+" annotation found", "resource", unstructuredToString(obj))
-					results <- &output{resource, false, nil}
+					log.Info("Keeping object in the system as "+resourcesv1alpha1.KeepObject+" annotation found", "resource", unstructuredToString(obj))
+					send(&output{resource: resource})
 					return
 				}
 
-				if err := cleanup(r.ctx, r.targetClient, r.targetScheme, obj, deletePVCs); err != nil {
-					r.log.Error(err, "Error during cleanup", "resource", resource)
-					results <- &output{resource: resource, deletionPending: true, err: err}
+				if _, ok := opts.skipKinds[obj.GroupVersionKind().GroupKind()]; ok {
+					log.Info("Releasing object instead of deleting it as its kind is excluded from pruning", "resource", resource)
+					patch := client.MergeFrom(obj.DeepCopy())
+					changes := removeOrigin(obj)
+					if len(changes) == 0 {
+						send(&output{resource: resource})
+						return
+					}
+					if err := r.targetClient.Patch(ctx, obj, patch); client.IgnoreNotFound(err) != nil {
+						log.Error(err, "Error during release", "resource", resource)
+						send(&output{resource: resource, err: err})
+						return
+					}
+					opts.auditRecorder.Record(audit.OperationUpdate, obj, opts.reason+", the object is released as its kind is excluded from pruning", changes)
+					send(&output{resource: resource})
+					return
+				}
+
+				if opts.gracePeriod > 0 || opts.waitForHealthy {
+					pruneAfter := pruneAfterOf(ref, obj, opts.gracePeriod)
+					if time.Now().Before(pruneAfter.Time) || opts.waitForHealthy {
+						if value := pruneAfter.UTC().Format(time.RFC3339); obj.GetAnnotations()[resourcesv1alpha1.PruneAfter] != value {
+							log.Info("Marking object as pending prune", "resource", resource, "pruneAfter", value)
+							patch := client.MergeFrom(obj.DeepCopy())
+							annotations := obj.GetAnnotations()
+							if annotations == nil {
+								annotations = map[string]string{}
+							}
+							annotations[resourcesv1alpha1.PruneAfter] = value
+							obj.SetAnnotations(annotations)
+							if err := r.targetClient.Patch(ctx, obj, patch); err != nil {
+								if apierrors.IsNotFound(err) {
+									send(&output{resource: resource})
+									return
+								}
+								log.Error(err, "Error during marking as pending prune", "resource", resource)
+								send(&output{resource: resource, err: err})
+								return
+							}
+							message := opts.reason + ", the object is deleted after " + value
+							if opts.waitForHealthy {
+								message += " once the remaining objects are healthy"
+							}
+							opts.auditRecorder.Record(audit.OperationUpdate, obj, message, []string{"metadata.annotations." + resourcesv1alpha1.PruneAfter})
+						}
+						ref.PruneAfter = &pruneAfter
+						send(&output{resource: resource, pendingPrune: &ref})
+						return
+					}
+				}
+
+				if !DeletionConfirmed(obj) {
+					log.Info("Not deleting object as "+resourcesv1alpha1.ConfirmationDeletion+" annotation is missing", "resource", resource)
+					send(&output{resource: resource, deletionPending: true, err: fmt.Errorf("deletion must be confirmed by annotating the object with %s=true", resourcesv1alpha1.ConfirmationDeletion)})
+					return
+				}
+
+				if err := cleanup(ctx, r.targetClient, r.targetScheme, obj, deletePVCs, opts.auditRecorder); err != nil {
+					log.Error(err, "Error during cleanup", "resource", resource)
+					send(&output{resource: resource, deletionPending: true, err: err})
 					return
 				}
 
@@ -649,16 +1631,17 @@ func (r *Reconciler) cleanOldResources(index *ObjectIndex, mr *resourcesv1alpha1
 					deleteOptions.PropagationPolicy = &deletePropagationForeground
 				}
 
-				if err := r.targetClient.Delete(r.ctx, obj, deleteOptions); err != nil {
+				if err := r.targetClient.Delete(ctx, obj, deleteOptions); err != nil {
 					if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
-						r.log.Error(err, "Error during deletion", "resource", resource)
-						results <- &output{resource, true, err}
+						log.Error(err, "Error during deletion", "resource", resource)
+						send(&output{resource: resource, deletionPending: true, err: err})
 						return
 					}
-					results <- &output{resource, false, nil}
+					send(&output{resource: resource})
 					return
 				}
-				results <- &output{resource, true, nil}
+				opts.auditRecorder.Record(audit.OperationDelete, obj, opts.reason, nil)
+				send(&output{resource: resource, deletionPending: true, err: nil})
 			}(oldResource)
 		}
 	}
@@ -669,6 +1652,11 @@ func (r *Reconciler) cleanOldResources(index *ObjectIndex, mr *resourcesv1alpha1
 	}()
 
 	for out := range results {
+		if out.pendingPrune != nil {
+			pendingPrune = append(pendingPrune, *out.pendingPrune)
+			continue
+		}
+
 		if out.deletionPending {
 			deletionPending = true
 			errMsg := fmt.Sprintf("deletion of old resource %q is still pending", out.resource)
@@ -684,7 +1672,50 @@ func (r *Reconciler) cleanOldResources(index *ObjectIndex, mr *resourcesv1alpha1
 		}
 	}
 
-	return deletionPending, errorList.ErrorOrNil()
+	return pendingPrune, deletionPending, errorList.ErrorOrNil()
+}
+
+// recordEvent records an event on the given ManagedResource if an event recorder is configured.
+func (r *Reconciler) recordEvent(mr *resourcesv1alpha1.ManagedResource, eventType, reason, message string) {
+	if r.eventRecorder != nil {
+		r.eventRecorder.Event(mr, eventType, reason, message)
+	}
+}
+
+// pruneAfterOf returns the time after which the given object, which is no longer part of its ManagedResource, is
+// deleted. If it is already pending prune, the time is taken from its reference in the status or its annotation.
+func pruneAfterOf(ref resourcesv1alpha1.ObjectReference, obj metav1.Object, gracePeriod time.Duration) metav1.Time {
+	if ref.PruneAfter != nil {
+		return *ref.PruneAfter
+	}
+	if t, err := time.Parse(time.RFC3339, obj.GetAnnotations()[resourcesv1alpha1.PruneAfter]); err == nil {
+		return metav1.NewTime(t)
+	}
+	return metav1.NewTime(time.Now().Add(gracePeriod).Truncate(time.Second))
+}
+
+// pruneAfterHealthy returns whether objects removed from the given ManagedResource are kept until the remaining ones
+// are healthy.
+func pruneAfterHealthy(mr *resourcesv1alpha1.ManagedResource) bool {
+	return mr.Spec.Prune != nil && mr.Spec.Prune.AfterHealthy != nil && *mr.Spec.Prune.AfterHealthy
+}
+
+// resourcesHealthy returns whether the health controller found the objects of the given ManagedResource (excluding
+// the ones pending prune) healthy.
+func resourcesHealthy(mr *resourcesv1alpha1.ManagedResource) bool {
+	condition := resourcesv1alpha1helper.GetCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesHealthy)
+	return condition != nil && condition.Status == resourcesv1alpha1.ConditionTrue
+}
+
+// withoutPendingPrune returns the given references without the ones of objects pending prune.
+func withoutPendingPrune(refs []resourcesv1alpha1.ObjectReference) []resourcesv1alpha1.ObjectReference {
+	out := make([]resourcesv1alpha1.ObjectReference, 0, len(refs))
+	for _, ref := range refs {
+		if ref.PruneAfter == nil {
+			out = append(out, ref)
+		}
+	}
+	return out
 }
 
 func tryUpdateManagedResourceStatus(
@@ -692,21 +1723,40 @@ func tryUpdateManagedResourceStatus(
 	c client.Client,
 	mr *resourcesv1alpha1.ManagedResource,
 	resources []resourcesv1alpha1.ObjectReference,
+	lastAppliedRevision *resourcesv1alpha1.AppliedRevision,
 	updatedConditions ...resourcesv1alpha1.ManagedResourceCondition) error {
-	return utils.TryUpdateStatus(ctx, retry.DefaultBackoff, c, mr, func() error {
+	return update.Try(ctx, c, mr, func() error {
 		mr.Status.Conditions = resourcesv1alpha1helper.MergeConditions(mr.Status.Conditions, updatedConditions...)
 		mr.Status.Resources = resources
 		mr.Status.ObservedGeneration = mr.Generation
+		if lastAppliedRevision != nil {
+			mr.Status.LastAppliedRevision = lastAppliedRevision
+		}
 		return nil
-	})
+	}, update.Options{Status: true})
 }
 
-func tryUpdateManagedResourceConditions(ctx context.Context, c client.Client, mr *resourcesv1alpha1.ManagedResource, conditions ...resourcesv1alpha1.ManagedResourceCondition) error {
-	return utils.TryUpdateStatus(ctx, retry.DefaultBackoff, c, mr, func() error {
-		newConditions := resourcesv1alpha1helper.MergeConditions(mr.Status.Conditions, conditions...)
-		mr.Status.Conditions = newConditions
-		return nil
-	})
+// appliedRevisionOf returns the revision of the given secrets with the given checksum.
+func appliedRevisionOf(secrets []*corev1.Secret, checksum string) *resourcesv1alpha1.AppliedRevision {
+	revision := &resourcesv1alpha1.AppliedRevision{Checksum: checksum}
+	for _, secret := range secrets {
+		revision.Secrets = append(revision.Secrets, resourcesv1alpha1.SecretRevision{Name: secret.Name, ResourceVersion: secret.ResourceVersion})
+	}
+	return revision
+}
+
+// newObjectReference returns the reference to the given object in the status of its ManagedResource.
+func newObjectReference(obj *unstructured.Unstructured, injectLabels map[string]string) resourcesv1alpha1.ObjectReference {
+	return resourcesv1alpha1.ObjectReference{
+		ObjectReference: corev1.ObjectReference{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+		},
+		Labels:      mergeMaps(obj.GetLabels(), injectLabels),
+		Annotations: obj.GetAnnotations(),
+	}
 }
 
 func unstructuredToString(o *unstructured.Unstructured) string {
@@ -714,6 +1764,30 @@ func unstructuredToString(o *unstructured.Unstructured) string {
 	return objectKey(o.GetAPIVersion(), o.GetKind(), o.GetNamespace(), o.GetName())
 }
 
+// setOriginLabel marks the given object as managed by the gardener-resource-manager instance with the given class.
+func setOriginLabel(obj *unstructured.Unstructured, class string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[resourcesv1alpha1.OriginLabel] = class
+	obj.SetLabels(labels)
+}
+
+// setOriginAnnotation marks the given object as managed by the ManagedResource with the given origin. Objects kept
+// after the deletion of their ManagedResource do not expire anymore once they are adopted, and objects pending prune
+// are not pruned anymore once they are added to a ManagedResource again.
+func setOriginAnnotation(obj *unstructured.Unstructured, origin string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[resourcesv1alpha1.OriginAnnotation] = origin
+	delete(annotations, resourcesv1alpha1.ExpiresAt)
+	delete(annotations, resourcesv1alpha1.PruneAfter)
+	obj.SetAnnotations(annotations)
+}
+
 // injectLabels injects the given labels into the given object's metadata and if present also into the
 // pod template's and volume claims templates' metadata
 func injectLabels(obj *unstructured.Unstructured, labels map[string]string) error {
@@ -814,6 +1888,9 @@ type object struct {
 	oldInformation            resourcesv1alpha1.ObjectReference
 	forceOverwriteLabels      bool
 	forceOverwriteAnnotations bool
+
+	forceOverwriteOwnerReferences bool
+	forceOverwriteFinalizers      bool
 }
 
 type decodingError struct {
@@ -15,9 +15,31 @@
 package managedresources
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+	"github.com/gardener/gardener-resource-manager/pkg/test/fake"
+
+	hvpav1alpha1 "github.com/gardener/hvpa-controller/api/v1alpha1"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 var _ = Describe("Controller", func() {
@@ -109,4 +131,511 @@ var _ = Describe("Controller", func() {
 			Expect(obj).To(Equal(expected))
 		})
 	})
+
+	Describe("#setOriginLabel", func() {
+		It("should add the origin label to the object's metadata", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetLabels(map[string]string{"foo": "bar"})
+
+			setOriginLabel(obj, "seed")
+			Expect(obj.GetLabels()).To(Equal(map[string]string{"foo": "bar", resourcesv1alpha1.OriginLabel: "seed"}))
+		})
+
+		It("should add the origin label to an object without labels", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+			setOriginLabel(obj, "resources")
+			Expect(obj.GetLabels()).To(Equal(map[string]string{resourcesv1alpha1.OriginLabel: "resources"}))
+		})
+	})
+
+	Describe("#setOriginAnnotation", func() {
+		It("should add the origin annotation to the object's metadata", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetAnnotations(map[string]string{"foo": "bar"})
+
+			setOriginAnnotation(obj, "seed:foo/bar")
+			Expect(obj.GetAnnotations()).To(Equal(map[string]string{"foo": "bar", resourcesv1alpha1.OriginAnnotation: "seed:foo/bar"}))
+		})
+
+		It("should remove the expiration time of adopted objects", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetAnnotations(map[string]string{resourcesv1alpha1.OriginAnnotation: "seed:foo/old", resourcesv1alpha1.ExpiresAt: "2020-01-01T00:00:00Z"})
+
+			setOriginAnnotation(obj, "seed:foo/bar")
+			Expect(obj.GetAnnotations()).To(Equal(map[string]string{resourcesv1alpha1.OriginAnnotation: "seed:foo/bar"}))
+		})
+
+		It("should remove the prune time of objects added again", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetAnnotations(map[string]string{resourcesv1alpha1.OriginAnnotation: "foo/bar", resourcesv1alpha1.PruneAfter: "2020-01-01T00:00:00Z"})
+
+			setOriginAnnotation(obj, "foo/bar")
+			Expect(obj.GetAnnotations()).To(Equal(map[string]string{resourcesv1alpha1.OriginAnnotation: "foo/bar"}))
+		})
+
+		It("should add the origin annotation to an object without annotations", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+			setOriginAnnotation(obj, "foo/bar")
+			Expect(obj.GetAnnotations()).To(Equal(map[string]string{resourcesv1alpha1.OriginAnnotation: "foo/bar"}))
+		})
+	})
+	Describe("#removeOrigin", func() {
+		It("should remove the origin label and annotation", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetLabels(map[string]string{"foo": "bar", resourcesv1alpha1.OriginLabel: "resources"})
+			obj.SetAnnotations(map[string]string{resourcesv1alpha1.OriginAnnotation: "foo/bar"})
+
+			Expect(removeOrigin(obj)).To(Equal([]string{
+				"metadata.labels." + resourcesv1alpha1.OriginLabel,
+				"metadata.annotations." + resourcesv1alpha1.OriginAnnotation,
+			}))
+			Expect(obj.GetLabels()).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(obj.GetAnnotations()).To(BeEmpty())
+		})
+
+		It("should not change objects without origin", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetLabels(map[string]string{"foo": "bar"})
+
+			Expect(removeOrigin(obj)).To(BeEmpty())
+			Expect(obj.GetLabels()).To(Equal(map[string]string{"foo": "bar"}))
+		})
+	})
+
+	Describe("#removeFinalizers", func() {
+		It("should remove the given finalizers only", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetFinalizers([]string{"foo", "bar", "baz"})
+
+			Expect(removeFinalizers(obj, sets.NewString("bar", "baz", "other"))).To(Equal([]string{"bar", "baz"}))
+			Expect(obj.GetFinalizers()).To(Equal([]string{"foo"}))
+		})
+
+		It("should not change objects without the given finalizers", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetFinalizers([]string{"foo"})
+
+			Expect(removeFinalizers(obj, sets.NewString("bar"))).To(BeEmpty())
+			Expect(obj.GetFinalizers()).To(Equal([]string{"foo"}))
+		})
+	})
+
+	Describe("#skippedPruneKinds", func() {
+		var (
+			r  *Reconciler
+			mr *resourcesv1alpha1.ManagedResource
+		)
+
+		BeforeEach(func() {
+			r = NewReconciler(nil, nil, nil, nil, ReconcilerOptions{PruneSkipKinds: []schema.GroupKind{{Kind: "Namespace"}}})
+			mr = &resourcesv1alpha1.ManagedResource{}
+		})
+
+		It("should return the kinds of the controller", func() {
+			Expect(r.skippedPruneKinds(mr)).To(Equal(map[schema.GroupKind]struct{}{{Kind: "Namespace"}: {}}))
+		})
+
+		It("should combine the kinds of the controller and the ManagedResource", func() {
+			mr.Spec.Prune = &resourcesv1alpha1.Prune{SkipKinds: []metav1.GroupKind{{Kind: "PersistentVolumeClaim"}, {Group: "apps", Kind: "StatefulSet"}}}
+
+			Expect(r.skippedPruneKinds(mr)).To(Equal(map[schema.GroupKind]struct{}{
+				{Kind: "Namespace"}:                  {},
+				{Kind: "PersistentVolumeClaim"}:      {},
+				{Group: "apps", Kind: "StatefulSet"}: {},
+			}))
+		})
+	})
+
+	Describe("#pruneGracePeriodOf", func() {
+		var (
+			r  *Reconciler
+			mr *resourcesv1alpha1.ManagedResource
+		)
+
+		BeforeEach(func() {
+			r = NewReconciler(nil, nil, nil, nil, ReconcilerOptions{PruneGracePeriod: time.Minute})
+			mr = &resourcesv1alpha1.ManagedResource{}
+		})
+
+		It("should return the grace period of the controller", func() {
+			Expect(r.pruneGracePeriodOf(mr)).To(Equal(time.Minute))
+		})
+
+		It("should return the grace period of the ManagedResource", func() {
+			mr.Spec.Prune = &resourcesv1alpha1.Prune{GracePeriod: &metav1.Duration{}}
+			Expect(r.pruneGracePeriodOf(mr)).To(BeZero())
+		})
+	})
+
+	Describe("#pruneAfterOf", func() {
+		var (
+			ref resourcesv1alpha1.ObjectReference
+			obj *unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			ref = resourcesv1alpha1.ObjectReference{ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "foo", Name: "bar"}}
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{}}
+		})
+
+		It("should return the time of the status", func() {
+			pruneAfter := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+			ref.PruneAfter = &pruneAfter
+			obj.SetAnnotations(map[string]string{resourcesv1alpha1.PruneAfter: "2020-02-01T00:00:00Z"})
+
+			Expect(pruneAfterOf(ref, obj, time.Hour)).To(Equal(pruneAfter))
+		})
+
+		It("should return the time of the annotation", func() {
+			obj.SetAnnotations(map[string]string{resourcesv1alpha1.PruneAfter: "2020-02-01T00:00:00Z"})
+
+			Expect(pruneAfterOf(ref, obj, time.Hour).Time.Equal(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("should return the time after the grace period for objects not pending prune yet", func() {
+			Expect(pruneAfterOf(ref, obj, time.Hour).Time).To(BeTemporally("~", time.Now().Add(time.Hour), 2*time.Second))
+		})
+	})
+
+	Describe("#withoutPendingPrune", func() {
+		It("should remove the references of objects pending prune", func() {
+			var (
+				pruneAfter = metav1.Now()
+				kept       = resourcesv1alpha1.ObjectReference{ObjectReference: corev1.ObjectReference{Kind: "ConfigMap", Name: "kept"}}
+				pending    = resourcesv1alpha1.ObjectReference{ObjectReference: corev1.ObjectReference{Kind: "ConfigMap", Name: "pending"}, PruneAfter: &pruneAfter}
+			)
+
+			Expect(withoutPendingPrune([]resourcesv1alpha1.ObjectReference{kept, pending})).To(Equal([]resourcesv1alpha1.ObjectReference{kept}))
+		})
+	})
+	Describe("#ignoreMode", func() {
+		It("should return whether the object is in mode Ignore", func() {
+			Expect(ignoreMode(nil)).To(BeFalse())
+			Expect(ignoreMode(map[string]string{resourcesv1alpha1.Mode: "Apply"})).To(BeFalse())
+			Expect(ignoreMode(map[string]string{resourcesv1alpha1.Mode: resourcesv1alpha1.ModeIgnore})).To(BeTrue())
+		})
+	})
+
+	Describe("#recordEvent", func() {
+		mr := &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"}}
+
+		It("should record events on the ManagedResource", func() {
+			eventRecorder := record.NewFakeRecorder(1)
+			r := &Reconciler{eventRecorder: eventRecorder}
+
+			r.recordEvent(mr, corev1.EventTypeWarning, "KindRemoved", "some message")
+			Expect(eventRecorder.Events).To(Receive(Equal("Warning KindRemoved some message")))
+		})
+
+		It("should discard events without event recorder", func() {
+			r := &Reconciler{}
+			Expect(func() { r.recordEvent(mr, corev1.EventTypeWarning, "KindRemoved", "some message") }).NotTo(Panic())
+		})
+	})
+
+	Describe("#cleanOldResources", func() {
+		var (
+			mockCtrl *gomock.Controller
+			c        *mockclient.MockClient
+			r        *Reconciler
+			mr       *resourcesv1alpha1.ManagedResource
+			index    *ObjectIndex
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			c = mockclient.NewMockClient(mockCtrl)
+			r = &Reconciler{targetClient: c}
+
+			mr = &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"}}
+			index = NewObjectIndex([]resourcesv1alpha1.ObjectReference{
+				{ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "foo"}},
+				{ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "bar"}},
+			}, nil)
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("should try to delete all objects on errors", func() {
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("fake")).Times(2)
+
+			_, deletionPending, err := r.cleanOldResources(context.TODO(), runtimelog.NullLogger{}, index, mr, cleanOptions{})
+			Expect(deletionPending).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("fake")))
+		})
+
+		It("should skip the remaining objects after the first error if failFast is set", func() {
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("fake"))
+
+			_, deletionPending, err := r.cleanOldResources(context.TODO(), runtimelog.NullLogger{}, index, mr, cleanOptions{failFast: true})
+			Expect(deletionPending).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("fake")))
+			Expect(err).To(MatchError(ContainSubstring("skipped as the deletion of another object failed")))
+		})
+
+		It("should not delete objects not matching the deletion selector", func() {
+			r.deletionSelector = labels.SelectorFromSet(labels.Set{"landscape": "dev"})
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+			pendingPrune, deletionPending, err := r.cleanOldResources(context.TODO(), runtimelog.NullLogger{}, index, mr, cleanOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletionPending).To(BeFalse())
+			Expect(pendingPrune).To(BeEmpty())
+		})
+
+		It("should remove the finalizers of objects whose deletion is blocked for too long", func() {
+			r.stripFinalizersTimeout = time.Minute
+			index = NewObjectIndex([]resourcesv1alpha1.ObjectReference{
+				{ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "foo"}},
+			}, nil)
+
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*unstructured.Unstructured).SetDeletionTimestamp(&metav1.Time{Time: time.Now().Add(-time.Hour)})
+				obj.(*unstructured.Unstructured).SetFinalizers([]string{"foo", "bar"})
+				return nil
+			}).Times(2)
+			c.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+				Expect(obj.(*unstructured.Unstructured).GetFinalizers()).To(Equal([]string{"bar"}))
+				return nil
+			})
+
+			_, deletionPending, err := r.cleanOldResources(context.TODO(), runtimelog.NullLogger{}, index, mr, cleanOptions{stripFinalizers: sets.NewString("foo")})
+			Expect(err).To(MatchError(ContainSubstring("is still pending")))
+			Expect(deletionPending).To(BeTrue())
+		})
+
+		It("should keep the objects pending prune if waitForHealthy is set", func() {
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			c.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+			pendingPrune, deletionPending, err := r.cleanOldResources(context.TODO(), runtimelog.NullLogger{}, index, mr, cleanOptions{waitForHealthy: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletionPending).To(BeFalse())
+			Expect(pendingPrune).To(HaveLen(2))
+			for _, ref := range pendingPrune {
+				Expect(ref.PruneAfter).NotTo(BeNil())
+			}
+		})
+	})
+
+	Describe("#applyNewResources", func() {
+		var (
+			c                    *fake.Client
+			r                    *Reconciler
+			namespace, configMap object
+		)
+
+		newObject := func(apiVersion, kind, namespace, name string) object {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(apiVersion)
+			obj.SetKind(kind)
+			obj.SetNamespace(namespace)
+			obj.SetName(name)
+			return object{obj: obj}
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(kubernetesscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(hvpav1alpha1.AddToScheme(scheme)).To(Succeed())
+
+			c = fake.NewClient(scheme)
+			r = &Reconciler{targetClient: c, targetScheme: scheme, maxConcurrentApplies: 1}
+
+			namespace = newObject("v1", "Namespace", "", "foo")
+			configMap = newObject("v1", "ConfigMap", "foo", "bar")
+		})
+
+		It("should create the objects phase by phase", func() {
+			Expect(r.applyNewResources(context.TODO(), runtimelog.NullLogger{}, []object{configMap, namespace}, applyOptions{origin: "origin"})).To(Succeed())
+
+			Expect(c.OperationStrings()).To(Equal([]string{
+				"create v1 Namespace foo",
+				"create v1 ConfigMap foo/bar",
+			}))
+		})
+
+		It("should not write unchanged objects again", func() {
+			Expect(r.applyNewResources(context.TODO(), runtimelog.NullLogger{}, []object{configMap}, applyOptions{origin: "origin"})).To(Succeed())
+			c.Reset()
+
+			configMap = newObject("v1", "ConfigMap", "foo", "bar")
+			Expect(r.applyNewResources(context.TODO(), runtimelog.NullLogger{}, []object{configMap}, applyOptions{origin: "origin"})).To(Succeed())
+
+			Expect(c.Operations()).To(BeEmpty())
+		})
+
+		It("should report failed writes", func() {
+			c.FailOn(fake.VerbCreate, schema.GroupKind{Kind: "ConfigMap"}, "foo", "bar", errors.New("fake"))
+
+			err := r.applyNewResources(context.TODO(), runtimelog.NullLogger{}, []object{namespace, configMap}, applyOptions{origin: "origin"})
+			Expect(err).To(MatchError(ContainSubstring("fake")))
+			Expect(c.OperationStrings()).To(ContainElement("create v1 ConfigMap foo/bar"))
+		})
+
+		It("should classify failed writes by their API status", func() {
+			c.FailOn(fake.VerbCreate, schema.GroupKind{Kind: "ConfigMap"}, "foo", "bar", apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "bar", nil))
+
+			err := r.applyNewResources(context.TODO(), runtimelog.NullLogger{}, []object{namespace, configMap}, applyOptions{origin: "origin"})
+			Expect(err).To(HaveOccurred())
+			Expect(ErrorClassOf(err)).To(Equal(resourcesv1alpha1.ErrorClassPermanentManifest))
+		})
+	})
+
+	Describe("#reconcile", func() {
+		var (
+			c, targetClient *fake.Client
+			r               *Reconciler
+			mr              *resourcesv1alpha1.ManagedResource
+			secret          *corev1.Secret
+			stopMapper      func()
+
+			key = client.ObjectKey{Namespace: "foo", Name: "bar"}
+		)
+
+		configMapRef := func(name string) resourcesv1alpha1.ObjectReference {
+			return resourcesv1alpha1.ObjectReference{ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: name}}
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(kubernetesscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+			targetScheme := runtime.NewScheme()
+			Expect(kubernetesscheme.AddToScheme(targetScheme)).To(Succeed())
+			Expect(hvpav1alpha1.AddToScheme(targetScheme)).To(Succeed())
+
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "objects"},
+				Data: map[string][]byte{
+					"a.yaml":   []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n"),
+					"b.yaml":   []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n"),
+					"new.yaml": []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: new\n"),
+				},
+			}
+			mr = &resourcesv1alpha1.ManagedResource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+				Spec:       resourcesv1alpha1.ManagedResourceSpec{SecretRefs: []corev1.LocalObjectReference{{Name: "objects"}}},
+				Status: resourcesv1alpha1.ManagedResourceStatus{
+					Resources:           []resourcesv1alpha1.ObjectReference{configMapRef("a"), configMapRef("b"), configMapRef("old")},
+					LastAppliedRevision: &resourcesv1alpha1.AppliedRevision{Checksum: "applied-before-the-restart"},
+				},
+			}
+
+			mapper, stop := configMapRESTMapper(GinkgoT())
+			stopMapper = stop
+
+			c = fake.NewClient(scheme, mr.DeepCopy(), secret.DeepCopy())
+			targetClient = fake.NewClient(targetScheme)
+			r = NewReconciler(context.TODO(), runtimelog.NullLogger{}, c, targetClient, ReconcilerOptions{
+				TargetRESTMapper:     mapper,
+				TargetScheme:         targetScheme,
+				Class:                NewClassFilter(""),
+				MaxConcurrentApplies: 1,
+				StatusDebouncer:      NewStatusDebouncer(context.TODO(), runtimelog.NullLogger{}, c, 0),
+			})
+		})
+
+		AfterEach(func() {
+			stopMapper()
+		})
+
+		reconcile := func() *resourcesv1alpha1.ManagedResource {
+			mr := &resourcesv1alpha1.ManagedResource{}
+			Expect(c.Get(context.TODO(), key, mr)).To(Succeed())
+			_, err := r.reconcile(context.TODO(), mr, runtimelog.NullLogger{})
+			Expect(err).NotTo(HaveOccurred())
+
+			actual := &resourcesv1alpha1.ManagedResource{}
+			Expect(c.Get(context.TODO(), key, actual)).To(Succeed())
+			return actual
+		}
+
+		breakSecret := func() {
+			Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "foo", Name: "objects"}, secret)).To(Succeed())
+			secret.Data["b.yaml"] = []byte("apiVersion: v1\nkind: ConfigMap\nmetadata: [\n")
+			Expect(c.Update(context.TODO(), secret)).To(Succeed())
+		}
+
+		expectOldResourcesKept := func(actual *resourcesv1alpha1.ManagedResource) {
+			Expect(targetClient.OperationStrings()).To(ConsistOf(
+				"create v1 ConfigMap default/a",
+				"create v1 ConfigMap default/new",
+			))
+			Expect(actual.Status.Resources).To(ConsistOf(configMapRef("a"), configMapRef("b"), configMapRef("new"), configMapRef("old")))
+			Expect(actual.Status.LastAppliedRevision).To(Equal(mr.Status.LastAppliedRevision))
+
+			condition := resourcesv1alpha1helper.GetCondition(actual.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(resourcesv1alpha1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(resourcesv1alpha1.ConditionDecodingFailed))
+		}
+
+		It("should record the last applied revision in the status", func() {
+			actual := reconcile()
+
+			Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "foo", Name: "objects"}, secret)).To(Succeed())
+			Expect(actual.Status.LastAppliedRevision).To(Equal(&resourcesv1alpha1.AppliedRevision{
+				Checksum: checksumOfSecrets([]*corev1.Secret{secret}),
+				Secrets:  []resourcesv1alpha1.SecretRevision{{Name: "objects", ResourceVersion: secret.ResourceVersion}},
+			}))
+		})
+
+		It("should apply the decodable resources and keep the old ones after a restart", func() {
+			r.decodeCache = NewDecodeCache()
+			breakSecret()
+
+			expectOldResourcesKept(reconcile())
+		})
+
+		It("should apply the decodable resources and keep the old ones if the decode cache is disabled", func() {
+			r.decodeCache = nil
+			breakSecret()
+
+			expectOldResourcesKept(reconcile())
+		})
+
+		It("should still apply the last applied resources if they are the ones recorded in the status", func() {
+			r.decodeCache = NewDecodeCache()
+			reconcile()
+			targetClient.Reset()
+			breakSecret()
+
+			actual := reconcile()
+			Expect(targetClient.Operations()).To(BeEmpty())
+			Expect(actual.Status.Resources).To(ConsistOf(configMapRef("a"), configMapRef("b"), configMapRef("new")))
+
+			condition := resourcesv1alpha1helper.GetCondition(actual.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(resourcesv1alpha1.ConditionDecodingFailed))
+			Expect(condition.Message).To(ContainSubstring("the last applied resources are still applied"))
+		})
+	})
+
+	Describe("#reconcileTimedOut", func() {
+		It("should explain the timeout in the conditions and return an error", func() {
+			scheme := runtime.NewScheme()
+			Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+			mr := &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"}}
+			c := fake.NewClient(scheme, mr.DeepCopy())
+			r := &Reconciler{client: c, statusDebouncer: NewStatusDebouncer(context.TODO(), runtimelog.NullLogger{}, c, 0)}
+
+			// the context of the reconciliation has expired, the status must be written nevertheless
+			_, err := r.reconcileTimedOut(context.TODO(), mr, runtimelog.NullLogger{}, time.Minute, context.DeadlineExceeded)
+			Expect(err).To(MatchError(ContainSubstring("did not finish within 1m0s")))
+
+			actual := &resourcesv1alpha1.ManagedResource{}
+			Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "foo", Name: "bar"}, actual)).To(Succeed())
+			condition := resourcesv1alpha1helper.GetCondition(actual.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(resourcesv1alpha1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(resourcesv1alpha1.ConditionReconcileTimedOut))
+		})
+	})
 })
@@ -0,0 +1,165 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DecodeCache caches the objects decoded from the secrets of ManagedResources, so that the reconciliations of
+// unchanged ManagedResources don't need to decode their secrets again. It keeps one entry per ManagedResource, keyed
+// by a checksum of the data of its secrets. Additionally, it keeps the objects which have last been applied
+// successfully, so that they can still be applied if the secrets are changed such that they cannot be decoded. A nil
+// DecodeCache caches nothing.
+type DecodeCache struct {
+	lock        sync.Mutex
+	entries     map[client.ObjectKey]decodeCacheEntry
+	lastApplied map[client.ObjectKey]decodeCacheEntry
+}
+
+type decodeCacheEntry struct {
+	checksum string
+	objs     []*unstructured.Unstructured
+}
+
+// NewDecodeCache creates a new DecodeCache.
+func NewDecodeCache() *DecodeCache {
+	return &DecodeCache{entries: map[client.ObjectKey]decodeCacheEntry{}, lastApplied: map[client.ObjectKey]decodeCacheEntry{}}
+}
+
+// Get returns copies of the objects cached for the given ManagedResource if they have been decoded from secrets with
+// the given checksum.
+func (c *DecodeCache) Get(key client.ObjectKey, checksum string) ([]*unstructured.Unstructured, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	c.lock.Unlock()
+
+	if !ok || entry.checksum != checksum {
+		return nil, false
+	}
+	return deepCopyObjects(entry.objs), true
+}
+
+// Set caches copies of the objects decoded from the secrets with the given checksum for the given ManagedResource.
+func (c *DecodeCache) Set(key client.ObjectKey, checksum string, objs []*unstructured.Unstructured) {
+	if c == nil {
+		return
+	}
+
+	entry := decodeCacheEntry{checksum: checksum, objs: deepCopyObjects(objs)}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries[key] = entry
+}
+
+// SetApplied records that the objects cached for the given ManagedResource with the given checksum have been applied
+// successfully. It does nothing if no objects are cached for the checksum.
+func (c *DecodeCache) SetApplied(key client.ObjectKey, checksum string) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// the cached objects are never modified, hence they are shared by both entries
+	if entry, ok := c.entries[key]; ok && entry.checksum == checksum {
+		c.lastApplied[key] = entry
+	}
+}
+
+// LastApplied returns copies of the objects which have last been applied successfully for the given ManagedResource
+// and the checksum of the secrets they have been decoded from.
+func (c *DecodeCache) LastApplied(key client.ObjectKey) ([]*unstructured.Unstructured, string, bool) {
+	if c == nil {
+		return nil, "", false
+	}
+
+	c.lock.Lock()
+	entry, ok := c.lastApplied[key]
+	c.lock.Unlock()
+
+	if !ok {
+		return nil, "", false
+	}
+	return deepCopyObjects(entry.objs), entry.checksum, true
+}
+
+// Forget removes the objects cached for the given ManagedResource.
+func (c *DecodeCache) Forget(key client.ObjectKey) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, key)
+	delete(c.lastApplied, key)
+}
+
+func deepCopyObjects(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	out := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		out = append(out, obj.DeepCopy())
+	}
+	return out
+}
+
+// checksumOfSecrets computes a checksum of the names and the data of the given secrets.
+func checksumOfSecrets(secrets []*corev1.Secret) string {
+	h := sha256.New()
+	for _, secret := range secrets {
+		writeWithLength(h, []byte(secret.Name))
+
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		writeLength(h, len(keys))
+		for _, key := range keys {
+			writeWithLength(h, []byte(key))
+			writeWithLength(h, secret.Data[key])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeWithLength writes the length of the given data before the data, so that the boundaries of the written
+// values are part of the checksum.
+func writeWithLength(h hash.Hash, data []byte) {
+	writeLength(h, len(data))
+	_, _ = h.Write(data)
+}
+
+func writeLength(h hash.Hash, length int) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(length))
+	_, _ = h.Write(b[:])
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("DecodeCache", func() {
+	var (
+		cache *DecodeCache
+		key   = client.ObjectKey{Namespace: "foo", Name: "bar"}
+		obj   *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		cache = NewDecodeCache()
+		obj = &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("foo")
+	})
+
+	It("should return copies of the cached objects for the same checksum", func() {
+		cache.Set(key, "1", []*unstructured.Unstructured{obj})
+		obj.SetName("changed")
+
+		objs, ok := cache.Get(key, "1")
+		Expect(ok).To(BeTrue())
+		Expect(objs).To(HaveLen(1))
+		Expect(objs[0].GetName()).To(Equal("foo"))
+
+		objs[0].SetName("changed")
+		objs, _ = cache.Get(key, "1")
+		Expect(objs[0].GetName()).To(Equal("foo"))
+	})
+
+	It("should not return objects for another checksum or forgotten ManagedResources", func() {
+		cache.Set(key, "1", []*unstructured.Unstructured{obj})
+
+		_, ok := cache.Get(key, "2")
+		Expect(ok).To(BeFalse())
+
+		cache.Forget(key)
+		_, ok = cache.Get(key, "1")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should keep the last applied objects when other objects are cached", func() {
+		cache.SetApplied(key, "1")
+		_, _, ok := cache.LastApplied(key)
+		Expect(ok).To(BeFalse())
+
+		cache.Set(key, "1", []*unstructured.Unstructured{obj})
+		cache.SetApplied(key, "2")
+		_, _, ok = cache.LastApplied(key)
+		Expect(ok).To(BeFalse())
+
+		cache.SetApplied(key, "1")
+		cache.Set(key, "2", nil)
+
+		objs, checksum, ok := cache.LastApplied(key)
+		Expect(ok).To(BeTrue())
+		Expect(checksum).To(Equal("1"))
+		Expect(objs).To(HaveLen(1))
+		Expect(objs[0].GetName()).To(Equal("foo"))
+
+		cache.Forget(key)
+		_, _, ok = cache.LastApplied(key)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should cache nothing if nil", func() {
+		cache = nil
+		cache.Set(key, "1", []*unstructured.Unstructured{obj})
+		_, ok := cache.Get(key, "1")
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("#checksumOfSecrets", func() {
+		newSecret := func(name string, data map[string][]byte) *corev1.Secret {
+			return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name}, Data: data}
+		}
+
+		It("should only change if the names or the data of the secrets change", func() {
+			checksum := checksumOfSecrets([]*corev1.Secret{newSecret("a", map[string][]byte{"x": []byte("1"), "y": []byte("2")})})
+
+			Expect(checksumOfSecrets([]*corev1.Secret{newSecret("a", map[string][]byte{"y": []byte("2"), "x": []byte("1")})})).To(Equal(checksum))
+			Expect(checksumOfSecrets([]*corev1.Secret{newSecret("b", map[string][]byte{"x": []byte("1"), "y": []byte("2")})})).NotTo(Equal(checksum))
+			Expect(checksumOfSecrets([]*corev1.Secret{newSecret("a", map[string][]byte{"x": []byte("1"), "y": []byte("3")})})).NotTo(Equal(checksum))
+			Expect(checksumOfSecrets([]*corev1.Secret{newSecret("a", map[string][]byte{"x": []byte("12")})})).NotTo(Equal(checksum))
+		})
+
+		It("should distinguish the boundaries of keys and values", func() {
+			Expect(checksumOfSecrets([]*corev1.Secret{newSecret("a", map[string][]byte{"xy": []byte("z")})})).
+				NotTo(Equal(checksumOfSecrets([]*corev1.Secret{newSecret("a", map[string][]byte{"x": []byte("yz")})})))
+		})
+	})
+})
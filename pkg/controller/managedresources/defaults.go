@@ -0,0 +1,102 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"github.com/gardener/gardener-resource-manager/pkg/defaults"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// withDefaults returns the given object with the fields defaulted by the defaulting functions of the given scheme,
+// so that it does not differ from the live object only because of the defaults of the API server. Fields which are
+// set already are never changed, and fields unknown to the typed object are kept. The given object is not modified,
+// as it may be cached. If the object cannot be defaulted, e.g. because it cannot be converted to its typed object,
+// it is returned unchanged and the API server takes care of the defaults as before.
+func withDefaults(scheme *runtime.Scheme, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if scheme == nil || !defaults.IsDefaulted(obj.GroupVersionKind()) {
+		return obj
+	}
+
+	typed, err := scheme.New(obj.GroupVersionKind())
+	if err != nil {
+		return obj
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return obj
+	}
+	withoutDefaults, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typed)
+	if err != nil {
+		return obj
+	}
+	scheme.Default(typed)
+	defaulted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typed)
+	if err != nil {
+		return obj
+	}
+
+	out := obj.DeepCopy()
+	addDefaults(out.Object, withoutDefaults, defaulted)
+	return out
+}
+
+// addDefaults adds the fields of `defaulted` which differ from `withoutDefaults` to `desired` unless they are set in
+// `desired` already. Lists are only descended into if they have the same length in `desired` and `defaulted`.
+func addDefaults(desired, withoutDefaults, defaulted map[string]interface{}) {
+	for key, value := range defaulted {
+		previous, wasSet := withoutDefaults[key]
+
+		desiredValue, ok := desired[key]
+		if !ok {
+			if !wasSet || !equality.Semantic.DeepEqual(previous, value) {
+				desired[key] = runtime.DeepCopyJSONValue(value)
+			}
+			continue
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			desiredMap, ok := desiredValue.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			previousMap, _ := previous.(map[string]interface{})
+			addDefaults(desiredMap, previousMap, value)
+
+		case []interface{}:
+			desiredList, ok := desiredValue.([]interface{})
+			if !ok || len(desiredList) != len(value) {
+				continue
+			}
+			previousList, _ := previous.([]interface{})
+			for i := range value {
+				var (
+					desiredItem, desiredIsMap = desiredList[i].(map[string]interface{})
+					item, isMap               = value[i].(map[string]interface{})
+					previousItem              map[string]interface{}
+				)
+				if !desiredIsMap || !isMap {
+					continue
+				}
+				if i < len(previousList) {
+					previousItem, _ = previousList[i].(map[string]interface{})
+				}
+				addDefaults(desiredItem, previousItem, item)
+			}
+		}
+	}
+}
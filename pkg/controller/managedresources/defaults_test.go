@@ -0,0 +1,113 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"github.com/gardener/gardener-resource-manager/pkg/defaults"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("#withDefaults", func() {
+	var s *runtime.Scheme
+
+	BeforeEach(func() {
+		s = runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(defaults.AddToScheme(s)).To(Succeed())
+	})
+
+	It("should only add the defaulted fields", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "foo", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"revisionHistoryLimit": int64(1),
+				"selector":             map[string]interface{}{"matchLabels": map[string]interface{}{"app": "foo"}},
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "foo"}},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{
+							"name":  "foo",
+							"image": "foo:v1",
+							"ports": []interface{}{map[string]interface{}{"containerPort": int64(8080)}},
+						}},
+						// a field unknown to the vendored API types
+						"os": map[string]interface{}{"name": "linux"},
+					},
+				},
+			},
+		}}
+		original := obj.DeepCopy()
+
+		defaulted := withDefaults(s, obj)
+
+		Expect(obj).To(Equal(original))
+		Expect(defaulted.Object).To(Equal(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "foo", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"revisionHistoryLimit":    int64(1),
+				"progressDeadlineSeconds": int64(600),
+				"strategy": map[string]interface{}{
+					"type":          "RollingUpdate",
+					"rollingUpdate": map[string]interface{}{"maxUnavailable": "25%", "maxSurge": "25%"},
+				},
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "foo"}},
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "foo"}},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{
+							"name":                     "foo",
+							"image":                    "foo:v1",
+							"imagePullPolicy":          "IfNotPresent",
+							"terminationMessagePath":   "/dev/termination-log",
+							"terminationMessagePolicy": "File",
+							"ports":                    []interface{}{map[string]interface{}{"containerPort": int64(8080), "protocol": "TCP"}},
+						}},
+						"os":                            map[string]interface{}{"name": "linux"},
+						"dnsPolicy":                     "ClusterFirst",
+						"enableServiceLinks":            true,
+						"restartPolicy":                 "Always",
+						"schedulerName":                 "default-scheduler",
+						"securityContext":               map[string]interface{}{},
+						"terminationGracePeriodSeconds": int64(30),
+					},
+				},
+			},
+		}))
+	})
+
+	It("should not change objects of other kinds", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}}
+		Expect(withDefaults(s, obj)).To(BeIdenticalTo(obj))
+		Expect(withDefaults(nil, obj)).To(BeIdenticalTo(obj))
+	})
+
+	It("should not change objects which cannot be converted", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"spec":       map[string]interface{}{"ports": "invalid"},
+		}}
+		Expect(withDefaults(s, obj)).To(BeIdenticalTo(obj))
+	})
+})
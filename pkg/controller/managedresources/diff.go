@@ -0,0 +1,120 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"sort"
+
+	"github.com/gardener/gardener-resource-manager/pkg/audit"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// BundleDiff is the difference between two versions of the objects of a ManagedResource.
+type BundleDiff struct {
+	// Added are the objects which are only contained in the new version.
+	Added []*unstructured.Unstructured
+	// Removed are the objects which are only contained in the old version, they are deleted by the reconciler.
+	Removed []*unstructured.Unstructured
+	// Changed are the objects which are contained in both versions but differ.
+	Changed []ObjectChange
+}
+
+// ObjectChange is an object which differs between two versions of the objects of a ManagedResource.
+type ObjectChange struct {
+	Old *unstructured.Unstructured
+	New *unstructured.Unstructured
+	// Fields are the paths of the changed fields, see audit.Changes.
+	Fields []string
+}
+
+// Diff decodes the objects of the old and the new secrets of a ManagedResource and returns the objects that are
+// added, removed or changed, e.g. for logging or rejecting risky changes before the secrets are updated. Objects are
+// identified like the reconciler does, i.e. by their group, kind, namespace and name, so that an object whose API
+// version changes is reported as changed. The given mapper is used for defaulting the namespaces of the objects like
+// Render does, if it is nil the namespace of all objects except Namespaces is defaulted. All lists are sorted. It fails
+// if the old or the new secrets contain an object multiple times.
+func Diff(log logr.Logger, mapper meta.RESTMapper, oldSecrets, newSecrets []*corev1.Secret) (*BundleDiff, error) {
+	if mapper == nil {
+		mapper = meta.NewDefaultRESTMapper(nil)
+	}
+
+	oldObjs, err := decodeAllSecrets(log, mapper, oldSecrets)
+	if err != nil {
+		return nil, err
+	}
+	newObjs, err := decodeAllSecrets(log, mapper, newSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	oldIndex := make(map[string]*unstructured.Unstructured, len(oldObjs))
+	for _, obj := range oldObjs {
+		oldIndex[objectKeyFromUnstructured(obj)] = obj
+	}
+
+	diff := &BundleDiff{}
+	for _, obj := range newObjs {
+		key := objectKeyFromUnstructured(obj)
+		old, ok := oldIndex[key]
+		if !ok {
+			diff.Added = append(diff.Added, obj)
+			continue
+		}
+		delete(oldIndex, key)
+
+		if fields := audit.Changes(old.Object, obj.Object); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ObjectChange{Old: old, New: obj, Fields: fields})
+		}
+	}
+	for _, obj := range oldIndex {
+		diff.Removed = append(diff.Removed, obj)
+	}
+
+	sortObjects(diff.Added)
+	sortObjects(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return objectKeyFromUnstructured(diff.Changed[i].New) < objectKeyFromUnstructured(diff.Changed[j].New)
+	})
+	return diff, nil
+}
+
+func sortObjects(objs []*unstructured.Unstructured) {
+	sort.Slice(objs, func(i, j int) bool { return objectKeyFromUnstructured(objs[i]) < objectKeyFromUnstructured(objs[j]) })
+}
+
+// Empty returns true if both versions contain the same objects.
+func (d *BundleDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// RemovedOf returns the removed objects of the given kinds, e.g. for rejecting the removal of
+// CustomResourceDefinitions.
+func (d *BundleDiff) RemovedOf(groupKinds ...schema.GroupKind) []*unstructured.Unstructured {
+	var out []*unstructured.Unstructured
+	for _, obj := range d.Removed {
+		for _, gk := range groupKinds {
+			if obj.GroupVersionKind().GroupKind() == gk {
+				out = append(out, obj)
+				break
+			}
+		}
+	}
+	return out
+}
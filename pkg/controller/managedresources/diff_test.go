@@ -0,0 +1,111 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Diff", func() {
+	newSecret := func(data string) []*corev1.Secret {
+		return []*corev1.Secret{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+			Data:       map[string][]byte{"objects.yaml": []byte(data)},
+		}}
+	}
+
+	names := func(objs []*unstructured.Unstructured) []string {
+		out := make([]string, 0, len(objs))
+		for _, obj := range objs {
+			out = append(out, unstructuredToString(obj))
+		}
+		return out
+	}
+
+	const (
+		crd = `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.example.com
+`
+		configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  foo: bar
+`
+		changedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  foo: baz
+`
+		deployment = `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: dep
+  namespace: kube-system
+`
+		upgradedDeployment = `apiVersion: extensions/v1
+kind: Deployment
+metadata:
+  name: dep
+  namespace: kube-system
+`
+		secret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+`
+	)
+
+	It("should return the added, removed and changed objects", func() {
+		diff, err := Diff(runtimelog.NullLogger{}, nil,
+			newSecret(crd+"---\n"+configMap+"---\n"+deployment),
+			newSecret(changedConfigMap+"---\n"+upgradedDeployment+"---\n"+secret))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(diff.Empty()).To(BeFalse())
+		Expect(names(diff.Added)).To(Equal([]string{"v1/Secret/default/secret"}))
+		Expect(names(diff.Removed)).To(Equal([]string{"apiextensions.k8s.io/v1beta1/CustomResourceDefinition/default/foos.example.com"}))
+		Expect(diff.Changed).To(HaveLen(2))
+		Expect(unstructuredToString(diff.Changed[0].Old)).To(Equal("v1/ConfigMap/default/cm"))
+		Expect(diff.Changed[0].Fields).To(Equal([]string{"data.foo"}))
+		Expect(unstructuredToString(diff.Changed[1].New)).To(Equal("extensions/v1/Deployment/kube-system/dep"))
+		Expect(diff.Changed[1].Fields).To(Equal([]string{"apiVersion"}))
+
+		Expect(names(diff.RemovedOf(schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}))).To(HaveLen(1))
+		Expect(diff.RemovedOf(schema.GroupKind{Kind: "ConfigMap"})).To(BeEmpty())
+	})
+
+	It("should return an empty diff for equal objects in a different order", func() {
+		diff, err := Diff(runtimelog.NullLogger{}, nil, newSecret(configMap+"---\n"+secret), newSecret(secret+"---\n"+configMap))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Empty()).To(BeTrue())
+	})
+
+	It("should fail if objects cannot be decoded", func() {
+		_, err := Diff(runtimelog.NullLogger{}, nil, newSecret(configMap), newSecret("foo: [bar"))
+		Expect(err).To(MatchError(ContainSubstring("could not decode all objects")))
+	})
+})
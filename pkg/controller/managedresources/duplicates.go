@@ -0,0 +1,85 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicateObjectPolicy describes how ManagedResources containing the same object multiple times are handled.
+type DuplicateObjectPolicy string
+
+const (
+	// DuplicateObjectPolicyFail neither applies nor deletes any object of ManagedResources containing duplicate objects.
+	DuplicateObjectPolicyFail DuplicateObjectPolicy = "Fail"
+	// DuplicateObjectPolicyWarn applies all occurrences of duplicate objects in their order (i.e. the last one wins),
+	// but records a warning event on the ManagedResource.
+	DuplicateObjectPolicyWarn DuplicateObjectPolicy = "Warn"
+)
+
+// ParseDuplicateObjectPolicy parses the given policy for duplicate objects.
+func ParseDuplicateObjectPolicy(policy string) (DuplicateObjectPolicy, error) {
+	switch p := DuplicateObjectPolicy(policy); p {
+	case DuplicateObjectPolicyFail, DuplicateObjectPolicyWarn:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown policy for duplicate objects %q, must be one of %s or %s", policy, DuplicateObjectPolicyFail, DuplicateObjectPolicyWarn)
+}
+
+// objectSource is the position of an object in the secrets of a ManagedResource.
+type objectSource struct {
+	secret            string
+	secretKey         string
+	objectIndexInFile int
+}
+
+func (s objectSource) String() string {
+	return fmt.Sprintf("index %d in '%s' in secret '%s'", s.objectIndexInFile, s.secretKey, s.secret)
+}
+
+// duplicateObject is an object which is contained multiple times in the secrets of a ManagedResource.
+type duplicateObject struct {
+	key     string
+	sources []objectSource
+}
+
+func (d *duplicateObject) String() string {
+	sources := make([]string, 0, len(d.sources))
+	for _, source := range d.sources {
+		sources = append(sources, source.String())
+	}
+	return fmt.Sprintf("%s is contained at %s", d.key, strings.Join(sources, " and "))
+}
+
+// duplicateObjects returns the objects of the given sources (by object key) which are contained more than once, in
+// the order of their first occurrence.
+func duplicateObjects(keys []string, sources map[string][]objectSource) []*duplicateObject {
+	var duplicates []*duplicateObject
+	for _, key := range keys {
+		if len(sources[key]) > 1 {
+			duplicates = append(duplicates, &duplicateObject{key: key, sources: sources[key]})
+		}
+	}
+	return duplicates
+}
+
+func duplicateObjectsMessage(duplicates []*duplicateObject) string {
+	messages := make([]string, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		messages = append(messages, duplicate.String())
+	}
+	return strings.Join(messages, "; ")
+}
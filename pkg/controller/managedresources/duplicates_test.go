@@ -0,0 +1,103 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Duplicate objects", func() {
+	Describe("#ParseDuplicateObjectPolicy", func() {
+		It("should parse the known policies", func() {
+			Expect(ParseDuplicateObjectPolicy("Fail")).To(Equal(DuplicateObjectPolicyFail))
+			Expect(ParseDuplicateObjectPolicy("Warn")).To(Equal(DuplicateObjectPolicyWarn))
+		})
+
+		It("should reject unknown policies", func() {
+			_, err := ParseDuplicateObjectPolicy("Ignore")
+			Expect(err).To(MatchError(`unknown policy for duplicate objects "Ignore", must be one of Fail or Warn`))
+		})
+	})
+
+	Describe("#decodeSecrets", func() {
+		var mapper *meta.DefaultRESTMapper
+
+		BeforeEach(func() {
+			mapper = meta.NewDefaultRESTMapper(nil)
+			mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+		})
+
+		It("should report objects contained multiple times with all their sources", func() {
+			secrets := []*corev1.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+					Data: map[string][]byte{"deployments.yaml": []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: other
+`)},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "baz"},
+					Data: map[string][]byte{"deployment.yaml": []byte(`apiVersion: apps/v1beta2
+kind: Deployment
+metadata:
+  name: dep
+  namespace: default
+`)},
+				},
+			}
+
+			objs, decodingErrors, duplicates, complete := decodeSecrets(runtimelog.NullLogger{}, mapper, nil, secrets)
+			Expect(objs).To(HaveLen(3))
+			Expect(decodingErrors).To(BeEmpty())
+			Expect(complete).To(BeFalse())
+			Expect(duplicateObjectsMessage(duplicates)).To(Equal("apps/Deployment/default/dep is contained at index 0 in 'deployments.yaml' in secret 'foo/bar' and index 0 in 'deployment.yaml' in secret 'foo/baz'"))
+		})
+
+		It("should not report objects differing in their namespace", func() {
+			secrets := []*corev1.Secret{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+				Data: map[string][]byte{"deployments.yaml": []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+  namespace: kube-system
+`)},
+			}}
+
+			objs, _, duplicates, complete := decodeSecrets(runtimelog.NullLogger{}, mapper, nil, secrets)
+			Expect(objs).To(HaveLen(2))
+			Expect(duplicates).To(BeEmpty())
+			Expect(complete).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,187 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"errors"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"github.com/hashicorp/go-multierror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClassifiedError is an error of the apply path with a class, which determines how the reconciliation is retried.
+type ClassifiedError interface {
+	error
+	// Class returns the class of the error.
+	Class() resourcesv1alpha1.ErrorClass
+}
+
+// TransientTargetError is an error of the target cluster which is expected to go away without changes of the
+// ManagedResource, e.g. a timeout, an unavailable API server or a namespace which does not exist yet.
+type TransientTargetError struct {
+	Err error
+}
+
+// Error implements `error`.
+func (e *TransientTargetError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *TransientTargetError) Unwrap() error {
+	return e.Err
+}
+
+// Class implements `ClassifiedError`.
+func (e *TransientTargetError) Class() resourcesv1alpha1.ErrorClass {
+	return resourcesv1alpha1.ErrorClassTransientTarget
+}
+
+// PermanentManifestError is an error caused by an object of the ManagedResource, e.g. because it is rejected as
+// invalid by the target cluster. It persists until the object is changed.
+type PermanentManifestError struct {
+	Err error
+}
+
+// Error implements `error`.
+func (e *PermanentManifestError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *PermanentManifestError) Unwrap() error {
+	return e.Err
+}
+
+// Class implements `ClassifiedError`.
+func (e *PermanentManifestError) Class() resourcesv1alpha1.ErrorClass {
+	return resourcesv1alpha1.ErrorClassPermanentManifest
+}
+
+// ConflictError is an error caused by a concurrent change of an object, which persisted after retrying with the
+// current state of the object.
+type ConflictError struct {
+	Err error
+}
+
+// Error implements `error`.
+func (e *ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Class implements `ClassifiedError`.
+func (e *ConflictError) Class() resourcesv1alpha1.ErrorClass {
+	return resourcesv1alpha1.ErrorClassConflict
+}
+
+// RBACDeniedError is an error caused by missing permissions of the gardener-resource-manager in the target cluster.
+// It persists until the permissions are granted.
+type RBACDeniedError struct {
+	Err error
+}
+
+// Error implements `error`.
+func (e *RBACDeniedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *RBACDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// Class implements `ClassifiedError`.
+func (e *RBACDeniedError) Class() resourcesv1alpha1.ErrorClass {
+	return resourcesv1alpha1.ErrorClassRBACDenied
+}
+
+// errorClassesByRetriability are the error classes ordered from the most to the least retriable one.
+var errorClassesByRetriability = []resourcesv1alpha1.ErrorClass{
+	resourcesv1alpha1.ErrorClassTransientTarget,
+	resourcesv1alpha1.ErrorClassConflict,
+	resourcesv1alpha1.ErrorClassRBACDenied,
+	resourcesv1alpha1.ErrorClassPermanentManifest,
+}
+
+// classifyError returns the given error as a ClassifiedError, whose class is determined by the status of the API
+// error it wraps. Errors without a status, e.g. network errors or unknown kinds, are considered transient, as they are
+// either caused by the target cluster or resolved by refreshing the discovery information.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified ClassifiedError
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return &TransientTargetError{Err: err}
+	}
+
+	switch status.Status().Reason {
+	case metav1.StatusReasonConflict, metav1.StatusReasonAlreadyExists:
+		return &ConflictError{Err: err}
+	case metav1.StatusReasonForbidden, metav1.StatusReasonUnauthorized:
+		return &RBACDeniedError{Err: err}
+	case metav1.StatusReasonInvalid, metav1.StatusReasonBadRequest, metav1.StatusReasonMethodNotAllowed,
+		metav1.StatusReasonRequestEntityTooLarge, metav1.StatusReasonNotAcceptable, metav1.StatusReasonUnsupportedMediaType:
+		return &PermanentManifestError{Err: err}
+	default:
+		return &TransientTargetError{Err: err}
+	}
+}
+
+// ErrorClassOf returns the class of the given error. If it aggregates multiple errors, the class of the most
+// retriable one is returned, so that a reconciliation failing for multiple reasons is retried as early as needed.
+// It returns an empty class if none of the errors is classified.
+func ErrorClassOf(err error) resourcesv1alpha1.ErrorClass {
+	classes := map[resourcesv1alpha1.ErrorClass]bool{}
+
+	var multiErr *multierror.Error
+	if errors.As(err, &multiErr) {
+		for _, e := range multiErr.Errors {
+			classes[ErrorClassOf(e)] = true
+		}
+	} else {
+		var classified ClassifiedError
+		if errors.As(err, &classified) {
+			classes[classified.Class()] = true
+		}
+	}
+
+	for _, class := range errorClassesByRetriability {
+		if classes[class] {
+			return class
+		}
+	}
+	return ""
+}
+
+// retriable returns whether errors of the given class are retried with a backoff. Errors of the other classes (at
+// least of the failed objects) persist until the ManagedResource or the permissions in the target cluster change, hence
+// they are only retried with the next sync. Errors without class are retried with a backoff.
+func retriable(class resourcesv1alpha1.ErrorClass) bool {
+	return class != resourcesv1alpha1.ErrorClassPermanentManifest && class != resourcesv1alpha1.ErrorClassRBACDenied
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"errors"
+	"fmt"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"github.com/hashicorp/go-multierror"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Errors", func() {
+	var (
+		configMaps = schema.GroupResource{Resource: "configmaps"}
+		configMap  = schema.GroupKind{Kind: "ConfigMap"}
+	)
+
+	DescribeTable("#classifyError",
+		func(err error, class resourcesv1alpha1.ErrorClass) {
+			wrapped := fmt.Errorf("error during apply of object %q: %w", "foo", err)
+
+			classified := classifyError(wrapped)
+			Expect(ErrorClassOf(classified)).To(Equal(class))
+			Expect(classified).To(MatchError(wrapped.Error()))
+			Expect(errors.Unwrap(classified)).To(BeIdenticalTo(wrapped))
+		},
+		Entry("conflict", apierrors.NewConflict(configMaps, "foo", errors.New("fake")), resourcesv1alpha1.ErrorClassConflict),
+		Entry("already exists", apierrors.NewAlreadyExists(configMaps, "foo"), resourcesv1alpha1.ErrorClassConflict),
+		Entry("forbidden", apierrors.NewForbidden(configMaps, "foo", errors.New("fake")), resourcesv1alpha1.ErrorClassRBACDenied),
+		Entry("unauthorized", apierrors.NewUnauthorized("fake"), resourcesv1alpha1.ErrorClassRBACDenied),
+		Entry("invalid", apierrors.NewInvalid(configMap, "foo", nil), resourcesv1alpha1.ErrorClassPermanentManifest),
+		Entry("bad request", apierrors.NewBadRequest("fake"), resourcesv1alpha1.ErrorClassPermanentManifest),
+		Entry("request entity too large", apierrors.NewRequestEntityTooLargeError("fake"), resourcesv1alpha1.ErrorClassPermanentManifest),
+		Entry("not found", apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "foo"), resourcesv1alpha1.ErrorClassTransientTarget),
+		Entry("timeout", apierrors.NewTimeoutError("fake", 1), resourcesv1alpha1.ErrorClassTransientTarget),
+		Entry("too many requests", apierrors.NewTooManyRequests("fake", 1), resourcesv1alpha1.ErrorClassTransientTarget),
+		Entry("service unavailable", apierrors.NewServiceUnavailable("fake"), resourcesv1alpha1.ErrorClassTransientTarget),
+		Entry("error without status", errors.New("connection refused"), resourcesv1alpha1.ErrorClassTransientTarget),
+	)
+
+	Describe("#classifyError", func() {
+		It("should keep already classified errors", func() {
+			err := &PermanentManifestError{Err: errors.New("fake")}
+			Expect(classifyError(fmt.Errorf("wrapped: %w", err))).To(MatchError("wrapped: fake"))
+			Expect(ErrorClassOf(classifyError(fmt.Errorf("wrapped: %w", err)))).To(Equal(resourcesv1alpha1.ErrorClassPermanentManifest))
+		})
+
+		It("should return nil for nil errors", func() {
+			Expect(classifyError(nil)).To(BeNil())
+		})
+	})
+
+	Describe("#ErrorClassOf", func() {
+		It("should return the most retriable class of aggregated errors", func() {
+			err := multierror.Append(nil,
+				&PermanentManifestError{Err: errors.New("invalid")},
+				&RBACDeniedError{Err: errors.New("forbidden")},
+				errors.New("unclassified"),
+			)
+			Expect(ErrorClassOf(err)).To(Equal(resourcesv1alpha1.ErrorClassRBACDenied))
+
+			err = multierror.Append(err, &ConflictError{Err: errors.New("conflict")})
+			Expect(ErrorClassOf(err)).To(Equal(resourcesv1alpha1.ErrorClassConflict))
+
+			err = multierror.Append(err, &TransientTargetError{Err: errors.New("timeout")})
+			Expect(ErrorClassOf(err)).To(Equal(resourcesv1alpha1.ErrorClassTransientTarget))
+		})
+
+		It("should return an empty class for unclassified errors", func() {
+			Expect(ErrorClassOf(errors.New("fake"))).To(BeEmpty())
+			Expect(ErrorClassOf(multierror.Append(nil, errors.New("fake")))).To(BeEmpty())
+		})
+	})
+
+	Describe("#retriable", func() {
+		It("should only retry transient errors and conflicts with a backoff", func() {
+			Expect(retriable(resourcesv1alpha1.ErrorClassTransientTarget)).To(BeTrue())
+			Expect(retriable(resourcesv1alpha1.ErrorClassConflict)).To(BeTrue())
+			Expect(retriable("")).To(BeTrue())
+			Expect(retriable(resourcesv1alpha1.ErrorClassPermanentManifest)).To(BeFalse())
+			Expect(retriable(resourcesv1alpha1.ErrorClassRBACDenied)).To(BeFalse())
+		})
+	})
+})
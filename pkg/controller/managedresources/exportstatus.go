@@ -0,0 +1,105 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// parseExportStatus parses the JSONPath expression of the export-status annotation of an object.
+func parseExportStatus(expression string) (*jsonpath.JSONPath, error) {
+	path := jsonpath.New(resourcesv1alpha1.ExportStatus).AllowMissingKeys(true)
+	if err := path.Parse(expression); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation %q: %v", resourcesv1alpha1.ExportStatus, expression, err)
+	}
+	return path, nil
+}
+
+// ExportStatus returns the value of the given JSONPath expression evaluated on the status of the given object. Only
+// the status is visible to the expression, other fields of the object are never exported. It returns nil if the
+// expression does not match any field (e.g. while the status has not been set yet), and a list if it matches several.
+func ExportStatus(obj runtime.Object, expression string) (*runtime.RawExtension, error) {
+	path, err := parseExportStatus(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var content map[string]interface{}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content = u.UnstructuredContent()
+	} else if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+		return nil, err
+	}
+
+	results, err := path.FindResults(map[string]interface{}{"status": content["status"]})
+	if err != nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+
+	var raw []byte
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		raw, err = json.Marshal(values[0])
+	default:
+		raw, err = json.Marshal(values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
+
+// MergeExportedStatus returns the given new exported values, keeping the last update time of the old values of the
+// same objects if they did not change. The last update time of changed values is set to the given time.
+func MergeExportedStatus(oldExported, newExported []resourcesv1alpha1.ExportedStatus, now metav1.Time) []resourcesv1alpha1.ExportedStatus {
+	if len(newExported) == 0 {
+		return nil
+	}
+
+	oldByObject := make(map[string]resourcesv1alpha1.ExportedStatus, len(oldExported))
+	for _, exported := range oldExported {
+		oldByObject[objectKey(exported.GroupVersionKind().Group, exported.Kind, exported.Namespace, exported.Name)] = exported
+	}
+
+	out := make([]resourcesv1alpha1.ExportedStatus, 0, len(newExported))
+	for _, exported := range newExported {
+		old, ok := oldByObject[objectKey(exported.GroupVersionKind().Group, exported.Kind, exported.Namespace, exported.Name)]
+		if ok && bytes.Equal(old.Value.Raw, exported.Value.Raw) {
+			exported.LastUpdateTime = old.LastUpdateTime
+		} else {
+			exported.LastUpdateTime = now
+		}
+		out = append(out, exported)
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources_test
+
+import (
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("ExportStatus", func() {
+	Describe("#ExportStatus", func() {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "migrate"},
+			Spec:       batchv1.JobSpec{Parallelism: func(v int32) *int32 { return &v }(2)},
+			Status: batchv1.JobStatus{
+				Succeeded: 1,
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, Message: "done"},
+				},
+			},
+		}
+
+		It("should export a field of the status", func() {
+			Expect(ExportStatus(job, "{.status.succeeded}")).To(Equal(&runtime.RawExtension{Raw: []byte(`1`)}))
+			Expect(ExportStatus(job, `{.status.conditions[?(@.type=="Complete")].message}`)).To(Equal(&runtime.RawExtension{Raw: []byte(`"done"`)}))
+		})
+
+		It("should export several fields as list", func() {
+			Expect(ExportStatus(job, "{.status.succeeded}{.status.conditions[*].type}")).To(Equal(&runtime.RawExtension{Raw: []byte(`[1,"Complete"]`)}))
+		})
+
+		It("should export unstructured objects", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Migration",
+				"status":     map[string]interface{}{"output": map[string]interface{}{"version": "v2"}},
+			}}
+			Expect(ExportStatus(obj, "{.status}")).To(Equal(&runtime.RawExtension{Raw: []byte(`{"output":{"version":"v2"}}`)}))
+		})
+
+		It("should not export fields outside of the status", func() {
+			Expect(ExportStatus(job, "{.spec.parallelism}")).To(BeNil())
+			Expect(ExportStatus(job, "{.status.failed}{.status.active}")).To(BeNil())
+		})
+
+		It("should fail for invalid expressions", func() {
+			_, err := ExportStatus(job, "{.status")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#MergeExportedStatus", func() {
+		var (
+			before = metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+			now    = metav1.NewTime(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+
+			ref = func(name string) corev1.ObjectReference {
+				return corev1.ObjectReference{APIVersion: "batch/v1", Kind: "Job", Namespace: "default", Name: name}
+			}
+			exported = func(name, value string, lastUpdateTime metav1.Time) resourcesv1alpha1.ExportedStatus {
+				return resourcesv1alpha1.ExportedStatus{ObjectReference: ref(name), Value: runtime.RawExtension{Raw: []byte(value)}, LastUpdateTime: lastUpdateTime}
+			}
+		)
+
+		It("should keep the last update time of unchanged values only", func() {
+			Expect(MergeExportedStatus(
+				[]resourcesv1alpha1.ExportedStatus{exported("unchanged", `1`, before), exported("changed", `1`, before), exported("removed", `1`, before)},
+				[]resourcesv1alpha1.ExportedStatus{exported("unchanged", `1`, metav1.Time{}), exported("changed", `2`, metav1.Time{}), exported("added", `1`, metav1.Time{})},
+				now,
+			)).To(Equal([]resourcesv1alpha1.ExportedStatus{exported("unchanged", `1`, before), exported("changed", `2`, now), exported("added", `1`, now)}))
+		})
+
+		It("should return nil if no values are exported", func() {
+			Expect(MergeExportedStatus([]resourcesv1alpha1.ExportedStatus{exported("removed", `1`, before)}, nil, now)).To(BeNil())
+		})
+	})
+})
@@ -22,7 +22,9 @@ import (
 
 	"github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -30,66 +32,136 @@ import (
 // DefaultClass is used a resource class is no class is specified on the command line
 const DefaultClass = "resources"
 
-// ClassFilter keeps the resource class for the actual controller instance
+// WildcardClass can be given as resource class to make the actual controller instance responsible for
+// ManagedResources of all classes.
+const WildcardClass = "*"
+
+// ClassFilter keeps the resource classes for the actual controller instance
 // and is used as Filter predicate for events finally passed to the controller
 type ClassFilter struct {
-	resourceClass string
+	resourceClass   string
+	resourceClasses sets.String
+	wildcard        bool
 
 	finalizer string
+	selector  labels.Selector
+	shard     *Shard
 }
 
 var _ predicate.Predicate = &ClassFilter{}
 
-// NewClassFilter returns a new `ClassFilter` instance.
+// NewClassFilter returns a new `ClassFilter` instance. The given class may be a comma-separated list of classes, the
+// first one of them is the primary class of the instance (see `ResourceClass`). If it contains `WildcardClass`, the
+// instance is responsible for all classes.
 func NewClassFilter(class string) *ClassFilter {
-	if class == "" {
-		class = DefaultClass
+	f := &ClassFilter{resourceClasses: sets.NewString()}
+
+	for _, c := range strings.Split(class, ",") {
+		switch c = strings.TrimSpace(c); c {
+		case "":
+		case WildcardClass:
+			f.wildcard = true
+		default:
+			if f.resourceClass == "" {
+				f.resourceClass = c
+			}
+			f.resourceClasses.Insert(c)
+		}
 	}
 
-	finalizer := FinalizerName + "-" + class
+	if f.resourceClass == "" {
+		f.resourceClass = DefaultClass
+		f.resourceClasses.Insert(DefaultClass)
+	}
+	f.finalizer = finalizerNameForClass(f.resourceClass)
+
+	return f
+}
+
+func finalizerNameForClass(class string) string {
 	if class == DefaultClass {
-		finalizer = FinalizerName
+		return FinalizerName
 	}
+	return FinalizerName + "-" + class
+}
 
-	return &ClassFilter{
-		resourceClass: class,
-		finalizer:     finalizer,
+// ResourceClassOf returns the resource class of the given ManagedResource, i.e. `DefaultClass` if it doesn't specify a
+// class.
+func ResourceClassOf(mr *v1alpha1.ManagedResource) string {
+	if mr.Spec.Class != nil && *mr.Spec.Class != "" {
+		return *mr.Spec.Class
 	}
+	return DefaultClass
+}
+
+// WithLabelSelector restricts the ManagedResources handled by the actual controller instance to the ones matching
+// the given selector. ManagedResources of the resource class not matching the selector are ignored altogether, i.e.
+// they are neither reconciled nor deleted, so that they can be handled by another instance for the same class.
+func (f *ClassFilter) WithLabelSelector(selector labels.Selector) *ClassFilter {
+	f.selector = selector
+	return f
 }
 
-// ResourceClass returns the actually configured resource class
+// WithShard restricts the ManagedResources handled by the actual controller instance to the ones belonging to the
+// given shard. Like for `WithLabelSelector`, ManagedResources of other shards are ignored altogether.
+func (f *ClassFilter) WithShard(shard *Shard) *ClassFilter {
+	f.shard = shard
+	return f
+}
+
+// ResourceClass returns the primary resource class of the actual controller instance, i.e. the first configured one
 func (f *ClassFilter) ResourceClass() string {
 	return f.resourceClass
 }
 
-// FinalizerName determines the finalizer name to be used for the actual resource class
+// FinalizerName determines the finalizer name to be used for the primary resource class
 func (f *ClassFilter) FinalizerName() string {
 	return f.finalizer
 }
 
+// FinalizerNameFor determines the finalizer name to be used for the resource class of the given ManagedResource
+func (f *ClassFilter) FinalizerNameFor(o runtime.Object) string {
+	return finalizerNameForClass(ResourceClassOf(o.(*v1alpha1.ManagedResource)))
+}
+
+// OwnsFinalizer checks whether the given finalizer belongs to one of the resource classes of the actual controller
+// instance
+func (f *ClassFilter) OwnsFinalizer(finalizer string) bool {
+	if f.wildcard {
+		return finalizer == FinalizerName || strings.HasPrefix(finalizer, FinalizerName+"-")
+	}
+	for class := range f.resourceClasses {
+		if finalizer == finalizerNameForClass(class) {
+			return true
+		}
+	}
+	return false
+}
+
 // Responsible checks whether an object should be managed by the actual controller instance
 func (f *ClassFilter) Responsible(o runtime.Object) bool {
-	r := o.(*v1alpha1.ManagedResource)
-	c := ""
-	if r.Spec.Class != nil && *r.Spec.Class != "" {
-		c = *r.Spec.Class
-	}
-	return c == f.resourceClass || (c == "" && f.resourceClass == DefaultClass)
+	return f.wildcard || f.resourceClasses.Has(ResourceClassOf(o.(*v1alpha1.ManagedResource)))
 }
 
 // Active checks whether a dedicated object must be handled by the actual controller
 // instance. This is split into two conditions. An object must be handled
-// if it has already been handled, indicated by the actual finalizer, or
+// if it has already been handled, indicated by one of the actual finalizers, or
 // if the actual controller is responsible for the object.
 func (f *ClassFilter) Active(o runtime.Object) (action bool, responsible bool) {
 	busy := false
-	responsible = f.Responsible(o)
 	r := o.(*v1alpha1.ManagedResource)
+	if f.selector != nil && !f.selector.Matches(labels.Set(r.Labels)) {
+		return false, false
+	}
+	if f.shard != nil && !f.shard.Owns(r) {
+		return false, false
+	}
+	responsible = f.Responsible(o)
 
 	for _, finalizer := range r.GetFinalizers() {
 		if strings.HasPrefix(finalizer, FinalizerName) {
 			busy = true
-			if finalizer == f.finalizer {
+			if f.OwnsFinalizer(finalizer) {
 				action = true
 				return
 			}
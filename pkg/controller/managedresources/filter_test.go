@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	. "github.com/onsi/ginkgo/extensions/table"
 )
@@ -81,6 +82,85 @@ var _ = Describe("ClassFilter", func() {
 		Entry("is not responsible and don't take action", mrNewClass, "", false, false),
 	)
 
+	DescribeTable("Active with multiple classes",
+		func(mr *v1alpha1.ManagedResource, class string, action, responsible bool) {
+			filter := managedresources.NewClassFilter(class)
+
+			act, resp := filter.Active(mr)
+			Expect(act).To(Equal(action))
+			Expect(resp).To(Equal(responsible))
+		},
+		Entry("is responsible and take action for the primary class", mrNewClass, "new,other", true, true),
+		Entry("is responsible and take action for another class", mrNewClass, "other, new", true, true),
+		Entry("is responsible and take action after a change to another class of the instance", mrNewClassOldFinalizer, "resources,new", true, true),
+		Entry("is not responsible and take action after a change to a class of another instance", mrNewClassOldFinalizer, "resources,other", true, false),
+		Entry("is responsible and take action for all classes", mrNewClassOldFinalizer, managedresources.WildcardClass, true, true),
+		Entry("is not responsible and don't take action", mrNewClass, "resources,other", false, false),
+	)
+
+	Describe("multiple classes", func() {
+		It("should use the first class as primary class", func() {
+			filter := managedresources.NewClassFilter("new,other")
+			Expect(filter.ResourceClass()).To(Equal(classNew))
+			Expect(filter.FinalizerName()).To(Equal(finalizerNew))
+		})
+
+		It("should use the default class as primary class for the wildcard", func() {
+			filter := managedresources.NewClassFilter(managedresources.WildcardClass)
+			Expect(filter.ResourceClass()).To(Equal(managedresources.DefaultClass))
+			Expect(filter.FinalizerName()).To(Equal(finalizerOld))
+		})
+
+		It("should determine the finalizer of the class of a ManagedResource", func() {
+			filter := managedresources.NewClassFilter("resources,new")
+			Expect(filter.FinalizerNameFor(mrOldClass)).To(Equal(finalizerOld))
+			Expect(filter.FinalizerNameFor(mrNewClassOldFinalizer)).To(Equal(finalizerNew))
+		})
+
+		It("should own the finalizers of all classes", func() {
+			filter := managedresources.NewClassFilter("resources,new")
+			Expect(filter.OwnsFinalizer(finalizerOld)).To(BeTrue())
+			Expect(filter.OwnsFinalizer(finalizerNew)).To(BeTrue())
+			Expect(filter.OwnsFinalizer(managedresources.FinalizerName + "-other")).To(BeFalse())
+			Expect(filter.OwnsFinalizer("foo")).To(BeFalse())
+
+			filter = managedresources.NewClassFilter(managedresources.WildcardClass)
+			Expect(filter.OwnsFinalizer(managedresources.FinalizerName + "-other")).To(BeTrue())
+			Expect(filter.OwnsFinalizer("foo")).To(BeFalse())
+		})
+	})
+
+	DescribeTable("Active with label selector",
+		func(mrLabels map[string]string, action, responsible bool) {
+			filter := managedresources.NewClassFilter(classNew).WithLabelSelector(labels.SelectorFromSet(labels.Set{"team": "foo"}))
+
+			mr := mrNewClass.DeepCopy()
+			mr.Labels = mrLabels
+
+			act, resp := filter.Active(mr)
+			Expect(act).To(Equal(action))
+			Expect(resp).To(Equal(responsible))
+		},
+		Entry("is responsible and take action if the selector matches", map[string]string{"team": "foo"}, true, true),
+		Entry("ignores the resource if the selector doesn't match", map[string]string{"team": "bar"}, false, false),
+		Entry("ignores the resource without labels", nil, false, false),
+	)
+
+	DescribeTable("Active with shard",
+		func(shardLabel string, action, responsible bool) {
+			filter := managedresources.NewClassFilter(classNew).WithShard(&managedresources.Shard{Index: 1, Count: 2})
+
+			mr := mrNewClass.DeepCopy()
+			mr.Labels = map[string]string{managedresources.ShardLabel: shardLabel}
+
+			act, resp := filter.Active(mr)
+			Expect(act).To(Equal(action))
+			Expect(resp).To(Equal(responsible))
+		},
+		Entry("is responsible and take action if the resource belongs to the shard", "1", true, true),
+		Entry("ignores the resource if it belongs to another shard", "0", false, false),
+	)
+
 	DescribeTable("Generic",
 		func(mr *v1alpha1.ManagedResource, class string, expectation bool) {
 			filter := managedresources.NewClassFilter(class)
@@ -0,0 +1,357 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+	"github.com/gardener/gardener-resource-manager/pkg/audit"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GarbageCollectorOptions configures the `GarbageCollector`.
+type GarbageCollectorOptions struct {
+	// SyncPeriod is the duration between two runs.
+	SyncPeriod time.Duration
+	// MinAge is the minimum age of objects which are deleted. Younger objects might have been created by a
+	// reconciliation whose status has not been written yet.
+	MinAge time.Duration
+	// DryRun only logs the objects which would be deleted.
+	DryRun bool
+	// DeletionSelector restricts the objects which are deleted in addition to the origin label (all if nil).
+	DeletionSelector labels.Selector
+}
+
+// GarbageCollector periodically deletes objects in the target cluster which carry the origin label of one of the
+// resource classes of the actual controller instance, but are not contained in the status of any ManagedResource and
+// whose ManagedResource (according to their origin annotation) does not exist anymore, e.g. because the finalizer of
+// a ManagedResource was removed while its objects were still being deleted. Objects kept after the deletion of their
+// ManagedResource are deleted once they are expired.
+type GarbageCollector struct {
+	ctx          context.Context
+	log          logr.Logger
+	client       client.Reader
+	targetClient client.Client
+	discovery    discovery.ServerResourcesInterface
+	class        *ClassFilter
+	clusterID    string
+	auditSink    audit.Sink
+	options      GarbageCollectorOptions
+	now          func() time.Time
+}
+
+// NewGarbageCollector creates a new GarbageCollector. Objects annotated with the origin of another cluster ID than
+// the given one are never deleted. The client reads the ManagedResources of the source cluster, it
+// must be able to see all of them (i.e. it must not be restricted to some namespaces). The target client should read
+// from the API server directly, as the objects of all resources served by the target cluster are listed. Collections
+// are aborted when the given context is cancelled.
+func NewGarbageCollector(ctx context.Context, log logr.Logger, c client.Reader, targetClient client.Client, discovery discovery.ServerResourcesInterface, class *ClassFilter, clusterID string, auditSink audit.Sink, options GarbageCollectorOptions) *GarbageCollector {
+	return &GarbageCollector{
+		ctx:          ctx,
+		log:          log,
+		client:       c,
+		targetClient: targetClient,
+		discovery:    discovery,
+		class:        class,
+		clusterID:    clusterID,
+		auditSink:    auditSink,
+		options:      options,
+		now:          time.Now,
+	}
+}
+
+// Start implements `manager.Runnable`. It collects garbage after each sync period until the context of the garbage
+// collector is cancelled or the given channel is closed.
+func (g *GarbageCollector) Start(stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(g.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := g.Collect(ctx); err != nil {
+			g.log.Error(err, "Garbage collection failed")
+		}
+	}, g.options.SyncPeriod)
+	return nil
+}
+
+// NeedLeaderElection implements `manager.LeaderElectionRunnable`.
+func (g *GarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Collect deletes all orphaned objects once.
+func (g *GarbageCollector) Collect(ctx context.Context) error {
+	ctx, reconcileID := utils.WithReconcileID(ctx)
+	log := g.log.WithValues(utils.LogKeyReconcileID, reconcileID, "dryRun", g.options.DryRun)
+
+	inventory, err := g.inventory(ctx, log)
+	if err != nil {
+		return err
+	}
+
+	resources, err := g.deletableResources(log)
+	if err != nil {
+		return err
+	}
+
+	var (
+		auditRecorder = audit.NewRecorder(g.auditSink, nil, types.NamespacedName{}, reconcileID)
+		selector      = g.originSelector()
+		deleted       int
+		errorList     []string
+	)
+
+	for _, gvk := range resources {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		if err := utils.ListPages(ctx, g.targetClient, list, utils.DefaultPageSize, func() error {
+			for i := range list.Items {
+				obj := &list.Items[i]
+				if !g.orphaned(log, inventory, obj) {
+					continue
+				}
+
+				if err := g.delete(ctx, log, obj, auditRecorder); err != nil {
+					errorList = append(errorList, err.Error())
+					continue
+				}
+				deleted++
+			}
+			return nil
+		}, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			if apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) || meta.IsNoMatchError(err) {
+				// the resource was removed from the cluster in the meantime or cannot be listed
+				continue
+			}
+			errorList = append(errorList, fmt.Sprintf("could not list %s: %v", gvk.GroupKind(), err))
+		}
+	}
+
+	log.Info("Finished garbage collection", "deleted", deleted)
+	if len(errorList) > 0 {
+		return fmt.Errorf("could not delete all orphaned objects: %s", strings.Join(errorList, ", "))
+	}
+	return nil
+}
+
+// inventory contains the ManagedResources and the objects contained in their status.
+type inventory struct {
+	objects          *ObjectIndex
+	managedResources sets.String
+	// complete is false if the status of a ManagedResource of the actual resource classes might not contain all of its
+	// objects.
+	complete bool
+}
+
+func (g *GarbageCollector) inventory(ctx context.Context, log logr.Logger) (*inventory, error) {
+	list := &resourcesv1alpha1.ManagedResourceList{}
+	if err := g.client.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("could not list ManagedResources: %w", err)
+	}
+
+	var (
+		references []resourcesv1alpha1.ObjectReference
+		inv        = &inventory{managedResources: sets.NewString(), complete: true}
+	)
+
+	for _, mr := range list.Items {
+		inv.managedResources.Insert(client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}.String())
+
+		// the status is not updated if the objects could not be applied, although some of them might have been created
+		if g.class.Responsible(&mr) && inv.complete {
+			if condition := resourcesv1alpha1helper.GetCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesApplied); condition != nil && condition.Reason == resourcesv1alpha1.ConditionApplyFailed {
+				log.Info("Not deleting objects without origin annotation as the resources of a ManagedResource could not be applied", "managedResource", client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name})
+				inv.complete = false
+			}
+		}
+
+		// objects of ManagedResources of other classes are kept as well, they might still be labeled with the class
+		// the ManagedResource had before
+		references = append(references, mr.Status.Resources...)
+	}
+
+	inv.objects = NewObjectIndex(references, NewEquivalences())
+	return inv, nil
+}
+
+// deletableResources returns the preferred version of all resources in the target cluster, which can be listed and
+// deleted.
+func (g *GarbageCollector) deletableResources(log logr.Logger) ([]schema.GroupVersionKind, error) {
+	resourceLists, err := g.discovery.ServerPreferredResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, fmt.Errorf("could not discover the resources of the target cluster: %w", err)
+		}
+		// collect the garbage of all other groups nevertheless
+		log.Info("Could not discover all resources of the target cluster", "err", err.Error())
+	}
+
+	var resources []schema.GroupVersionKind
+	for _, resourceList := range discovery.FilteredBy(discovery.SupportsAllVerbs{Verbs: []string{"list", "delete"}}, resourceLists) {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			// subresources cannot be listed
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+			resources = append(resources, groupVersion.WithKind(resource.Kind))
+		}
+	}
+	return resources, nil
+}
+
+// originSelector selects the objects labeled with one of the resource classes of the actual controller instance, which
+// match the deletion selector.
+func (g *GarbageCollector) originSelector() labels.Selector {
+	var (
+		requirement *labels.Requirement
+		err         error
+	)
+
+	if g.class.wildcard {
+		requirement, err = labels.NewRequirement(resourcesv1alpha1.OriginLabel, selection.Exists, nil)
+	} else {
+		requirement, err = labels.NewRequirement(resourcesv1alpha1.OriginLabel, selection.In, g.class.resourceClasses.List())
+	}
+	if err != nil {
+		// cannot happen, the classes are valid label values as they are used as label values already
+		panic(err)
+	}
+
+	selector := labels.NewSelector().Add(*requirement)
+	if g.options.DeletionSelector != nil {
+		if requirements, selectable := g.options.DeletionSelector.Requirements(); selectable {
+			selector = selector.Add(requirements...)
+		}
+	}
+	return selector
+}
+
+// orphaned returns whether the given object does not belong to any ManagedResource of the given inventory and may be
+// deleted.
+func (g *GarbageCollector) orphaned(log logr.Logger, inv *inventory, obj *unstructured.Unstructured) bool {
+	ref := resourcesv1alpha1.ObjectReference{ObjectReference: corev1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}}
+	if _, ok := inv.objects.Lookup(ref); ok {
+		return false
+	}
+
+	expiresAt, expiring := obj.GetAnnotations()[resourcesv1alpha1.ExpiresAt]
+
+	if origin, ok := obj.GetAnnotations()[resourcesv1alpha1.OriginAnnotation]; ok {
+		clusterID, key, err := resourcesv1alpha1helper.ParseOrigin(origin)
+		if err != nil {
+			log.Info("Not deleting orphaned object as its origin annotation is invalid", "resource", unstructuredToString(obj), "err", err.Error())
+			return false
+		}
+		// objects of another source cluster and objects of existing ManagedResources, which have not been recorded in
+		// their status yet
+		if clusterID != g.clusterID || inv.managedResources.Has(key.String()) {
+			return false
+		}
+	} else if !expiring && !inv.complete {
+		return false
+	}
+
+	// objects kept after the deletion of their ManagedResource are only deleted after their expiration time
+	if expiring {
+		expirationTime, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			log.Info("Not deleting kept object as its expiration time is invalid", "resource", unstructuredToString(obj), "err", err.Error())
+			return false
+		}
+		if g.now().Before(expirationTime) {
+			return false
+		}
+	}
+
+	switch {
+	case obj.GetDeletionTimestamp() != nil:
+		return false
+	case g.now().Sub(obj.GetCreationTimestamp().Time) < g.options.MinAge:
+		return false
+	case keepObject(obj):
+		return false
+	case !DeletionConfirmed(obj):
+		log.Info("Not deleting orphaned object as "+resourcesv1alpha1.ConfirmationDeletion+" annotation is missing", "resource", unstructuredToString(obj))
+		return false
+	}
+	return true
+}
+
+func (g *GarbageCollector) delete(ctx context.Context, log logr.Logger, obj *unstructured.Unstructured, auditRecorder *audit.Recorder) error {
+	resource := unstructuredToString(obj)
+
+	if g.options.DryRun {
+		log.Info("Would delete orphaned object", "resource", resource)
+		return nil
+	}
+
+	log.Info("Deleting orphaned object", "resource", resource)
+	// the object must not be deleted if it has been adopted by a ManagedResource in the meantime
+	if err := g.targetClient.Delete(ctx, obj, client.Preconditions{UID: uidOf(obj), ResourceVersion: resourceVersionOf(obj)}); client.IgnoreNotFound(err) != nil {
+		if apierrors.IsConflict(err) {
+			return nil
+		}
+		return fmt.Errorf("could not delete %s: %v", resource, err)
+	}
+	auditRecorder.Record(audit.OperationDelete, obj, "object is not contained in any ManagedResource anymore", nil)
+	return nil
+}
+
+func uidOf(obj metav1.Object) *types.UID {
+	uid := obj.GetUID()
+	return &uid
+}
+
+func resourceVersionOf(obj metav1.Object) *string {
+	resourceVersion := obj.GetResourceVersion()
+	return &resourceVersion
+}
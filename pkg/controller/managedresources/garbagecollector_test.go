@@ -0,0 +1,240 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources_test
+
+import (
+	"context"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeDiscovery struct {
+	discovery.ServerResourcesInterface
+	resources []*metav1.APIResourceList
+	err       error
+}
+
+func (d *fakeDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return d.resources, d.err
+}
+
+var _ = Describe("GarbageCollector", func() {
+	var (
+		ctx          = context.TODO()
+		ctrl         *gomock.Controller
+		c            *mockclient.MockClient
+		targetClient *mockclient.MockClient
+
+		fakeDisc *fakeDiscovery
+		options  GarbageCollectorOptions
+		mrs      []resourcesv1alpha1.ManagedResource
+		objects  map[string][]unstructured.Unstructured
+		selector string
+		old      = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	)
+
+	newObject := func(apiVersion, kind, namespace, name string, annotations map[string]string) unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(map[string]string{resourcesv1alpha1.OriginLabel: "seed"})
+		obj.SetAnnotations(annotations)
+		obj.SetCreationTimestamp(old)
+		return obj
+	}
+
+	newReference := func(apiVersion, kind, namespace, name string) resourcesv1alpha1.ObjectReference {
+		return resourcesv1alpha1.ObjectReference{ObjectReference: corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name}}
+	}
+
+	newGarbageCollector := func() *GarbageCollector {
+		return NewGarbageCollector(ctx, log.NullLogger{}, c, targetClient, fakeDisc, NewClassFilter("seed"), "seed", nil, options)
+	}
+
+	expectDeletion := func(obj unstructured.Unstructured) {
+		targetClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&unstructured.Unstructured{}), gomock.Any()).
+			DoAndReturn(func(_ context.Context, deleted runtime.Object, _ ...client.DeleteOption) error {
+				Expect(deleted).To(Equal(&obj))
+				return nil
+			})
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		targetClient = mockclient.NewMockClient(ctrl)
+
+		fakeDisc = &fakeDiscovery{resources: []*metav1.APIResourceList{
+			{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{
+					{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: metav1.Verbs{"list", "delete"}},
+					{Name: "namespaces", Kind: "Namespace", Verbs: metav1.Verbs{"list", "delete"}},
+					{Name: "pods/status", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"list", "delete"}},
+					{Name: "componentstatuses", Kind: "ComponentStatus", Verbs: metav1.Verbs{"list"}},
+				},
+			},
+			{
+				GroupVersion: "extensions/v1beta1",
+				APIResources: []metav1.APIResource{
+					{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: metav1.Verbs{"list", "delete"}},
+				},
+			},
+		}}
+		options = GarbageCollectorOptions{MinAge: time.Hour}
+		selector = resourcesv1alpha1.OriginLabel + " in (seed)"
+
+		mrs = []resourcesv1alpha1.ManagedResource{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+				Spec:       resourcesv1alpha1.ManagedResourceSpec{Class: pointer.StringPtr("seed")},
+				Status: resourcesv1alpha1.ManagedResourceStatus{Resources: []resourcesv1alpha1.ObjectReference{
+					newReference("v1", "ConfigMap", "default", "managed"),
+					newReference("apps/v1", "Deployment", "default", "managed"),
+				}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "other-class"},
+				Status: resourcesv1alpha1.ManagedResourceStatus{Resources: []resourcesv1alpha1.ObjectReference{
+					newReference("v1", "ConfigMap", "default", "other-class"),
+				}},
+			},
+		}
+
+		young := newObject("v1", "ConfigMap", "default", "young", nil)
+		young.SetCreationTimestamp(metav1.Now())
+		objects = map[string][]unstructured.Unstructured{
+			"ConfigMapList": {
+				newObject("v1", "ConfigMap", "default", "managed", nil),
+				newObject("v1", "ConfigMap", "default", "other-class", nil),
+				newObject("v1", "ConfigMap", "default", "orphaned", nil),
+				newObject("v1", "ConfigMap", "default", "kept", map[string]string{resourcesv1alpha1.KeepObject: "true"}),
+				young,
+				newObject("v1", "ConfigMap", "default", "not-recorded", map[string]string{resourcesv1alpha1.OriginAnnotation: "seed:foo/bar"}),
+				newObject("v1", "ConfigMap", "default", "other-cluster", map[string]string{resourcesv1alpha1.OriginAnnotation: "other:foo/deleted"}),
+				newObject("v1", "ConfigMap", "default", "deleted-mr", map[string]string{resourcesv1alpha1.OriginAnnotation: "seed:foo/deleted"}),
+				newObject("v1", "ConfigMap", "default", "expired", map[string]string{
+					resourcesv1alpha1.OriginAnnotation: "seed:foo/deleted",
+					resourcesv1alpha1.ExpiresAt:        time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+				}),
+				newObject("v1", "ConfigMap", "default", "not-expired", map[string]string{
+					resourcesv1alpha1.OriginAnnotation: "seed:foo/deleted",
+					resourcesv1alpha1.ExpiresAt:        time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+				}),
+			},
+			"NamespaceList": {
+				newObject("v1", "Namespace", "", "unconfirmed", nil),
+				newObject("v1", "Namespace", "", "confirmed", map[string]string{resourcesv1alpha1.ConfirmationDeletion: "true"}),
+			},
+			"DeploymentList": {
+				newObject("extensions/v1beta1", "Deployment", "default", "managed", nil),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectLists := func() {
+		c.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{})).
+			DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+				list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
+				return nil
+			})
+		targetClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&unstructured.UnstructuredList{}), gomock.Any()).
+			DoAndReturn(func(_ context.Context, list runtime.Object, opts ...client.ListOption) error {
+				listOpts := &client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				Expect(listOpts.LabelSelector.String()).To(Equal(selector))
+
+				l := list.(*unstructured.UnstructuredList)
+				l.Items = objects[l.GetKind()]
+				return nil
+			}).Times(3)
+	}
+
+	It("should delete orphaned objects", func() {
+		expectLists()
+		expectDeletion(objects["ConfigMapList"][2])
+		expectDeletion(objects["ConfigMapList"][7])
+		expectDeletion(objects["ConfigMapList"][8])
+		expectDeletion(objects["NamespaceList"][1])
+
+		Expect(newGarbageCollector().Collect(ctx)).To(Succeed())
+	})
+
+	It("should only list the orphaned objects in dry run mode", func() {
+		options.DryRun = true
+		expectLists()
+
+		Expect(newGarbageCollector().Collect(ctx)).To(Succeed())
+	})
+
+	It("should only list the objects matching the deletion selector", func() {
+		deletionSelector, err := labels.Parse("landscape=dev")
+		Expect(err).NotTo(HaveOccurred())
+		options.DeletionSelector = deletionSelector
+		selector = "landscape=dev," + resourcesv1alpha1.OriginLabel + " in (seed)"
+
+		objects = map[string][]unstructured.Unstructured{}
+		expectLists()
+
+		Expect(newGarbageCollector().Collect(ctx)).To(Succeed())
+	})
+
+	It("should collect the garbage of the discovered groups if a group could not be discovered", func() {
+		fakeDisc.err = &discovery.ErrGroupDiscoveryFailed{Groups: map[schema.GroupVersion]error{{Group: "metrics.k8s.io", Version: "v1beta1"}: nil}}
+		expectLists()
+		expectDeletion(objects["ConfigMapList"][2])
+		expectDeletion(objects["ConfigMapList"][7])
+		expectDeletion(objects["ConfigMapList"][8])
+		expectDeletion(objects["NamespaceList"][1])
+
+		Expect(newGarbageCollector().Collect(ctx)).To(Succeed())
+	})
+
+	It("should only delete objects with origin annotation if the resources of a ManagedResource could not be applied", func() {
+		mrs[0].Status.Conditions = []resourcesv1alpha1.ManagedResourceCondition{{
+			Type:   resourcesv1alpha1.ResourcesApplied,
+			Status: resourcesv1alpha1.ConditionFalse,
+			Reason: resourcesv1alpha1.ConditionApplyFailed,
+		}}
+		expectLists()
+		expectDeletion(objects["ConfigMapList"][7])
+		expectDeletion(objects["ConfigMapList"][8])
+
+		Expect(newGarbageCollector().Collect(ctx)).To(Succeed())
+	})
+})
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// generatedNameHashLength is the number of characters of the template hash appended to the `metadata.generateName`
+// of an object.
+const generatedNameHashLength = 8
+
+// setGeneratedName names the given object without `metadata.name` by appending the hash of its template to its
+// `metadata.generateName`. The name is stable as long as the template doesn't change, so that the object is only
+// re-created (under a new name, while the old one is pruned) if the template changes.
+func setGeneratedName(obj *unstructured.Unstructured) error {
+	if obj.GetName() != "" || obj.GetGenerateName() == "" {
+		return nil
+	}
+
+	template, err := json.Marshal(obj.Object)
+	if err != nil {
+		return fmt.Errorf("could not compute the template hash of %s %q: %w", obj.GetKind(), obj.GetGenerateName(), err)
+	}
+	hash := sha256.Sum256(template)
+	obj.SetName(obj.GetGenerateName() + hex.EncodeToString(hash[:])[:generatedNameHashLength])
+	return nil
+}
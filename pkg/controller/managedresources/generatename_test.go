@@ -0,0 +1,64 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("GenerateName", func() {
+	Describe("#setGeneratedName", func() {
+		newJob := func(image string) *unstructured.Unstructured {
+			return &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+				"metadata":   map[string]interface{}{"namespace": "foo", "generateName": "migrate-"},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "migrate", "image": image}}},
+					},
+				},
+			}}
+		}
+
+		It("should derive a stable name from the template", func() {
+			job1, job2 := newJob("migrate:v1"), newJob("migrate:v1")
+
+			Expect(setGeneratedName(job1)).To(Succeed())
+			Expect(setGeneratedName(job2)).To(Succeed())
+			Expect(job1.GetName()).To(HavePrefix("migrate-"))
+			Expect(job1.GetName()).To(HaveLen(len("migrate-") + generatedNameHashLength))
+			Expect(job2.GetName()).To(Equal(job1.GetName()))
+		})
+
+		It("should derive another name if the template changes", func() {
+			job1, job2 := newJob("migrate:v1"), newJob("migrate:v2")
+
+			Expect(setGeneratedName(job1)).To(Succeed())
+			Expect(setGeneratedName(job2)).To(Succeed())
+			Expect(job2.GetName()).NotTo(Equal(job1.GetName()))
+		})
+
+		It("should keep the name of objects with a name", func() {
+			job := newJob("migrate:v1")
+			job.SetName("migrate")
+
+			Expect(setGeneratedName(job)).To(Succeed())
+			Expect(job.GetName()).To(Equal("migrate"))
+		})
+	})
+})
@@ -0,0 +1,79 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	"github.com/gardener/gardener-resource-manager/pkg/metrics"
+
+	"github.com/go-logr/logr"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// handoverPollInterval is the interval in which it is checked whether a ManagedResource handed over to another
+// controller instance has been taken over.
+const handoverPollInterval = 10 * time.Second
+
+// takenOver returns true if the instance responsible for the resource class of the given ManagedResource has taken it
+// over, i.e. if it has added its finalizer.
+func takenOver(class *ClassFilter, mr *resourcesv1alpha1.ManagedResource) bool {
+	return sets.NewString(mr.Finalizers...).Has(class.FinalizerNameFor(mr))
+}
+
+// handOver hands the given ManagedResource over to the instance responsible for its new resource class. Its objects
+// are neither applied nor deleted anymore, but the finalizers of the actual instance are kept until the responsible
+// instance has taken it over, so that the objects are never without a controller (and still deleted if the
+// ManagedResource is deleted in the meantime).
+func (r *Reconciler) handOver(ctx context.Context, mr *resourcesv1alpha1.ManagedResource, log logr.Logger) (ctrl.Result, error) {
+	class := ResourceClassOf(mr)
+
+	if !takenOver(r.class, mr) {
+		log.Info("Waiting for the ManagedResource to be taken over by the instance responsible for its class", "class", class)
+
+		conditionResourcesApplied := resourcesv1alpha1helper.GetOrInitCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
+		if conditionResourcesApplied.Reason != resourcesv1alpha1.ConditionHandoverPending {
+			conditionResourcesApplied = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesApplied, resourcesv1alpha1.ConditionProgressing, resourcesv1alpha1.ConditionHandoverPending,
+				fmt.Sprintf("The resources are not managed anymore until the ManagedResource is taken over by the instance responsible for class %q.", class))
+			if err := tryUpdateManagedResourceConditions(ctx, r.client, mr, r.statusDebouncer.WithDeferredConditions(mr, conditionResourcesApplied)...); err != nil {
+				return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
+			}
+		}
+
+		// adding a finalizer doesn't change the generation, hence the ManagedResource is not reconciled on its own
+		return ctrl.Result{RequeueAfter: handoverPollInterval}, nil
+	}
+
+	log.Info("Removing finalizer without deleting the resources, as the ManagedResource has been taken over", "class", class)
+	for _, finalizer := range sets.NewString(mr.Finalizers...).List() {
+		if r.class.OwnsFinalizer(finalizer) {
+			if err := utils.DeleteFinalizer(ctx, r.client, finalizer, mr); err != nil {
+				return ctrl.Result{}, fmt.Errorf("error removing finalizer from ManagedResource: %+v", err)
+			}
+		}
+	}
+
+	metrics.ForgetManagedResource(mr.Namespace, mr.Name)
+	r.decodeCache.Forget(client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name})
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Handover", func() {
+	var (
+		mockCtrl *gomock.Controller
+		c        *mockclient.MockClient
+		r        *Reconciler
+		mr       *resourcesv1alpha1.ManagedResource
+
+		oldFinalizer = FinalizerName + "-old"
+		newFinalizer = FinalizerName + "-new"
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(mockCtrl)
+		r = &Reconciler{client: c, class: NewClassFilter("old")}
+
+		mr = &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar", Finalizers: []string{oldFinalizer}},
+			Spec:       resourcesv1alpha1.ManagedResourceSpec{Class: pointer.StringPtr("new")},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("#takenOver", func() {
+		It("should return true once the responsible instance has added its finalizer", func() {
+			Expect(takenOver(r.class, mr)).To(BeFalse())
+			mr.Finalizers = append(mr.Finalizers, newFinalizer)
+			Expect(takenOver(r.class, mr)).To(BeTrue())
+		})
+	})
+
+	Describe("#handOver", func() {
+		It("should keep the finalizer until the ManagedResource has been taken over", func() {
+			mr.Status.Conditions = []resourcesv1alpha1.ManagedResourceCondition{{
+				Type:   resourcesv1alpha1.ResourcesApplied,
+				Status: resourcesv1alpha1.ConditionProgressing,
+				Reason: resourcesv1alpha1.ConditionHandoverPending,
+			}}
+
+			Expect(r.handOver(context.TODO(), mr, runtimelog.NullLogger{})).To(Equal(ctrl.Result{RequeueAfter: handoverPollInterval}))
+			Expect(mr.Finalizers).To(ConsistOf(oldFinalizer))
+		})
+
+		It("should remove its finalizer without deleting the objects once the ManagedResource has been taken over", func() {
+			mr.Finalizers = append(mr.Finalizers, newFinalizer)
+
+			c.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "foo", Name: "bar"}, mr)
+			c.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResource{})).DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+				Expect(obj.(*resourcesv1alpha1.ManagedResource).Finalizers).To(ConsistOf(newFinalizer))
+				return nil
+			})
+
+			Expect(r.handOver(context.TODO(), mr, runtimelog.NullLogger{})).To(Equal(ctrl.Result{}))
+		})
+	})
+})
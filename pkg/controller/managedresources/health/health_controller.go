@@ -23,12 +23,16 @@ import (
 	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
 	"github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
 	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	"github.com/gardener/gardener-resource-manager/pkg/health"
+	"github.com/gardener/gardener-resource-manager/pkg/update"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -42,18 +46,21 @@ type HealthReconciler struct {
 	targetScheme *runtime.Scheme
 	classFilter  *managedresources.ClassFilter
 	syncPeriod   time.Duration
+
+	statusDebouncer *managedresources.StatusDebouncer
 }
 
-func NewHealthReconciler(ctx context.Context, log logr.Logger, client, targetClient client.Client, targetScheme *runtime.Scheme, classFilter *managedresources.ClassFilter, syncPeriod time.Duration) *HealthReconciler {
-	return &HealthReconciler{ctx, log, client, targetClient, targetScheme, classFilter, syncPeriod}
+func NewHealthReconciler(ctx context.Context, log logr.Logger, client, targetClient client.Client, targetScheme *runtime.Scheme, classFilter *managedresources.ClassFilter, syncPeriod time.Duration, statusDebouncer *managedresources.StatusDebouncer) *HealthReconciler {
+	return &HealthReconciler{ctx, log, client, targetClient, targetScheme, classFilter, syncPeriod, statusDebouncer}
 }
 
 func (r *HealthReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	log := r.log.WithValues("object", req)
+	ctx, reconcileID := utils.WithReconcileID(r.ctx)
+	log := r.log.WithValues("object", req, utils.LogKeyReconcileID, reconcileID)
 	log.Info("Starting ManagedResource health checks")
 
 	mr := &resourcesv1alpha1.ManagedResource{}
-	if err := r.client.Get(r.ctx, req.NamespacedName, mr); err != nil {
+	if err := r.client.Get(ctx, req.NamespacedName, mr); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Stopping health checks for ManagedResource, as it has been deleted")
 			return reconcile.Result{}, nil
@@ -72,7 +79,7 @@ func (r *HealthReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	if !mr.DeletionTimestamp.IsZero() {
 		conditionResourcesHealthy = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesHealthy, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionDeletionPending, "The resources are currently being deleted.")
-		if err := tryUpdateManagedResourceCondition(r.ctx, r.client, mr, conditionResourcesHealthy); err != nil {
+		if err := r.tryUpdateManagedResourceStatus(ctx, mr, conditionResourcesHealthy, mr.Status.ExportedStatus); err != nil {
 			return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v ", err)
 		}
 
@@ -80,6 +87,12 @@ func (r *HealthReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return reconcile.Result{}, nil
 	}
 
+	// the conditions of ignored ManagedResources are maintained by the resource controller
+	if resourcesv1alpha1helper.IsIgnored(mr) {
+		log.Info("Skipping health checks for ManagedResource, as it is ignored")
+		return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+	}
+
 	// skip health checks until ManagedResource has been reconciled completely successfully to prevent writing
 	// falsy health condition (resources may need a second try to apply, e.g. CRDs and CRs in the same MR)
 	conditionResourcesApplied := resourcesv1alpha1helper.GetCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
@@ -87,26 +100,28 @@ func (r *HealthReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		log.Info("Skipping health checks for ManagedResource, as it is has not been reconciled successfully yet.")
 		return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
 	}
+	if r.statusDebouncer.Pending(mr) {
+		log.Info("Skipping health checks for ManagedResource, as it is currently being reconciled.")
+		return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+	}
+
+	exportedStatus := r.exportStatus(ctx, log, mr)
 
 	resourcesObjectReferences := mr.Status.Resources
 	for _, ref := range resourcesObjectReferences {
-		var obj runtime.Object
-		// sigs.k8s.io/controller-runtime/pkg/client.DelegatingReader does not use the cache for unstructured.Unstructured
-		// objects, so we create a new object of the object's type to use the caching client
-		obj, err := r.targetScheme.New(ref.GroupVersionKind())
-		if err != nil {
-			log.Info("could not create new object of kind for health checks (probably not registered in the used scheme), falling back to unstructured request",
-				"GroupVersionKind", ref.GroupVersionKind().String(), "error", err.Error())
-
-			// fallback to unstructured requests if the object's type is not registered in the scheme
-			unstructuredObj := &unstructured.Unstructured{}
-			unstructuredObj.SetAPIVersion(ref.APIVersion)
-			unstructuredObj.SetKind(ref.Kind)
-			obj = unstructuredObj
+		// objects pending prune are about to be deleted, hence their health does not matter anymore
+		if ref.PruneAfter != nil {
+			continue
+		}
+		// objects in mode Ignore are managed by hand
+		if ref.Annotations[resourcesv1alpha1.Mode] == resourcesv1alpha1.ModeIgnore {
+			continue
 		}
 
-		if err := r.targetClient.Get(r.ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
-			if apierrors.IsNotFound(err) {
+		obj, err := r.getObject(ctx, log, ref)
+		if err != nil {
+			// objects whose kind has been removed from the target cluster are missing as well
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
 				log.Info("Could not get object", "namespace", ref.Namespace, "name", ref.Name)
 
 				var (
@@ -115,7 +130,7 @@ func (r *HealthReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 				)
 
 				conditionResourcesHealthy = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesHealthy, resourcesv1alpha1.ConditionFalse, reason, message)
-				if err := tryUpdateManagedResourceCondition(r.ctx, r.client, mr, conditionResourcesHealthy); err != nil {
+				if err := r.tryUpdateManagedResourceStatus(ctx, mr, conditionResourcesHealthy, exportedStatus); err != nil {
 					return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v ", err)
 				}
 
@@ -125,14 +140,14 @@ func (r *HealthReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			return ctrl.Result{}, err
 		}
 
-		if err := CheckHealth(r.targetScheme, obj); err != nil {
+		if err := health.CheckHealth(r.targetScheme, obj); err != nil {
 			var (
 				reason  = ref.Kind + "Unhealthy"
 				message = fmt.Sprintf("Required %s %q in namespace %q is unhealthy: %v", ref.Kind, ref.Name, ref.Namespace, err.Error())
 			)
 
 			conditionResourcesHealthy = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesHealthy, resourcesv1alpha1.ConditionFalse, reason, message)
-			if err := tryUpdateManagedResourceCondition(r.ctx, r.client, mr, conditionResourcesHealthy); err != nil {
+			if err := r.tryUpdateManagedResourceStatus(ctx, mr, conditionResourcesHealthy, exportedStatus); err != nil {
 				return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v ", err)
 			}
 
@@ -141,7 +156,7 @@ func (r *HealthReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	conditionResourcesHealthy = resourcesv1alpha1helper.UpdatedCondition(conditionResourcesHealthy, resourcesv1alpha1.ConditionTrue, "ResourcesHealthy", "All resources are healthy.")
-	if err := tryUpdateManagedResourceCondition(r.ctx, r.client, mr, conditionResourcesHealthy); err != nil {
+	if err := r.tryUpdateManagedResourceStatus(ctx, mr, conditionResourcesHealthy, exportedStatus); err != nil {
 		return ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v ", err)
 	}
 
@@ -149,10 +164,70 @@ func (r *HealthReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
 }
 
-func tryUpdateManagedResourceCondition(ctx context.Context, c client.Client, mr *resourcesv1alpha1.ManagedResource, condition resourcesv1alpha1.ManagedResourceCondition) error {
-	return utils.TryUpdateStatus(ctx, retry.DefaultBackoff, c, mr, func() error {
-		newConditions := resourcesv1alpha1helper.MergeConditions(mr.Status.Conditions, condition)
+// getObject reads the given object from the target cluster.
+func (r *HealthReconciler) getObject(ctx context.Context, log logr.Logger, ref resourcesv1alpha1.ObjectReference) (runtime.Object, error) {
+	// sigs.k8s.io/controller-runtime/pkg/client.DelegatingReader does not use the cache for unstructured.Unstructured
+	// objects, so we create a new object of the object's type to use the caching client
+	obj, err := r.targetScheme.New(ref.GroupVersionKind())
+	if err != nil {
+		log.Info("could not create new object of kind for health checks (probably not registered in the used scheme), falling back to unstructured request",
+			"GroupVersionKind", ref.GroupVersionKind().String(), "error", err.Error())
+
+		// fallback to unstructured requests if the object's type is not registered in the scheme
+		unstructuredObj := &unstructured.Unstructured{}
+		unstructuredObj.SetAPIVersion(ref.APIVersion)
+		unstructuredObj.SetKind(ref.Kind)
+		obj = unstructuredObj
+	}
+
+	return obj, r.targetClient.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj)
+}
+
+// exportStatus returns the values exported from the status of the objects annotated with the export-status
+// annotation. The last exported values of objects which cannot be read are kept, e.g. of Jobs which have been deleted
+// after their completion.
+func (r *HealthReconciler) exportStatus(ctx context.Context, log logr.Logger, mr *resourcesv1alpha1.ManagedResource) []resourcesv1alpha1.ExportedStatus {
+	lastExported := make(map[corev1.ObjectReference]resourcesv1alpha1.ExportedStatus, len(mr.Status.ExportedStatus))
+	for _, exported := range mr.Status.ExportedStatus {
+		lastExported[exported.ObjectReference] = exported
+	}
+
+	var exportedStatus []resourcesv1alpha1.ExportedStatus
+	for _, ref := range mr.Status.Resources {
+		expression, ok := ref.Annotations[resourcesv1alpha1.ExportStatus]
+		if !ok || ref.PruneAfter != nil {
+			continue
+		}
+
+		key := corev1.ObjectReference{APIVersion: ref.APIVersion, Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name}
+		obj, err := r.getObject(ctx, log, ref)
+		if err == nil {
+			var value *runtime.RawExtension
+			if value, err = managedresources.ExportStatus(obj, expression); err == nil {
+				if value != nil {
+					exportedStatus = append(exportedStatus, resourcesv1alpha1.ExportedStatus{ObjectReference: key, Value: *value})
+				}
+				continue
+			}
+		}
+
+		log.Info("Could not export status, keeping the last exported value", "object", key, "error", err.Error())
+		if exported, ok := lastExported[key]; ok {
+			exportedStatus = append(exportedStatus, exported)
+		}
+	}
+
+	return exportedStatus
+}
+
+// tryUpdateManagedResourceStatus updates the given condition, together with the conditions deferred by the resource
+// controller, and the given exported status.
+func (r *HealthReconciler) tryUpdateManagedResourceStatus(ctx context.Context, mr *resourcesv1alpha1.ManagedResource, condition resourcesv1alpha1.ManagedResourceCondition, exportedStatus []resourcesv1alpha1.ExportedStatus) error {
+	conditions := r.statusDebouncer.WithDeferredConditions(mr, condition)
+	return update.Try(ctx, r.client, mr, func() error {
+		newConditions := resourcesv1alpha1helper.MergeConditions(mr.Status.Conditions, conditions...)
 		mr.Status.Conditions = newConditions
+		mr.Status.ExportedStatus = managedresources.MergeExportedStatus(mr.Status.ExportedStatus, exportedStatus, metav1.Now())
 		return nil
-	})
+	}, update.Options{Status: true})
 }
@@ -0,0 +1,243 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+	"github.com/gardener/gardener-resource-manager/pkg/audit"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hookPollInterval is the interval in which the completion of running hooks is checked.
+const hookPollInterval = 5 * time.Second
+
+// hookKinds are the kinds of objects which can be hooks.
+var hookKinds = map[schema.GroupKind]struct{}{
+	{Group: batchv1.GroupName, Kind: "Job"}: {},
+	{Group: corev1.GroupName, Kind: "Pod"}:  {},
+}
+
+// hookOf returns the phase in which the given object is run as hook, or an empty string if it is no hook. Only Jobs
+// and Pods can be hooks, the hook annotation is ignored on objects of other kinds.
+func hookOf(obj *unstructured.Unstructured) string {
+	if _, ok := hookKinds[obj.GroupVersionKind().GroupKind()]; !ok {
+		return ""
+	}
+	switch hook := obj.GetAnnotations()[resourcesv1alpha1.Hook]; hook {
+	case resourcesv1alpha1.HookPreApply, resourcesv1alpha1.HookPostApply, resourcesv1alpha1.HookPreDelete:
+		return hook
+	}
+	return ""
+}
+
+// groupByHook splits the given objects into the regular objects and the hooks of the apply phases.
+func groupByHook(objects []object) (regular, preApply, postApply []object) {
+	for _, obj := range objects {
+		switch hookOf(obj.obj) {
+		case resourcesv1alpha1.HookPreApply:
+			preApply = append(preApply, obj)
+		case resourcesv1alpha1.HookPostApply:
+			postApply = append(postApply, obj)
+		default:
+			regular = append(regular, obj)
+		}
+	}
+	return regular, preApply, postApply
+}
+
+// hookCompleted returns whether the given hook has completed successfully, or an error if it failed.
+func hookCompleted(obj *unstructured.Unstructured) (bool, error) {
+	switch obj.GetKind() {
+	case "Job":
+		job := &batchv1.Job{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, job); err != nil {
+			return false, err
+		}
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				return false, fmt.Errorf("job failed: %s", condition.Message)
+			}
+		}
+	case "Pod":
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod); err != nil {
+			return false, err
+		}
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			return true, nil
+		case corev1.PodFailed:
+			return false, fmt.Errorf("pod failed: %s", pod.Status.Message)
+		}
+	}
+	return false, nil
+}
+
+// checkHooks returns the hooks which have not yet completed and the failures of the failed hooks. Hooks which don't
+// exist (anymore) are considered completed if missingCompleted is true and pending otherwise.
+func (r *Reconciler) checkHooks(ctx context.Context, hooks []object, missingCompleted bool) (pending, failed []string, err error) {
+	for _, hook := range hooks {
+		if ignoreMode(hook.obj.GetAnnotations()) {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(hook.obj.GroupVersionKind())
+		resource := unstructuredToString(hook.obj)
+		if err := r.targetClient.Get(ctx, client.ObjectKey{Namespace: hook.obj.GetNamespace(), Name: hook.obj.GetName()}, obj); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, fmt.Errorf("could not get hook %q: %w", resource, err)
+			}
+			if !missingCompleted {
+				pending = append(pending, resource)
+			}
+			continue
+		}
+
+		completed, err := hookCompleted(obj)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", resource, err))
+			continue
+		}
+		if !completed {
+			pending = append(pending, resource)
+		}
+	}
+	return pending, failed, nil
+}
+
+// reconcileHooks reports the state of the given hooks of the given phase in the `ResourcesApplied` condition. It
+// returns true if all hooks have completed, otherwise the reconciliation has to be stopped with the returned result.
+func (r *Reconciler) reconcileHooks(ctx context.Context, log logr.Logger, mr *resourcesv1alpha1.ManagedResource, phase string, pending, failed []string, condition resourcesv1alpha1.ManagedResourceCondition, resources []resourcesv1alpha1.ObjectReference) (bool, ctrl.Result, error) {
+	var result ctrl.Result
+	switch {
+	case len(failed) > 0:
+		log.Info("Hooks failed", "phase", phase, "failed", failed)
+		condition = resourcesv1alpha1helper.UpdatedCondition(condition, resourcesv1alpha1.ConditionFalse, resourcesv1alpha1.ConditionHookFailed, fmt.Sprintf("The %s hooks failed: %s", phase, strings.Join(failed, "; ")))
+		// failed hooks are not retried, the ManagedResource is reconciled again once it or its secrets change
+		result = ctrl.Result{RequeueAfter: r.syncPeriod}
+	case len(pending) > 0:
+		log.Info("Waiting for hooks to complete", "phase", phase, "pending", pending)
+		condition = resourcesv1alpha1helper.UpdatedCondition(condition, resourcesv1alpha1.ConditionProgressing, resourcesv1alpha1.ConditionHookPending, fmt.Sprintf("Waiting for the %s hooks to complete: %s", phase, strings.Join(pending, ", ")))
+		result = ctrl.Result{RequeueAfter: hookPollInterval}
+	default:
+		return true, ctrl.Result{}, nil
+	}
+
+	// the resources are updated as well, so that the hooks are not garbage collected
+	if err := tryUpdateManagedResourceStatus(ctx, r.client, mr, resources, nil, r.statusDebouncer.WithDeferredConditions(mr, condition)...); err != nil {
+		return false, ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
+	}
+	return false, result, nil
+}
+
+// reconcilePreDeleteHooks applies and awaits the pre-delete hooks of the given deleted ManagedResource. The hooks are
+// added to its resources once they are applied, so that they are deleted with all other objects afterwards and are
+// not run again. It returns true if all hooks have completed, otherwise the deletion has to be stopped with the
+// returned result.
+func (r *Reconciler) reconcilePreDeleteHooks(ctx context.Context, log logr.Logger, mr *resourcesv1alpha1.ManagedResource, condition resourcesv1alpha1.ManagedResourceCondition, auditRecorder *audit.Recorder) (bool, ctrl.Result, error) {
+	hooks, err := r.preDeleteHooks(ctx, log, mr)
+	if err != nil || len(hooks) == 0 {
+		return err == nil, ctrl.Result{}, err
+	}
+
+	var (
+		index     = NewObjectIndex(mr.Status.Resources, nil)
+		resources = append([]resourcesv1alpha1.ObjectReference{}, mr.Status.Resources...)
+		created   []object
+	)
+	for _, hook := range hooks {
+		ref := newObjectReference(hook.obj, mr.Spec.InjectLabels)
+		if _, ok := index.Lookup(ref); !ok {
+			created = append(created, hook)
+			resources = append(resources, ref)
+		}
+	}
+
+	if len(created) > 0 {
+		origin := resourcesv1alpha1helper.Origin(r.clusterID, client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name})
+		if err := r.applyNewResources(ctx, log, created, applyOptions{
+			class:          ResourceClassOf(mr),
+			origin:         origin,
+			labelsToInject: mr.Spec.InjectLabels,
+			equivalences:   NewEquivalences(),
+			auditRecorder:  auditRecorder,
+		}); err != nil {
+			return false, ctrl.Result{}, fmt.Errorf("could not apply the %s hooks: %+v", resourcesv1alpha1.HookPreDelete, err)
+		}
+		sortObjectReferences(resources)
+	}
+
+	pending, failed, err := r.checkHooks(ctx, hooks, true)
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+
+	done, result, err := r.reconcileHooks(ctx, log, mr, resourcesv1alpha1.HookPreDelete, pending, failed, condition, resources)
+	if done && len(created) > 0 {
+		if err := tryUpdateManagedResourceStatus(ctx, r.client, mr, resources, nil); err != nil {
+			return false, ctrl.Result{}, fmt.Errorf("could not update the ManagedResource status: %+v", err)
+		}
+	}
+	return done, result, err
+}
+
+// preDeleteHooks returns the pre-delete hooks contained in the secrets of the given ManagedResource. Secrets which
+// don't exist anymore are skipped.
+func (r *Reconciler) preDeleteHooks(ctx context.Context, log logr.Logger, mr *resourcesv1alpha1.ManagedResource) ([]object, error) {
+	secrets := make([]*corev1.Secret, 0, len(mr.Spec.SecretRefs))
+	for _, ref := range mr.Spec.SecretRefs {
+		secret := &corev1.Secret{}
+		if err := r.client.Get(ctx, client.ObjectKey{Namespace: mr.Namespace, Name: ref.Name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Info("Not running the pre-delete hooks of a deleted secret", "secret", ref.Name)
+				continue
+			}
+			return nil, fmt.Errorf("could not read secret '%s': %+v", ref.Name, err)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	objs, _, _, _ := decodeSecrets(log, r.targetRESTMapper, r.strictDecodingScheme(), secrets)
+
+	var hooks []object
+	for _, obj := range objs {
+		if hookOf(obj) == resourcesv1alpha1.HookPreDelete {
+			hooks = append(hooks, object{obj: obj})
+		}
+	}
+	return hooks, nil
+}
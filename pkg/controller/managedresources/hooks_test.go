@@ -0,0 +1,102 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Hooks", func() {
+	newObject := func(apiVersion, kind, hook string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName(kind + "-" + hook)
+		if hook != "" {
+			obj.SetAnnotations(map[string]string{resourcesv1alpha1.Hook: hook})
+		}
+		return obj
+	}
+
+	DescribeTable("#hookOf",
+		func(obj *unstructured.Unstructured, expected string) {
+			Expect(hookOf(obj)).To(Equal(expected))
+		},
+		Entry("Job hook", newObject("batch/v1", "Job", resourcesv1alpha1.HookPreApply), resourcesv1alpha1.HookPreApply),
+		Entry("Pod hook", newObject("v1", "Pod", resourcesv1alpha1.HookPreDelete), resourcesv1alpha1.HookPreDelete),
+		Entry("Job without hook", newObject("batch/v1", "Job", ""), ""),
+		Entry("unknown hook", newObject("batch/v1", "Job", "post-delete"), ""),
+		Entry("hook of other kind", newObject("apps/v1", "Deployment", resourcesv1alpha1.HookPostApply), ""),
+	)
+
+	Describe("#groupByHook", func() {
+		It("should split the regular objects from the hooks", func() {
+			var (
+				deployment = object{obj: newObject("apps/v1", "Deployment", "")}
+				preApply   = object{obj: newObject("batch/v1", "Job", resourcesv1alpha1.HookPreApply)}
+				postApply  = object{obj: newObject("v1", "Pod", resourcesv1alpha1.HookPostApply)}
+			)
+
+			regular, pre, post := groupByHook([]object{postApply, deployment, preApply})
+			Expect(regular).To(Equal([]object{deployment}))
+			Expect(pre).To(Equal([]object{preApply}))
+			Expect(post).To(Equal([]object{postApply}))
+		})
+	})
+
+	Describe("#hookCompleted", func() {
+		toUnstructured := func(obj runtime.Object, apiVersion, kind string) *unstructured.Unstructured {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			Expect(err).NotTo(HaveOccurred())
+			u := &unstructured.Unstructured{Object: content}
+			u.SetAPIVersion(apiVersion)
+			u.SetKind(kind)
+			return u
+		}
+
+		It("should report the completion of Jobs", func() {
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+			Expect(hookCompleted(toUnstructured(job, "batch/v1", "Job"))).To(BeFalse())
+
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+			Expect(hookCompleted(toUnstructured(job, "batch/v1", "Job"))).To(BeTrue())
+
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "backoff limit exceeded"}}
+			_, err := hookCompleted(toUnstructured(job, "batch/v1", "Job"))
+			Expect(err).To(MatchError(ContainSubstring("backoff limit exceeded")))
+		})
+
+		It("should report the completion of Pods", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+			Expect(hookCompleted(toUnstructured(pod, "v1", "Pod"))).To(BeFalse())
+
+			pod.Status.Phase = corev1.PodSucceeded
+			Expect(hookCompleted(toUnstructured(pod, "v1", "Pod"))).To(BeTrue())
+
+			pod.Status.Phase = corev1.PodFailed
+			_, err := hookCompleted(toUnstructured(pod, "v1", "Pod"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,51 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretRefsIndex is the name of the field index of ManagedResources by the names of their referenced secrets.
+const SecretRefsIndex = "spec.secretRefs.name"
+
+// SecretRefsIndexer extracts the names of the secrets referenced by a ManagedResource for the `SecretRefsIndex`.
+func SecretRefsIndexer(obj runtime.Object) []string {
+	mr, ok := obj.(*resourcesv1alpha1.ManagedResource)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(mr.Spec.SecretRefs))
+	for _, ref := range mr.Spec.SecretRefs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+// AddSecretRefsIndex adds the `SecretRefsIndex` to the given indexer, so that the ManagedResources referencing a
+// secret can be listed with `MatchingSecretRef` instead of filtering all ManagedResources of its namespace.
+func AddSecretRefsIndex(indexer client.FieldIndexer) error {
+	return indexer.IndexField(&resourcesv1alpha1.ManagedResource{}, SecretRefsIndex, SecretRefsIndexer)
+}
+
+// MatchingSecretRef returns the list options selecting the ManagedResources referencing the secret with the given
+// namespace and name. It requires the `SecretRefsIndex`.
+func MatchingSecretRef(namespace, name string) []client.ListOption {
+	return []client.ListOption{client.InNamespace(namespace), client.MatchingFields{SecretRefsIndex: name}}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources_test
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Index", func() {
+	Describe("#SecretRefsIndexer", func() {
+		It("should return the names of all referenced secrets", func() {
+			mr := &resourcesv1alpha1.ManagedResource{Spec: resourcesv1alpha1.ManagedResourceSpec{
+				SecretRefs: []corev1.LocalObjectReference{{Name: "foo"}, {Name: "bar"}},
+			}}
+			Expect(SecretRefsIndexer(mr)).To(Equal([]string{"foo", "bar"}))
+		})
+
+		It("should return nothing for other objects", func() {
+			Expect(SecretRefsIndexer(&corev1.Secret{})).To(BeEmpty())
+		})
+	})
+
+	Describe("#MatchingSecretRef", func() {
+		It("should select the ManagedResources referencing the secret in its namespace", func() {
+			Expect(MatchingSecretRef("foo", "bar")).To(Equal([]client.ListOption{
+				client.InNamespace("foo"),
+				client.MatchingFields{SecretRefsIndex: "bar"},
+			}))
+		})
+	})
+})
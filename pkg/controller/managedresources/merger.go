@@ -19,9 +19,12 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -32,9 +35,10 @@ Any modifications are discarded and the resource is returned to the original sta
 )
 
 // merge merges the values of the `desired` object into the `current` object while preserving `current`'s important
-// metadata (like resourceVersion and finalizers), status and selected spec fields of the respective kind (e.g.
-// .spec.selector of a Job).
-func merge(desired, current *unstructured.Unstructured, forceOverwriteLabels bool, existingLabels map[string]string, forceOverwriteAnnotations bool, existingAnnotations map[string]string, preserveReplicas, preserveResources bool) error {
+// metadata (like resourceVersion), status and selected spec fields of the respective kind (e.g. .spec.selector of a
+// Job). The owner references and finalizers of `current`, which are usually maintained by other controllers, are
+// merged with the desired ones unless they are forced to be overwritten.
+func merge(desired, current *unstructured.Unstructured, forceOverwriteLabels bool, existingLabels map[string]string, forceOverwriteAnnotations bool, existingAnnotations map[string]string, forceOverwriteOwnerReferences, forceOverwriteFinalizers bool, preserveReplicas, preserveResources bool) error {
 	// save copy of current object before merging
 	oldObject := current.DeepCopy()
 
@@ -73,6 +77,18 @@ func merge(desired, current *unstructured.Unstructured, forceOverwriteLabels boo
 	ann[descriptionAnnotation] = descriptionAnnotationText
 	newObject.SetAnnotations(ann)
 
+	if forceOverwriteOwnerReferences {
+		newObject.SetOwnerReferences(desired.GetOwnerReferences())
+	} else if references, changed := mergeOwnerReferences(oldObject.GetOwnerReferences(), desired.GetOwnerReferences()); changed {
+		newObject.SetOwnerReferences(references)
+	}
+
+	if forceOverwriteFinalizers {
+		newObject.SetFinalizers(desired.GetFinalizers())
+	} else if finalizers, changed := mergeFinalizers(oldObject.GetFinalizers(), desired.GetFinalizers()); changed {
+		newObject.SetFinalizers(finalizers)
+	}
+
 	// keep status of old object if it is set and not empty
 	var oldStatus map[string]interface{}
 	if oldStatusInterface, containsStatus := oldObject.Object["status"]; containsStatus {
@@ -104,6 +120,58 @@ func merge(desired, current *unstructured.Unstructured, forceOverwriteLabels boo
 	return nil
 }
 
+// mergeOwnerReferences adds the desired owner references to the current ones. Desired references to an owner which is
+// already referenced replace the current reference. It returns whether the current references were changed.
+func mergeOwnerReferences(current, desired []metav1.OwnerReference) ([]metav1.OwnerReference, bool) {
+	var (
+		merged  = append([]metav1.OwnerReference{}, current...)
+		changed bool
+	)
+
+	for _, reference := range desired {
+		found := false
+		for i, existing := range merged {
+			if sameOwner(existing, reference) {
+				found = true
+				if !apiequality.Semantic.DeepEqual(existing, reference) {
+					merged[i] = reference
+					changed = true
+				}
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, reference)
+			changed = true
+		}
+	}
+	return merged, changed
+}
+
+func sameOwner(a, b metav1.OwnerReference) bool {
+	if a.UID != "" && b.UID != "" {
+		return a.UID == b.UID
+	}
+	return a.APIVersion == b.APIVersion && a.Kind == b.Kind && a.Name == b.Name
+}
+
+// mergeFinalizers adds the desired finalizers to the current ones. It returns whether the current finalizers were
+// changed.
+func mergeFinalizers(current, desired []string) ([]string, bool) {
+	var (
+		merged  = append([]string{}, current...)
+		changed bool
+	)
+
+	for _, finalizer := range desired {
+		if !sets.NewString(merged...).Has(finalizer) {
+			merged = append(merged, finalizer)
+			changed = true
+		}
+	}
+	return merged, changed
+}
+
 func mergeDeployment(scheme *runtime.Scheme, oldObj, newObj runtime.Object, preserveReplicas, preserveResources bool) error {
 	oldDeployment := &appsv1.Deployment{}
 	if err := scheme.Convert(oldObj, oldDeployment, nil); err != nil {
@@ -223,8 +291,17 @@ func mergeStatefulSet(scheme *runtime.Scheme, oldObj, newObj runtime.Object, pre
 	return scheme.Convert(newStatefulSet, newObj, nil)
 }
 
+// allocatedServiceSpecFields are fields of the spec of Services which are allocated or defaulted by the API server
+// together with the ClusterIP, but are not part of the vendored API types and are hence dropped by the conversion.
+var allocatedServiceSpecFields = []string{"clusterIPs", "ipFamilies", "ipFamilyPolicy"}
+
 // mergeService merges new service into old service
 func mergeService(scheme *runtime.Scheme, oldObj, newObj runtime.Object) error {
+	var (
+		oldFields = unstructuredSpecFields(oldObj, allocatedServiceSpecFields)
+		newFields = unstructuredSpecFields(newObj, allocatedServiceSpecFields)
+	)
+
 	oldService := &corev1.Service{}
 	if err := scheme.Convert(oldObj, oldService, nil); err != nil {
 		return err
@@ -268,8 +345,8 @@ func mergeService(scheme *runtime.Scheme, oldObj, newObj runtime.Object) error {
 	// ClusterIP is immutable unless we want to transform the service into headless
 	// where ClusterIP = None or if the previous type of the service was ExternalName
 	// and the user wants to explicitly set an ClusterIP.
-	if newService.Spec.ClusterIP != corev1.ClusterIPNone &&
-		oldService.Spec.Type != corev1.ServiceTypeExternalName {
+	preserveClusterIP := newService.Spec.ClusterIP != corev1.ClusterIPNone && oldService.Spec.Type != corev1.ServiceTypeExternalName
+	if preserveClusterIP {
 		newService.Spec.ClusterIP = oldService.Spec.ClusterIP
 	}
 
@@ -281,7 +358,50 @@ func mergeService(scheme *runtime.Scheme, oldObj, newObj runtime.Object) error {
 		newService.Spec.HealthCheckNodePort = oldService.Spec.HealthCheckNodePort
 	}
 
-	return scheme.Convert(newService, newObj, nil)
+	if err := scheme.Convert(newService, newObj, nil); err != nil {
+		return err
+	}
+
+	// the allocated fields are kept like the ClusterIP unless they are set explicitly
+	if preserveClusterIP {
+		for field, value := range oldFields {
+			if _, ok := newFields[field]; !ok {
+				newFields[field] = value
+			}
+		}
+	}
+	return setUnstructuredSpecFields(newObj, newFields)
+}
+
+// unstructuredSpecFields returns the values of the given fields of the spec of the object if it is unstructured.
+func unstructuredSpecFields(obj runtime.Object, fields []string) map[string]interface{} {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	values := map[string]interface{}{}
+	for _, field := range fields {
+		if value, ok, err := unstructured.NestedFieldCopy(u.Object, "spec", field); err == nil && ok {
+			values[field] = value
+		}
+	}
+	return values
+}
+
+// setUnstructuredSpecFields sets the given fields of the spec of the object if it is unstructured.
+func setUnstructuredSpecFields(obj runtime.Object, values map[string]interface{}) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	for field, value := range values {
+		if err := unstructured.SetNestedField(u.Object, value, "spec", field); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func mergeServiceAccount(scheme *runtime.Scheme, oldObj, newObj runtime.Object) error {
@@ -79,7 +79,7 @@ var _ = Describe("merger", func() {
 			expected := current.DeepCopy()
 			addDescriptionAnnotations(expected)
 
-			Expect(merge(desired, current, false, nil, false, nil, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.Object["metadata"]).To(Equal(expected.Object["metadata"]))
 		})
 
@@ -90,7 +90,7 @@ var _ = Describe("merger", func() {
 
 			expected := desired.DeepCopy()
 
-			Expect(merge(desired, current, true, existingLabels, false, nil, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, true, existingLabels, false, nil, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.GetLabels()).To(Equal(expected.GetLabels()))
 		})
 
@@ -105,7 +105,7 @@ var _ = Describe("merger", func() {
 				"other": "baz",
 			})
 
-			Expect(merge(desired, current, false, existingLabels, false, nil, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, existingLabels, false, nil, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.GetLabels()).To(Equal(expected.GetLabels()))
 		})
 
@@ -119,7 +119,7 @@ var _ = Describe("merger", func() {
 				"other": "baz",
 			})
 
-			Expect(merge(desired, current, false, existingLabels, false, nil, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, existingLabels, false, nil, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.GetLabels()).To(Equal(expected.GetLabels()))
 		})
 
@@ -134,7 +134,7 @@ var _ = Describe("merger", func() {
 				"other": "baz",
 			})
 
-			Expect(merge(desired, current, false, existingLabels, false, nil, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, existingLabels, false, nil, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.GetLabels()).To(Equal(expected.GetLabels()))
 		})
 
@@ -146,7 +146,7 @@ var _ = Describe("merger", func() {
 			expected := desired.DeepCopy()
 			addDescriptionAnnotations(expected)
 
-			Expect(merge(desired, current, false, nil, true, existingAnnotations, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, nil, true, existingAnnotations, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.GetAnnotations()).To(Equal(expected.GetAnnotations()))
 		})
 
@@ -162,7 +162,7 @@ var _ = Describe("merger", func() {
 			})
 			addDescriptionAnnotations(expected)
 
-			Expect(merge(desired, current, false, nil, false, existingAnnotations, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, nil, false, existingAnnotations, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.GetAnnotations()).To(Equal(expected.GetAnnotations()))
 		})
 
@@ -177,7 +177,7 @@ var _ = Describe("merger", func() {
 			})
 			addDescriptionAnnotations(expected)
 
-			Expect(merge(desired, current, false, nil, false, existingAnnotations, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, nil, false, existingAnnotations, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.GetAnnotations()).To(Equal(expected.GetAnnotations()))
 		})
 
@@ -193,7 +193,7 @@ var _ = Describe("merger", func() {
 			})
 			addDescriptionAnnotations(expected)
 
-			Expect(merge(desired, current, false, nil, false, existingAnnotations, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, nil, false, existingAnnotations, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.GetAnnotations()).To(Equal(expected.GetAnnotations()))
 		})
 
@@ -207,7 +207,7 @@ var _ = Describe("merger", func() {
 
 			expected := current.DeepCopy()
 
-			Expect(merge(desired, current, false, nil, false, nil, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.Object["status"]).To(Equal(expected.Object["status"]))
 		})
 
@@ -218,7 +218,7 @@ var _ = Describe("merger", func() {
 
 			current.Object["status"] = map[string]interface{}{}
 
-			Expect(merge(desired, current, false, nil, false, nil, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.Object["status"]).To(BeNil())
 		})
 
@@ -229,10 +229,52 @@ var _ = Describe("merger", func() {
 
 			delete(current.Object, "status")
 
-			Expect(merge(desired, current, false, nil, false, nil, false, false)).NotTo(HaveOccurred(), "merge should be successful")
+			Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).NotTo(HaveOccurred(), "merge should be successful")
 			Expect(current.Object["status"]).To(BeNil())
 		})
 
+		Describe("owner references and finalizers", func() {
+			var foreignOwner, desiredOwner metav1.OwnerReference
+
+			BeforeEach(func() {
+				foreignOwner = current.GetOwnerReferences()[0]
+				desiredOwner = metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "anchor", UID: "1234"}
+
+				desired.SetOwnerReferences([]metav1.OwnerReference{desiredOwner})
+				desired.SetFinalizers([]string{"desired"})
+			})
+
+			It("should merge the desired owner references and finalizers with the current ones", func() {
+				Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).To(Succeed())
+				Expect(current.GetOwnerReferences()).To(Equal([]metav1.OwnerReference{foreignOwner, desiredOwner}))
+				Expect(current.GetFinalizers()).To(Equal([]string{"finalizer", "desired"}))
+			})
+
+			It("should replace current owner references to the same owner", func() {
+				foreignOwner.Controller = pointer.BoolPtr(false)
+				desired.SetOwnerReferences([]metav1.OwnerReference{foreignOwner})
+
+				Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).To(Succeed())
+				Expect(current.GetOwnerReferences()).To(Equal([]metav1.OwnerReference{foreignOwner}))
+			})
+
+			It("should keep the current owner references and finalizers if none are desired", func() {
+				desired.SetOwnerReferences(nil)
+				desired.SetFinalizers(nil)
+				expected := current.DeepCopy()
+
+				Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).To(Succeed())
+				Expect(current.Object["metadata"].(map[string]interface{})["ownerReferences"]).To(Equal(expected.Object["metadata"].(map[string]interface{})["ownerReferences"]))
+				Expect(current.GetFinalizers()).To(Equal([]string{"finalizer"}))
+			})
+
+			It("should overwrite the current owner references and finalizers if forced", func() {
+				Expect(merge(desired, current, false, nil, false, nil, true, true, false, false)).To(Succeed())
+				Expect(current.GetOwnerReferences()).To(Equal([]metav1.OwnerReference{desiredOwner}))
+				Expect(current.GetFinalizers()).To(Equal([]string{"desired"}))
+			})
+		})
+
 		Describe("sets warning annotation", func() {
 			AfterEach(func() {
 				Expect(current.GetAnnotations()).
@@ -240,12 +282,12 @@ var _ = Describe("merger", func() {
 			})
 
 			It("when forceOverrideAnnotation is false", func() {
-				Expect(merge(desired, current, false, nil, false, nil, false, false)).ToNot(HaveOccurred(), "merge succeeds")
+				Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).ToNot(HaveOccurred(), "merge succeeds")
 			})
 			It("when forceOverrideAnnotation is false and old annotations exist", func() {
 				desired.SetAnnotations(map[string]string{"goo": "boo"})
 				current.SetAnnotations(map[string]string{"foo": "bar"})
-				Expect(merge(desired, current, false, nil, false, nil, false, false)).ToNot(HaveOccurred(), "merge succeeds")
+				Expect(merge(desired, current, false, nil, false, nil, false, false, false, false)).ToNot(HaveOccurred(), "merge succeeds")
 
 				Expect(current.GetAnnotations()).To(HaveKeyWithValue("goo", "boo"))
 				Expect(current.GetAnnotations()).To(HaveKeyWithValue("foo", "bar"))
@@ -253,7 +295,7 @@ var _ = Describe("merger", func() {
 
 			It("when forceOverrideAnnotation is true", func() {
 				desired.SetAnnotations(map[string]string{"goo": "boo"})
-				Expect(merge(desired, current, false, nil, true, nil, false, false)).ToNot(HaveOccurred(), "merge succeeds")
+				Expect(merge(desired, current, false, nil, true, nil, false, false, false, false)).ToNot(HaveOccurred(), "merge succeeds")
 				Expect(current.GetAnnotations()).To(HaveKeyWithValue("goo", "boo"))
 			})
 		})
@@ -845,6 +887,39 @@ var _ = Describe("merger", func() {
 				expected = new.DeepCopy()
 			}),
 		)
+		Describe("allocated fields unknown to the scheme", func() {
+			var oldObj, newObj *unstructured.Unstructured
+
+			BeforeEach(func() {
+				oldObj, newObj = &unstructured.Unstructured{}, &unstructured.Unstructured{}
+				Expect(s.Convert(old, oldObj, nil)).To(Succeed())
+				Expect(s.Convert(new, newObj, nil)).To(Succeed())
+				Expect(unstructured.SetNestedStringSlice(oldObj.Object, []string{"1.2.3.4", "fd00::1"}, "spec", "clusterIPs")).To(Succeed())
+				Expect(unstructured.SetNestedStringSlice(oldObj.Object, []string{"IPv4", "IPv6"}, "spec", "ipFamilies")).To(Succeed())
+				Expect(unstructured.SetNestedField(oldObj.Object, "PreferDualStack", "spec", "ipFamilyPolicy")).To(Succeed())
+			})
+
+			It("should keep the allocated fields if they are not set", func() {
+				Expect(mergeService(s, oldObj, newObj)).To(Succeed())
+				Expect(newObj.Object["spec"]).To(HaveKeyWithValue("clusterIPs", []interface{}{"1.2.3.4", "fd00::1"}))
+				Expect(newObj.Object["spec"]).To(HaveKeyWithValue("ipFamilies", []interface{}{"IPv4", "IPv6"}))
+				Expect(newObj.Object["spec"]).To(HaveKeyWithValue("ipFamilyPolicy", "PreferDualStack"))
+			})
+
+			It("should not overwrite the allocated fields if they are set", func() {
+				Expect(unstructured.SetNestedField(newObj.Object, "SingleStack", "spec", "ipFamilyPolicy")).To(Succeed())
+
+				Expect(mergeService(s, oldObj, newObj)).To(Succeed())
+				Expect(newObj.Object["spec"]).To(HaveKeyWithValue("ipFamilyPolicy", "SingleStack"))
+			})
+
+			It("should not keep the allocated fields of headless services", func() {
+				Expect(unstructured.SetNestedField(newObj.Object, "None", "spec", "clusterIP")).To(Succeed())
+
+				Expect(mergeService(s, oldObj, newObj)).To(Succeed())
+				Expect(newObj.Object["spec"]).NotTo(HaveKey("clusterIPs"))
+			})
+		})
 	})
 })
 
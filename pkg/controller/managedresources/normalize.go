@@ -0,0 +1,125 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// normalize returns the given object with the values written differently than the API server returns them in their
+// canonical form, so that they do not cause an update with every reconciliation: quantities (e.g. `1000m` instead of
+// `1`), durations (e.g. `1m` instead of `1m0s`), integers written as floats, and empty or null fields which are
+// omitted by the API server. The canonical form is taken from the typed object of the given scheme, hence objects of
+// kinds unknown to the scheme (e.g. custom resources) are returned unchanged. Values which are not changed by the
+// conversion to the typed object are kept, including fields unknown to it. The given object is not modified, as it
+// may be cached.
+func normalize(scheme *runtime.Scheme, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if scheme == nil {
+		return obj
+	}
+
+	typed, err := scheme.New(obj.GroupVersionKind())
+	if err != nil {
+		return obj
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return obj
+	}
+	canonical, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typed)
+	if err != nil {
+		return obj
+	}
+
+	out := obj.DeepCopy()
+	normalizeMap(out.Object, canonical)
+	return out
+}
+
+// normalizeMap replaces the values of `desired` by the values of `canonical` if they are equal in meaning, and
+// removes empty fields which are missing in `canonical`.
+func normalizeMap(desired, canonical map[string]interface{}) {
+	for key, value := range desired {
+		canonicalValue, ok := canonical[key]
+		if !ok {
+			if isEmpty(value) {
+				delete(desired, key)
+			}
+			continue
+		}
+		desired[key] = normalizeValue(value, canonicalValue)
+	}
+}
+
+func normalizeValue(value, canonical interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if canonicalMap, ok := canonical.(map[string]interface{}); ok {
+			normalizeMap(value, canonicalMap)
+		}
+
+	case []interface{}:
+		if canonicalList, ok := canonical.([]interface{}); ok && len(canonicalList) == len(value) {
+			for i := range value {
+				value[i] = normalizeValue(value[i], canonicalList[i])
+			}
+		}
+
+	case string:
+		if canonicalString, ok := canonical.(string); ok && canonicalString != value && (equalQuantities(value, canonicalString) || equalDurations(value, canonicalString)) {
+			return canonicalString
+		}
+
+	case float64:
+		if canonicalInt, ok := canonical.(int64); ok && float64(canonicalInt) == value {
+			return canonicalInt
+		}
+	}
+
+	return value
+}
+
+func isEmpty(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+func equalQuantities(a, b string) bool {
+	quantityA, err := resource.ParseQuantity(a)
+	if err != nil {
+		return false
+	}
+	quantityB, err := resource.ParseQuantity(b)
+	return err == nil && quantityA.Cmp(quantityB) == 0
+}
+
+func equalDurations(a, b string) bool {
+	durationA, err := time.ParseDuration(a)
+	if err != nil {
+		return false
+	}
+	durationB, err := time.ParseDuration(b)
+	return err == nil && durationA == durationB
+}
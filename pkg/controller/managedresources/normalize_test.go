@@ -0,0 +1,100 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	druidv1alpha1 "github.com/gardener/etcd-druid/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("#normalize", func() {
+	var s *runtime.Scheme
+
+	BeforeEach(func() {
+		s = runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+	})
+
+	It("should write quantities, integers and empty fields like the API server", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": "foo", "labels": map[string]interface{}{}, "annotations": nil},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{
+					"name":    "foo",
+					"args":    []interface{}{"1000m"},
+					"command": []interface{}{},
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": "1000m", "memory": "1024Mi"},
+						"limits":   map[string]interface{}{"cpu": "2", "memory": "1.5Gi"},
+					},
+				}},
+				"terminationGracePeriodSeconds": float64(30),
+				"nodeSelector":                  map[string]interface{}{},
+				// a field unknown to the vendored API types
+				"os": map[string]interface{}{"name": "linux"},
+			},
+		}}
+		original := obj.DeepCopy()
+
+		normalized := normalize(s, obj)
+
+		Expect(obj).To(Equal(original))
+		Expect(normalized.Object).To(Equal(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": "foo"},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{
+					"name": "foo",
+					"args": []interface{}{"1000m"},
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+						"limits":   map[string]interface{}{"cpu": "2", "memory": "1536Mi"},
+					},
+				}},
+				"terminationGracePeriodSeconds": int64(30),
+				"os":                            map[string]interface{}{"name": "linux"},
+			},
+		}))
+	})
+
+	It("should write durations like the API server", func() {
+		Expect(druidv1alpha1.AddToScheme(s)).To(Succeed())
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "druid.gardener.cloud/v1alpha1",
+			"kind":       "Etcd",
+			"metadata":   map[string]interface{}{"name": "foo"},
+			"spec": map[string]interface{}{
+				"backup": map[string]interface{}{"garbageCollectionPeriod": "12h", "deltaSnapshotPeriod": "5m0s"},
+			},
+		}}
+
+		Expect(normalize(s, obj).Object["spec"]).To(Equal(map[string]interface{}{
+			"backup": map[string]interface{}{"garbageCollectionPeriod": "12h0m0s", "deltaSnapshotPeriod": "5m0s"},
+		}))
+	})
+
+	It("should not change objects of unknown kinds", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "example.com/v1", "kind": "Foo", "spec": map[string]interface{}{}}}
+		Expect(normalize(s, obj)).To(BeIdenticalTo(obj))
+		Expect(normalize(nil, obj)).To(BeIdenticalTo(obj))
+	})
+})
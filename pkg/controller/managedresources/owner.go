@@ -0,0 +1,79 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+	"fmt"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// owner is the object in the target cluster which is set as owner of the objects of a ManagedResource.
+type owner struct {
+	groupKind schema.GroupKind
+	namespace string
+	reference metav1.OwnerReference
+}
+
+// ownerOf returns the owner of the objects of the given ManagedResource, or nil if it has none.
+func (r *Reconciler) ownerOf(ctx context.Context, mr *resourcesv1alpha1.ManagedResource) (*owner, error) {
+	if mr.Spec.Owner == nil {
+		return nil, nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(mr.Spec.Owner.APIVersion)
+	obj.SetKind(mr.Spec.Owner.Kind)
+	if err := r.targetClient.Get(ctx, client.ObjectKey{Namespace: mr.Spec.Owner.Namespace, Name: mr.Spec.Owner.Name}, obj); err != nil {
+		return nil, fmt.Errorf("could not get owner %q: %w", unstructuredToString(obj), err)
+	}
+
+	return &owner{
+		groupKind: obj.GroupVersionKind().GroupKind(),
+		namespace: obj.GetNamespace(),
+		reference: metav1.OwnerReference{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Name:       obj.GetName(),
+			UID:        obj.GetUID(),
+		},
+	}, nil
+}
+
+// setOn adds the owner reference to the given object unless it is already set. Namespaced owners are only set on the
+// objects in their namespace, as owner references across namespaces are considered absent by the garbage collector,
+// which would delete the objects immediately. The owner is never set on itself.
+func (o *owner) setOn(obj *unstructured.Unstructured) {
+	if o == nil || (o.namespace != "" && obj.GetNamespace() != o.namespace) {
+		return
+	}
+	if obj.GroupVersionKind().GroupKind() == o.groupKind && obj.GetNamespace() == o.namespace && obj.GetName() == o.reference.Name {
+		return
+	}
+
+	references := obj.GetOwnerReferences()
+	for _, reference := range references {
+		if reference.UID == o.reference.UID {
+			return
+		}
+	}
+	obj.SetOwnerReferences(append(references, o.reference))
+}
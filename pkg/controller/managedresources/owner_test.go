@@ -0,0 +1,91 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Owner", func() {
+	var (
+		o         *owner
+		reference = metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "anchor", UID: "1234"}
+		newObject = func(kind, namespace, name string) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind(kind)
+			obj.SetNamespace(namespace)
+			obj.SetName(name)
+			return obj
+		}
+	)
+
+	BeforeEach(func() {
+		o = &owner{groupKind: schema.GroupKind{Kind: "ConfigMap"}, namespace: "default", reference: reference}
+	})
+
+	Describe("#setOn", func() {
+		It("should add the owner reference to objects in the namespace of the owner", func() {
+			other := metav1.OwnerReference{APIVersion: "v1", Kind: "Secret", Name: "other", UID: "5678"}
+			obj := newObject("Secret", "default", "foo")
+			obj.SetOwnerReferences([]metav1.OwnerReference{other})
+
+			o.setOn(obj)
+			Expect(obj.GetOwnerReferences()).To(Equal([]metav1.OwnerReference{other, reference}))
+
+			o.setOn(obj)
+			Expect(obj.GetOwnerReferences()).To(Equal([]metav1.OwnerReference{other, reference}))
+		})
+
+		It("should not add namespaced owners to objects in other namespaces or cluster-scoped objects", func() {
+			obj := newObject("Secret", "kube-system", "foo")
+			o.setOn(obj)
+			Expect(obj.GetOwnerReferences()).To(BeEmpty())
+
+			obj = newObject("Namespace", "", "foo")
+			o.setOn(obj)
+			Expect(obj.GetOwnerReferences()).To(BeEmpty())
+		})
+
+		It("should add cluster-scoped owners to all objects", func() {
+			o = &owner{groupKind: schema.GroupKind{Kind: "Namespace"}, reference: metav1.OwnerReference{APIVersion: "v1", Kind: "Namespace", Name: "anchor", UID: "1234"}}
+
+			obj := newObject("Secret", "kube-system", "foo")
+			o.setOn(obj)
+			Expect(obj.GetOwnerReferences()).To(HaveLen(1))
+
+			obj = newObject("ClusterRole", "", "foo")
+			o.setOn(obj)
+			Expect(obj.GetOwnerReferences()).To(HaveLen(1))
+		})
+
+		It("should not set the owner on itself", func() {
+			obj := newObject("ConfigMap", "default", "anchor")
+			o.setOn(obj)
+			Expect(obj.GetOwnerReferences()).To(BeEmpty())
+		})
+
+		It("should do nothing without owner", func() {
+			o = nil
+			obj := newObject("Secret", "default", "foo")
+			o.setOn(obj)
+			Expect(obj.GetOwnerReferences()).To(BeEmpty())
+		})
+	})
+})
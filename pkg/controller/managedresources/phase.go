@@ -0,0 +1,132 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/health"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultMaxConcurrentApplies is the default number of objects of a ManagedResource which are applied in parallel.
+	DefaultMaxConcurrentApplies = 10
+	// DefaultWaitForReadyTimeout is the default duration the controller waits for a readiness gate to become ready.
+	DefaultWaitForReadyTimeout = 2 * time.Minute
+)
+
+// readinessPollInterval is the interval in which the health of a readiness gate is checked.
+var readinessPollInterval = 2 * time.Second
+
+// prerequisiteKinds are the kinds of objects which other objects of the same ManagedResource may depend on, e.g. the
+// CustomResourceDefinition of a custom resource or the Namespace of a namespaced object.
+var prerequisiteKinds = map[schema.GroupKind]struct{}{
+	{Group: apiextensionsv1beta1.GroupName, Kind: "CustomResourceDefinition"}: {},
+	{Group: corev1.GroupName, Kind: "Namespace"}:                              {},
+}
+
+// groupByApplyPhase groups the given objects into phases which must be applied one after another. The objects of one
+// phase don't depend on each other, hence they can be applied in parallel. The order of the objects within a phase
+// is kept.
+func groupByApplyPhase(objects []object) [][]object {
+	var prerequisites, others []object
+	for _, obj := range objects {
+		if _, ok := prerequisiteKinds[obj.obj.GroupVersionKind().GroupKind()]; ok {
+			prerequisites = append(prerequisites, obj)
+		} else {
+			others = append(others, obj)
+		}
+	}
+
+	var phases [][]object
+	for _, phase := range [][]object{prerequisites, others} {
+		if len(phase) > 0 {
+			phases = append(phases, phase)
+		}
+	}
+	return phases
+}
+
+// groupByApplyWave groups the given objects into waves which must be applied one after another. The waves are the
+// apply phases, split after every readiness gate (i.e. an object annotated with
+// `resources.gardener.cloud/wait-for-ready=true`), so that each readiness gate ends a wave.
+func groupByApplyWave(objects []object) [][]object {
+	var waves [][]object
+	for _, phase := range groupByApplyPhase(objects) {
+		var wave []object
+		for _, obj := range phase {
+			wave = append(wave, obj)
+			if isReadinessGate(obj.obj) {
+				waves = append(waves, wave)
+				wave = nil
+			}
+		}
+		if len(wave) > 0 {
+			waves = append(waves, wave)
+		}
+	}
+	return waves
+}
+
+// isReadinessGate returns true if the given object must be ready before the objects following it are applied.
+// Objects in mode Ignore are no readiness gates.
+func isReadinessGate(obj *unstructured.Unstructured) bool {
+	annotations := obj.GetAnnotations()
+	return annotations[resourcesv1alpha1.WaitForReady] == "true" && !ignoreMode(annotations)
+}
+
+// readinessGateOf returns the readiness gate ending the given wave, or nil if the wave doesn't end with one.
+func readinessGateOf(wave []object) *unstructured.Unstructured {
+	if len(wave) == 0 || !isReadinessGate(wave[len(wave)-1].obj) {
+		return nil
+	}
+	return wave[len(wave)-1].obj
+}
+
+// waitForReady waits until the given object in the target cluster passes the health checks, or fails after the
+// configured timeout.
+func (r *Reconciler) waitForReady(ctx context.Context, log logr.Logger, gate *unstructured.Unstructured) error {
+	resource := unstructuredToString(gate)
+	log.Info("Waiting for readiness gate", "resource", resource)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, r.waitForReadyTimeout)
+	defer cancel()
+
+	var lastErr error
+	if err := wait.PollImmediateUntil(readinessPollInterval, func() (bool, error) {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(gate.GroupVersionKind())
+		if err := r.targetClient.Get(ctx, client.ObjectKey{Namespace: gate.GetNamespace(), Name: gate.GetName()}, current); err != nil {
+			lastErr = err
+			return false, nil
+		}
+		lastErr = health.CheckHealth(r.targetScheme, current)
+		return lastErr == nil, nil
+	}, timeoutCtx.Done()); err != nil {
+		return fmt.Errorf("object %q did not become ready within %s, skipped all objects following it: %v", resource, r.waitForReadyTimeout, lastErr)
+	}
+	return nil
+}
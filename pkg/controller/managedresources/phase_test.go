@@ -0,0 +1,171 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+	"errors"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Phase", func() {
+	Describe("#groupByApplyPhase", func() {
+		newObject := func(apiVersion, kind, name string) object {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(apiVersion)
+			obj.SetKind(kind)
+			obj.SetName(name)
+			return object{obj: obj}
+		}
+
+		var (
+			crd        = newObject("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "foos.example.com")
+			namespace  = newObject("v1", "Namespace", "foo")
+			configMap  = newObject("v1", "ConfigMap", "foo")
+			customFoo  = newObject("example.com/v1", "Foo", "foo")
+			deployment = newObject("apps/v1", "Deployment", "foo")
+		)
+
+		It("should apply CustomResourceDefinitions and Namespaces first", func() {
+			Expect(groupByApplyPhase([]object{configMap, crd, customFoo, namespace, deployment})).To(Equal([][]object{
+				{crd, namespace},
+				{configMap, customFoo, deployment},
+			}))
+		})
+
+		It("should omit empty phases", func() {
+			Expect(groupByApplyPhase([]object{configMap, deployment})).To(Equal([][]object{{configMap, deployment}}))
+			Expect(groupByApplyPhase([]object{namespace})).To(Equal([][]object{{namespace}}))
+			Expect(groupByApplyPhase(nil)).To(BeEmpty())
+		})
+	})
+
+	Describe("#groupByApplyWave", func() {
+		newObject := func(kind, name string, annotations map[string]string) object {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind(kind)
+			obj.SetName(name)
+			obj.SetAnnotations(annotations)
+			return object{obj: obj}
+		}
+
+		var (
+			waitForReady = map[string]string{resourcesv1alpha1.WaitForReady: "true"}
+			namespace    = newObject("Namespace", "foo", nil)
+			service      = newObject("Service", "webhook", nil)
+			webhook      = newObject("Pod", "webhook", waitForReady)
+			configMap    = newObject("ConfigMap", "foo", nil)
+			ignored      = newObject("Pod", "ignored", map[string]string{resourcesv1alpha1.WaitForReady: "true", resourcesv1alpha1.Mode: resourcesv1alpha1.ModeIgnore})
+		)
+
+		It("should split the phases after every readiness gate", func() {
+			Expect(groupByApplyWave([]object{service, webhook, namespace, configMap})).To(Equal([][]object{
+				{namespace},
+				{service, webhook},
+				{configMap},
+			}))
+			Expect(groupByApplyWave([]object{service, webhook})).To(Equal([][]object{{service, webhook}}))
+		})
+
+		It("should not split the phases after objects in mode Ignore", func() {
+			Expect(groupByApplyWave([]object{ignored, configMap})).To(Equal([][]object{{ignored, configMap}}))
+		})
+
+		It("should return the readiness gate ending a wave", func() {
+			Expect(readinessGateOf([]object{service, webhook})).To(Equal(webhook.obj))
+			Expect(readinessGateOf([]object{webhook, service})).To(BeNil())
+			Expect(readinessGateOf([]object{ignored})).To(BeNil())
+			Expect(readinessGateOf(nil)).To(BeNil())
+		})
+	})
+
+	Describe("#waitForReady", func() {
+		var (
+			ctrl *gomock.Controller
+			c    *mockclient.MockClient
+			r    *Reconciler
+			gate *unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			c = mockclient.NewMockClient(ctrl)
+
+			scheme := runtime.NewScheme()
+			Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+			r = &Reconciler{targetClient: c, targetScheme: scheme}
+
+			gate = &unstructured.Unstructured{}
+			gate.SetAPIVersion("apps/v1")
+			gate.SetKind("Deployment")
+			gate.SetNamespace("foo")
+			gate.SetName("webhook")
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		getDeployment := func(available bool) func(context.Context, interface{}, runtime.Object) error {
+			return func(_ context.Context, _ interface{}, obj runtime.Object) error {
+				status := "False"
+				if available {
+					status = "True"
+				}
+				obj.(*unstructured.Unstructured).Object = map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata":   map[string]interface{}{"namespace": "foo", "name": "webhook", "generation": int64(1)},
+					"status": map[string]interface{}{
+						"observedGeneration": int64(1),
+						"conditions":         []interface{}{map[string]interface{}{"type": "Available", "status": status}},
+					},
+				}
+				return nil
+			}
+		}
+
+		It("should succeed if the readiness gate is healthy", func() {
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(getDeployment(true))
+
+			Expect(r.waitForReady(context.TODO(), runtimelog.NullLogger{}, gate)).To(Succeed())
+		})
+
+		It("should fail if the readiness gate does not become healthy in time", func() {
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(getDeployment(false))
+
+			err := r.waitForReady(context.TODO(), runtimelog.NullLogger{}, gate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`object "apps/v1/Deployment/foo/webhook" did not become ready`))
+		})
+
+		It("should fail if the readiness gate cannot be read", func() {
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("fake"))
+
+			Expect(r.waitForReady(context.TODO(), runtimelog.NullLogger{}, gate)).To(MatchError(ContainSubstring("fake")))
+		})
+	})
+})
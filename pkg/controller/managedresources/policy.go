@@ -0,0 +1,100 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"fmt"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ClusterScopedObjectPolicy describes whether ManagedResources may contain cluster-scoped objects.
+type ClusterScopedObjectPolicy string
+
+const (
+	// ClusterScopedObjectPolicyAllow allows cluster-scoped objects.
+	ClusterScopedObjectPolicyAllow ClusterScopedObjectPolicy = "Allow"
+	// ClusterScopedObjectPolicyForbid forbids cluster-scoped objects.
+	ClusterScopedObjectPolicyForbid ClusterScopedObjectPolicy = "Forbid"
+	// ClusterScopedObjectPolicyRequireConfirmation allows cluster-scoped objects only if they are annotated with
+	// `confirmation.gardener.cloud/cluster-scoped=true`.
+	ClusterScopedObjectPolicyRequireConfirmation ClusterScopedObjectPolicy = "RequireConfirmation"
+)
+
+// ApplyPolicy restricts the objects which ManagedResources may apply to the target cluster. A nil policy allows all
+// objects.
+type ApplyPolicy struct {
+	// ClusterScopedObjects maps resource classes to the policy for cluster-scoped objects in their ManagedResources.
+	// Classes which are not contained allow cluster-scoped objects.
+	ClusterScopedObjects map[string]ClusterScopedObjectPolicy
+	// ObjectNamespaces maps resource classes to the only namespace in which their ManagedResources may create namespaced
+	// objects. An empty namespace restricts them to the namespace of the ManagedResource itself. Classes which are not
+	// contained may create objects in all namespaces.
+	ObjectNamespaces map[string]string
+}
+
+// ParseClusterScopedObjectPolicy parses the given policy for cluster-scoped objects.
+func ParseClusterScopedObjectPolicy(policy string) (ClusterScopedObjectPolicy, error) {
+	switch p := ClusterScopedObjectPolicy(policy); p {
+	case ClusterScopedObjectPolicyAllow, ClusterScopedObjectPolicyForbid, ClusterScopedObjectPolicyRequireConfirmation:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown policy for cluster-scoped objects %q, must be one of %s, %s or %s", policy, ClusterScopedObjectPolicyAllow, ClusterScopedObjectPolicyForbid, ClusterScopedObjectPolicyRequireConfirmation)
+}
+
+// Violations returns a description of every object of the given ManagedResource violating the policy. Objects in mode
+// Ignore are not applied and hence not checked. The namespace of the objects must already be defaulted or unset
+// depending on the scope of their kind.
+func (p *ApplyPolicy) Violations(mr *resourcesv1alpha1.ManagedResource, objs []*unstructured.Unstructured) []string {
+	if p == nil {
+		return nil
+	}
+
+	var (
+		violations                         []string
+		class                              = ResourceClassOf(mr)
+		clusterScopedPolicy                = p.ClusterScopedObjects[class]
+		objectNamespace, restrictNamespace = p.ObjectNamespaces[class]
+	)
+	if restrictNamespace && objectNamespace == "" {
+		objectNamespace = mr.Namespace
+	}
+
+	for _, obj := range objs {
+		if ignoreMode(obj.GetAnnotations()) {
+			continue
+		}
+
+		if obj.GetNamespace() != "" {
+			if restrictNamespace && obj.GetNamespace() != objectNamespace {
+				violations = append(violations, fmt.Sprintf("object %s is not in the namespace %s", unstructuredToString(obj), objectNamespace))
+			}
+			continue
+		}
+
+		switch clusterScopedPolicy {
+		case ClusterScopedObjectPolicyForbid:
+			violations = append(violations, fmt.Sprintf("cluster-scoped object %s is forbidden", unstructuredToString(obj)))
+		case ClusterScopedObjectPolicyRequireConfirmation:
+			if !annotationExistsAndValueTrue(obj, resourcesv1alpha1.ConfirmationClusterScoped) {
+				violations = append(violations, fmt.Sprintf("cluster-scoped object %s must be confirmed with the %s=true annotation", unstructuredToString(obj), resourcesv1alpha1.ConfirmationClusterScoped))
+			}
+		}
+	}
+
+	return violations
+}
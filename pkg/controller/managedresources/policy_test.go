@@ -0,0 +1,136 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources_test
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("ApplyPolicy", func() {
+	var (
+		class = "tenant"
+
+		mr             *resourcesv1alpha1.ManagedResource
+		clusterRole    *unstructured.Unstructured
+		configMap      *unstructured.Unstructured
+		newClusterRole = func(annotations map[string]string) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("rbac.authorization.k8s.io/v1")
+			obj.SetKind("ClusterRole")
+			obj.SetName("foo")
+			obj.SetAnnotations(annotations)
+			return obj
+		}
+	)
+
+	BeforeEach(func() {
+		mr = &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mr"},
+			Spec:       resourcesv1alpha1.ManagedResourceSpec{Class: &class},
+		}
+		clusterRole = newClusterRole(nil)
+		configMap = &unstructured.Unstructured{}
+		configMap.SetAPIVersion("v1")
+		configMap.SetKind("ConfigMap")
+		configMap.SetNamespace("default")
+		configMap.SetName("foo")
+	})
+
+	Describe("#Violations", func() {
+		It("should allow all objects if no policy is given", func() {
+			var policy *ApplyPolicy
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{clusterRole, configMap})).To(BeEmpty())
+		})
+
+		It("should allow cluster-scoped objects for classes without policy", func() {
+			policy := &ApplyPolicy{ClusterScopedObjects: map[string]ClusterScopedObjectPolicy{"other": ClusterScopedObjectPolicyForbid}}
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{clusterRole, configMap})).To(BeEmpty())
+		})
+
+		It("should allow cluster-scoped objects if the policy is Allow", func() {
+			policy := &ApplyPolicy{ClusterScopedObjects: map[string]ClusterScopedObjectPolicy{class: ClusterScopedObjectPolicyAllow}}
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{clusterRole, configMap})).To(BeEmpty())
+		})
+
+		It("should reject cluster-scoped objects if the policy is Forbid", func() {
+			policy := &ApplyPolicy{ClusterScopedObjects: map[string]ClusterScopedObjectPolicy{class: ClusterScopedObjectPolicyForbid}}
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{clusterRole, configMap})).To(ConsistOf(
+				ContainSubstring("ClusterRole"),
+			))
+		})
+
+		It("should not check objects in mode Ignore", func() {
+			policy := &ApplyPolicy{ClusterScopedObjects: map[string]ClusterScopedObjectPolicy{class: ClusterScopedObjectPolicyForbid}}
+			ignored := newClusterRole(map[string]string{resourcesv1alpha1.Mode: resourcesv1alpha1.ModeIgnore})
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{ignored})).To(BeEmpty())
+		})
+
+		It("should require the confirmation of cluster-scoped objects if the policy is RequireConfirmation", func() {
+			policy := &ApplyPolicy{ClusterScopedObjects: map[string]ClusterScopedObjectPolicy{class: ClusterScopedObjectPolicyRequireConfirmation}}
+			confirmed := newClusterRole(map[string]string{resourcesv1alpha1.ConfirmationClusterScoped: "true"})
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{confirmed, configMap})).To(BeEmpty())
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{clusterRole, configMap})).To(ConsistOf(
+				ContainSubstring(resourcesv1alpha1.ConfirmationClusterScoped),
+			))
+		})
+	})
+
+	Describe("#Violations with restricted object namespaces", func() {
+		It("should restrict namespaced objects to the namespace of the ManagedResource", func() {
+			policy := &ApplyPolicy{ObjectNamespaces: map[string]string{class: ""}}
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{clusterRole, configMap})).To(BeEmpty())
+
+			configMap.SetNamespace("kube-system")
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{clusterRole, configMap})).To(ConsistOf(
+				ContainSubstring("is not in the namespace default"),
+			))
+		})
+
+		It("should restrict namespaced objects to the configured namespace", func() {
+			policy := &ApplyPolicy{ObjectNamespaces: map[string]string{class: "kube-system"}}
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{configMap})).To(ConsistOf(
+				ContainSubstring("is not in the namespace kube-system"),
+			))
+
+			configMap.SetNamespace("kube-system")
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{configMap})).To(BeEmpty())
+		})
+
+		It("should not restrict the namespaces of classes which are not configured", func() {
+			policy := &ApplyPolicy{ObjectNamespaces: map[string]string{"other": ""}}
+			configMap.SetNamespace("kube-system")
+			Expect(policy.Violations(mr, []*unstructured.Unstructured{configMap})).To(BeEmpty())
+		})
+	})
+
+	Describe("#ParseClusterScopedObjectPolicy", func() {
+		It("should parse known policies", func() {
+			for _, p := range []ClusterScopedObjectPolicy{ClusterScopedObjectPolicyAllow, ClusterScopedObjectPolicyForbid, ClusterScopedObjectPolicyRequireConfirmation} {
+				Expect(ParseClusterScopedObjectPolicy(string(p))).To(Equal(p))
+			}
+		})
+
+		It("should fail for unknown policies", func() {
+			_, err := ParseClusterScopedObjectPolicy("Maybe")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"fmt"
+	"strings"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Render decodes the objects of the given secrets and returns them as they are created in the target cluster by the
+// reconciler of the given ManagedResource, i.e. with defaulted namespaces, generated names, injected labels, the
+// description annotation and the origin label of its resource class. The origin annotation is only set if the
+// ManagedResource has a name. Objects which are not applied (pre-delete hooks and objects in mode Ignore) are skipped.
+// The given mapper is used for looking up the scope of the objects' kinds, the namespace of objects of unknown kinds
+// is defaulted like the reconciler does before their kind is known.
+func Render(log logr.Logger, mapper meta.RESTMapper, mr *resourcesv1alpha1.ManagedResource, clusterID string, secrets []*corev1.Secret) ([]*unstructured.Unstructured, error) {
+	decodedObjs, err := decodeAllSecrets(log, mapper, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		class  = ResourceClassOf(mr)
+		origin string
+		out    = make([]*unstructured.Unstructured, 0, len(decodedObjs))
+	)
+	if mr.Name != "" {
+		origin = resourcesv1alpha1helper.Origin(clusterID, client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name})
+	}
+
+	for _, desired := range decodedObjs {
+		if hookOf(desired) == resourcesv1alpha1.HookPreDelete || ignoreMode(desired.GetAnnotations()) {
+			continue
+		}
+
+		current := desired.DeepCopy()
+		if ignore(desired) {
+			annotations := current.GetAnnotations()
+			delete(annotations, descriptionAnnotation)
+			current.SetAnnotations(annotations)
+			out = append(out, current)
+			continue
+		}
+
+		if err := injectLabels(desired, mr.Spec.InjectLabels); err != nil {
+			return nil, fmt.Errorf("error injecting labels into object %q: %s", unstructuredToString(desired), err)
+		}
+		if err := merge(desired, current, isTrue(mr.Spec.ForceOverwriteLabels), nil, isTrue(mr.Spec.ForceOverwriteAnnotations), nil, isTrue(mr.Spec.ForceOverwriteOwnerReferences), isTrue(mr.Spec.ForceOverwriteFinalizers), false, false); err != nil {
+			return nil, err
+		}
+
+		setOriginLabel(current, class)
+		if origin != "" {
+			setOriginAnnotation(current, origin)
+		}
+		out = append(out, current)
+	}
+
+	return out, nil
+}
+
+func isTrue(value *bool) bool {
+	return value != nil && *value
+}
+
+// decodeAllSecrets decodes the objects of the given secrets like the reconciler does, but fails if any of them cannot
+// be decoded or is contained multiple times.
+func decodeAllSecrets(log logr.Logger, mapper meta.RESTMapper, secrets []*corev1.Secret) ([]*unstructured.Unstructured, error) {
+	objs, decodingErrors, duplicates, _ := decodeSecrets(log, mapper, nil, secrets)
+	if len(decodingErrors) > 0 {
+		messages := make([]string, 0, len(decodingErrors))
+		for _, decodingError := range decodingErrors {
+			messages = append(messages, decodingError.String())
+		}
+		return nil, fmt.Errorf("could not decode all objects: %s", strings.Join(messages, " "))
+	}
+	if len(duplicates) > 0 {
+		return nil, fmt.Errorf("the objects contain duplicates: %s", duplicateObjectsMessage(duplicates))
+	}
+	return objs, nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Render", func() {
+	var (
+		mapper *meta.DefaultRESTMapper
+		mr     *resourcesv1alpha1.ManagedResource
+		secret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		mapper = meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+		mapper.Add(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, meta.RESTScopeNamespace)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+		mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+
+		mr = &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "mr"},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				Class:        pointer.StringPtr("seed"),
+				InjectLabels: map[string]string{"shoot.gardener.cloud/no-cleanup": "true"},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "secret"},
+			Data: map[string][]byte{
+				"deployment.yaml": []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+  labels:
+    app: dep
+spec:
+  template:
+    metadata:
+      labels:
+        app: dep
+`),
+				"rbac.yaml": []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: role
+  namespace: foo
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+  annotations:
+    resources.gardener.cloud/mode: Ignore
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: cleanup
+  annotations:
+    resources.gardener.cloud/hook: pre-delete
+`),
+			},
+		}
+	})
+
+	It("should return the objects as they are created in the target cluster", func() {
+		objs, err := Render(runtimelog.NullLogger{}, mapper, mr, "garden", []*corev1.Secret{secret})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(2))
+
+		deployment := objs[0]
+		Expect(deployment.GetNamespace()).To(Equal(metav1.NamespaceDefault))
+		Expect(deployment.GetLabels()).To(Equal(map[string]string{
+			"app":                             "dep",
+			"shoot.gardener.cloud/no-cleanup": "true",
+			resourcesv1alpha1.OriginLabel:     "seed",
+		}))
+		Expect(deployment.GetAnnotations()).To(Equal(map[string]string{
+			descriptionAnnotation:              descriptionAnnotationText,
+			resourcesv1alpha1.OriginAnnotation: "garden:shoot--foo--bar/mr",
+		}))
+		Expect(deployment.Object["spec"]).To(HaveKeyWithValue("template", HaveKeyWithValue("metadata", HaveKeyWithValue("labels", map[string]interface{}{
+			"app":                             "dep",
+			"shoot.gardener.cloud/no-cleanup": "true",
+		}))))
+
+		clusterRole := objs[1]
+		Expect(clusterRole.GetName()).To(Equal("role"))
+		Expect(clusterRole.GetNamespace()).To(BeEmpty())
+	})
+
+	It("should not set the origin annotation if the ManagedResource has no name", func() {
+		mr.Name = ""
+
+		objs, err := Render(runtimelog.NullLogger{}, mapper, mr, "", []*corev1.Secret{secret})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs[0].GetAnnotations()).NotTo(HaveKey(resourcesv1alpha1.OriginAnnotation))
+	})
+
+	It("should fail if an object cannot be decoded", func() {
+		secret.Data["invalid.yaml"] = []byte("foo: [")
+
+		_, err := Render(runtimelog.NullLogger{}, mapper, mr, "", []*corev1.Secret{secret})
+		Expect(err).To(MatchError(ContainSubstring("Could not decode resource at index 0 in 'invalid.yaml' in secret 'shoot--foo--bar/secret'")))
+	})
+})
@@ -20,13 +20,12 @@ import (
 	"time"
 
 	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
-	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	"github.com/gardener/gardener-resource-manager/pkg/update"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -45,26 +44,23 @@ func (r *SecretReconciler) InjectClient(client client.Client) error {
 	return nil
 }
 
-// InjectStopChannel injects a stop channel into the reconciler.
-func (r *SecretReconciler) InjectStopChannel(stopCh <-chan struct{}) error {
-	r.ctx = utils.ContextFromStopChannel(stopCh)
-	return nil
-}
-
-// NewSecretReconciler creates a new secret reconciler.
-func NewSecretReconciler(log logr.Logger, class *ClassFilter) *SecretReconciler {
+// NewSecretReconciler creates a new secret reconciler. All requests of its reconciliations are made with (children
+// of) the given context, i.e. cancelling it aborts them.
+func NewSecretReconciler(ctx context.Context, log logr.Logger, class *ClassFilter) *SecretReconciler {
 	return &SecretReconciler{
 		log:   log,
 		class: class,
+		ctx:   ctx,
 	}
 }
 
 // Reconcile implements `reconcile.SecretReconciler`.
 func (r *SecretReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx := r.ctx
 	log := r.log.WithValues("secret", req)
 
 	secret := &corev1.Secret{}
-	if err := r.client.Get(r.ctx, req.NamespacedName, secret); err != nil {
+	if err := r.client.Get(ctx, req.NamespacedName, secret); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Stopping reconciliation of Secret, as it has been deleted")
 			return reconcile.Result{}, nil
@@ -73,46 +69,47 @@ func (r *SecretReconciler) Reconcile(req reconcile.Request) (reconcile.Result, e
 	}
 
 	resourceList := &resourcesv1alpha1.ManagedResourceList{}
-	if err := r.client.List(r.ctx, resourceList, client.InNamespace(secret.Namespace)); err != nil {
-		return reconcile.Result{}, fmt.Errorf("could not fetch ManagedResources in namespace of Secret: %+v", err)
+	if err := r.client.List(ctx, resourceList, MatchingSecretRef(secret.Namespace, secret.Name)...); err != nil {
+		return reconcile.Result{}, fmt.Errorf("could not fetch ManagedResources referencing Secret: %+v", err)
 	}
 
-	// check if there is at least one ManagedResource this controller is responsible for and which references this secret
-	secretIsReferenced := false
+	// determine the finalizers of the classes of all ManagedResources this controller is responsible for and which
+	// reference this secret, class might have changed, then we need to remove our finalizer
+	requiredFinalizers := sets.NewString()
 	for _, resource := range resourceList.Items {
 		for _, ref := range resource.Spec.SecretRefs {
-			// check if we are responsible for this MR, class might have changed, then we need to remove our finalizer
 			if ref.Name == secret.Name && r.class.Responsible(&resource) {
-				secretIsReferenced = true
+				requiredFinalizers.Insert(r.class.FinalizerNameFor(&resource))
 				break
 			}
 		}
 	}
 
-	controllerFinalizer := r.class.FinalizerName()
 	secretFinalizers := sets.NewString(secret.Finalizers...)
-	addFinalizer, removeFinalizer := false, false
-	if secretIsReferenced && !secretFinalizers.Has(controllerFinalizer) {
-		addFinalizer = true
-		log.Info("adding finalizer to secret because it is referenced by a ManagedResource",
-			"finalizer", controllerFinalizer)
-	} else if !secretIsReferenced && secretFinalizers.Has(controllerFinalizer) {
-		removeFinalizer = true
-		log.Info("removing finalizer from secret because it is not referenced by a ManagedResource of this class",
-			"finalizer", controllerFinalizer)
+	addFinalizers, removeFinalizers := requiredFinalizers.Difference(secretFinalizers), sets.NewString()
+	for finalizer := range secretFinalizers.Difference(requiredFinalizers) {
+		if r.class.OwnsFinalizer(finalizer) {
+			removeFinalizers.Insert(finalizer)
+		}
 	}
 
-	if addFinalizer || removeFinalizer {
-		if err := utils.TryUpdate(r.ctx, retry.DefaultBackoff, r.client, secret, func() error {
+	if addFinalizers.Len() > 0 {
+		log.Info("adding finalizers to secret because it is referenced by a ManagedResource",
+			"finalizers", addFinalizers.List())
+	}
+	if removeFinalizers.Len() > 0 {
+		log.Info("removing finalizers from secret because it is not referenced by a ManagedResource of their class",
+			"finalizers", removeFinalizers.List())
+	}
+
+	if addFinalizers.Len() > 0 || removeFinalizers.Len() > 0 {
+		if err := update.Try(ctx, r.client, secret, func() error {
 			secretFinalizers := sets.NewString(secret.Finalizers...)
-			if addFinalizer {
-				secretFinalizers.Insert(controllerFinalizer)
-			} else if removeFinalizer {
-				secretFinalizers.Delete(controllerFinalizer)
-			}
+			secretFinalizers.Insert(addFinalizers.UnsortedList()...)
+			secretFinalizers.Delete(removeFinalizers.UnsortedList()...)
 			secret.Finalizers = secretFinalizers.UnsortedList()
 			return nil
-		}); client.IgnoreNotFound(err) != nil {
+		}, update.Options{}); client.IgnoreNotFound(err) != nil {
 			r.log.Error(err, "failed to update finalizers of Secret")
 			// dont' run into exponential backoff for adding/removing finalizers
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
@@ -41,6 +41,7 @@ import (
 
 var _ = Describe("SecretReconciler", func() {
 	var (
+		ctx  = context.TODO()
 		ctrl *gomock.Controller
 		c    *mockclient.MockClient
 
@@ -55,7 +56,7 @@ var _ = Describe("SecretReconciler", func() {
 		c = mockclient.NewMockClient(ctrl)
 
 		filter = managedresources.NewClassFilter("seed")
-		r = managedresources.NewSecretReconciler(log.NullLogger{}, filter)
+		r = managedresources.NewSecretReconciler(ctx, log.NullLogger{}, filter)
 
 		Expect(inject.ClientInto(c, r)).To(BeTrue())
 
@@ -75,15 +76,9 @@ var _ = Describe("SecretReconciler", func() {
 		ctrl.Finish()
 	})
 
-	Describe("#InjectStopChannel", func() {
-		It("should be able to inject a stop channel", func() {
-			Expect(inject.StopChannelInto(context.TODO().Done(), r)).To(BeTrue())
-		})
-	})
-
 	Describe("#Reconcile", func() {
 		It("should do nothing if the secret has been deleted", func() {
-			c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 				Return(apierrors.NewNotFound(corev1.Resource("secrets"), secret.Name))
 
 			res, err := r.Reconcile(secretReq)
@@ -96,7 +91,7 @@ var _ = Describe("SecretReconciler", func() {
 		It("should do nothing if secret get fails", func() {
 			fakeErr := fmt.Errorf("fake")
 
-			c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 				Return(fakeErr)
 
 			_, err := r.Reconcile(secretReq)
@@ -107,12 +102,12 @@ var _ = Describe("SecretReconciler", func() {
 			fakeErr := fmt.Errorf("fake")
 
 			gomock.InOrder(
-				c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 					DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 						secret.DeepCopyInto(obj.(*corev1.Secret))
 						return nil
 					}),
-				c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+				c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 					Return(fakeErr),
 			)
 
@@ -122,12 +117,12 @@ var _ = Describe("SecretReconciler", func() {
 
 		It("should do nothing if there is no MR in namespace", func() {
 			gomock.InOrder(
-				c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 					DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 						secret.DeepCopyInto(obj.(*corev1.Secret))
 						return nil
 					}),
-				c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+				c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 					Return(nil),
 			)
 
@@ -149,12 +144,12 @@ var _ = Describe("SecretReconciler", func() {
 			}}
 
 			gomock.InOrder(
-				c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 					DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 						secret.DeepCopyInto(obj.(*corev1.Secret))
 						return nil
 					}),
-				c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+				c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 					DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 						list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
 						return nil
@@ -179,12 +174,12 @@ var _ = Describe("SecretReconciler", func() {
 			}}
 
 			gomock.InOrder(
-				c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 					DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 						secret.DeepCopyInto(obj.(*corev1.Secret))
 						return nil
 					}),
-				c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+				c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 					DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 						list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
 						return nil
@@ -210,12 +205,12 @@ var _ = Describe("SecretReconciler", func() {
 				},
 			}}
 
-			c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 				DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 					secret.DeepCopyInto(obj.(*corev1.Secret))
 					return nil
 				})
-			c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 				DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 					list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
 					return nil
@@ -238,17 +233,17 @@ var _ = Describe("SecretReconciler", func() {
 				},
 			}}
 
-			c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 				DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 					secret.DeepCopyInto(obj.(*corev1.Secret))
 					return nil
 				}).Times(2)
-			c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 				DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 					list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
 					return nil
 				})
-			c.EXPECT().Update(nil, gomock.AssignableToTypeOf(secret)).
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(secret)).
 				DoAndReturn(func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
 					s := obj.(*corev1.Secret)
 					Expect(s.Finalizers).To(ConsistOf(filter.FinalizerName()))
@@ -270,12 +265,12 @@ var _ = Describe("SecretReconciler", func() {
 				},
 			}}
 
-			c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 				DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 					secret.DeepCopyInto(obj.(*corev1.Secret))
 					return nil
 				})
-			c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 				DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 					list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
 					return nil
@@ -298,17 +293,17 @@ var _ = Describe("SecretReconciler", func() {
 				},
 			}}
 
-			c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 				DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 					secret.DeepCopyInto(obj.(*corev1.Secret))
 					return nil
 				}).Times(2)
-			c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 				DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 					list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
 					return nil
 				})
-			c.EXPECT().Update(nil, gomock.AssignableToTypeOf(secret)).
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(secret)).
 				DoAndReturn(func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
 					s := obj.(*corev1.Secret)
 					Expect(s.Finalizers).To(BeEmpty())
@@ -334,17 +329,17 @@ var _ = Describe("SecretReconciler", func() {
 				},
 			}}
 
-			c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 				DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 					secret.DeepCopyInto(obj.(*corev1.Secret))
 					return nil
 				}).Times(2)
-			c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 				DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 					list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
 					return nil
 				})
-			c.EXPECT().Update(nil, gomock.AssignableToTypeOf(secret)).
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(secret)).
 				DoAndReturn(func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
 					s := obj.(*corev1.Secret)
 					Expect(s.Finalizers).To(BeEmpty())
@@ -358,6 +353,47 @@ var _ = Describe("SecretReconciler", func() {
 			}))
 		})
 
+		It("should maintain the finalizers of all classes of the instance", func() {
+			filter = managedresources.NewClassFilter("seed,shoot")
+			r = managedresources.NewSecretReconciler(ctx, log.NullLogger{}, filter)
+			Expect(inject.ClientInto(c, r)).To(BeTrue())
+
+			otherFinalizer := managedresources.FinalizerName + "-other"
+			secret.Finalizers = []string{filter.FinalizerName(), otherFinalizer}
+
+			mrs := []resourcesv1alpha1.ManagedResource{{
+				Spec: resourcesv1alpha1.ManagedResourceSpec{
+					Class: pointer.StringPtr("shoot"),
+					SecretRefs: []corev1.LocalObjectReference{{
+						Name: secret.Name,
+					}},
+				},
+			}}
+
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+					secret.DeepCopyInto(obj.(*corev1.Secret))
+					return nil
+				}).Times(2)
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
+				DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+					list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
+					return nil
+				})
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(secret)).
+				DoAndReturn(func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					s := obj.(*corev1.Secret)
+					Expect(s.Finalizers).To(ConsistOf(managedresources.FinalizerName+"-shoot", otherFinalizer))
+					return nil
+				})
+
+			res, err := r.Reconcile(secretReq)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(reconcile.Result{
+				Requeue: false,
+			}))
+		})
+
 		It("should requeue if secret update fails", func() {
 			secret.Finalizers = []string{filter.FinalizerName()}
 
@@ -370,17 +406,17 @@ var _ = Describe("SecretReconciler", func() {
 				},
 			}}
 
-			c.EXPECT().Get(nil, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			c.EXPECT().Get(ctx, secretReq.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
 				DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 					secret.DeepCopyInto(obj.(*corev1.Secret))
 					return nil
 				}).Times(2)
-			c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 				DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 					list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
 					return nil
 				})
-			c.EXPECT().Update(nil, gomock.AssignableToTypeOf(secret)).
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(secret)).
 				DoAndReturn(func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
 					return fmt.Errorf("fake")
 				})
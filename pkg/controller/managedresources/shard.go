@@ -0,0 +1,92 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ShardLabel is a label on ManagedResources assigning them explicitly to the shard with the given index, overriding
+// the shard computed from their UID.
+const ShardLabel = "resources.gardener.cloud/shard"
+
+// Shard is one of several disjoint subsets of the ManagedResources of a resource class, each of which is reconciled
+// by another controller instance.
+type Shard struct {
+	// Index is the index of the shard, between 0 and Count-1.
+	Index int
+	// Count is the total number of shards.
+	Count int
+}
+
+// NewShard returns the shard with the given index of the given number of shards.
+func NewShard(index, count int) (*Shard, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("number of shards must be at least 1")
+	}
+	if index < 0 || index >= count {
+		return nil, fmt.Errorf("shard index must be between 0 and %d", count-1)
+	}
+	return &Shard{Index: index, Count: count}, nil
+}
+
+// ShardIndexFromHostname returns the shard index from the ordinal suffix of the given hostname, e.g. 2 for
+// `gardener-resource-manager-2` as assigned to the pods of a StatefulSet.
+func ShardIndexFromHostname(hostname string) (int, error) {
+	i := strings.LastIndex(hostname, "-")
+	if i < 0 {
+		return 0, fmt.Errorf("hostname %q has no ordinal suffix", hostname)
+	}
+	index, err := strconv.Atoi(hostname[i+1:])
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("hostname %q has no ordinal suffix", hostname)
+	}
+	return index, nil
+}
+
+// Owns checks whether the given ManagedResource belongs to the shard.
+func (s *Shard) Owns(obj metav1.Object) bool {
+	if value, ok := obj.GetLabels()[ShardLabel]; ok {
+		if index, err := strconv.Atoi(value); err == nil && index >= 0 && index < s.Count {
+			return index == s.Index
+		}
+	}
+	return ShardFor(obj.GetUID(), s.Count) == s.Index
+}
+
+// ShardFor returns the index of the shard the object with the given UID belongs to if there are `count` shards. It
+// uses a consistent hash, so that only about 1/count of the objects move to another shard if a shard is added.
+func ShardFor(uid types.UID, count int) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(uid))
+	return jumpHash(h.Sum64(), count)
+}
+
+// jumpHash implements the jump consistent hash algorithm of Lamping and Veach (https://arxiv.org/abs/1406.2294).
+func jumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources_test
+
+import (
+	"fmt"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Shard", func() {
+	uids := func(n int) []types.UID {
+		out := make([]types.UID, 0, n)
+		for i := 0; i < n; i++ {
+			out = append(out, types.UID(fmt.Sprintf("5c5d5e9a-%04d-4a1b-9c2d-%012d", i, i)))
+		}
+		return out
+	}
+
+	Describe("#NewShard", func() {
+		It("should return valid shards", func() {
+			Expect(NewShard(1, 3)).To(Equal(&Shard{Index: 1, Count: 3}))
+		})
+
+		It("should reject invalid shards", func() {
+			_, err := NewShard(0, 0)
+			Expect(err).To(HaveOccurred())
+			_, err = NewShard(3, 3)
+			Expect(err).To(HaveOccurred())
+			_, err = NewShard(-1, 3)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#ShardIndexFromHostname", func() {
+		It("should return the ordinal of the hostname", func() {
+			Expect(ShardIndexFromHostname("gardener-resource-manager-2")).To(Equal(2))
+		})
+
+		It("should fail for hostnames without ordinal", func() {
+			_, err := ShardIndexFromHostname("gardener-resource-manager-5d8f9c9d4-x2k4p")
+			Expect(err).To(HaveOccurred())
+			_, err = ShardIndexFromHostname("localhost")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#ShardFor", func() {
+		It("should distribute the objects evenly", func() {
+			counts := make([]int, 4)
+			for _, uid := range uids(4000) {
+				shard := ShardFor(uid, 4)
+				Expect(shard).To(BeNumerically(">=", 0))
+				Expect(shard).To(BeNumerically("<", 4))
+				counts[shard]++
+			}
+			for _, count := range counts {
+				Expect(count).To(BeNumerically("~", 1000, 150))
+			}
+		})
+
+		It("should only move objects to the new shard if a shard is added", func() {
+			moved := 0
+			for _, uid := range uids(4000) {
+				before, after := ShardFor(uid, 3), ShardFor(uid, 4)
+				if before != after {
+					Expect(after).To(Equal(3))
+					moved++
+				}
+			}
+			Expect(moved).To(BeNumerically("~", 1000, 150))
+		})
+	})
+
+	Describe("#Owns", func() {
+		var shards []*Shard
+
+		BeforeEach(func() {
+			shards = []*Shard{{Index: 0, Count: 2}, {Index: 1, Count: 2}}
+		})
+
+		It("should assign every object to exactly one shard", func() {
+			for _, uid := range uids(100) {
+				obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{UID: uid}}
+				Expect(shards[0].Owns(obj)).NotTo(Equal(shards[1].Owns(obj)))
+			}
+		})
+
+		It("should respect the shard label", func() {
+			for _, uid := range uids(10) {
+				obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{UID: uid, Labels: map[string]string{ShardLabel: "1"}}}
+				Expect(shards[0].Owns(obj)).To(BeFalse())
+				Expect(shards[1].Owns(obj)).To(BeTrue())
+			}
+		})
+
+		It("should ignore invalid shard labels", func() {
+			obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{UID: "foo", Labels: map[string]string{ShardLabel: "5"}}}
+			Expect(shards[ShardFor("foo", 2)].Owns(obj)).To(BeTrue())
+		})
+	})
+})
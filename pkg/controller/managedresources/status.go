@@ -0,0 +1,191 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+	"github.com/gardener/gardener-resource-manager/pkg/update"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StatusDebouncer defers intermediate condition updates of ManagedResources (e.g. `Progressing` while the resources
+// are applied) for a short window. If another status update for the same ManagedResource is made within this window,
+// the deferred conditions are written together with it, otherwise they are written when the window expires.
+// This saves one write per reconciliation for ManagedResources that are reconciled quickly.
+// While deferred conditions are written, further writes of conditions for the same ManagedResource wait for it, so
+// that they cannot be overwritten by the outdated deferred conditions.
+type StatusDebouncer struct {
+	ctx    context.Context
+	log    logr.Logger
+	client client.Client
+	window time.Duration
+
+	lock     sync.Mutex
+	pending  map[client.ObjectKey]*pendingConditions
+	inFlight map[client.ObjectKey]chan struct{}
+}
+
+type pendingConditions struct {
+	conditions []resourcesv1alpha1.ManagedResourceCondition
+	timer      *time.Timer
+}
+
+// NewStatusDebouncer creates a new StatusDebouncer deferring condition updates for the given window. If the window is
+// not positive, conditions are written immediately. Deferred conditions are written with the given context.
+func NewStatusDebouncer(ctx context.Context, log logr.Logger, c client.Client, window time.Duration) *StatusDebouncer {
+	return &StatusDebouncer{
+		ctx:      ctx,
+		log:      log,
+		client:   c,
+		window:   window,
+		pending:  map[client.ObjectKey]*pendingConditions{},
+		inFlight: map[client.ObjectKey]chan struct{}{},
+	}
+}
+
+// Defer defers the update of the given conditions of the given ManagedResource. Conditions deferred earlier for the
+// same ManagedResource are kept, unless they are of the same type. The window is not extended by subsequent calls,
+// so that intermediate conditions are visible after one window at the latest.
+func (d *StatusDebouncer) Defer(ctx context.Context, mr *resourcesv1alpha1.ManagedResource, conditions ...resourcesv1alpha1.ManagedResourceCondition) error {
+	if d.window <= 0 {
+		return tryUpdateManagedResourceConditions(ctx, d.client, mr, conditions...)
+	}
+
+	key := client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if p, ok := d.pending[key]; ok {
+		p.conditions = resourcesv1alpha1helper.MergeConditions(p.conditions, conditions...)
+		return nil
+	}
+
+	d.pending[key] = &pendingConditions{
+		conditions: resourcesv1alpha1helper.MergeConditions(nil, conditions...),
+		timer:      time.AfterFunc(d.window, func() { d.flush(key) }),
+	}
+	return nil
+}
+
+// Take returns and forgets the conditions deferred for the given ManagedResource. If deferred conditions of the
+// ManagedResource are currently written, it waits until they are written. The caller is responsible for writing the
+// returned conditions together with the conditions of its own update, see `WithDeferredConditions`.
+func (d *StatusDebouncer) Take(mr *resourcesv1alpha1.ManagedResource) []resourcesv1alpha1.ManagedResourceCondition {
+	key := client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.waitForWrite(key)
+	return d.take(key)
+}
+
+// Pending returns whether conditions are deferred or being written for the given ManagedResource.
+func (d *StatusDebouncer) Pending(mr *resourcesv1alpha1.ManagedResource) bool {
+	key := client.ObjectKey{Namespace: mr.Namespace, Name: mr.Name}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	_, pending := d.pending[key]
+	_, inFlight := d.inFlight[key]
+	return pending || inFlight
+}
+
+// WithDeferredConditions returns the given conditions, preceded by the conditions deferred for the given
+// ManagedResource that are not overwritten by them.
+func (d *StatusDebouncer) WithDeferredConditions(mr *resourcesv1alpha1.ManagedResource, conditions ...resourcesv1alpha1.ManagedResourceCondition) []resourcesv1alpha1.ManagedResourceCondition {
+	return resourcesv1alpha1helper.MergeConditions(d.Take(mr), conditions...)
+}
+
+// waitForWrite waits until no deferred conditions of the ManagedResource with the given key are written anymore. The
+// lock must be held by the caller, it is released while waiting.
+func (d *StatusDebouncer) waitForWrite(key client.ObjectKey) {
+	for {
+		done, ok := d.inFlight[key]
+		if !ok {
+			return
+		}
+		d.lock.Unlock()
+		<-done
+		d.lock.Lock()
+	}
+}
+
+func (d *StatusDebouncer) take(key client.ObjectKey) []resourcesv1alpha1.ManagedResourceCondition {
+	p, ok := d.pending[key]
+	if !ok {
+		return nil
+	}
+	p.timer.Stop()
+	delete(d.pending, key)
+	return p.conditions
+}
+
+// Flush writes all deferred conditions immediately, e.g. when shutting down.
+func (d *StatusDebouncer) Flush() {
+	d.lock.Lock()
+	keys := make([]client.ObjectKey, 0, len(d.pending))
+	for key := range d.pending {
+		keys = append(keys, key)
+	}
+	d.lock.Unlock()
+
+	for _, key := range keys {
+		d.flush(key)
+	}
+}
+
+func (d *StatusDebouncer) flush(key client.ObjectKey) {
+	d.lock.Lock()
+	d.waitForWrite(key)
+	conditions := d.take(key)
+	if len(conditions) == 0 {
+		d.lock.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	d.inFlight[key] = done
+	d.lock.Unlock()
+
+	defer func() {
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		delete(d.inFlight, key)
+		close(done)
+	}()
+
+	mr := &resourcesv1alpha1.ManagedResource{}
+	mr.Namespace, mr.Name = key.Namespace, key.Name
+	if err := tryUpdateManagedResourceConditions(d.ctx, d.client, mr, conditions...); err != nil {
+		d.log.Error(err, "Could not write deferred conditions of ManagedResource", "object", key)
+	}
+}
+
+func tryUpdateManagedResourceConditions(ctx context.Context, c client.Client, mr *resourcesv1alpha1.ManagedResource, conditions ...resourcesv1alpha1.ManagedResourceCondition) error {
+	return update.Try(ctx, c, mr, func() error {
+		newConditions := resourcesv1alpha1helper.MergeConditions(mr.Status.Conditions, conditions...)
+		mr.Status.Conditions = newConditions
+		return nil
+	}, update.Options{Status: true})
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// statusWriter records the status conditions of all updated ManagedResources.
+type statusWriter struct {
+	lock    sync.Mutex
+	updates [][]resourcesv1alpha1.ManagedResourceCondition
+}
+
+func (w *statusWriter) Update(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.updates = append(w.updates, obj.(*resourcesv1alpha1.ManagedResource).Status.Conditions)
+	return nil
+}
+
+func (w *statusWriter) Patch(_ context.Context, _ runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return nil
+}
+
+func (w *statusWriter) Updates() [][]resourcesv1alpha1.ManagedResourceCondition {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.updates
+}
+
+// blockingStatusWriter is a statusWriter whose first update blocks until it is released.
+type blockingStatusWriter struct {
+	statusWriter
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	w.once.Do(func() {
+		close(w.started)
+		<-w.release
+	})
+	return w.statusWriter.Update(ctx, obj, opts...)
+}
+
+var _ = Describe("StatusDebouncer", func() {
+	var (
+		ctrl   *gomock.Controller
+		c      *mockclient.MockClient
+		writer *statusWriter
+
+		ctx = context.TODO()
+		mr  *resourcesv1alpha1.ManagedResource
+
+		progressing = resourcesv1alpha1.ManagedResourceCondition{Type: resourcesv1alpha1.ResourcesApplied, Status: resourcesv1alpha1.ConditionProgressing}
+		pending     = resourcesv1alpha1.ManagedResourceCondition{Type: resourcesv1alpha1.ResourcesHealthy, Status: resourcesv1alpha1.ConditionUnknown}
+		applied     = resourcesv1alpha1.ManagedResourceCondition{Type: resourcesv1alpha1.ResourcesApplied, Status: resourcesv1alpha1.ConditionTrue}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		writer = &statusWriter{}
+		mr = &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"}}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should coalesce deferred conditions with the next update", func() {
+		d := NewStatusDebouncer(ctx, log.NullLogger{}, c, time.Hour)
+
+		Expect(d.Defer(ctx, mr, pending, progressing)).To(Succeed())
+		Expect(d.Pending(mr)).To(BeTrue())
+
+		Expect(d.WithDeferredConditions(mr, applied)).To(Equal([]resourcesv1alpha1.ManagedResourceCondition{pending, applied}))
+		Expect(d.Pending(mr)).To(BeFalse())
+		Expect(d.WithDeferredConditions(mr, applied)).To(Equal([]resourcesv1alpha1.ManagedResourceCondition{applied}))
+	})
+
+	It("should write deferred conditions when the window expires", func() {
+		d := NewStatusDebouncer(ctx, log.NullLogger{}, c, 10*time.Millisecond)
+
+		c.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "foo", Name: "bar"}, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResource{}))
+		c.EXPECT().Status().Return(writer)
+
+		Expect(d.Defer(ctx, mr, pending, progressing)).To(Succeed())
+		Eventually(writer.Updates).Should(Equal([][]resourcesv1alpha1.ManagedResourceCondition{{pending, progressing}}))
+		Expect(d.Pending(mr)).To(BeFalse())
+	})
+
+	It("should write deferred conditions when flushed", func() {
+		d := NewStatusDebouncer(ctx, log.NullLogger{}, c, time.Hour)
+
+		c.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "foo", Name: "bar"}, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResource{}))
+		c.EXPECT().Status().Return(writer)
+
+		Expect(d.Defer(ctx, mr, progressing)).To(Succeed())
+		d.Flush()
+		Expect(writer.Updates()).To(Equal([][]resourcesv1alpha1.ManagedResourceCondition{{progressing}}))
+	})
+
+	It("should not overwrite a concurrent update with deferred conditions", func() {
+		d := NewStatusDebouncer(ctx, log.NullLogger{}, c, time.Hour)
+		writer := &blockingStatusWriter{started: make(chan struct{}), release: make(chan struct{})}
+
+		c.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "foo", Name: "bar"}, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResource{})).Times(2)
+		c.EXPECT().Status().Return(writer).Times(2)
+
+		Expect(d.Defer(ctx, mr, progressing)).To(Succeed())
+		go d.Flush()
+		Eventually(writer.started).Should(BeClosed())
+		Expect(d.Pending(mr)).To(BeTrue())
+
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "bar"}, mr)).To(Succeed())
+			mr.Status.Conditions = d.WithDeferredConditions(mr, applied)
+			Expect(c.Status().Update(ctx, mr)).To(Succeed())
+		}()
+
+		Consistently(done, 50*time.Millisecond).ShouldNot(BeClosed())
+		close(writer.release)
+		Eventually(done).Should(BeClosed())
+		Expect(writer.Updates()).To(Equal([][]resourcesv1alpha1.ManagedResourceCondition{{progressing}, {applied}}))
+		Expect(d.Pending(mr)).To(BeFalse())
+	})
+
+	It("should write conditions immediately if the window is not positive", func() {
+		d := NewStatusDebouncer(ctx, log.NullLogger{}, c, 0)
+
+		c.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "foo", Name: "bar"}, mr)
+		c.EXPECT().Status().Return(writer)
+
+		Expect(d.Defer(ctx, mr, progressing)).To(Succeed())
+		Expect(writer.Updates()).To(Equal([][]resourcesv1alpha1.ManagedResourceCondition{{progressing}}))
+		Expect(d.Pending(mr)).To(BeFalse())
+	})
+})
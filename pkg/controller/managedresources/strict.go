@@ -0,0 +1,58 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// strictDecodingScheme returns the scheme whose kinds are decoded strictly, nil if strict decoding is disabled.
+func (r *Reconciler) strictDecodingScheme() *runtime.Scheme {
+	if !r.strictDecoding {
+		return nil
+	}
+	return r.targetScheme
+}
+
+// checkUnknownFields returns an error if the given object contains fields which are unknown to its typed object in
+// the given scheme, e.g. misspelled fields which would be dropped silently by the API server. Objects of kinds
+// unknown to the scheme (e.g. custom resources) are not checked, neither are any objects if the scheme is nil.
+func checkUnknownFields(scheme *runtime.Scheme, obj *unstructured.Unstructured) error {
+	if scheme == nil {
+		return nil
+	}
+
+	typed, err := scheme.New(obj.GroupVersionKind())
+	if err != nil {
+		return nil
+	}
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(typed); err != nil {
+		return fmt.Errorf("strict decoding of %s %s failed: %v", obj.GetAPIVersion(), obj.GetKind(), err)
+	}
+	return nil
+}
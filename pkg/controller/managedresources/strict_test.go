@@ -0,0 +1,98 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Strict decoding", func() {
+	var s *runtime.Scheme
+
+	BeforeEach(func() {
+		s = runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+	})
+
+	Describe("#checkUnknownFields", func() {
+		newDeployment := func(spec map[string]interface{}) *unstructured.Unstructured {
+			return &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "foo"},
+				"spec":       spec,
+			}}
+		}
+
+		It("should accept objects without unknown fields", func() {
+			Expect(checkUnknownFields(s, newDeployment(map[string]interface{}{"replicas": int64(1)}))).To(Succeed())
+		})
+
+		It("should reject objects with unknown fields", func() {
+			err := checkUnknownFields(s, newDeployment(map[string]interface{}{"replcias": int64(1)}))
+			Expect(err).To(MatchError(ContainSubstring(`strict decoding of apps/v1 Deployment failed: json: unknown field "replcias"`)))
+		})
+
+		It("should not check objects of unknown kinds or without scheme", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "example.com/v1", "kind": "Foo", "foo": "bar"}}
+			Expect(checkUnknownFields(s, obj)).To(Succeed())
+			Expect(checkUnknownFields(nil, newDeployment(map[string]interface{}{"replcias": int64(1)}))).To(Succeed())
+		})
+	})
+
+	It("should report the objects with unknown fields as decoding errors", func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+
+		secrets := []*corev1.Secret{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+			Data: map[string][]byte{"deployments.yaml": []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: valid
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: invalid
+spec:
+  replcias: 1
+`)},
+		}}
+
+		objs, decodingErrors, _, complete := decodeSecrets(runtimelog.NullLogger{}, mapper, s, secrets)
+		Expect(objs).To(HaveLen(1))
+		Expect(objs[0].GetName()).To(Equal("valid"))
+		Expect(complete).To(BeFalse())
+		Expect(decodingErrors).To(HaveLen(1))
+		Expect(decodingErrors[0].String()).To(Equal(`Could not decode resource at index 1 in 'deployments.yaml' in secret 'foo/bar': strict decoding of apps/v1 Deployment failed: json: unknown field "replcias".`))
+
+		objs, decodingErrors, _, complete = decodeSecrets(runtimelog.NullLogger{}, mapper, nil, secrets)
+		Expect(objs).To(HaveLen(2))
+		Expect(decodingErrors).To(BeEmpty())
+		Expect(complete).To(BeTrue())
+	})
+})
@@ -0,0 +1,235 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"math"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// apiUpgrade describes a newer API version of a deprecated kind. The conversion adapts the fields whose schema or
+// defaults changed (may be nil if the schemas are the same), it returns false if the object cannot be expressed in
+// the newer version.
+type apiUpgrade struct {
+	groupVersion schema.GroupVersion
+	convert      func(obj *unstructured.Unstructured) bool
+}
+
+var (
+	appsV1       = schema.GroupVersion{Group: "apps", Version: "v1"}
+	networkingV1 = schema.GroupVersion{Group: "networking.k8s.io", Version: "v1"}
+	rbacV1       = schema.GroupVersion{Group: "rbac.authorization.k8s.io", Version: "v1"}
+
+	// apiUpgrades are the newer API versions of deprecated kinds, the preferred one first. The group of a kind is only
+	// changed according to the default equivalences, so that the upgraded objects are not pruned as removed objects.
+	apiUpgrades = map[schema.GroupVersionKind][]apiUpgrade{
+		{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:                        {{appsV1, convertToAppsV1(extensionsDeploymentDefaults)}},
+		{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:                              {{appsV1, convertToAppsV1(appsV1beta1DeploymentDefaults)}},
+		{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:                              {{appsV1, convertToAppsV1(nil)}},
+		{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:                         {{appsV1, convertToAppsV1(extensionsDaemonSetDefaults)}},
+		{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:                               {{appsV1, convertToAppsV1(nil)}},
+		{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:                        {{appsV1, convertToAppsV1(nil)}},
+		{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:                              {{appsV1, convertToAppsV1(nil)}},
+		{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:                             {{appsV1, convertToAppsV1(appsV1beta1StatefulSetDefaults)}},
+		{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:                             {{appsV1, convertToAppsV1(nil)}},
+		{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:                           {{networkingV1, convertIngressToNetworkingV1}, {schema.GroupVersion{Group: "networking.k8s.io", Version: "v1beta1"}, nil}},
+		{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}:                    {{networkingV1, convertIngressToNetworkingV1}},
+		{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:                     {{networkingV1, nil}},
+		{Group: "extensions", Version: "v1beta1", Kind: "PodSecurityPolicy"}:                 {{schema.GroupVersion{Group: "policy", Version: "v1beta1"}, nil}},
+		{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}:                   {{schema.GroupVersion{Group: "policy", Version: "v1"}, convertPodDisruptionBudgetToPolicyV1}},
+		{Group: "batch", Version: "v1beta1", Kind: "CronJob"}:                                {{schema.GroupVersion{Group: "batch", Version: "v1"}, nil}},
+		{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler"}:          {{schema.GroupVersion{Group: "autoscaling", Version: "v2"}, nil}},
+		{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "Role"}:               {{rbacV1, nil}},
+		{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "RoleBinding"}:        {{rbacV1, nil}},
+		{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRole"}:        {{rbacV1, nil}},
+		{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRoleBinding"}: {{rbacV1, nil}},
+		{Group: "scheduling.k8s.io", Version: "v1beta1", Kind: "PriorityClass"}:              {{schema.GroupVersion{Group: "scheduling.k8s.io", Version: "v1"}, nil}},
+		{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass"}:                  {{schema.GroupVersion{Group: "storage.k8s.io", Version: "v1"}, nil}},
+	}
+)
+
+// upgradeAPIVersion returns the given object in the preferred newer API version served by the target cluster if its
+// API version is deprecated, otherwise the object itself. The given object is not modified, as it may be cached.
+func (c *Capabilities) upgradeAPIVersion(log logr.Logger, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	upgrades, ok := apiUpgrades[obj.GroupVersionKind()]
+	if c == nil || !ok {
+		return obj, nil
+	}
+
+	groupVersions, err := c.servedGroupVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, upgrade := range upgrades {
+		if !groupVersions.Has(upgrade.groupVersion.String()) {
+			continue
+		}
+
+		upgraded := obj.DeepCopy()
+		if upgrade.convert != nil && !upgrade.convert(upgraded) {
+			log.Info("Not upgrading deprecated API version, as the object cannot be converted", "object", objectKeyFromUnstructured(obj), "apiVersion", obj.GetAPIVersion(), "upgradeAPIVersion", upgrade.groupVersion.String())
+			return obj, nil
+		}
+		upgraded.SetAPIVersion(upgrade.groupVersion.String())
+
+		log.V(1).Info("Upgrading deprecated API version", "object", objectKeyFromUnstructured(obj), "apiVersion", obj.GetAPIVersion(), "upgradeAPIVersion", upgrade.groupVersion.String())
+		return upgraded, nil
+	}
+
+	return obj, nil
+}
+
+// The defaults of some fields of workloads changed with `apps/v1`, they are set explicitly when upgrading, so that
+// the behaviour of the workloads does not change.
+var (
+	extensionsDeploymentDefaults = []func(spec map[string]interface{}){
+		setDefault(int64(math.MaxInt32), "revisionHistoryLimit"),
+		setDefault(int64(math.MaxInt32), "progressDeadlineSeconds"),
+		setRollingUpdateDefaults(int64(1), int64(1)),
+	}
+	appsV1beta1DeploymentDefaults = []func(spec map[string]interface{}){
+		setDefault(int64(2), "revisionHistoryLimit"),
+	}
+	extensionsDaemonSetDefaults = []func(spec map[string]interface{}){
+		setDefault("OnDelete", "updateStrategy", "type"),
+	}
+	appsV1beta1StatefulSetDefaults = []func(spec map[string]interface{}){
+		setDefault("OnDelete", "updateStrategy", "type"),
+	}
+)
+
+// convertToAppsV1 returns a conversion of workloads to `apps/v1`, which applies the given defaults of the old version
+// to the spec. `apps/v1` requires a selector, which defaulted to the labels of the pod template in older versions.
+func convertToAppsV1(defaults []func(spec map[string]interface{})) func(obj *unstructured.Unstructured) bool {
+	return func(obj *unstructured.Unstructured) bool {
+		spec, ok := obj.Object["spec"].(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		if _, ok := spec["selector"]; !ok {
+			labels, _, _ := unstructured.NestedMap(spec, "template", "metadata", "labels")
+			if len(labels) == 0 {
+				return false
+			}
+			spec["selector"] = map[string]interface{}{"matchLabels": labels}
+		}
+
+		// fields removed in `apps/v1`
+		delete(spec, "rollbackTo")
+		delete(spec, "templateGeneration")
+
+		for _, setDefaults := range defaults {
+			setDefaults(spec)
+		}
+		return true
+	}
+}
+
+func setDefault(value interface{}, fields ...string) func(spec map[string]interface{}) {
+	return func(spec map[string]interface{}) {
+		if _, ok, _ := unstructured.NestedFieldNoCopy(spec, fields...); !ok {
+			_ = unstructured.SetNestedField(spec, runtime.DeepCopyJSONValue(value), fields...)
+		}
+	}
+}
+
+func setRollingUpdateDefaults(maxSurge, maxUnavailable interface{}) func(spec map[string]interface{}) {
+	return func(spec map[string]interface{}) {
+		if strategyType, ok, _ := unstructured.NestedString(spec, "strategy", "type"); ok && strategyType != "RollingUpdate" {
+			return
+		}
+		setDefault(maxSurge, "strategy", "rollingUpdate", "maxSurge")(spec)
+		setDefault(maxUnavailable, "strategy", "rollingUpdate", "maxUnavailable")(spec)
+	}
+}
+
+// convertIngressToNetworkingV1 converts the backends of Ingresses to `networking.k8s.io/v1`, which references the
+// ports of services in a struct and requires the type of all paths (`ImplementationSpecific` was the default before).
+func convertIngressToNetworkingV1(obj *unstructured.Unstructured) bool {
+	spec, ok := obj.Object["spec"].(map[string]interface{})
+	if !ok {
+		return true
+	}
+
+	if backend, ok := spec["backend"].(map[string]interface{}); ok {
+		spec["defaultBackend"] = convertIngressBackend(backend)
+		delete(spec, "backend")
+	}
+
+	rules, _ := spec["rules"].([]interface{})
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		paths, _, _ := unstructured.NestedSlice(rule, "http", "paths")
+		if len(paths) == 0 {
+			continue
+		}
+
+		for i, p := range paths {
+			path, ok := p.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if backend, ok := path["backend"].(map[string]interface{}); ok {
+				path["backend"] = convertIngressBackend(backend)
+			}
+			if _, ok := path["pathType"]; !ok {
+				path["pathType"] = "ImplementationSpecific"
+			}
+			paths[i] = path
+		}
+		if err := unstructured.SetNestedSlice(rule, paths, "http", "paths"); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func convertIngressBackend(backend map[string]interface{}) map[string]interface{} {
+	serviceName, ok := backend["serviceName"]
+	if !ok {
+		// resource backends did not change
+		return backend
+	}
+
+	port := map[string]interface{}{}
+	switch servicePort := backend["servicePort"].(type) {
+	case string:
+		port["name"] = servicePort
+	case int64, float64:
+		port["number"] = servicePort
+	}
+
+	converted := map[string]interface{}{"service": map[string]interface{}{"name": serviceName, "port": port}}
+	if resource, ok := backend["resource"]; ok {
+		converted["resource"] = resource
+	}
+	return converted
+}
+
+// convertPodDisruptionBudgetToPolicyV1 only upgrades PodDisruptionBudgets with a non-empty selector, as an empty
+// selector selects no pods in `policy/v1beta1`, but all pods of the namespace in `policy/v1`.
+func convertPodDisruptionBudgetToPolicyV1(obj *unstructured.Unstructured) bool {
+	selector, _, _ := unstructured.NestedMap(obj.Object, "spec", "selector")
+	return len(selector) > 0
+}
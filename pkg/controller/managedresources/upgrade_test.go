@@ -0,0 +1,152 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresources
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("#upgradeAPIVersion", func() {
+	var (
+		disc *fakeCapabilitiesDiscovery
+		caps *Capabilities
+	)
+
+	serve := func(groupVersions ...string) {
+		groups := &metav1.APIGroupList{}
+		for _, groupVersion := range groupVersions {
+			groups.Groups = append(groups.Groups, metav1.APIGroup{Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: groupVersion}}})
+		}
+		disc.groups = groups
+		caps.Reset()
+	}
+
+	BeforeEach(func() {
+		disc = &fakeCapabilitiesDiscovery{}
+		caps = NewCapabilities(disc)
+		serve("v1", "apps/v1", "networking.k8s.io/v1", "networking.k8s.io/v1beta1", "policy/v1")
+	})
+
+	It("should not change objects of current API versions", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment"}}
+		Expect(caps.upgradeAPIVersion(runtimelog.NullLogger{}, obj)).To(BeIdenticalTo(obj))
+		Expect((*Capabilities)(nil).upgradeAPIVersion(runtimelog.NullLogger{}, obj)).To(BeIdenticalTo(obj))
+	})
+
+	It("should not change objects if no newer version is served", func() {
+		serve("v1", "extensions/v1beta1")
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "extensions/v1beta1", "kind": "Ingress"}}
+		Expect(caps.upgradeAPIVersion(runtimelog.NullLogger{}, obj)).To(BeIdenticalTo(obj))
+	})
+
+	It("should convert the backends of Ingresses", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "extensions/v1beta1",
+			"kind":       "Ingress",
+			"spec": map[string]interface{}{
+				"backend": map[string]interface{}{"serviceName": "default", "servicePort": int64(80)},
+				"rules": []interface{}{map[string]interface{}{
+					"host": "foo.example.com",
+					"http": map[string]interface{}{"paths": []interface{}{
+						map[string]interface{}{"path": "/", "backend": map[string]interface{}{"serviceName": "foo", "servicePort": "http"}},
+						map[string]interface{}{"path": "/bar", "pathType": "Prefix", "backend": map[string]interface{}{"resource": map[string]interface{}{"kind": "Bucket", "name": "bar"}}},
+					}},
+				}},
+			},
+		}}
+		original := obj.DeepCopy()
+
+		upgraded, err := caps.upgradeAPIVersion(runtimelog.NullLogger{}, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj).To(Equal(original))
+		Expect(upgraded.Object).To(Equal(map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"spec": map[string]interface{}{
+				"defaultBackend": map[string]interface{}{"service": map[string]interface{}{"name": "default", "port": map[string]interface{}{"number": int64(80)}}},
+				"rules": []interface{}{map[string]interface{}{
+					"host": "foo.example.com",
+					"http": map[string]interface{}{"paths": []interface{}{
+						map[string]interface{}{"path": "/", "pathType": "ImplementationSpecific", "backend": map[string]interface{}{"service": map[string]interface{}{"name": "foo", "port": map[string]interface{}{"name": "http"}}}},
+						map[string]interface{}{"path": "/bar", "pathType": "Prefix", "backend": map[string]interface{}{"resource": map[string]interface{}{"kind": "Bucket", "name": "bar"}}},
+					}},
+				}},
+			},
+		}))
+	})
+
+	It("should fall back to older replacements without conversion", func() {
+		serve("v1", "networking.k8s.io/v1beta1")
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "extensions/v1beta1",
+			"kind":       "Ingress",
+			"spec":       map[string]interface{}{"backend": map[string]interface{}{"serviceName": "default", "servicePort": int64(80)}},
+		}}
+
+		upgraded, err := caps.upgradeAPIVersion(runtimelog.NullLogger{}, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upgraded.GetAPIVersion()).To(Equal("networking.k8s.io/v1beta1"))
+		Expect(upgraded.Object["spec"]).To(Equal(obj.Object["spec"]))
+	})
+
+	It("should default the selector and keep the defaults of workloads", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "extensions/v1beta1",
+			"kind":       "Deployment",
+			"spec": map[string]interface{}{
+				"rollbackTo": map[string]interface{}{"revision": int64(1)},
+				"strategy":   map[string]interface{}{"rollingUpdate": map[string]interface{}{"maxSurge": "50%"}},
+				"template":   map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "foo"}}},
+			},
+		}}
+
+		upgraded, err := caps.upgradeAPIVersion(runtimelog.NullLogger{}, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upgraded.Object).To(Equal(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec": map[string]interface{}{
+				"selector":                map[string]interface{}{"matchLabels": map[string]interface{}{"app": "foo"}},
+				"revisionHistoryLimit":    int64(math.MaxInt32),
+				"progressDeadlineSeconds": int64(math.MaxInt32),
+				"strategy":                map[string]interface{}{"rollingUpdate": map[string]interface{}{"maxSurge": "50%", "maxUnavailable": int64(1)}},
+				"template":                map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "foo"}}},
+			},
+		}))
+	})
+
+	It("should not upgrade objects which cannot be converted", func() {
+		deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "extensions/v1beta1",
+			"kind":       "Deployment",
+			"spec":       map[string]interface{}{"template": map[string]interface{}{}},
+		}}
+		Expect(caps.upgradeAPIVersion(runtimelog.NullLogger{}, deployment)).To(BeIdenticalTo(deployment))
+
+		// an empty selector selects no pods in policy/v1beta1, but all pods in policy/v1
+		podDisruptionBudget := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "policy/v1beta1",
+			"kind":       "PodDisruptionBudget",
+			"spec":       map[string]interface{}{"selector": map[string]interface{}{}},
+		}}
+		Expect(caps.upgradeAPIVersion(runtimelog.NullLogger{}, podDisruptionBudget)).To(BeIdenticalTo(podDisruptionBudget))
+	})
+})
@@ -0,0 +1,172 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// policyNamePrefix is the prefix of the names of the NetworkPolicies maintained for Services.
+const policyNamePrefix = "ingress-to-"
+
+// Reconciler maintains an ingress NetworkPolicy for every Service in the target cluster annotated with
+// `networking.resources.gardener.cloud/from-pod-selector`. The NetworkPolicy allows traffic to the ports of the
+// Service's pods from the selected pods, so that the owners of workloads deployed via ManagedResources only need to
+// annotate their Services instead of maintaining NetworkPolicies themselves.
+type Reconciler struct {
+	ctx          context.Context
+	log          logr.Logger
+	targetClient client.Client
+}
+
+// NewReconciler creates a new network policy controller maintaining the NetworkPolicies with the given client for the
+// target cluster.
+func NewReconciler(ctx context.Context, log logr.Logger, targetClient client.Client) *Reconciler {
+	return &Reconciler{ctx, log, targetClient}
+}
+
+// PolicyNameFor returns the name of the NetworkPolicy maintained for the Service with the given name.
+func PolicyNameFor(serviceName string) string {
+	return policyNamePrefix + serviceName
+}
+
+// Reconcile implements `reconcile.Reconciler`.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx := r.ctx
+	log := r.log.WithValues("service", req)
+
+	service := &corev1.Service{}
+	if err := r.targetClient.Get(ctx, req.NamespacedName, service); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the NetworkPolicy is deleted by the garbage collector of the target cluster
+			log.Info("Stopping reconciliation of Service, as it has been deleted")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Service: %+v", err)
+	}
+
+	if service.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: service.Namespace, Name: PolicyNameFor(service.Name)}}
+
+	ingressRule, err := ingressRuleFor(service)
+	if err != nil {
+		// the Service is reconciled again when its annotations are fixed
+		log.Error(err, "Service has invalid annotations")
+		return reconcile.Result{}, nil
+	}
+	if ingressRule == nil || len(service.Spec.Selector) == 0 {
+		return reconcile.Result{}, r.deletePolicy(ctx, policy, service)
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.targetClient, policy, func() error {
+		policy.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(service, corev1.SchemeGroupVersion.WithKind("Service"))}
+		policy.Spec = networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: service.Spec.Selector},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{*ingressRule},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		}
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("could not create or update NetworkPolicy %s: %+v", policy.Name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("Applied NetworkPolicy for Service", "networkPolicy", policy.Name, "operation", result)
+	}
+	return reconcile.Result{}, nil
+}
+
+// deletePolicy deletes the given NetworkPolicy if it is controlled by the given Service, NetworkPolicies with the same
+// name created by others are left alone.
+func (r *Reconciler) deletePolicy(ctx context.Context, policy *networkingv1.NetworkPolicy, service *corev1.Service) error {
+	if err := r.targetClient.Get(ctx, client.ObjectKey{Namespace: policy.Namespace, Name: policy.Name}, policy); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(policy, service) {
+		return nil
+	}
+
+	if err := r.targetClient.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("could not delete NetworkPolicy %s: %+v", policy.Name, err)
+	}
+	r.log.Info("Deleted NetworkPolicy of Service", "service", service.Namespace+"/"+service.Name, "networkPolicy", policy.Name)
+	return nil
+}
+
+// ingressRuleFor returns the ingress rule allowing traffic to the ports of the given Service from the pods and
+// namespaces it is annotated with. It returns nil if the Service is not annotated.
+func ingressRuleFor(service *corev1.Service) (*networkingv1.NetworkPolicyIngressRule, error) {
+	value, ok := service.Annotations[resourcesv1alpha1.NetworkingFromPodSelector]
+	if !ok {
+		return nil, nil
+	}
+	podSelector, err := metav1.ParseToLabelSelector(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s must be a label selector: %+v", resourcesv1alpha1.NetworkingFromPodSelector, err)
+	}
+	// normalize the parsed selector to its state read from the API server, as the NetworkPolicy is updated otherwise
+	if len(podSelector.MatchLabels) == 0 {
+		podSelector.MatchLabels = nil
+	}
+	if len(podSelector.MatchExpressions) == 0 {
+		podSelector.MatchExpressions = nil
+	}
+
+	var peers []networkingv1.NetworkPolicyPeer
+	if value, ok := service.Annotations[resourcesv1alpha1.NetworkingNamespaceSelectors]; ok {
+		var namespaceSelectors []metav1.LabelSelector
+		if err := json.Unmarshal([]byte(value), &namespaceSelectors); err != nil {
+			return nil, fmt.Errorf("annotation %s must be a JSON list of label selectors: %+v", resourcesv1alpha1.NetworkingNamespaceSelectors, err)
+		}
+		for i := range namespaceSelectors {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{PodSelector: podSelector, NamespaceSelector: &namespaceSelectors[i]})
+		}
+	} else {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{PodSelector: podSelector})
+	}
+
+	var ports []networkingv1.NetworkPolicyPort
+	for _, servicePort := range service.Spec.Ports {
+		protocol := servicePort.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		// traffic is allowed to the ports of the pods, which may differ from the ports of the Service
+		port := servicePort.TargetPort
+		if port.Type == intstr.Int && port.IntVal == 0 {
+			port = intstr.FromInt(int(servicePort.Port))
+		}
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+	}
+
+	return &networkingv1.NetworkPolicyIngressRule{From: peers, Ports: ports}, nil
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy_test
+
+import (
+	"context"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/networkpolicy"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx  = context.TODO()
+		ctrl *gomock.Controller
+		c    *mockclient.MockClient
+
+		r         *Reconciler
+		service   *corev1.Service
+		req       reconcile.Request
+		policyKey client.ObjectKey
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		r = NewReconciler(ctx, runtimelog.NullLogger{}, c)
+
+		service = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "monitoring",
+				Name:      "grafana",
+				UID:       "1234",
+				Annotations: map[string]string{
+					resourcesv1alpha1.NetworkingFromPodSelector: "app=prometheus",
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "grafana"},
+				Ports: []corev1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromInt(3000)},
+					{Name: "metrics", Port: 9090, Protocol: corev1.ProtocolTCP},
+					{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromString("dns")},
+				},
+			},
+		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: service.Namespace, Name: service.Name}}
+		policyKey = client.ObjectKey{Namespace: service.Namespace, Name: "ingress-to-grafana"}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectGetService := func() {
+		c.EXPECT().Get(ctx, req.NamespacedName, gomock.AssignableToTypeOf(&corev1.Service{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *corev1.Service) error {
+				service.DeepCopyInto(obj)
+				return nil
+			})
+	}
+
+	expectCreatePolicy := func() *networkingv1.NetworkPolicy {
+		created := &networkingv1.NetworkPolicy{}
+		c.EXPECT().Get(ctx, policyKey, gomock.AssignableToTypeOf(&networkingv1.NetworkPolicy{})).
+			Return(apierrors.NewNotFound(networkingv1.Resource("networkpolicies"), policyKey.Name))
+		c.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&networkingv1.NetworkPolicy{})).
+			DoAndReturn(func(_ context.Context, obj *networkingv1.NetworkPolicy, _ ...client.CreateOption) error {
+				obj.DeepCopyInto(created)
+				return nil
+			})
+		return created
+	}
+
+	It("should stop if the Service has been deleted", func() {
+		c.EXPECT().Get(ctx, req.NamespacedName, gomock.AssignableToTypeOf(&corev1.Service{})).
+			Return(apierrors.NewNotFound(corev1.Resource("services"), service.Name))
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should create the NetworkPolicy for the pods of the Service", func() {
+		expectGetService()
+		policy := expectCreatePolicy()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+
+		tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+		http, metrics, dns := intstr.FromInt(3000), intstr.FromInt(9090), intstr.FromString("dns")
+		Expect(policy.OwnerReferences).To(ConsistOf(*metav1.NewControllerRef(service, corev1.SchemeGroupVersion.WithKind("Service"))))
+		Expect(policy.Spec).To(Equal(networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "grafana"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus"}},
+				}},
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &tcp, Port: &http},
+					{Protocol: &tcp, Port: &metrics},
+					{Protocol: &udp, Port: &dns},
+				},
+			}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		}))
+	})
+
+	It("should allow the pods of the selected namespaces", func() {
+		service.Annotations[resourcesv1alpha1.NetworkingNamespaceSelectors] = `[{"matchLabels":{"role":"monitoring"}},{"matchLabels":{"role":"logging"}}]`
+		expectGetService()
+		policy := expectCreatePolicy()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+
+		podSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus"}}
+		Expect(policy.Spec.Ingress[0].From).To(Equal([]networkingv1.NetworkPolicyPeer{
+			{PodSelector: podSelector, NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "monitoring"}}},
+			{PodSelector: podSelector, NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "logging"}}},
+		}))
+	})
+
+	It("should ignore Services with invalid annotations", func() {
+		service.Annotations[resourcesv1alpha1.NetworkingNamespaceSelectors] = "role=monitoring"
+		expectGetService()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should delete the NetworkPolicy if the Service is not annotated anymore", func() {
+		service.Annotations = nil
+		expectGetService()
+		c.EXPECT().Get(ctx, policyKey, gomock.AssignableToTypeOf(&networkingv1.NetworkPolicy{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *networkingv1.NetworkPolicy) error {
+				obj.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(service, corev1.SchemeGroupVersion.WithKind("Service"))}
+				return nil
+			})
+		c.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&networkingv1.NetworkPolicy{}))
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should not delete NetworkPolicies not controlled by the Service", func() {
+		service.Annotations = nil
+		expectGetService()
+		c.EXPECT().Get(ctx, policyKey, gomock.AssignableToTypeOf(&networkingv1.NetworkPolicy{}))
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should not maintain a NetworkPolicy for Services without selector", func() {
+		service.Spec.Selector = nil
+		expectGetService()
+		c.EXPECT().Get(ctx, policyKey, gomock.AssignableToTypeOf(&networkingv1.NetworkPolicy{})).
+			Return(apierrors.NewNotFound(networkingv1.Resource("networkpolicies"), policyKey.Name))
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+})
@@ -0,0 +1,250 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	"github.com/gardener/gardener-resource-manager/pkg/update"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// CriticalComponentLabel is a label on DaemonSets and their pods in the target cluster. If set to true, nodes are
+	// tainted with TaintCriticalComponentsNotReady until the pods of the DaemonSet running on them are ready.
+	CriticalComponentLabel = "node.gardener.cloud/critical-component"
+	// TaintCriticalComponentsNotReady is the key of the taint of nodes whose critical components are not ready yet.
+	// Nodes have to register with this taint, it is removed by the node controller once the critical components are
+	// ready.
+	TaintCriticalComponentsNotReady = "node.gardener.cloud/critical-components-not-ready"
+
+	// recheckInterval is the interval in which nodes waiting for critical components are checked again, in addition to
+	// the checks triggered by updates of the critical pods, e.g. to notice deleted critical DaemonSets.
+	recheckInterval = 30 * time.Second
+)
+
+// daemonSetTolerations are the tolerations added to all pods of DaemonSets by the DaemonSet controller.
+var daemonSetTolerations = []corev1.Toleration{
+	{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: "node.kubernetes.io/unreachable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: "node.kubernetes.io/disk-pressure", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "node.kubernetes.io/memory-pressure", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "node.kubernetes.io/pid-pressure", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "node.kubernetes.io/unschedulable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// Reconciler removes the TaintCriticalComponentsNotReady taint from the nodes of the target cluster once the pods of
+// all DaemonSets labelled with CriticalComponentLabel which are scheduled onto them are ready. Hence, workloads are
+// not scheduled onto half-initialized nodes, e.g. before their network or storage plugins are running.
+type Reconciler struct {
+	ctx          context.Context
+	log          logr.Logger
+	targetClient client.Client
+	targetReader client.Reader
+}
+
+// NewReconciler creates a new node controller, which reads the nodes from the given client and lists the critical
+// DaemonSets and pods in pages with the given reader, which should not be cached to not cache all pods of the target
+// cluster.
+func NewReconciler(ctx context.Context, log logr.Logger, targetClient client.Client, targetReader client.Reader) *Reconciler {
+	return &Reconciler{ctx, log, targetClient, targetReader}
+}
+
+// Reconcile implements `reconcile.Reconciler`.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx := r.ctx
+	log := r.log.WithValues("node", req.Name)
+
+	node := &corev1.Node{}
+	if err := r.targetClient.Get(ctx, req.NamespacedName, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("Stopping reconciliation of Node, as it has been deleted")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Node: %+v", err)
+	}
+
+	if !hasTaint(node) {
+		return reconcile.Result{}, nil
+	}
+
+	var (
+		daemonSets    []appsv1.DaemonSet
+		pods          []corev1.Pod
+		daemonSetList = &appsv1.DaemonSetList{}
+		podList       = &corev1.PodList{}
+	)
+	if err := utils.ListPages(ctx, r.targetReader, daemonSetList, utils.DefaultPageSize, func() error {
+		daemonSets = append(daemonSets, daemonSetList.Items...)
+		return nil
+	}, client.MatchingLabels{CriticalComponentLabel: "true"}); err != nil {
+		return reconcile.Result{}, fmt.Errorf("could not list critical DaemonSets: %+v", err)
+	}
+	if err := utils.ListPages(ctx, r.targetReader, podList, utils.DefaultPageSize, func() error {
+		pods = append(pods, podList.Items...)
+		return nil
+	}, client.MatchingLabels{CriticalComponentLabel: "true"}, client.MatchingFields{"spec.nodeName": node.Name}); err != nil {
+		return reconcile.Result{}, fmt.Errorf("could not list critical pods on Node: %+v", err)
+	}
+
+	var notReady []string
+	for i := range daemonSets {
+		daemonSet := &daemonSets[i]
+		if !scheduledOnto(daemonSet, node) {
+			continue
+		}
+		if !hasReadyPod(daemonSet, pods) {
+			notReady = append(notReady, daemonSet.Namespace+"/"+daemonSet.Name)
+		}
+	}
+	if len(notReady) > 0 {
+		log.Info("Waiting for critical components to become ready on Node", "daemonSets", notReady)
+		return reconcile.Result{RequeueAfter: recheckInterval}, nil
+	}
+
+	// updates conflicting with changes of other taints are retried with the current state
+	if err := update.Try(ctx, r.targetClient, node, func() error {
+		var taints []corev1.Taint
+		for _, taint := range node.Spec.Taints {
+			if taint.Key != TaintCriticalComponentsNotReady {
+				taints = append(taints, taint)
+			}
+		}
+		node.Spec.Taints = taints
+		return nil
+	}, update.Options{Skip: func(obj runtime.Object) bool { return !hasTaint(obj.(*corev1.Node)) }}); client.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, fmt.Errorf("could not remove taint %s from Node: %+v", TaintCriticalComponentsNotReady, err)
+	}
+
+	log.Info("Removed taint from Node, as all critical components are ready", "taint", TaintCriticalComponentsNotReady)
+	return reconcile.Result{}, nil
+}
+
+func hasTaint(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == TaintCriticalComponentsNotReady {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReadyPod returns true if one of the given pods belongs to the given DaemonSet and is ready.
+func hasReadyPod(daemonSet *appsv1.DaemonSet, pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		if controller := metav1.GetControllerOf(&pod); controller == nil || controller.UID != daemonSet.UID {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scheduledOnto returns true if the pods of the given DaemonSet are scheduled onto the given node, i.e. if they match
+// its labels and tolerate its taints. Critical DaemonSets have to tolerate TaintCriticalComponentsNotReady, their pods
+// are never scheduled onto the node otherwise.
+func scheduledOnto(daemonSet *appsv1.DaemonSet, node *corev1.Node) bool {
+	podSpec := daemonSet.Spec.Template.Spec
+
+	if !labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if affinity := podSpec.Affinity; affinity != nil && affinity.NodeAffinity != nil && affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		if !matchesNodeSelectorTerms(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, node) {
+			return false
+		}
+	}
+
+	tolerations := append(append([]corev1.Toleration{}, podSpec.Tolerations...), daemonSetTolerations...)
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !toleratesTaint(tolerations, taint) {
+			return false
+		}
+	}
+	return true
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNodeSelectorTerms returns true if the given node matches one of the given terms. Terms are matched with the
+// node's labels and `metadata.name` for their fields.
+func matchesNodeSelectorTerms(terms []corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		if matchesRequirements(term.MatchExpressions, labels.Set(node.Labels)) &&
+			matchesRequirements(term.MatchFields, labels.Set{"metadata.name": node.Name}) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRequirements(requirements []corev1.NodeSelectorRequirement, set labels.Set) bool {
+	for _, requirement := range requirements {
+		var operator selection.Operator
+		switch requirement.Operator {
+		case corev1.NodeSelectorOpIn:
+			operator = selection.In
+		case corev1.NodeSelectorOpNotIn:
+			operator = selection.NotIn
+		case corev1.NodeSelectorOpExists:
+			operator = selection.Exists
+		case corev1.NodeSelectorOpDoesNotExist:
+			operator = selection.DoesNotExist
+		case corev1.NodeSelectorOpGt:
+			operator = selection.GreaterThan
+		case corev1.NodeSelectorOpLt:
+			operator = selection.LessThan
+		default:
+			return false
+		}
+
+		r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil || !r.Matches(set) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/node"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx  = context.TODO()
+		ctrl *gomock.Controller
+		c    *mockclient.MockClient
+
+		r         *Reconciler
+		node      *corev1.Node
+		daemonSet *appsv1.DaemonSet
+		pod       *corev1.Pod
+		req       reconcile.Request
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		r = NewReconciler(ctx, runtimelog.NullLogger{}, c, c)
+
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"worker.gardener.cloud/pool": "a"}},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+				{Key: TaintCriticalComponentsNotReady, Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoExecute},
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectPreferNoSchedule},
+			}},
+		}
+		daemonSet = &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "calico-node", UID: "1234", Labels: map[string]string{CriticalComponentLabel: "true"}},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Tolerations: []corev1.Toleration{{Key: TaintCriticalComponentsNotReady, Operator: corev1.TolerationOpExists}},
+			}}},
+		}
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "kube-system",
+				Name:            "calico-node-abcde",
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(daemonSet, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))},
+			},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectGetNode := func() {
+		c.EXPECT().Get(ctx, req.NamespacedName, gomock.AssignableToTypeOf(&corev1.Node{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *corev1.Node) error {
+				node.DeepCopyInto(obj)
+				return nil
+			})
+	}
+
+	expectList := func() {
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&appsv1.DaemonSetList{}), client.Limit(utils.DefaultPageSize), client.MatchingLabels{CriticalComponentLabel: "true"}).
+			DoAndReturn(func(_ context.Context, list *appsv1.DaemonSetList, _ ...client.ListOption) error {
+				list.Items = []appsv1.DaemonSet{*daemonSet}
+				return nil
+			})
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), client.Limit(utils.DefaultPageSize), client.MatchingLabels{CriticalComponentLabel: "true"}, client.MatchingFields{"spec.nodeName": node.Name}).
+			DoAndReturn(func(_ context.Context, list *corev1.PodList, _ ...client.ListOption) error {
+				list.Items = []corev1.Pod{*pod}
+				return nil
+			})
+	}
+
+	expectRemoveTaint := func() {
+		expectGetNode()
+		c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&corev1.Node{})).
+			DoAndReturn(func(_ context.Context, obj *corev1.Node, _ ...client.UpdateOption) error {
+				Expect(obj.Spec.Taints).To(Equal(node.Spec.Taints[1:]))
+				return nil
+			})
+	}
+
+	It("should stop if the node has been deleted", func() {
+		c.EXPECT().Get(ctx, req.NamespacedName, gomock.AssignableToTypeOf(&corev1.Node{})).
+			Return(apierrors.NewNotFound(corev1.Resource("nodes"), node.Name))
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should do nothing for nodes without the taint", func() {
+		node.Spec.Taints = nil
+		expectGetNode()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should remove the taint if the critical pods are ready", func() {
+		expectGetNode()
+		expectList()
+		expectRemoveTaint()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should wait for critical pods which are not ready", func() {
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+		expectGetNode()
+		expectList()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Second}))
+	})
+
+	It("should find critical pods on later pages", func() {
+		otherPod := pod.DeepCopy()
+		otherPod.Name, otherPod.OwnerReferences[0].UID = "kube-proxy-abcde", "5678"
+
+		expectGetNode()
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&appsv1.DaemonSetList{}), client.Limit(utils.DefaultPageSize), client.MatchingLabels{CriticalComponentLabel: "true"}).
+			DoAndReturn(func(_ context.Context, list *appsv1.DaemonSetList, _ ...client.ListOption) error {
+				list.Items = []appsv1.DaemonSet{*daemonSet}
+				return nil
+			})
+		gomock.InOrder(
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), client.Limit(utils.DefaultPageSize), client.MatchingLabels{CriticalComponentLabel: "true"}, client.MatchingFields{"spec.nodeName": node.Name}).
+				DoAndReturn(func(_ context.Context, list *corev1.PodList, _ ...client.ListOption) error {
+					list.Items = []corev1.Pod{*otherPod}
+					list.Continue = "next"
+					return nil
+				}),
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), client.Limit(utils.DefaultPageSize), client.Continue("next"), client.MatchingLabels{CriticalComponentLabel: "true"}, client.MatchingFields{"spec.nodeName": node.Name}).
+				DoAndReturn(func(_ context.Context, list *corev1.PodList, _ ...client.ListOption) error {
+					list.Items = []corev1.Pod{*pod}
+					list.Continue = ""
+					return nil
+				}),
+		)
+		expectRemoveTaint()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should wait for critical pods which do not exist yet", func() {
+		pod.OwnerReferences[0].UID = "5678"
+		expectGetNode()
+		expectList()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Second}))
+	})
+
+	It("should not wait for DaemonSets whose node selector does not match", func() {
+		daemonSet.Spec.Template.Spec.NodeSelector = map[string]string{"worker.gardener.cloud/pool": "b"}
+		pod.Status.Conditions = nil
+		expectGetNode()
+		expectList()
+		expectRemoveTaint()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should not wait for DaemonSets whose node affinity does not match", func() {
+		daemonSet.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "worker.gardener.cloud/pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"a"}}}},
+				{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-2"}}}},
+			}},
+		}}
+		pod.Status.Conditions = nil
+		expectGetNode()
+		expectList()
+		expectRemoveTaint()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should wait for DaemonSets whose node affinity matches", func() {
+		daemonSet.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}}}},
+			}},
+		}}
+		pod.Status.Conditions = nil
+		expectGetNode()
+		expectList()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Second}))
+	})
+
+	It("should not wait for DaemonSets which do not tolerate the taint", func() {
+		daemonSet.Spec.Template.Spec.Tolerations = nil
+		pod.Status.Conditions = nil
+		expectGetNode()
+		expectList()
+		expectRemoveTaint()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+})
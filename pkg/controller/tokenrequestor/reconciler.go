@@ -0,0 +1,274 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenrequestor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/update"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultServiceAccountNamespace is the namespace of the ServiceAccount if the secret is not annotated with
+	// `serviceaccount.resources.gardener.cloud/namespace`.
+	DefaultServiceAccountNamespace = metav1.NamespaceSystem
+	// DefaultExpirationDuration is the requested validity of the tokens if the secret is not annotated with
+	// `serviceaccount.resources.gardener.cloud/token-expiration-duration`.
+	DefaultExpirationDuration = 12 * time.Hour
+	// MinExpirationDuration is the minimum validity of tokens accepted by the kube-apiserver.
+	MinExpirationDuration = 10 * time.Minute
+
+	// DataKeyToken is the data key of the secret to which the token is written if it does not contain a kubeconfig.
+	DataKeyToken = "token"
+	// DataKeyKubeconfig is the data key of the secret containing the kubeconfig into which the token is embedded.
+	DataKeyKubeconfig = "kubeconfig"
+
+	// renewFraction is the fraction of the validity of a token after which it is renewed.
+	renewFraction = 0.8
+)
+
+// Reconciler requests tokens for ServiceAccounts in the target cluster and writes them into the secrets labelled
+// with `resources.gardener.cloud/purpose=token-requestor` in the source cluster. The tokens are renewed after 80% of
+// their validity, so that the consumers of the secrets always find a valid token.
+type Reconciler struct {
+	log          logr.Logger
+	client       client.Client
+	ctx          context.Context
+	targetCoreV1 corev1client.ServiceAccountsGetter
+
+	now func() time.Time
+}
+
+// InjectClient injects a client into the reconciler.
+func (r *Reconciler) InjectClient(client client.Client) error {
+	r.client = client
+	return nil
+}
+
+// NewReconciler creates a new token requestor, which requests the tokens with the given client for the target
+// cluster. All requests of its reconciliations are made with (children of) the given context.
+func NewReconciler(ctx context.Context, log logr.Logger, targetCoreV1 corev1client.ServiceAccountsGetter) *Reconciler {
+	return &Reconciler{
+		log:          log,
+		ctx:          ctx,
+		targetCoreV1: targetCoreV1,
+		now:          time.Now,
+	}
+}
+
+// Reconcile implements `reconcile.Reconciler`.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx := r.ctx
+	log := r.log.WithValues("secret", req)
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, req.NamespacedName, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("Stopping reconciliation of Secret, as it has been deleted")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Secret: %+v", err)
+	}
+
+	if secret.Labels[resourcesv1alpha1.ResourceManagerPurpose] != resourcesv1alpha1.LabelPurposeTokenRequest {
+		log.V(1).Info("Skipping Secret, as it is not labelled for the token requestor")
+		return reconcile.Result{}, nil
+	}
+
+	serviceAccount, expirationDuration, err := serviceAccountFor(secret)
+	if err != nil {
+		// the secret is reconciled again when its annotations are fixed
+		log.Error(err, "Secret has invalid annotations")
+		return reconcile.Result{}, nil
+	}
+	log = log.WithValues("serviceAccount", serviceAccount.Namespace+"/"+serviceAccount.Name)
+
+	now := r.now()
+	if renewAt, ok := renewTimestampOf(secret); ok && now.Before(renewAt) {
+		return reconcile.Result{RequeueAfter: renewAt.Sub(now)}, nil
+	}
+
+	if err := r.ensureServiceAccount(ctx, serviceAccount); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return reconcile.Result{}, err
+	}
+	tokenRequest, err := r.targetCoreV1.ServiceAccounts(serviceAccount.Namespace).CreateToken(serviceAccount.Name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: expirationSecondsOf(expirationDuration),
+		},
+	})
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("could not request token for ServiceAccount %s/%s: %+v", serviceAccount.Namespace, serviceAccount.Name, err)
+	}
+
+	// the kube-apiserver may issue tokens with another validity than requested
+	validity := expirationDuration
+	if expiresAt := tokenRequest.Status.ExpirationTimestamp; !expiresAt.IsZero() {
+		validity = expiresAt.Sub(now)
+	}
+	renewAt := now.Add(time.Duration(float64(validity) * renewFraction)).UTC().Truncate(time.Second)
+
+	var writeErr error
+	if err := update.Try(ctx, r.client, secret, func() error {
+		if writeErr = writeToken(secret, tokenRequest.Status.Token); writeErr != nil {
+			return writeErr
+		}
+		metav1.SetMetaDataAnnotation(&secret.ObjectMeta, resourcesv1alpha1.ServiceAccountTokenRenewTimestamp, renewAt.Format(time.RFC3339))
+		return nil
+	}, update.Options{Strategy: update.StrategyMergePatch}); err != nil {
+		if writeErr != nil {
+			log.Error(writeErr, "Could not write token into Secret")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not write token into Secret: %+v", err)
+	}
+
+	log.Info("Requested new token for ServiceAccount", "renewAt", renewAt.Format(time.RFC3339))
+	return reconcile.Result{RequeueAfter: renewAt.Sub(now)}, nil
+}
+
+// ensureServiceAccount creates the given ServiceAccount in the target cluster if it does not exist yet. The typed
+// clients of client-go don't accept contexts yet, hence they are only checked before each request, so that no new
+// requests are started after the reconciliation has been aborted.
+func (r *Reconciler) ensureServiceAccount(ctx context.Context, serviceAccount *corev1.ServiceAccount) error {
+	serviceAccounts := r.targetCoreV1.ServiceAccounts(serviceAccount.Namespace)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := serviceAccounts.Get(serviceAccount.Name, metav1.GetOptions{}); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("could not fetch ServiceAccount %s/%s: %+v", serviceAccount.Namespace, serviceAccount.Name, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := serviceAccounts.Create(serviceAccount); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("could not create ServiceAccount %s/%s: %+v", serviceAccount.Namespace, serviceAccount.Name, err)
+	}
+	return nil
+}
+
+// serviceAccountFor returns the ServiceAccount and the requested validity of its tokens the given secret is annotated
+// with.
+func serviceAccountFor(secret *corev1.Secret) (*corev1.ServiceAccount, time.Duration, error) {
+	name := secret.Annotations[resourcesv1alpha1.ServiceAccountName]
+	if name == "" {
+		return nil, 0, fmt.Errorf("annotation %s must be set", resourcesv1alpha1.ServiceAccountName)
+	}
+	namespace := secret.Annotations[resourcesv1alpha1.ServiceAccountNamespace]
+	if namespace == "" {
+		namespace = DefaultServiceAccountNamespace
+	}
+
+	expirationDuration := DefaultExpirationDuration
+	if value, ok := secret.Annotations[resourcesv1alpha1.ServiceAccountTokenExpirationDuration]; ok {
+		var err error
+		if expirationDuration, err = time.ParseDuration(value); err != nil {
+			return nil, 0, fmt.Errorf("annotation %s must be a duration: %+v", resourcesv1alpha1.ServiceAccountTokenExpirationDuration, err)
+		}
+		if expirationDuration < MinExpirationDuration {
+			return nil, 0, fmt.Errorf("annotation %s must be at least %s", resourcesv1alpha1.ServiceAccountTokenExpirationDuration, MinExpirationDuration)
+		}
+	}
+
+	return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}, expirationDuration, nil
+}
+
+// renewTimestampOf returns the time after which the token in the given secret is renewed. It returns false if the
+// secret does not contain a token yet or the timestamp cannot be parsed, so that the token is renewed right away.
+func renewTimestampOf(secret *corev1.Secret) (time.Time, bool) {
+	value, ok := secret.Annotations[resourcesv1alpha1.ServiceAccountTokenRenewTimestamp]
+	if !ok {
+		return time.Time{}, false
+	}
+	renewAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return renewAt, true
+}
+
+func expirationSecondsOf(duration time.Duration) *int64 {
+	seconds := int64(duration / time.Second)
+	return &seconds
+}
+
+// writeToken writes the given token into the kubeconfig of the given secret, replacing the token of the user of its
+// current context. If the secret does not contain a kubeconfig, the token is written to the `token` data key.
+func writeToken(secret *corev1.Secret, token string) error {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	data, ok := secret.Data[DataKeyKubeconfig]
+	if !ok {
+		secret.Data[DataKeyToken] = []byte(token)
+		return nil
+	}
+
+	kubeconfig := &clientcmdv1.Config{}
+	if err := yaml.Unmarshal(data, kubeconfig); err != nil {
+		return fmt.Errorf("could not decode kubeconfig: %+v", err)
+	}
+
+	authInfoName := ""
+	for _, namedContext := range kubeconfig.Contexts {
+		if namedContext.Name == kubeconfig.CurrentContext {
+			authInfoName = namedContext.Context.AuthInfo
+			break
+		}
+	}
+	if authInfoName == "" {
+		return fmt.Errorf("kubeconfig has no current context with a user")
+	}
+
+	found := false
+	for i := range kubeconfig.AuthInfos {
+		if kubeconfig.AuthInfos[i].Name == authInfoName {
+			kubeconfig.AuthInfos[i].AuthInfo.Token = token
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("kubeconfig has no user %q of the current context", authInfoName)
+	}
+
+	data, err := yaml.Marshal(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("could not encode kubeconfig: %+v", err)
+	}
+	secret.Data[DataKeyKubeconfig] = data
+	return nil
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenrequestor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx    = context.TODO()
+		ctrl   *gomock.Controller
+		c      *mockclient.MockClient
+		target *fakeTarget
+		now    = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		r       *Reconciler
+		secret  *corev1.Secret
+		req     reconcile.Request
+		patched *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		target = &fakeTarget{now: now, serviceAccounts: map[types.NamespacedName]*corev1.ServiceAccount{}}
+
+		r = NewReconciler(ctx, runtimelog.NullLogger{}, target)
+		r.now = func() time.Time { return now }
+		Expect(inject.ClientInto(c, r)).To(BeTrue())
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "shoot--foo--bar",
+				Name:      "shoot-access-foo",
+				Labels: map[string]string{
+					resourcesv1alpha1.ResourceManagerPurpose: resourcesv1alpha1.LabelPurposeTokenRequest,
+				},
+				Annotations: map[string]string{
+					resourcesv1alpha1.ServiceAccountName: "foo",
+				},
+			},
+		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}}
+		patched = nil
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectGet := func() {
+		c.EXPECT().Get(ctx, req.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *corev1.Secret) error {
+				secret.DeepCopyInto(obj)
+				return nil
+			})
+	}
+
+	expectPatch := func() {
+		expectGet()
+		c.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&corev1.Secret{}), gomock.Any()).
+			DoAndReturn(func(_ context.Context, obj *corev1.Secret, _ client.Patch, _ ...client.PatchOption) error {
+				patched = obj.DeepCopy()
+				return nil
+			})
+	}
+
+	It("should stop if the secret has been deleted", func() {
+		c.EXPECT().Get(ctx, req.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			Return(apierrors.NewNotFound(corev1.Resource("secrets"), secret.Name))
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should ignore secrets without the purpose label", func() {
+		secret.Labels = nil
+		expectGet()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+		Expect(target.tokenRequests).To(BeEmpty())
+	})
+
+	It("should ignore secrets without ServiceAccount name", func() {
+		delete(secret.Annotations, resourcesv1alpha1.ServiceAccountName)
+		expectGet()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+		Expect(target.tokenRequests).To(BeEmpty())
+	})
+
+	It("should ignore secrets with too short expiration duration", func() {
+		secret.Annotations[resourcesv1alpha1.ServiceAccountTokenExpirationDuration] = "5m"
+		expectGet()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+		Expect(target.tokenRequests).To(BeEmpty())
+	})
+
+	It("should not send requests to the target cluster if the context has been cancelled", func() {
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		r = NewReconciler(cancelledCtx, runtimelog.NullLogger{}, target)
+		r.now = func() time.Time { return now }
+		Expect(inject.ClientInto(c, r)).To(BeTrue())
+
+		c.EXPECT().Get(cancelledCtx, req.NamespacedName, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *corev1.Secret) error {
+				secret.DeepCopyInto(obj)
+				return nil
+			})
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(target.serviceAccounts).To(BeEmpty())
+		Expect(target.tokenRequests).To(BeEmpty())
+	})
+
+	It("should create the ServiceAccount and write the token", func() {
+		expectGet()
+		expectPatch()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: 9*time.Hour + 36*time.Minute}))
+
+		Expect(target.serviceAccounts).To(HaveKey(types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "foo"}))
+		Expect(target.tokenRequests).To(HaveLen(1))
+		Expect(*target.tokenRequests[0].Spec.ExpirationSeconds).To(Equal(int64(12 * 60 * 60)))
+		Expect(patched.Data).To(HaveKeyWithValue(DataKeyToken, []byte("token-1")))
+		Expect(patched.Annotations).To(HaveKeyWithValue(resourcesv1alpha1.ServiceAccountTokenRenewTimestamp, "2020-06-01T09:36:00Z"))
+	})
+
+	It("should use the given namespace and expiration duration", func() {
+		secret.Annotations[resourcesv1alpha1.ServiceAccountNamespace] = "shoot-system"
+		secret.Annotations[resourcesv1alpha1.ServiceAccountTokenExpirationDuration] = "1h"
+		target.serviceAccounts[types.NamespacedName{Namespace: "shoot-system", Name: "foo"}] = &corev1.ServiceAccount{}
+		expectGet()
+		expectPatch()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: 48 * time.Minute}))
+
+		Expect(target.serviceAccounts).To(HaveLen(1))
+		Expect(target.tokenRequests).To(HaveLen(1))
+		Expect(*target.tokenRequests[0].Spec.ExpirationSeconds).To(Equal(int64(60 * 60)))
+	})
+
+	It("should renew the token based on its actual validity", func() {
+		target.validity = time.Hour
+		expectGet()
+		expectPatch()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: 48 * time.Minute}))
+	})
+
+	It("should requeue secrets whose token does not need to be renewed", func() {
+		secret.Annotations[resourcesv1alpha1.ServiceAccountTokenRenewTimestamp] = now.Add(time.Hour).Format(time.RFC3339)
+		expectGet()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{RequeueAfter: time.Hour}))
+		Expect(target.tokenRequests).To(BeEmpty())
+	})
+
+	It("should renew the token after the renew timestamp", func() {
+		secret.Annotations[resourcesv1alpha1.ServiceAccountTokenRenewTimestamp] = now.Add(-time.Minute).Format(time.RFC3339)
+		secret.Data = map[string][]byte{DataKeyToken: []byte("token-0")}
+		expectGet()
+		expectPatch()
+
+		_, err := r.Reconcile(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patched.Data).To(HaveKeyWithValue(DataKeyToken, []byte("token-1")))
+	})
+
+	It("should embed the token into the kubeconfig", func() {
+		secret.Data = map[string][]byte{DataKeyKubeconfig: []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: shoot
+  cluster:
+    server: https://kube-apiserver
+users:
+- name: foo
+  user:
+    token: static
+contexts:
+- name: shoot
+  context:
+    cluster: shoot
+    user: foo
+current-context: shoot
+`)}
+		expectGet()
+		expectPatch()
+
+		_, err := r.Reconcile(req)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patched.Data).NotTo(HaveKey(DataKeyToken))
+		config := &clientcmdv1.Config{}
+		Expect(yaml.Unmarshal(patched.Data[DataKeyKubeconfig], config)).To(Succeed())
+		Expect(config.AuthInfos[0].AuthInfo.Token).To(Equal("token-1"))
+		Expect(config.Clusters[0].Cluster.Server).To(Equal("https://kube-apiserver"))
+	})
+
+	It("should not write the token if the kubeconfig has no current context", func() {
+		secret.Data = map[string][]byte{DataKeyKubeconfig: []byte("apiVersion: v1\nkind: Config\n")}
+		// the secret is read again before the token is written into it
+		expectGet()
+		expectGet()
+
+		Expect(r.Reconcile(req)).To(Equal(reconcile.Result{}))
+	})
+
+	It("should fail if the token cannot be requested", func() {
+		target.err = fmt.Errorf("fake")
+		expectGet()
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(MatchError(ContainSubstring("could not request token for ServiceAccount kube-system/foo")))
+	})
+})
+
+type fakeTarget struct {
+	now             time.Time
+	validity        time.Duration
+	err             error
+	serviceAccounts map[types.NamespacedName]*corev1.ServiceAccount
+	tokenRequests   []*authenticationv1.TokenRequest
+}
+
+func (f *fakeTarget) ServiceAccounts(namespace string) corev1client.ServiceAccountInterface {
+	return &fakeServiceAccounts{target: f, namespace: namespace}
+}
+
+type fakeServiceAccounts struct {
+	corev1client.ServiceAccountInterface
+	target    *fakeTarget
+	namespace string
+}
+
+func (f *fakeServiceAccounts) Get(name string, _ metav1.GetOptions) (*corev1.ServiceAccount, error) {
+	if serviceAccount, ok := f.target.serviceAccounts[types.NamespacedName{Namespace: f.namespace, Name: name}]; ok {
+		return serviceAccount, nil
+	}
+	return nil, apierrors.NewNotFound(corev1.Resource("serviceaccounts"), name)
+}
+
+func (f *fakeServiceAccounts) Create(serviceAccount *corev1.ServiceAccount) (*corev1.ServiceAccount, error) {
+	f.target.serviceAccounts[types.NamespacedName{Namespace: f.namespace, Name: serviceAccount.Name}] = serviceAccount
+	return serviceAccount, nil
+}
+
+func (f *fakeServiceAccounts) CreateToken(_ string, tokenRequest *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error) {
+	if f.target.err != nil {
+		return nil, f.target.err
+	}
+	f.target.tokenRequests = append(f.target.tokenRequests, tokenRequest)
+
+	validity := f.target.validity
+	if validity == 0 {
+		validity = time.Duration(*tokenRequest.Spec.ExpirationSeconds) * time.Second
+	}
+	return &authenticationv1.TokenRequest{
+		Spec: tokenRequest.Spec,
+		Status: authenticationv1.TokenRequestStatus{
+			Token:               fmt.Sprintf("token-%d", len(f.target.tokenRequests)),
+			ExpirationTimestamp: metav1.NewTime(f.target.now.Add(validity)),
+		},
+	}, nil
+}
@@ -14,15 +14,27 @@
 
 package utils
 
-import "context"
+import (
+	"context"
 
-// ContextFromStopChannel creates a new context from a given stop channel.
-func ContextFromStopChannel(stopCh <-chan struct{}) context.Context {
-	ctx, cancel := context.WithCancel(context.Background())
-	go func() {
-		defer cancel()
-		<-stopCh
-	}()
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
 
-	return ctx
+// LogKeyReconcileID is the log key for the ID of a single reconciliation.
+const LogKeyReconcileID = "reconcileID"
+
+type reconcileIDKey struct{}
+
+// WithReconcileID generates a new ID identifying a single reconciliation and returns it together with a child context
+// carrying it.
+func WithReconcileID(ctx context.Context) (context.Context, string) {
+	id := string(uuid.NewUUID())
+	return context.WithValue(ctx, reconcileIDKey{}, id), id
+}
+
+// ReconcileIDFromContext returns the ID of the reconciliation carried by the given context or an empty string if the
+// context does not carry one.
+func ReconcileIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(reconcileIDKey{}).(string)
+	return id
 }
@@ -0,0 +1,41 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"context"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("context", func() {
+	Describe("#WithReconcileID", func() {
+		It("should carry a new reconcile ID in the context", func() {
+			ctx, id := WithReconcileID(context.TODO())
+			Expect(id).NotTo(BeEmpty())
+			Expect(ReconcileIDFromContext(ctx)).To(Equal(id))
+
+			_, otherID := WithReconcileID(context.TODO())
+			Expect(otherID).NotTo(Equal(id))
+		})
+
+		It("should return an empty ID for contexts without reconcile ID", func() {
+			Expect(ReconcileIDFromContext(context.TODO())).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// Drainer keeps track of the reconciliations in progress so that they can be finished before the process exits.
+type Drainer struct {
+	lock     sync.Mutex
+	draining bool
+	inFlight sync.WaitGroup
+}
+
+// NewDrainer creates a new Drainer.
+func NewDrainer() *Drainer {
+	return &Drainer{}
+}
+
+// Wrap wraps the given reconciler so that its reconciliations are tracked by the drainer. Once draining has started,
+// the returned reconciler does not start new reconciliations anymore.
+func (d *Drainer) Wrap(reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return &drainingReconciler{drainer: d, reconciler: reconciler}
+}
+
+// Drain stops all wrapped reconcilers from starting new reconciliations and waits until the reconciliations in
+// progress have finished or the given timeout has expired. It returns false if the timeout expired.
+func (d *Drainer) Drain(timeout time.Duration) bool {
+	d.lock.Lock()
+	d.draining = true
+	d.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.inFlight.Wait()
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (d *Drainer) start() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.draining {
+		return false
+	}
+	d.inFlight.Add(1)
+	return true
+}
+
+type drainingReconciler struct {
+	drainer    *Drainer
+	reconciler reconcile.Reconciler
+}
+
+// Reconcile implements `reconcile.Reconciler`.
+func (r *drainingReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	if !r.drainer.start() {
+		// the object is reconciled again by the next instance after the restart
+		return reconcile.Result{}, nil
+	}
+	defer r.drainer.inFlight.Done()
+
+	return r.reconciler.Reconcile(req)
+}
+
+// InjectFunc implements `inject.Injector`, so that the dependencies of the wrapped reconciler are injected.
+func (r *drainingReconciler) InjectFunc(f inject.Func) error {
+	return f(r.reconciler)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"context"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type blockingReconciler struct {
+	started  chan struct{}
+	release  chan struct{}
+	requests int
+	stop     <-chan struct{}
+}
+
+func (r *blockingReconciler) Reconcile(reconcile.Request) (reconcile.Result, error) {
+	r.requests++
+	r.started <- struct{}{}
+	<-r.release
+	return reconcile.Result{Requeue: true}, nil
+}
+
+func (r *blockingReconciler) InjectStopChannel(stop <-chan struct{}) error {
+	r.stop = stop
+	return nil
+}
+
+var _ = Describe("Drainer", func() {
+	var (
+		drainer    *Drainer
+		reconciler *blockingReconciler
+		wrapped    reconcile.Reconciler
+	)
+
+	BeforeEach(func() {
+		drainer = NewDrainer()
+		reconciler = &blockingReconciler{started: make(chan struct{}, 1), release: make(chan struct{})}
+		wrapped = drainer.Wrap(reconciler)
+	})
+
+	It("should wait for reconciliations in progress", func() {
+		results := make(chan reconcile.Result, 1)
+		go func() {
+			defer GinkgoRecover()
+			result, err := wrapped.Reconcile(reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			results <- result
+		}()
+		Eventually(reconciler.started).Should(Receive())
+
+		drained := make(chan bool, 1)
+		go func() {
+			drained <- drainer.Drain(time.Minute)
+		}()
+		Consistently(drained).ShouldNot(Receive())
+
+		close(reconciler.release)
+		Eventually(drained).Should(Receive(BeTrue()))
+		Expect(results).To(Receive(Equal(reconcile.Result{Requeue: true})))
+	})
+
+	It("should give up after the timeout", func() {
+		go func() {
+			_, _ = wrapped.Reconcile(reconcile.Request{})
+		}()
+		Eventually(reconciler.started).Should(Receive())
+
+		Expect(drainer.Drain(10 * time.Millisecond)).To(BeFalse())
+		close(reconciler.release)
+	})
+
+	It("should not start new reconciliations while draining", func() {
+		Expect(drainer.Drain(time.Minute)).To(BeTrue())
+
+		result, err := wrapped.Reconcile(reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(reconciler.requests).To(BeZero())
+	})
+
+	It("should pass the client and stop channel on to the wrapped reconcilers", func() {
+		key := types.NamespacedName{Namespace: "foo", Name: "bar"}
+		c := newOperationAnnotatedClient(key)
+		stop := make(chan struct{})
+		defer close(stop)
+
+		wrapped = drainer.Wrap(extensionscontroller.OperationAnnotationWrapper(&resourcesv1alpha1.ManagedResource{}, reconciler))
+		Expect(setFields(c, stop)(wrapped)).To(Succeed())
+
+		close(reconciler.release)
+		Expect(wrapped.Reconcile(reconcile.Request{NamespacedName: key})).To(Equal(reconcile.Result{Requeue: true}))
+		Expect(reconciler.requests).To(Equal(1))
+		Expect(reconciler.stop).To(Equal((<-chan struct{})(stop)))
+
+		mr := &resourcesv1alpha1.ManagedResource{}
+		Expect(c.Get(context.TODO(), key, mr)).To(Succeed())
+		Expect(mr.Annotations).NotTo(HaveKey(v1beta1constants.GardenerOperation))
+	})
+})
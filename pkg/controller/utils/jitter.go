@@ -0,0 +1,61 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"math/rand"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// JitterRequeues wraps the given reconciler so that the delays of its requeues (`RequeueAfter`) are shortened by a
+// random fraction of up to maxFactor. This spreads the periodic resyncs of objects which have been reconciled at the
+// same time (e.g. after a restart) over the sync period, while every object is still resynced within it. The
+// given reconciler is returned as is if maxFactor is not positive.
+func JitterRequeues(reconciler reconcile.Reconciler, maxFactor float64) reconcile.Reconciler {
+	if maxFactor <= 0 {
+		return reconciler
+	}
+	return &jitteringReconciler{reconciler: reconciler, maxFactor: maxFactor}
+}
+
+type jitteringReconciler struct {
+	reconciler reconcile.Reconciler
+	maxFactor  float64
+}
+
+// Reconcile implements `reconcile.Reconciler`.
+func (r *jitteringReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.reconciler.Reconcile(req)
+	if result.RequeueAfter > 0 {
+		result.RequeueAfter = jitterDown(result.RequeueAfter, r.maxFactor)
+	}
+	return result, err
+}
+
+// InjectFunc implements `inject.Injector`, so that the dependencies of the wrapped reconciler are injected.
+func (r *jitteringReconciler) InjectFunc(f inject.Func) error {
+	return f(r.reconciler)
+}
+
+// jitterDown returns a random duration between (1 - maxFactor) * d and d.
+func jitterDown(d time.Duration, maxFactor float64) time.Duration {
+	if maxFactor > 1 {
+		maxFactor = 1
+	}
+	return d - time.Duration(rand.Float64()*maxFactor*float64(d))
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"time"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type fixedReconciler reconcile.Result
+
+func (r fixedReconciler) Reconcile(reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result(r), nil
+}
+
+var _ = Describe("JitterRequeues", func() {
+	It("should shorten the requeue delay by at most the given factor", func() {
+		reconciler := JitterRequeues(fixedReconciler{RequeueAfter: time.Minute}, 0.25)
+
+		var delays []time.Duration
+		for i := 0; i < 100; i++ {
+			result, err := reconciler.Reconcile(reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">=", 45*time.Second))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+			delays = append(delays, result.RequeueAfter)
+		}
+		Expect(delays).To(ContainElement(BeNumerically("<", time.Minute)))
+	})
+
+	It("should not change results without requeue delay", func() {
+		reconciler := JitterRequeues(fixedReconciler{Requeue: true}, 0.25)
+		Expect(reconciler.Reconcile(reconcile.Request{})).To(Equal(reconcile.Result{Requeue: true}))
+	})
+
+	It("should return the reconciler as is if the factor is not positive", func() {
+		reconciler := fixedReconciler{RequeueAfter: time.Minute}
+		Expect(JitterRequeues(reconciler, 0)).To(Equal(reconciler))
+	})
+})
@@ -0,0 +1,103 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// namespaceLimiterIdleTimeout is the duration after which the token bucket of a namespace without reconciliations is
+// discarded.
+const namespaceLimiterIdleTimeout = 10 * time.Minute
+
+// LimitPerNamespace wraps the given reconciler so that the reconciliations of the objects of each namespace are
+// limited by an own token bucket with the given rate and burst. Reconciliations exceeding the limit are requeued
+// without calling the given reconciler, so that a namespace with objects changing all the time cannot occupy all
+// workers. The requeued requests are recorded in the given ThrottledRequests (may be nil), so that a priority queue
+// keeps their priority. The given reconciler is returned as is if the rate is not positive.
+func LimitPerNamespace(reconciler reconcile.Reconciler, qps float64, burst int, throttled *ThrottledRequests) reconcile.Reconciler {
+	if qps <= 0 {
+		return reconciler
+	}
+	return &namespaceLimitingReconciler{
+		reconciler: reconciler,
+		limit:      rate.Limit(qps),
+		burst:      burst,
+		throttled:  throttled,
+		limiters:   map[string]*namespaceLimiter{},
+		lastPrune:  time.Now(),
+	}
+}
+
+type namespaceLimiter struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+type namespaceLimitingReconciler struct {
+	reconciler reconcile.Reconciler
+	limit      rate.Limit
+	burst      int
+	throttled  *ThrottledRequests
+
+	lock      sync.Mutex
+	limiters  map[string]*namespaceLimiter
+	lastPrune time.Time
+}
+
+// Reconcile implements `reconcile.Reconciler`.
+func (r *namespaceLimitingReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	reservation := r.limiterFor(req.Namespace).Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		// give the token back, the request competes for a token again when it is requeued
+		reservation.Cancel()
+		r.throttled.add(req)
+		return reconcile.Result{RequeueAfter: delay}, nil
+	}
+	return r.reconciler.Reconcile(req)
+}
+
+// InjectFunc implements `inject.Injector`, so that the dependencies of the wrapped reconciler are injected.
+func (r *namespaceLimitingReconciler) InjectFunc(f inject.Func) error {
+	return f(r.reconciler)
+}
+
+func (r *namespaceLimitingReconciler) limiterFor(namespace string) *rate.Limiter {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	t := time.Now()
+	if t.Sub(r.lastPrune) > namespaceLimiterIdleTimeout {
+		for ns, l := range r.limiters {
+			if t.Sub(l.lastUsed) > namespaceLimiterIdleTimeout {
+				delete(r.limiters, ns)
+			}
+		}
+		r.lastPrune = t
+	}
+
+	l, ok := r.limiters[namespace]
+	if !ok {
+		l = &namespaceLimiter{limiter: rate.NewLimiter(r.limit, r.burst)}
+		r.limiters[namespace] = l
+	}
+	l.lastUsed = t
+	return l.limiter
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"time"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type countingReconciler struct {
+	requests map[string]int
+}
+
+func (r *countingReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	r.requests[req.Namespace]++
+	return reconcile.Result{}, nil
+}
+
+var _ = Describe("LimitPerNamespace", func() {
+	var (
+		inner      *countingReconciler
+		reconciler reconcile.Reconciler
+	)
+
+	request := func(namespace string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "foo"}}
+	}
+
+	BeforeEach(func() {
+		inner = &countingReconciler{requests: map[string]int{}}
+		reconciler = LimitPerNamespace(inner, 0.1, 2, nil)
+	})
+
+	It("should requeue reconciliations exceeding the limit of the namespace", func() {
+		for i := 0; i < 2; i++ {
+			Expect(reconciler.Reconcile(request("foo"))).To(Equal(reconcile.Result{}))
+		}
+
+		result, err := reconciler.Reconcile(request("foo"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", 10*time.Second, time.Second))
+		Expect(inner.requests).To(Equal(map[string]int{"foo": 2}))
+	})
+
+	It("should not limit other namespaces", func() {
+		for i := 0; i < 3; i++ {
+			_, _ = reconciler.Reconcile(request("foo"))
+		}
+
+		Expect(reconciler.Reconcile(request("bar"))).To(Equal(reconcile.Result{}))
+		Expect(inner.requests).To(Equal(map[string]int{"foo": 2, "bar": 1}))
+	})
+
+	It("should not consume tokens for requeued reconciliations", func() {
+		for i := 0; i < 2; i++ {
+			_, _ = reconciler.Reconcile(request("foo"))
+		}
+
+		first, _ := reconciler.Reconcile(request("foo"))
+		second, _ := reconciler.Reconcile(request("foo"))
+		Expect(second.RequeueAfter).To(BeNumerically("~", first.RequeueAfter, time.Second))
+	})
+
+	It("should keep the priority of requeued reconciliations in a priority queue", func() {
+		throttled := NewThrottledRequests()
+		queue := NewPriorityQueue(workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()), time.Hour, throttled)
+		defer queue.ShutDown()
+		reconciler = LimitPerNamespace(inner, 20, 1, throttled)
+
+		// processes the next item of the queue like the controller-runtime does
+		process := func() {
+			item, shutdown := queue.Get()
+			Expect(shutdown).To(BeFalse())
+			defer queue.Done(item)
+
+			result, err := reconciler.Reconcile(item.(reconcile.Request))
+			Expect(err).NotTo(HaveOccurred())
+			if result.RequeueAfter > 0 {
+				queue.Forget(item)
+				queue.AddAfter(item, result.RequeueAfter)
+			}
+		}
+
+		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "first"}})
+		process()
+
+		// the queue is not empty while the throttled reconciliation is requeued, resyncs would be held back
+		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "second"}})
+		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "bar", Name: "busy"}})
+		process()
+		Expect(queue.Len()).To(Equal(1))
+
+		Eventually(queue.Len).Should(Equal(2))
+		Expect(inner.requests).To(Equal(map[string]int{"foo": 1}))
+	})
+
+	It("should return the reconciler as is if the rate is not positive", func() {
+		Expect(LimitPerNamespace(inner, 0, 2, nil)).To(BeIdenticalTo(inner))
+	})
+})
@@ -0,0 +1,52 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultPageSize is the default number of objects requested per page by `ListPages`.
+const DefaultPageSize = 500
+
+// ListPages lists the objects matching the given options in pages of the given size and calls fn after each page has
+// been read into the given list. This prevents huge single responses and memory spikes of the API server if there
+// are lots of objects. Readers backed by an informer cache ignore the limit and return all objects in a single page.
+func ListPages(ctx context.Context, c client.Reader, list runtime.Object, pageSize int64, fn func() error, opts ...client.ListOption) error {
+	listOpts := append([]client.ListOption{client.Limit(pageSize)}, opts...)
+
+	for {
+		if err := c.List(ctx, list, listOpts...); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+
+		accessor, err := meta.ListAccessor(list)
+		if err != nil {
+			return err
+		}
+		continueToken := accessor.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+		listOpts = append([]client.ListOption{client.Limit(pageSize), client.Continue(continueToken)}, opts...)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Pager", func() {
+	var (
+		ctx  = context.TODO()
+		ctrl *gomock.Controller
+		c    *mockclient.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	page := func(continueToken string, names ...string) func(context.Context, runtime.Object, ...client.ListOption) error {
+		return func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+			secretList := list.(*corev1.SecretList)
+			secretList.Continue = continueToken
+			secretList.Items = nil
+			for _, name := range names {
+				secretList.Items = append(secretList.Items, corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name}})
+			}
+			return nil
+		}
+	}
+
+	Describe("#ListPages", func() {
+		It("should list all pages", func() {
+			gomock.InOrder(
+				c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.SecretList{}), client.Limit(2), client.InNamespace("foo")).
+					DoAndReturn(page("next", "a", "b")),
+				c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.SecretList{}), client.Limit(2), client.Continue("next"), client.InNamespace("foo")).
+					DoAndReturn(page("", "c")),
+			)
+
+			var (
+				list  = &corev1.SecretList{}
+				names []string
+			)
+			Expect(ListPages(ctx, c, list, 2, func() error {
+				for _, secret := range list.Items {
+					names = append(names, secret.Name)
+				}
+				return nil
+			}, client.InNamespace("foo"))).To(Succeed())
+			Expect(names).To(Equal([]string{"a", "b", "c"}))
+		})
+
+		It("should stop on errors of the callback", func() {
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.SecretList{}), client.Limit(2)).
+				DoAndReturn(page("next", "a", "b"))
+
+			Expect(ListPages(ctx, c, &corev1.SecretList{}, 2, func() error {
+				return fmt.Errorf("fake")
+			})).To(MatchError("fake"))
+		})
+
+		It("should return list errors", func() {
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.SecretList{}), client.Limit(2)).Return(fmt.Errorf("fake"))
+
+			Expect(ListPages(ctx, c, &corev1.SecretList{}, 2, func() error { return nil })).To(MatchError("fake"))
+		})
+	})
+})
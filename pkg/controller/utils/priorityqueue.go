@@ -0,0 +1,170 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// NewPriorityQueue returns a work queue which prefers items added without delay over items added with a delay.
+// Items are added without delay by the event handlers, e.g. when an object is created, changed, deleted or annotated
+// to be reconciled. Items are added with a delay for periodic resyncs (`RequeueAfter`). Those are held back when they
+// are due, until the queue is empty, so that they don't delay the reconciliation of changes under load. If the queue
+// has not been empty for maxDeferral, the resyncs are not held back anymore until it has been empty again, so that
+// they are not starved. Retries of failed items are rate limited as usual and not held back, and so are items added
+// with a delay because they have been throttled as recorded by the given ThrottledRequests (may be nil).
+func NewPriorityQueue(queue workqueue.RateLimitingInterface, maxDeferral time.Duration, throttled *ThrottledRequests) workqueue.RateLimitingInterface {
+	q := &priorityQueue{
+		RateLimitingInterface: queue,
+		resyncs:               workqueue.NewDelayingQueue(),
+		taken:                 make(chan struct{}, 1),
+		maxDeferral:           maxDeferral,
+		throttled:             throttled,
+	}
+	go q.releaseResyncs()
+	return q
+}
+
+// SetPriorityQueue replaces the work queue of the given controller, which must not have been started yet, with a
+// priority queue (see `NewPriorityQueue`) using the given rate limiter.
+func SetPriorityQueue(controller interface{}, name string, rateLimiter workqueue.RateLimiter, maxDeferral time.Duration, throttled *ThrottledRequests) error {
+	return setMakeQueue(controller, name, func() workqueue.RateLimitingInterface {
+		return NewPriorityQueue(workqueue.NewNamedRateLimitingQueue(rateLimiter, name), maxDeferral, throttled)
+	})
+}
+
+// ThrottledRequests records the requests which a reconciler requeues with a delay only because they have been
+// throttled (see `LimitPerNamespace`), so that a priority queue does not mistake them for resyncs and demote them.
+// It is safe for concurrent use.
+type ThrottledRequests struct {
+	lock     sync.Mutex
+	requests map[interface{}]struct{}
+}
+
+// NewThrottledRequests creates a new ThrottledRequests.
+func NewThrottledRequests() *ThrottledRequests {
+	return &ThrottledRequests{requests: map[interface{}]struct{}{}}
+}
+
+// add records that the given request is requeued because it has been throttled.
+func (t *ThrottledRequests) add(req interface{}) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.requests[req] = struct{}{}
+}
+
+// take returns and forgets whether the given request is requeued because it has been throttled.
+func (t *ThrottledRequests) take(req interface{}) bool {
+	if t == nil {
+		return false
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, ok := t.requests[req]
+	delete(t.requests, req)
+	return ok
+}
+
+type priorityQueue struct {
+	workqueue.RateLimitingInterface
+
+	// resyncs contains the items added with a delay, they are moved to the queue by releaseResyncs.
+	resyncs     workqueue.DelayingInterface
+	taken       chan struct{}
+	maxDeferral time.Duration
+	throttled   *ThrottledRequests
+}
+
+// AddAfter implements `workqueue.DelayingInterface`.
+func (q *priorityQueue) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+	if q.throttled.take(item) {
+		// throttled items are added like new events once their delay has passed, instead of being held back
+		q.RateLimitingInterface.AddAfter(item, duration)
+		return
+	}
+	q.resyncs.AddAfter(item, duration)
+}
+
+// Get implements `workqueue.Interface`.
+func (q *priorityQueue) Get() (interface{}, bool) {
+	item, shutdown := q.RateLimitingInterface.Get()
+	select {
+	case q.taken <- struct{}{}:
+	default:
+	}
+	return item, shutdown
+}
+
+// ShutDown implements `workqueue.Interface`.
+func (q *priorityQueue) ShutDown() {
+	q.resyncs.ShutDown()
+	q.RateLimitingInterface.ShutDown()
+}
+
+func (q *priorityQueue) releaseResyncs() {
+	var busySince time.Time
+
+	for {
+		item, shutdown := q.resyncs.Get()
+		if shutdown {
+			return
+		}
+
+		if busySince.IsZero() {
+			busySince = time.Now()
+		}
+		if q.waitUntilEmpty(time.Until(busySince.Add(q.maxDeferral))) {
+			busySince = time.Time{}
+		}
+
+		q.RateLimitingInterface.Add(item)
+		q.resyncs.Done(item)
+	}
+}
+
+// waitUntilEmpty waits until the queue is empty or the given timeout has expired. It returns false if the timeout
+// expired.
+func (q *priorityQueue) waitUntilEmpty(timeout time.Duration) bool {
+	if q.RateLimitingInterface.Len() == 0 {
+		return true
+	}
+	if timeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for q.RateLimitingInterface.Len() > 0 {
+		select {
+		case <-q.taken:
+		case <-timer.C:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"time"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var _ = Describe("PriorityQueue", func() {
+	var queue workqueue.RateLimitingInterface
+
+	newQueue := func(maxDeferral time.Duration) workqueue.RateLimitingInterface {
+		return NewPriorityQueue(workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()), maxDeferral, nil)
+	}
+
+	get := func() interface{} {
+		item, shutdown := queue.Get()
+		Expect(shutdown).To(BeFalse())
+		queue.Done(item)
+		return item
+	}
+
+	AfterEach(func() {
+		queue.ShutDown()
+	})
+
+	It("should add due resyncs if the queue is empty", func() {
+		queue = newQueue(time.Hour)
+
+		queue.AddAfter("resync", time.Millisecond)
+		Eventually(queue.Len).Should(Equal(1))
+		Expect(get()).To(Equal("resync"))
+	})
+
+	It("should hold back due resyncs until the queue is empty", func() {
+		queue = newQueue(time.Hour)
+
+		queue.Add("first")
+		queue.AddAfter("resync", time.Millisecond)
+		Consistently(queue.Len, 50*time.Millisecond).Should(Equal(1))
+
+		queue.Add("second")
+		Expect(get()).To(Equal("first"))
+		Expect(get()).To(Equal("second"))
+		Eventually(queue.Len).Should(Equal(1))
+		Expect(get()).To(Equal("resync"))
+	})
+
+	It("should not hold back due resyncs longer than the maximum deferral", func() {
+		queue = newQueue(50 * time.Millisecond)
+
+		queue.Add("busy")
+		queue.AddAfter("resync", time.Millisecond)
+		Eventually(queue.Len).Should(Equal(2))
+	})
+
+	It("should add items without delay immediately", func() {
+		queue = newQueue(time.Hour)
+
+		queue.Add("first")
+		queue.AddAfter("second", 0)
+		Expect(queue.Len()).To(Equal(2))
+	})
+})
@@ -0,0 +1,96 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// RateLimiterOptions configures the rate limiter of the work queue of a controller.
+type RateLimiterOptions struct {
+	// BaseDelay is the delay of the first retry of a failed item, it is doubled with every further failure.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay of the retries of a failed item.
+	MaxDelay time.Duration
+	// QPS is the overall number of retries per second of all items.
+	QPS float64
+	// Burst is the size of the bucket limiting the overall retries of all items.
+	Burst int
+}
+
+// DefaultRateLimiterOptions returns the options of the default rate limiter of controller-runtime, with the maximum
+// delay capped to 2m instead of 1000s so that failed items are retried soon after the failure has gone.
+func DefaultRateLimiterOptions() RateLimiterOptions {
+	return RateLimiterOptions{
+		BaseDelay: 5 * time.Millisecond,
+		MaxDelay:  2 * time.Minute,
+		QPS:       10,
+		Burst:     100,
+	}
+}
+
+// Validate checks whether the options are valid.
+func (o RateLimiterOptions) Validate() error {
+	if o.BaseDelay <= 0 {
+		return fmt.Errorf("base delay must be greater than 0")
+	}
+	if o.MaxDelay < o.BaseDelay {
+		return fmt.Errorf("max delay must not be lower than the base delay")
+	}
+	if o.QPS <= 0 {
+		return fmt.Errorf("qps must be greater than 0")
+	}
+	if o.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1")
+	}
+	return nil
+}
+
+// RateLimiter returns a rate limiter with a per-item exponential backoff and an overall token bucket.
+func (o RateLimiterOptions) RateLimiter() workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(o.BaseDelay, o.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(o.QPS), o.Burst)},
+	)
+}
+
+// SetRateLimiter replaces the rate limiter of the work queue of the given controller, which must not have been started
+// yet. controller-runtime does not allow to configure the rate limiter of its controllers, hence it is replaced in the
+// queue constructor of the controller.
+func SetRateLimiter(controller interface{}, name string, rateLimiter workqueue.RateLimiter) error {
+	return setMakeQueue(controller, name, func() workqueue.RateLimitingInterface {
+		return workqueue.NewNamedRateLimitingQueue(rateLimiter, name)
+	})
+}
+
+func setMakeQueue(controller interface{}, name string, makeQueueFunc func() workqueue.RateLimitingInterface) error {
+	v := reflect.ValueOf(controller)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("controller %s is of unsupported type %T", name, controller)
+	}
+
+	makeQueue := v.Elem().FieldByName("MakeQueue")
+	if !makeQueue.IsValid() || !makeQueue.CanSet() || makeQueue.Type() != reflect.TypeOf(makeQueueFunc) {
+		return fmt.Errorf("controller %s of type %T has no queue constructor", name, controller)
+	}
+
+	makeQueue.Set(reflect.ValueOf(makeQueueFunc))
+	return nil
+}
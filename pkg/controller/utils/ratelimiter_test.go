@@ -0,0 +1,85 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"time"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("RateLimiter", func() {
+	Describe("RateLimiterOptions", func() {
+		DescribeTable("#Validate",
+			func(mutate func(*RateLimiterOptions), matcher OmegaMatcher) {
+				opts := DefaultRateLimiterOptions()
+				mutate(&opts)
+				Expect(opts.Validate()).To(matcher)
+			},
+			Entry("default options", func(*RateLimiterOptions) {}, Succeed()),
+			Entry("zero base delay", func(o *RateLimiterOptions) { o.BaseDelay = 0 }, MatchError(ContainSubstring("base delay"))),
+			Entry("max delay lower than base delay", func(o *RateLimiterOptions) { o.MaxDelay = time.Millisecond }, MatchError(ContainSubstring("max delay"))),
+			Entry("zero qps", func(o *RateLimiterOptions) { o.QPS = 0 }, MatchError(ContainSubstring("qps"))),
+			Entry("zero burst", func(o *RateLimiterOptions) { o.Burst = 0 }, MatchError(ContainSubstring("burst"))),
+		)
+
+		It("should cap the backoff of failed items", func() {
+			rateLimiter := RateLimiterOptions{BaseDelay: time.Second, MaxDelay: 5 * time.Second, QPS: 100, Burst: 100}.RateLimiter()
+
+			Expect(rateLimiter.When("foo")).To(Equal(time.Second))
+			Expect(rateLimiter.When("foo")).To(Equal(2 * time.Second))
+			Expect(rateLimiter.When("foo")).To(Equal(4 * time.Second))
+			Expect(rateLimiter.When("foo")).To(Equal(5 * time.Second))
+			Expect(rateLimiter.When("bar")).To(Equal(time.Second))
+
+			rateLimiter.Forget("foo")
+			Expect(rateLimiter.When("foo")).To(Equal(time.Second))
+		})
+	})
+
+	Describe("#SetRateLimiter", func() {
+		It("should replace the queue constructor of controllers", func() {
+			mgr, err := manager.New(&rest.Config{Host: "http://localhost"}, manager.Options{
+				MetricsBindAddress: "0",
+				MapperProvider: func(*rest.Config) (meta.RESTMapper, error) {
+					return meta.NewDefaultRESTMapper(nil), nil
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", mgr, controller.Options{
+				Reconciler: reconcile.Func(func(reconcile.Request) (reconcile.Result, error) { return reconcile.Result{}, nil }),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(SetRateLimiter(c, "foo", workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Second))).To(Succeed())
+		})
+
+		It("should fail for controllers without queue constructor", func() {
+			Expect(SetRateLimiter(&struct{}{}, "foo", workqueue.DefaultControllerRateLimiter())).To(MatchError(ContainSubstring("no queue constructor")))
+			Expect(SetRateLimiter(struct{}{}, "foo", workqueue.DefaultControllerRateLimiter())).To(MatchError(ContainSubstring("unsupported type")))
+		})
+	})
+})
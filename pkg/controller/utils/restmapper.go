@@ -0,0 +1,132 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// Resetter is implemented by RESTMappers which cache discovery information, e.g.
+// `restmapper.DeferredDiscoveryRESTMapper`. After a reset, the discovery information is fetched again on the next
+// lookup.
+type Resetter interface {
+	Reset()
+}
+
+// Resetters resets all of its elements, e.g. for resetting all caches of the discovery information of a cluster
+// with the same RESTMapperInvalidator.
+type Resetters []Resetter
+
+// Reset implements `Resetter`.
+func (r Resetters) Reset() {
+	for _, resetter := range r {
+		resetter.Reset()
+	}
+}
+
+// RESTMapperInvalidator resets a RESTMapper after a TTL and whenever a CustomResourceDefinition is added, deleted or
+// changes the resources it serves. This way, new resources are discovered without waiting for a failed lookup, and
+// resources removed from the cluster are not served from the cache forever.
+type RESTMapperInvalidator struct {
+	log    logr.Logger
+	mapper Resetter
+	ttl    time.Duration
+}
+
+var _ toolscache.ResourceEventHandler = &RESTMapperInvalidator{}
+
+// NewRESTMapperInvalidator creates a new RESTMapperInvalidator for the given mapper. The mapper is not reset
+// periodically if the TTL is not positive.
+func NewRESTMapperInvalidator(log logr.Logger, mapper Resetter, ttl time.Duration) *RESTMapperInvalidator {
+	return &RESTMapperInvalidator{log: log, mapper: mapper, ttl: ttl}
+}
+
+// Start implements `manager.Runnable`. It resets the mapper after each TTL until the given channel is closed.
+func (i *RESTMapperInvalidator) Start(stop <-chan struct{}) error {
+	if i.ttl <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(i.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			i.log.V(1).Info("Resetting REST mapper as the TTL of the discovery information expired", "ttl", i.ttl.String())
+			i.mapper.Reset()
+		}
+	}
+}
+
+// NeedLeaderElection implements `manager.LeaderElectionRunnable`. The mapper is used by all instances.
+func (i *RESTMapperInvalidator) NeedLeaderElection() bool {
+	return false
+}
+
+// OnAdd implements `cache.ResourceEventHandler`.
+func (i *RESTMapperInvalidator) OnAdd(obj interface{}) {
+	i.reset("added", obj)
+}
+
+// OnUpdate implements `cache.ResourceEventHandler`. The mapper is only reset if the CustomResourceDefinition changes
+// the resources it serves, i.e. its spec, accepted names or whether it is established.
+func (i *RESTMapperInvalidator) OnUpdate(oldObj, newObj interface{}) {
+	oldCRD, ok := oldObj.(*apiextensionsv1beta1.CustomResourceDefinition)
+	if !ok {
+		return
+	}
+	newCRD, ok := newObj.(*apiextensionsv1beta1.CustomResourceDefinition)
+	if !ok {
+		return
+	}
+
+	if apiequality.Semantic.DeepEqual(oldCRD.Spec, newCRD.Spec) &&
+		apiequality.Semantic.DeepEqual(oldCRD.Status.AcceptedNames, newCRD.Status.AcceptedNames) &&
+		isEstablished(oldCRD) == isEstablished(newCRD) {
+		return
+	}
+	i.reset("changed", newCRD)
+}
+
+// OnDelete implements `cache.ResourceEventHandler`.
+func (i *RESTMapperInvalidator) OnDelete(obj interface{}) {
+	i.reset("deleted", obj)
+}
+
+func (i *RESTMapperInvalidator) reset(event string, obj interface{}) {
+	name := ""
+	if crd, ok := obj.(*apiextensionsv1beta1.CustomResourceDefinition); ok {
+		name = crd.Name
+	}
+	i.log.V(1).Info("Resetting REST mapper as a CustomResourceDefinition has been "+event, "name", name)
+	i.mapper.Reset()
+}
+
+func isEstablished(crd *apiextensionsv1beta1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1beta1.Established {
+			return condition.Status == apiextensionsv1beta1.ConditionTrue
+		}
+	}
+	return false
+}
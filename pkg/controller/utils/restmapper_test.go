@@ -0,0 +1,110 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"sync/atomic"
+	"time"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type countingResetter struct {
+	resets int32
+}
+
+func (r *countingResetter) Reset() {
+	atomic.AddInt32(&r.resets, 1)
+}
+
+func (r *countingResetter) Resets() int32 {
+	return atomic.LoadInt32(&r.resets)
+}
+
+var _ = Describe("RESTMapperInvalidator", func() {
+	var (
+		mapper      *countingResetter
+		invalidator *RESTMapperInvalidator
+		crd         *apiextensionsv1beta1.CustomResourceDefinition
+	)
+
+	BeforeEach(func() {
+		mapper = &countingResetter{}
+		invalidator = NewRESTMapperInvalidator(log.NullLogger{}, mapper, 0)
+		crd = &apiextensionsv1beta1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com", ResourceVersion: "1"},
+			Spec:       apiextensionsv1beta1.CustomResourceDefinitionSpec{Group: "example.com"},
+			Status: apiextensionsv1beta1.CustomResourceDefinitionStatus{
+				AcceptedNames: apiextensionsv1beta1.CustomResourceDefinitionNames{Plural: "foos", Kind: "Foo"},
+			},
+		}
+	})
+
+	It("should reset the mapper if a CustomResourceDefinition is added or deleted", func() {
+		invalidator.OnAdd(crd)
+		Expect(mapper.Resets()).To(Equal(int32(1)))
+		invalidator.OnDelete(crd)
+		Expect(mapper.Resets()).To(Equal(int32(2)))
+	})
+
+	It("should reset the mapper if a CustomResourceDefinition changes the resources it serves", func() {
+		established := crd.DeepCopy()
+		established.Status.Conditions = []apiextensionsv1beta1.CustomResourceDefinitionCondition{
+			{Type: apiextensionsv1beta1.Established, Status: apiextensionsv1beta1.ConditionTrue},
+		}
+		invalidator.OnUpdate(crd, established)
+		Expect(mapper.Resets()).To(Equal(int32(1)))
+
+		changed := established.DeepCopy()
+		changed.Spec.Versions = []apiextensionsv1beta1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}}
+		invalidator.OnUpdate(established, changed)
+		Expect(mapper.Resets()).To(Equal(int32(2)))
+	})
+
+	It("should not reset the mapper for other changes of a CustomResourceDefinition", func() {
+		changed := crd.DeepCopy()
+		changed.ResourceVersion = "2"
+		changed.Labels = map[string]string{"foo": "bar"}
+		invalidator.OnUpdate(crd, changed)
+		Expect(mapper.Resets()).To(BeZero())
+	})
+
+	It("should reset the mapper after each TTL", func() {
+		invalidator = NewRESTMapperInvalidator(log.NullLogger{}, mapper, 10*time.Millisecond)
+
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(invalidator.Start(stop)).To(Succeed())
+		}()
+
+		Eventually(mapper.Resets).Should(BeNumerically(">=", 2))
+		close(stop)
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("should not reset the mapper periodically if the TTL is not positive", func() {
+		Expect(invalidator.Start(make(chan struct{}))).To(Succeed())
+		Expect(mapper.Resets()).To(BeZero())
+	})
+})
@@ -16,8 +16,8 @@ package utils
 
 import (
 	"context"
-	"reflect"
-	"time"
+
+	"github.com/gardener/gardener-resource-manager/pkg/update"
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,8 +26,6 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -71,59 +69,37 @@ func NewGenericEvent(meta metav1.Object, obj runtime.Object) event.GenericEvent
 // EnsureFinalizer ensures that a finalizer of the given name is set on the given object.
 // If the finalizer is not set, it adds it to the list of finalizers and updates the remote object.
 func EnsureFinalizer(ctx context.Context, c client.Client, finalizerName string, obj runtime.Object) error {
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		key, err := client.ObjectKeyFromObject(obj)
-		if err != nil {
-			return err
-		}
-
-		if err := c.Get(ctx, key, obj); err != nil {
-			return err
-		}
-
+	return update.Try(ctx, c, obj, func() error {
 		finalizers, accessor, err := finalizersAndAccessorOf(obj)
 		if err != nil {
 			return err
 		}
 
-		if finalizers.Has(finalizerName) {
-			return nil
-		}
-
 		finalizers.Insert(finalizerName)
 		accessor.SetFinalizers(finalizers.UnsortedList())
-
-		return c.Update(ctx, obj)
-	})
+		return nil
+	}, update.Options{Skip: func(obj runtime.Object) bool { return hasFinalizer(obj, finalizerName) }})
 }
 
 // DeleteFinalizer ensures that the given finalizer is not present anymore in the given object.
 // If it is set, it removes it and issues an update.
 func DeleteFinalizer(ctx context.Context, c client.Client, finalizerName string, obj runtime.Object) error {
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		key, err := client.ObjectKeyFromObject(obj)
-		if err != nil {
-			return err
-		}
-
-		if err := c.Get(ctx, key, obj); client.IgnoreNotFound(err) != nil {
-			return err
-		}
-
+	return client.IgnoreNotFound(update.Try(ctx, c, obj, func() error {
 		finalizers, accessor, err := finalizersAndAccessorOf(obj)
 		if err != nil {
 			return err
 		}
 
-		if !finalizers.Has(finalizerName) {
-			return nil
-		}
-
 		finalizers.Delete(finalizerName)
 		accessor.SetFinalizers(finalizers.UnsortedList())
+		return nil
+	}, update.Options{Skip: func(obj runtime.Object) bool { return !hasFinalizer(obj, finalizerName) }}))
+}
 
-		return c.Update(ctx, obj)
-	})
+func hasFinalizer(obj runtime.Object, finalizerName string) bool {
+	finalizers, _, err := finalizersAndAccessorOf(obj)
+	// errors are returned by the transformation
+	return err == nil && finalizers.Has(finalizerName)
 }
 
 func finalizersAndAccessorOf(obj runtime.Object) (sets.String, metav1.Object, error) {
@@ -135,12 +111,6 @@ func finalizersAndAccessorOf(obj runtime.Object) (sets.String, metav1.Object, er
 	return sets.NewString(accessor.GetFinalizers()...), accessor, nil
 }
 
-// TryUpdate tries to apply the given transformation function onto the given object, and to update it afterwards.
-// It retries the update with an exponential backoff.
-func TryUpdate(ctx context.Context, backoff wait.Backoff, c client.Client, obj runtime.Object, transform func() error) error {
-	return tryUpdate(ctx, backoff, c, obj, c.Update, transform)
-}
-
 // TypedCreateOrUpdate is like controllerutil.CreateOrUpdate, it retrieves the current state of the object from the
 // API server, applies the given mutate func and creates or updates it afterwards. In contrast to
 // controllerutil.CreateOrUpdate it tries to create a new typed object of obj's kind (using the provided scheme)
@@ -195,65 +165,3 @@ func TypedCreateOrUpdate(ctx context.Context, c client.Client, scheme *runtime.S
 
 	return controllerutil.OperationResultUpdated, c.Update(ctx, obj)
 }
-
-// TryUpdateStatus tries to apply the given transformation function onto the given object, and to update its
-// status afterwards. It retries the status update with an exponential backoff.
-func TryUpdateStatus(ctx context.Context, backoff wait.Backoff, c client.Client, obj runtime.Object, transform func() error) error {
-	return tryUpdate(ctx, backoff, c, obj, c.Status().Update, transform)
-}
-
-func tryUpdate(ctx context.Context, backoff wait.Backoff, c client.Client, obj runtime.Object, updateFunc func(context.Context, runtime.Object, ...client.UpdateOption) error, transform func() error) error {
-	key, err := client.ObjectKeyFromObject(obj)
-	if err != nil {
-		return err
-	}
-
-	return exponentialBackoff(ctx, backoff, func() (bool, error) {
-		if err := c.Get(ctx, key, obj); err != nil {
-			return false, err
-		}
-
-		beforeTransform := obj.DeepCopyObject()
-		if err := transform(); err != nil {
-			return false, err
-		}
-
-		if reflect.DeepEqual(obj, beforeTransform) {
-			return true, nil
-		}
-
-		if err := updateFunc(ctx, obj); err != nil {
-			if apierrors.IsConflict(err) {
-				return false, nil
-			}
-			return false, err
-		}
-		return true, nil
-	})
-}
-
-func exponentialBackoff(ctx context.Context, backoff wait.Backoff, condition wait.ConditionFunc) error {
-	duration := backoff.Duration
-
-	for i := 0; i < backoff.Steps; i++ {
-		if ok, err := condition(); err != nil || ok {
-			return err
-		}
-
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			adjusted := duration
-			if backoff.Jitter > 0.0 {
-				adjusted = wait.Jitter(duration, backoff.Jitter)
-			}
-			time.Sleep(adjusted)
-			duration = time.Duration(float64(duration) * backoff.Factor)
-		}
-
-		i++
-	}
-
-	return wait.ErrWaitTimeout
-}
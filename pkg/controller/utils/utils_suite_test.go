@@ -17,11 +17,49 @@ package utils_test
 import (
 	"testing"
 
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/test/fake"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 )
 
 func TestUtils(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Utils Suite")
 }
+
+// newOperationAnnotatedClient returns a fake client containing the ManagedResource with the given key, which is
+// annotated to be reconciled by an OperationAnnotationWrapper.
+func newOperationAnnotatedClient(key types.NamespacedName) *fake.Client {
+	scheme := runtime.NewScheme()
+	Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClient(scheme, &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   key.Namespace,
+		Name:        key.Name,
+		Annotations: map[string]string{v1beta1constants.GardenerOperation: v1beta1constants.GardenerOperationReconcile},
+	}})
+}
+
+// setFields returns a function injecting the given client and stop channel into a reconciler and all reconcilers
+// wrapped by it, like the controller-runtime does when a controller is created.
+func setFields(c client.Client, stop <-chan struct{}) inject.Func {
+	var f inject.Func
+	f = func(i interface{}) error {
+		if _, err := inject.ClientInto(c, i); err != nil {
+			return err
+		}
+		if _, err := inject.StopChannelInto(stop, i); err != nil {
+			return err
+		}
+		_, err := inject.InjectorInto(f, i)
+		return err
+	}
+	return f
+}
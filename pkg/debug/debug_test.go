@@ -0,0 +1,164 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/debug"
+	"github.com/gardener/gardener-resource-manager/pkg/test/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+type fakeReconciler struct {
+	result reconcile.Result
+	err    error
+	client client.Client
+}
+
+func (f *fakeReconciler) Reconcile(reconcile.Request) (reconcile.Result, error) {
+	return f.result, f.err
+}
+
+func (f *fakeReconciler) InjectClient(c client.Client) error {
+	f.client = c
+	return nil
+}
+
+var _ = Describe("Debug", func() {
+	Describe("Tracker", func() {
+		var (
+			tracker *Tracker
+			c       *fake.Client
+			key     = types.NamespacedName{Namespace: "foo", Name: "bar"}
+			req     = reconcile.Request{NamespacedName: key}
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+			c = fake.NewClient(scheme, &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}})
+			tracker = NewTracker(c, &resourcesv1alpha1.ManagedResource{})
+		})
+
+		It("should record successful reconciliations", func() {
+			reconciler := tracker.Wrap("ctrl", &fakeReconciler{result: reconcile.Result{RequeueAfter: time.Minute}})
+
+			result, err := reconciler.Reconcile(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			states := tracker.States(key)
+			Expect(states).To(HaveKey("ctrl"))
+			Expect(states["ctrl"].Reconciling).To(BeFalse())
+			Expect(states["ctrl"].Reconciles).To(Equal(1))
+			Expect(states["ctrl"].ConsecutiveFailures).To(BeZero())
+			Expect(states["ctrl"].LastReconcileTime).NotTo(BeNil())
+			Expect(states["ctrl"].NextReconcileTime).NotTo(BeNil())
+			Expect(states["ctrl"].LastError).To(BeEmpty())
+		})
+
+		It("should inject dependencies into the wrapped reconciler", func() {
+			var (
+				fake = &fakeReconciler{}
+				c    = struct{ client.Client }{}
+			)
+
+			Expect(inject.InjectorInto(func(i interface{}) error {
+				_, err := inject.ClientInto(c, i)
+				return err
+			}, tracker.Wrap("ctrl", fake))).To(BeTrue())
+			Expect(fake.client).To(Equal(c))
+		})
+
+		It("should record failed reconciliations", func() {
+			fake := &fakeReconciler{err: fmt.Errorf("fake")}
+			reconciler := tracker.Wrap("ctrl", fake)
+
+			_, err := reconciler.Reconcile(req)
+			Expect(err).To(MatchError("fake"))
+			_, err = reconciler.Reconcile(req)
+			Expect(err).To(MatchError("fake"))
+
+			state := tracker.States(key)["ctrl"]
+			Expect(state.Reconciles).To(Equal(2))
+			Expect(state.ConsecutiveFailures).To(Equal(2))
+			Expect(state.LastError).To(Equal("fake"))
+			Expect(state.LastErrorTime).NotTo(BeNil())
+
+			fake.err = nil
+			_, err = reconciler.Reconcile(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tracker.States(key)["ctrl"].ConsecutiveFailures).To(BeZero())
+		})
+
+		It("should record states per controller and forget them", func() {
+			_, _ = tracker.Wrap("ctrl1", &fakeReconciler{}).Reconcile(req)
+			_, _ = tracker.Wrap("ctrl2", &fakeReconciler{}).Reconcile(req)
+
+			Expect(tracker.States(key)).To(HaveLen(2))
+
+			tracker.Forget(key)
+			Expect(tracker.States(key)).To(BeEmpty())
+		})
+
+		It("should forget the states of deleted objects", func() {
+			reconciler := tracker.Wrap("ctrl", &fakeReconciler{})
+			_, _ = reconciler.Reconcile(req)
+			Expect(tracker.States(key)).To(HaveKey("ctrl"))
+
+			Expect(c.Delete(context.TODO(), &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}})).To(Succeed())
+			_, _ = reconciler.Reconcile(req)
+			Expect(tracker.States(key)).To(BeEmpty())
+		})
+
+		It("should not wrap reconcilers if it is nil", func() {
+			reconciler := &fakeReconciler{}
+			Expect((*Tracker)(nil).Wrap("ctrl", reconciler)).To(BeIdenticalTo(reconciler))
+		})
+	})
+
+	Describe("#NewServer", func() {
+		DescribeTable("should validate the bind address",
+			func(address string, valid bool) {
+				_, err := NewServer(runtimelog.NullLogger{}, address)
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("IPv4 loopback", "127.0.0.1:8081", true),
+			Entry("IPv6 loopback", "[::1]:8081", true),
+			Entry("localhost", "localhost:8081", true),
+			Entry("all interfaces", ":8081", false),
+			Entry("public address", "10.0.0.1:8081", false),
+			Entry("missing port", "127.0.0.1", false),
+		)
+	})
+})
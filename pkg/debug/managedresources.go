@@ -0,0 +1,94 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagedResourcesPath is the path under which the internal state of all ManagedResources is served.
+const ManagedResourcesPath = "/debug/managedresources"
+
+// ManagedResourceState is the internal state of a ManagedResource as seen by the controllers.
+type ManagedResourceState struct {
+	Namespace          string `json:"namespace"`
+	Name               string `json:"name"`
+	Class              string `json:"class,omitempty"`
+	Deleting           bool   `json:"deleting"`
+	Generation         int64  `json:"generation"`
+	ObservedGeneration int64  `json:"observedGeneration"`
+	// Resources is the number of objects in the ManagedResource's inventory (`.status.resources`).
+	Resources int `json:"resources"`
+	// Conditions are the ManagedResource's current conditions.
+	Conditions []resourcesv1alpha1.ManagedResourceCondition `json:"conditions,omitempty"`
+	// Controllers contains the reconciliation state of the ManagedResource per controller.
+	Controllers map[string]State `json:"controllers,omitempty"`
+}
+
+// NewManagedResourcesHandler returns a handler serving the internal state of all ManagedResources as JSON. The
+// ManagedResources are read with the given client and enriched with the states recorded by the tracker. The optional
+// query parameters `namespace` and `name` restrict the output to matching ManagedResources. The positions of the
+// ManagedResources in the work queues are not served, as the work queues of client-go don't expose the order of
+// their items.
+func NewManagedResourcesHandler(c client.Reader, tracker *Tracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = r.URL.Query().Get("namespace")
+			name      = r.URL.Query().Get("name")
+		)
+
+		mrList := &resourcesv1alpha1.ManagedResourceList{}
+		if err := c.List(r.Context(), mrList, client.InNamespace(namespace)); err != nil {
+			http.Error(w, fmt.Sprintf("could not list ManagedResources: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		out := make([]ManagedResourceState, 0, len(mrList.Items))
+		for _, mr := range mrList.Items {
+			if name != "" && mr.Name != name {
+				continue
+			}
+
+			state := ManagedResourceState{
+				Namespace:          mr.Namespace,
+				Name:               mr.Name,
+				Deleting:           mr.DeletionTimestamp != nil,
+				Generation:         mr.Generation,
+				ObservedGeneration: mr.Status.ObservedGeneration,
+				Resources:          len(mr.Status.Resources),
+				Conditions:         mr.Status.Conditions,
+				Controllers:        tracker.States(types.NamespacedName{Namespace: mr.Namespace, Name: mr.Name}),
+			}
+			if mr.Spec.Class != nil {
+				state.Class = *mr.Spec.Class
+			}
+			out = append(out, state)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(out); err != nil {
+			http.Error(w, fmt.Sprintf("could not encode response: %v", err), http.StatusInternalServerError)
+		}
+	})
+}
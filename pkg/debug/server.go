@@ -0,0 +1,96 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Server serves debug endpoints. It only listens on loopback addresses, so that the endpoints are only reachable
+// from within the pod.
+type Server struct {
+	log     logr.Logger
+	address string
+	mux     *http.ServeMux
+}
+
+var _ manager.LeaderElectionRunnable = &Server{}
+
+// NewServer creates a new debug server listening on the given address. It returns an error if the address does not
+// refer to a loopback interface.
+func NewServer(log logr.Logger, address string) (*Server, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid debug bind address %q: %w", address, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("debug bind address %q must be a loopback address", address)
+	}
+
+	return &Server{
+		log:     log,
+		address: address,
+		mux:     http.NewServeMux(),
+	}, nil
+}
+
+// Handle registers the handler for the given pattern.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// EnableProfiling registers the `net/http/pprof` handlers under `/debug/pprof/`.
+func (s *Server) EnableProfiling() {
+	s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+	s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
+
+// Start implements `manager.Runnable`. It serves the registered handlers until the stop channel is closed.
+func (s *Server) Start(stopCh <-chan struct{}) error {
+	server := &http.Server{Addr: s.address, Handler: s.mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.log.Info("Serving debug endpoints", "address", s.address)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("error serving debug endpoints: %w", err)
+	case <-stopCh:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(ctx)
+	}
+}
+
+// NeedLeaderElection implements `manager.LeaderElectionRunnable`. The debug endpoints are served by all instances.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// now is the function used to determine the current time (overwritten in tests).
+var now = time.Now
+
+// State describes how a controller last handled a single object.
+type State struct {
+	// Reconciling is true while the object is currently being reconciled.
+	Reconciling bool `json:"reconciling"`
+	// Reconciles is the number of reconciliations since the controller started.
+	Reconciles int `json:"reconciles"`
+	// ConsecutiveFailures is the number of reconciliations that failed in a row.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// LastReconcileTime is the time when the last reconciliation was started.
+	LastReconcileTime *time.Time `json:"lastReconcileTime,omitempty"`
+	// LastReconcileDuration is the duration of the last finished reconciliation.
+	LastReconcileDuration string `json:"lastReconcileDuration,omitempty"`
+	// NextReconcileTime is the time when the object was scheduled to be requeued by the last reconciliation.
+	NextReconcileTime *time.Time `json:"nextReconcileTime,omitempty"`
+	// LastError is the error returned by the last failed reconciliation.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is the time when the last reconciliation failed.
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// Tracker records the reconciliation states of all objects handled by a set of controllers. The states of an object
+// are forgotten when a reconciliation finds that it does not exist anymore.
+type Tracker struct {
+	client client.Reader
+	obj    runtime.Object
+
+	lock   sync.RWMutex
+	states map[types.NamespacedName]map[string]*State
+}
+
+// NewTracker creates a new, empty Tracker for objects of the kind of the given object, whose existence is checked
+// with the given client.
+func NewTracker(c client.Reader, obj runtime.Object) *Tracker {
+	return &Tracker{
+		client: c,
+		obj:    obj,
+		states: make(map[types.NamespacedName]map[string]*State),
+	}
+}
+
+// Wrap wraps the given reconciler so that all its reconciliations are recorded in the tracker for the controller
+// with the given name. If the tracker is nil, the reconciler is returned as is.
+func (t *Tracker) Wrap(controllerName string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if t == nil {
+		return reconciler
+	}
+	return &trackingReconciler{tracker: t, controllerName: controllerName, reconciler: reconciler}
+}
+
+// States returns a copy of the states recorded for the object with the given key, indexed by controller name.
+func (t *Tracker) States(key types.NamespacedName) map[string]State {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	out := make(map[string]State, len(t.states[key]))
+	for controllerName, state := range t.states[key] {
+		out[controllerName] = *state
+	}
+	return out
+}
+
+// Forget removes all states recorded for the object with the given key.
+func (t *Tracker) Forget(key types.NamespacedName) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.states, key)
+}
+
+// forgetIfDeleted removes all states recorded for the object with the given key if it does not exist anymore and is
+// not being reconciled by another controller.
+func (t *Tracker) forgetIfDeleted(key types.NamespacedName) {
+	if err := t.client.Get(context.Background(), key, t.obj.DeepCopyObject()); !apierrors.IsNotFound(err) {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, state := range t.states[key] {
+		if state.Reconciling {
+			return
+		}
+	}
+	delete(t.states, key)
+}
+
+func (t *Tracker) started(controllerName string, key types.NamespacedName) time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	start := now()
+	state := t.stateFor(controllerName, key)
+	state.Reconciling = true
+	state.Reconciles++
+	state.LastReconcileTime = &start
+	return start
+}
+
+func (t *Tracker) finished(controllerName string, key types.NamespacedName, start time.Time, result reconcile.Result, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	end := now()
+	state := t.stateFor(controllerName, key)
+	state.Reconciling = false
+	state.LastReconcileDuration = end.Sub(start).String()
+	state.NextReconcileTime = nil
+	if result.RequeueAfter > 0 {
+		next := end.Add(result.RequeueAfter)
+		state.NextReconcileTime = &next
+	}
+
+	if err != nil {
+		state.ConsecutiveFailures++
+		state.LastError = err.Error()
+		state.LastErrorTime = &end
+		return
+	}
+	state.ConsecutiveFailures = 0
+}
+
+func (t *Tracker) stateFor(controllerName string, key types.NamespacedName) *State {
+	states, ok := t.states[key]
+	if !ok {
+		states = make(map[string]*State)
+		t.states[key] = states
+	}
+
+	state, ok := states[controllerName]
+	if !ok {
+		state = &State{}
+		states[controllerName] = state
+	}
+	return state
+}
+
+type trackingReconciler struct {
+	tracker        *Tracker
+	controllerName string
+	reconciler     reconcile.Reconciler
+}
+
+// Reconcile implements `reconcile.Reconciler`.
+func (r *trackingReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	start := r.tracker.started(r.controllerName, req.NamespacedName)
+	result, err := r.reconciler.Reconcile(req)
+	r.tracker.finished(r.controllerName, req.NamespacedName, start, result, err)
+	if err == nil && result == (reconcile.Result{}) {
+		// reconciliations of deleted objects neither fail nor requeue them
+		r.tracker.forgetIfDeleted(req.NamespacedName)
+	}
+	return result, err
+}
+
+// InjectFunc implements `inject.Injector`, so that the dependencies of the wrapped reconciler are injected.
+func (r *trackingReconciler) InjectFunc(f inject.Func) error {
+	return f(r.reconciler)
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package defaults contains defaulting functions for the most common built-in kinds, which mirror the defaults set by
+// the API server. The vendored API types do not come with their defaulting functions, hence they are registered in
+// the target scheme to default desired objects the same way the API server defaults the live objects.
+package defaults
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+var (
+	schemeBuilder = runtime.NewSchemeBuilder(addDefaultingFuncs)
+	// AddToScheme registers the defaulting functions of the built-in kinds in the given scheme.
+	AddToScheme = schemeBuilder.AddToScheme
+
+	defaultedKinds = map[schema.GroupVersionKind]struct{}{
+		corev1.SchemeGroupVersion.WithKind("Pod"):           {},
+		corev1.SchemeGroupVersion.WithKind("Service"):       {},
+		appsv1.SchemeGroupVersion.WithKind("DaemonSet"):     {},
+		appsv1.SchemeGroupVersion.WithKind("Deployment"):    {},
+		appsv1.SchemeGroupVersion.WithKind("ReplicaSet"):    {},
+		appsv1.SchemeGroupVersion.WithKind("StatefulSet"):   {},
+		batchv1.SchemeGroupVersion.WithKind("Job"):          {},
+		batchv1beta1.SchemeGroupVersion.WithKind("CronJob"): {},
+	}
+)
+
+// IsDefaulted returns whether defaulting functions are registered for the given kind.
+func IsDefaulted(gvk schema.GroupVersionKind) bool {
+	_, ok := defaultedKinds[gvk]
+	return ok
+}
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&corev1.Pod{}, func(obj interface{}) { setDefaultsPodSpec(&obj.(*corev1.Pod).Spec) })
+	scheme.AddTypeDefaultingFunc(&corev1.Service{}, func(obj interface{}) { setDefaultsService(obj.(*corev1.Service)) })
+	scheme.AddTypeDefaultingFunc(&appsv1.DaemonSet{}, func(obj interface{}) { setDefaultsDaemonSet(obj.(*appsv1.DaemonSet)) })
+	scheme.AddTypeDefaultingFunc(&appsv1.Deployment{}, func(obj interface{}) { setDefaultsDeployment(obj.(*appsv1.Deployment)) })
+	scheme.AddTypeDefaultingFunc(&appsv1.ReplicaSet{}, func(obj interface{}) { setDefaultsPodSpec(&obj.(*appsv1.ReplicaSet).Spec.Template.Spec) })
+	scheme.AddTypeDefaultingFunc(&appsv1.StatefulSet{}, func(obj interface{}) { setDefaultsStatefulSet(obj.(*appsv1.StatefulSet)) })
+	scheme.AddTypeDefaultingFunc(&batchv1.Job{}, func(obj interface{}) { setDefaultsJobSpec(&obj.(*batchv1.Job).Spec) })
+	scheme.AddTypeDefaultingFunc(&batchv1beta1.CronJob{}, func(obj interface{}) { setDefaultsCronJob(obj.(*batchv1beta1.CronJob)) })
+	return nil
+}
+
+// The replicas of workloads are not defaulted, as unset replicas are preserved when the objects are updated.
+
+func setDefaultsDeployment(obj *appsv1.Deployment) {
+	if obj.Spec.Strategy.Type == "" {
+		obj.Spec.Strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+	}
+	if obj.Spec.Strategy.Type == appsv1.RollingUpdateDeploymentStrategyType {
+		if obj.Spec.Strategy.RollingUpdate == nil {
+			obj.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+		}
+		if obj.Spec.Strategy.RollingUpdate.MaxUnavailable == nil {
+			obj.Spec.Strategy.RollingUpdate.MaxUnavailable = intOrStringPtr(intstr.FromString("25%"))
+		}
+		if obj.Spec.Strategy.RollingUpdate.MaxSurge == nil {
+			obj.Spec.Strategy.RollingUpdate.MaxSurge = intOrStringPtr(intstr.FromString("25%"))
+		}
+	}
+	if obj.Spec.RevisionHistoryLimit == nil {
+		obj.Spec.RevisionHistoryLimit = pointer.Int32Ptr(10)
+	}
+	if obj.Spec.ProgressDeadlineSeconds == nil {
+		obj.Spec.ProgressDeadlineSeconds = pointer.Int32Ptr(600)
+	}
+	setDefaultsPodSpec(&obj.Spec.Template.Spec)
+}
+
+func setDefaultsStatefulSet(obj *appsv1.StatefulSet) {
+	if obj.Spec.PodManagementPolicy == "" {
+		obj.Spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	}
+	if obj.Spec.UpdateStrategy.Type == "" {
+		obj.Spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	}
+	if obj.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType {
+		if obj.Spec.UpdateStrategy.RollingUpdate == nil {
+			obj.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{}
+		}
+		if obj.Spec.UpdateStrategy.RollingUpdate.Partition == nil {
+			obj.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(0)
+		}
+	}
+	if obj.Spec.RevisionHistoryLimit == nil {
+		obj.Spec.RevisionHistoryLimit = pointer.Int32Ptr(10)
+	}
+	setDefaultsPodSpec(&obj.Spec.Template.Spec)
+}
+
+func setDefaultsDaemonSet(obj *appsv1.DaemonSet) {
+	if obj.Spec.UpdateStrategy.Type == "" {
+		obj.Spec.UpdateStrategy.Type = appsv1.RollingUpdateDaemonSetStrategyType
+	}
+	if obj.Spec.UpdateStrategy.Type == appsv1.RollingUpdateDaemonSetStrategyType {
+		if obj.Spec.UpdateStrategy.RollingUpdate == nil {
+			obj.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{}
+		}
+		if obj.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable == nil {
+			obj.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable = intOrStringPtr(intstr.FromInt(1))
+		}
+	}
+	if obj.Spec.RevisionHistoryLimit == nil {
+		obj.Spec.RevisionHistoryLimit = pointer.Int32Ptr(10)
+	}
+	setDefaultsPodSpec(&obj.Spec.Template.Spec)
+}
+
+func setDefaultsJobSpec(spec *batchv1.JobSpec) {
+	if spec.Completions == nil && spec.Parallelism == nil {
+		spec.Completions = pointer.Int32Ptr(1)
+	}
+	if spec.Parallelism == nil {
+		spec.Parallelism = pointer.Int32Ptr(1)
+	}
+	if spec.BackoffLimit == nil {
+		spec.BackoffLimit = pointer.Int32Ptr(6)
+	}
+	setDefaultsPodSpec(&spec.Template.Spec)
+}
+
+func setDefaultsCronJob(obj *batchv1beta1.CronJob) {
+	if obj.Spec.ConcurrencyPolicy == "" {
+		obj.Spec.ConcurrencyPolicy = batchv1beta1.AllowConcurrent
+	}
+	if obj.Spec.Suspend == nil {
+		obj.Spec.Suspend = pointer.BoolPtr(false)
+	}
+	if obj.Spec.SuccessfulJobsHistoryLimit == nil {
+		obj.Spec.SuccessfulJobsHistoryLimit = pointer.Int32Ptr(3)
+	}
+	if obj.Spec.FailedJobsHistoryLimit == nil {
+		obj.Spec.FailedJobsHistoryLimit = pointer.Int32Ptr(1)
+	}
+	setDefaultsJobSpec(&obj.Spec.JobTemplate.Spec)
+}
+
+func setDefaultsService(obj *corev1.Service) {
+	if obj.Spec.SessionAffinity == "" {
+		obj.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	}
+	if obj.Spec.SessionAffinity == corev1.ServiceAffinityClientIP &&
+		(obj.Spec.SessionAffinityConfig == nil || obj.Spec.SessionAffinityConfig.ClientIP == nil || obj.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds == nil) {
+		obj.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: pointer.Int32Ptr(corev1.DefaultClientIPServiceAffinitySeconds)}}
+	}
+	if obj.Spec.Type == "" {
+		obj.Spec.Type = corev1.ServiceTypeClusterIP
+	}
+	for i := range obj.Spec.Ports {
+		port := &obj.Spec.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if port.TargetPort == intstr.FromInt(0) || port.TargetPort == intstr.FromString("") {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+	}
+	if (obj.Spec.Type == corev1.ServiceTypeNodePort || obj.Spec.Type == corev1.ServiceTypeLoadBalancer) && obj.Spec.ExternalTrafficPolicy == "" {
+		obj.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+	}
+}
+
+func setDefaultsPodSpec(spec *corev1.PodSpec) {
+	if spec.DNSPolicy == "" {
+		spec.DNSPolicy = corev1.DNSClusterFirst
+	}
+	if spec.RestartPolicy == "" {
+		spec.RestartPolicy = corev1.RestartPolicyAlways
+	}
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	if spec.TerminationGracePeriodSeconds == nil {
+		spec.TerminationGracePeriodSeconds = pointer.Int64Ptr(corev1.DefaultTerminationGracePeriodSeconds)
+	}
+	if spec.SchedulerName == "" {
+		spec.SchedulerName = corev1.DefaultSchedulerName
+	}
+	if spec.EnableServiceLinks == nil {
+		spec.EnableServiceLinks = pointer.BoolPtr(corev1.DefaultEnableServiceLinks)
+	}
+
+	for i := range spec.InitContainers {
+		setDefaultsContainer(&spec.InitContainers[i], spec.HostNetwork)
+	}
+	for i := range spec.Containers {
+		setDefaultsContainer(&spec.Containers[i], spec.HostNetwork)
+	}
+	for i := range spec.Volumes {
+		setDefaultsVolume(&spec.Volumes[i])
+	}
+}
+
+func setDefaultsContainer(container *corev1.Container, hostNetwork bool) {
+	if container.ImagePullPolicy == "" {
+		container.ImagePullPolicy = defaultImagePullPolicy(container.Image)
+	}
+	if container.TerminationMessagePath == "" {
+		container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	}
+	if container.TerminationMessagePolicy == "" {
+		container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	}
+
+	for i := range container.Ports {
+		port := &container.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if hostNetwork && port.HostPort == 0 {
+			port.HostPort = port.ContainerPort
+		}
+	}
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.FieldRef != nil && env.ValueFrom.FieldRef.APIVersion == "" {
+			env.ValueFrom.FieldRef.APIVersion = "v1"
+		}
+	}
+	for _, probe := range []*corev1.Probe{container.LivenessProbe, container.ReadinessProbe, container.StartupProbe} {
+		setDefaultsProbe(probe)
+	}
+}
+
+// defaultImagePullPolicy returns the pull policy of images without explicit policy: images with the `latest` tag (or
+// without tag) are always pulled, all others only if they are not present.
+func defaultImagePullPolicy(image string) corev1.PullPolicy {
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	if i := strings.LastIndex(image, ":"); i == -1 || strings.Contains(image[i:], "/") || image[i+1:] == "latest" {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
+func setDefaultsProbe(probe *corev1.Probe) {
+	if probe == nil {
+		return
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	if probe.HTTPGet != nil {
+		if probe.HTTPGet.Path == "" {
+			probe.HTTPGet.Path = "/"
+		}
+		if probe.HTTPGet.Scheme == "" {
+			probe.HTTPGet.Scheme = corev1.URISchemeHTTP
+		}
+	}
+}
+
+func setDefaultsVolume(volume *corev1.Volume) {
+	switch {
+	case volume.Secret != nil:
+		if volume.Secret.DefaultMode == nil {
+			volume.Secret.DefaultMode = pointer.Int32Ptr(corev1.SecretVolumeSourceDefaultMode)
+		}
+	case volume.ConfigMap != nil:
+		if volume.ConfigMap.DefaultMode == nil {
+			volume.ConfigMap.DefaultMode = pointer.Int32Ptr(corev1.ConfigMapVolumeSourceDefaultMode)
+		}
+	case volume.DownwardAPI != nil:
+		if volume.DownwardAPI.DefaultMode == nil {
+			volume.DownwardAPI.DefaultMode = pointer.Int32Ptr(corev1.DownwardAPIVolumeSourceDefaultMode)
+		}
+	case volume.Projected != nil:
+		if volume.Projected.DefaultMode == nil {
+			volume.Projected.DefaultMode = pointer.Int32Ptr(corev1.ProjectedVolumeSourceDefaultMode)
+		}
+	case volume.HostPath != nil:
+		if volume.HostPath.Type == nil {
+			hostPathType := corev1.HostPathUnset
+			volume.HostPath.Type = &hostPathType
+		}
+	}
+}
+
+func intOrStringPtr(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package defaults_test
+
+import (
+	. "github.com/gardener/gardener-resource-manager/pkg/defaults"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Defaults", func() {
+	var s *runtime.Scheme
+
+	BeforeEach(func() {
+		s = runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(AddToScheme(s)).To(Succeed())
+	})
+
+	It("should know the defaulted kinds", func() {
+		Expect(IsDefaulted(appsv1.SchemeGroupVersion.WithKind("Deployment"))).To(BeTrue())
+		Expect(IsDefaulted(corev1.SchemeGroupVersion.WithKind("ConfigMap"))).To(BeFalse())
+	})
+
+	It("should default Deployments without replicas", func() {
+		deployment := &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:           "foo",
+							Image:          "foo:v1",
+							Ports:          []corev1.ContainerPort{{ContainerPort: 8080}},
+							ReadinessProbe: &corev1.Probe{Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromInt(8080)}}},
+						}},
+						Volumes: []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}}}},
+					},
+				},
+			},
+		}
+
+		s.Default(deployment)
+
+		Expect(deployment.Spec.Replicas).To(BeNil())
+		Expect(deployment.Spec.RevisionHistoryLimit).To(Equal(pointer.Int32Ptr(10)))
+		Expect(deployment.Spec.ProgressDeadlineSeconds).To(Equal(pointer.Int32Ptr(600)))
+		Expect(deployment.Spec.Strategy).To(Equal(appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "25%"},
+				MaxSurge:       &intstr.IntOrString{Type: intstr.String, StrVal: "25%"},
+			},
+		}))
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.DNSPolicy).To(Equal(corev1.DNSClusterFirst))
+		Expect(podSpec.RestartPolicy).To(Equal(corev1.RestartPolicyAlways))
+		Expect(podSpec.TerminationGracePeriodSeconds).To(Equal(pointer.Int64Ptr(30)))
+		Expect(podSpec.SchedulerName).To(Equal(corev1.DefaultSchedulerName))
+		Expect(podSpec.SecurityContext).To(Equal(&corev1.PodSecurityContext{}))
+		Expect(podSpec.Volumes[0].ConfigMap.DefaultMode).To(Equal(pointer.Int32Ptr(0644)))
+
+		container := podSpec.Containers[0]
+		Expect(container.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		Expect(container.TerminationMessagePath).To(Equal("/dev/termination-log"))
+		Expect(container.TerminationMessagePolicy).To(Equal(corev1.TerminationMessageReadFile))
+		Expect(container.Ports[0].Protocol).To(Equal(corev1.ProtocolTCP))
+		Expect(container.ReadinessProbe).To(Equal(&corev1.Probe{
+			Handler:          corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromInt(8080), Scheme: corev1.URISchemeHTTP}},
+			TimeoutSeconds:   1,
+			PeriodSeconds:    10,
+			SuccessThreshold: 1,
+			FailureThreshold: 3,
+		}))
+	})
+
+	It("should not overwrite set fields", func() {
+		statefulSet := &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				UpdateStrategy:       appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+				RevisionHistoryLimit: pointer.Int32Ptr(1),
+			},
+		}
+
+		s.Default(statefulSet)
+
+		Expect(statefulSet.Spec.UpdateStrategy).To(Equal(appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}))
+		Expect(statefulSet.Spec.RevisionHistoryLimit).To(Equal(pointer.Int32Ptr(1)))
+		Expect(statefulSet.Spec.PodManagementPolicy).To(Equal(appsv1.OrderedReadyPodManagement))
+	})
+
+	It("should default the target ports of Services", func() {
+		service := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Port: 443}, {Port: 80, TargetPort: intstr.FromString("http")}},
+			},
+		}
+
+		s.Default(service)
+
+		Expect(service.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityNone))
+		Expect(service.Spec.ExternalTrafficPolicy).To(Equal(corev1.ServiceExternalTrafficPolicyTypeCluster))
+		Expect(service.Spec.Ports).To(Equal([]corev1.ServicePort{
+			{Port: 443, TargetPort: intstr.FromInt(443), Protocol: corev1.ProtocolTCP},
+			{Port: 80, TargetPort: intstr.FromString("http"), Protocol: corev1.ProtocolTCP},
+		}))
+	})
+
+	It("should default CronJobs and their Jobs", func() {
+		cronJob := &batchv1beta1.CronJob{}
+
+		s.Default(cronJob)
+
+		Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1beta1.AllowConcurrent))
+		Expect(cronJob.Spec.Suspend).To(Equal(pointer.BoolPtr(false)))
+		Expect(cronJob.Spec.SuccessfulJobsHistoryLimit).To(Equal(pointer.Int32Ptr(3)))
+		Expect(cronJob.Spec.FailedJobsHistoryLimit).To(Equal(pointer.Int32Ptr(1)))
+		Expect(cronJob.Spec.JobTemplate.Spec.Parallelism).To(Equal(pointer.Int32Ptr(1)))
+		Expect(cronJob.Spec.JobTemplate.Spec.Completions).To(Equal(pointer.Int32Ptr(1)))
+		Expect(cronJob.Spec.JobTemplate.Spec.BackoffLimit).To(Equal(pointer.Int32Ptr(6)))
+		Expect(cronJob.Spec.JobTemplate.Spec.Template.Spec.DNSPolicy).To(Equal(corev1.DNSClusterFirst))
+	})
+
+	It("should only default the parallelism of Jobs with completions", func() {
+		job := &batchv1.Job{Spec: batchv1.JobSpec{Completions: pointer.Int32Ptr(3)}}
+
+		s.Default(job)
+
+		Expect(job.Spec.Completions).To(Equal(pointer.Int32Ptr(3)))
+		Expect(job.Spec.Parallelism).To(Equal(pointer.Int32Ptr(1)))
+	})
+
+	DescribeTable("#ImagePullPolicy",
+		func(image string, policy corev1.PullPolicy) {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: image}}}}
+			s.Default(pod)
+			Expect(pod.Spec.Containers[0].ImagePullPolicy).To(Equal(policy))
+		},
+		Entry("without tag", "foo", corev1.PullAlways),
+		Entry("latest tag", "foo:latest", corev1.PullAlways),
+		Entry("registry with port", "registry:5000/foo", corev1.PullAlways),
+		Entry("tag", "registry:5000/foo:v1", corev1.PullIfNotPresent),
+		Entry("digest", "foo@sha256:0123", corev1.PullIfNotPresent),
+	)
+})
@@ -0,0 +1,249 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Report describes the state of a ManagedResource in the source cluster and of its objects in the target cluster.
+type Report struct {
+	Namespace          string       `json:"namespace"`
+	Name               string       `json:"name"`
+	Class              string       `json:"class,omitempty"`
+	Generation         int64        `json:"generation"`
+	ObservedGeneration int64        `json:"observedGeneration"`
+	DeletionTimestamp  *metav1.Time `json:"deletionTimestamp,omitempty"`
+	Finalizers         []string     `json:"finalizers,omitempty"`
+	Ignored            bool         `json:"ignored,omitempty"`
+	KeepObjects        bool         `json:"keepObjects,omitempty"`
+	// Conditions are the conditions of the ManagedResource which are not true.
+	Conditions []resourcesv1alpha1.ManagedResourceCondition `json:"conditions,omitempty"`
+	// Secrets are the secrets referenced by the ManagedResource.
+	Secrets []Secret `json:"secrets,omitempty"`
+	// Objects are the objects of the ManagedResource's inventory (`.status.resources`).
+	Objects []Object `json:"objects,omitempty"`
+	// Findings explain why the ManagedResource is not (yet) reconciled or deleted.
+	Findings []string `json:"findings,omitempty"`
+}
+
+// Secret describes a secret referenced by a ManagedResource.
+type Secret struct {
+	Name              string       `json:"name"`
+	Missing           bool         `json:"missing,omitempty"`
+	DeletionTimestamp *metav1.Time `json:"deletionTimestamp,omitempty"`
+	Finalizers        []string     `json:"finalizers,omitempty"`
+	// Error is the error which occurred while reading the secret.
+	Error string `json:"error,omitempty"`
+}
+
+// Object describes an object of a ManagedResource in the target cluster.
+type Object struct {
+	corev1.ObjectReference `json:",inline"`
+	Missing                bool         `json:"missing,omitempty"`
+	DeletionTimestamp      *metav1.Time `json:"deletionTimestamp,omitempty"`
+	Finalizers             []string     `json:"finalizers,omitempty"`
+	// Kept is true if the object is annotated with resources.gardener.cloud/keep-object=true.
+	Kept bool `json:"kept,omitempty"`
+	// DeletionUnconfirmed is true if the object is only deleted once it is annotated with
+	// confirmation.gardener.cloud/deletion=true.
+	DeletionUnconfirmed bool `json:"deletionUnconfirmed,omitempty"`
+	// Error is the error which occurred while reading the object, e.g. if its kind is not known anymore.
+	Error string `json:"error,omitempty"`
+}
+
+// String returns the kind and the key of the object.
+func (o Object) String() string {
+	if o.Namespace == "" {
+		return fmt.Sprintf("%s %s", o.Kind, o.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+// Diagnose reads the ManagedResource with the given key, its secrets from the source cluster and its objects from the
+// target cluster, and returns a report explaining its state. Errors reading secrets or objects are part of the
+// report, only an error reading the ManagedResource itself is returned.
+func Diagnose(ctx context.Context, sourceClient, targetClient client.Reader, key types.NamespacedName) (*Report, error) {
+	mr := &resourcesv1alpha1.ManagedResource{}
+	if err := sourceClient.Get(ctx, key, mr); err != nil {
+		return nil, fmt.Errorf("could not get ManagedResource %s: %+v", key, err)
+	}
+
+	report := &Report{
+		Namespace:          mr.Namespace,
+		Name:               mr.Name,
+		Class:              managedresources.ResourceClassOf(mr),
+		Generation:         mr.Generation,
+		ObservedGeneration: mr.Status.ObservedGeneration,
+		DeletionTimestamp:  mr.DeletionTimestamp,
+		Finalizers:         mr.Finalizers,
+		Ignored:            resourcesv1alpha1helper.IsIgnored(mr),
+		KeepObjects:        mr.Spec.KeepObjects != nil && *mr.Spec.KeepObjects,
+	}
+
+	for _, condition := range mr.Status.Conditions {
+		if condition.Status != resourcesv1alpha1.ConditionTrue {
+			report.Conditions = append(report.Conditions, condition)
+		}
+	}
+
+	for _, ref := range mr.Spec.SecretRefs {
+		report.Secrets = append(report.Secrets, diagnoseSecret(ctx, sourceClient, mr.Namespace, ref.Name))
+	}
+
+	for _, ref := range mr.Status.Resources {
+		report.Objects = append(report.Objects, diagnoseObject(ctx, targetClient, ref.ObjectReference))
+	}
+
+	report.Findings = findings(report)
+	return report, nil
+}
+
+func diagnoseSecret(ctx context.Context, c client.Reader, namespace, name string) Secret {
+	out := Secret{Name: name}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			out.Missing = true
+		} else {
+			out.Error = err.Error()
+		}
+		return out
+	}
+
+	out.DeletionTimestamp = secret.DeletionTimestamp
+	out.Finalizers = secret.Finalizers
+	return out
+}
+
+func getObject(ctx context.Context, c client.Reader, ref corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	return obj, c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj)
+}
+
+func diagnoseObject(ctx context.Context, c client.Reader, ref corev1.ObjectReference) Object {
+	out := Object{ObjectReference: ref}
+
+	obj, err := getObject(ctx, c, ref)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			out.Missing = true
+		} else {
+			out.Error = err.Error()
+		}
+		return out
+	}
+
+	out.DeletionTimestamp = obj.GetDeletionTimestamp()
+	out.Finalizers = obj.GetFinalizers()
+	out.Kept = annotationTrue(obj, resourcesv1alpha1.KeepObject)
+	out.DeletionUnconfirmed = !managedresources.DeletionConfirmed(obj)
+	return out
+}
+
+func annotationTrue(obj metav1.Object, key string) bool {
+	value, _ := strconv.ParseBool(obj.GetAnnotations()[key])
+	return value
+}
+
+func findings(r *Report) []string {
+	var (
+		out      []string
+		deleting = r.DeletionTimestamp != nil
+	)
+
+	if r.Ignored {
+		out = append(out, fmt.Sprintf("The ManagedResource is annotated with %s=true, it is neither reconciled nor are its objects deleted.", resourcesv1alpha1.Ignore))
+	}
+
+	if deleting {
+		if len(r.Finalizers) > 0 {
+			out = append(out, fmt.Sprintf("The ManagedResource is being deleted since %s, its deletion is blocked by the finalizers %s.", r.DeletionTimestamp.UTC().Format(time.RFC3339), strings.Join(r.Finalizers, ", ")))
+		}
+		for _, finalizer := range r.Finalizers {
+			if !strings.HasPrefix(finalizer, managedresources.FinalizerName) {
+				out = append(out, fmt.Sprintf("The finalizer %s is not maintained by the gardener-resource-manager, it has to be removed by its owner.", finalizer))
+			}
+		}
+	} else if r.ObservedGeneration < r.Generation {
+		out = append(out, fmt.Sprintf("The generation %d of the ManagedResource has not been observed yet (observed: %d), no gardener-resource-manager responsible for the class %q might be running.", r.Generation, r.ObservedGeneration, r.Class))
+	}
+
+	for _, condition := range r.Conditions {
+		out = append(out, fmt.Sprintf("The condition %s is %s (%s): %s", condition.Type, condition.Status, condition.Reason, condition.Message))
+	}
+
+	for _, secret := range r.Secrets {
+		switch {
+		case secret.Error != "":
+			out = append(out, fmt.Sprintf("The referenced secret %s could not be read: %s", secret.Name, secret.Error))
+		case secret.Missing:
+			out = append(out, fmt.Sprintf("The referenced secret %s does not exist.", secret.Name))
+		case secret.DeletionTimestamp != nil:
+			out = append(out, fmt.Sprintf("The referenced secret %s is being deleted, its deletion is blocked by the finalizers %s.", secret.Name, strings.Join(secret.Finalizers, ", ")))
+		}
+	}
+
+	remaining := 0
+	for _, obj := range r.Objects {
+		switch {
+		case obj.Error != "":
+			out = append(out, fmt.Sprintf("The object %s could not be read from the target cluster: %s", obj, obj.Error))
+		case obj.Missing:
+			if !deleting {
+				out = append(out, fmt.Sprintf("The object %s does not exist in the target cluster, it is recreated by the next reconciliation.", obj))
+			}
+		case deleting && !r.KeepObjects && !obj.Kept:
+			remaining++
+			switch {
+			case obj.DeletionTimestamp != nil && len(obj.Finalizers) > 0:
+				out = append(out, fmt.Sprintf("The object %s is being deleted since %s, its deletion is blocked by the finalizers %s.", obj, obj.DeletionTimestamp.UTC().Format(time.RFC3339), strings.Join(obj.Finalizers, ", ")))
+			case obj.DeletionUnconfirmed:
+				out = append(out, fmt.Sprintf("The object %s is only deleted once it is annotated with %s=true.", obj, resourcesv1alpha1.ConfirmationDeletion))
+			}
+		}
+	}
+
+	if deleting {
+		switch {
+		case r.KeepObjects:
+			out = append(out, "The objects are kept in the target cluster as .spec.keepObjects is true.")
+		case remaining > 0:
+			out = append(out, fmt.Sprintf("%d of %d objects remain in the target cluster.", remaining, len(r.Objects)))
+		default:
+			out = append(out, "No objects remain in the target cluster, the finalizers of the gardener-resource-manager are removed by the next reconciliation.")
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,272 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/diagnose"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Diagnose", func() {
+	var (
+		ctx          = context.TODO()
+		ctrl         *gomock.Controller
+		sourceClient *mockclient.MockClient
+		targetClient *mockclient.MockClient
+
+		deletionTimestamp = metav1.NewTime(time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC))
+		key               = types.NamespacedName{Namespace: "shoot--foo--bar", Name: "mr"}
+		mr                *resourcesv1alpha1.ManagedResource
+		targetObjects     map[string]*unstructured.Unstructured
+	)
+
+	ref := func(apiVersion, kind, namespace, name string) resourcesv1alpha1.ObjectReference {
+		return resourcesv1alpha1.ObjectReference{ObjectReference: corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name}}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		sourceClient = mockclient.NewMockClient(ctrl)
+		targetClient = mockclient.NewMockClient(ctrl)
+
+		mr = &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 2},
+			Spec:       resourcesv1alpha1.ManagedResourceSpec{SecretRefs: []corev1.LocalObjectReference{{Name: "secret"}}},
+			Status: resourcesv1alpha1.ManagedResourceStatus{
+				ObservedGeneration: 2,
+				Conditions: []resourcesv1alpha1.ManagedResourceCondition{
+					{Type: resourcesv1alpha1.ResourcesApplied, Status: resourcesv1alpha1.ConditionTrue},
+					{Type: resourcesv1alpha1.ResourcesHealthy, Status: resourcesv1alpha1.ConditionFalse, Reason: "ResourcesUnhealthy", Message: "Deployment is unhealthy"},
+				},
+				Resources: []resourcesv1alpha1.ObjectReference{
+					ref("apps/v1", "Deployment", "kube-system", "dep"),
+					ref("v1", "ConfigMap", "kube-system", "cm"),
+				},
+			},
+		}
+		targetObjects = map[string]*unstructured.Unstructured{}
+
+		sourceClient.EXPECT().Get(ctx, key, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResource{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *resourcesv1alpha1.ManagedResource) error {
+				*obj = *mr
+				return nil
+			}).AnyTimes()
+		sourceClient.EXPECT().Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: "secret"}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			Return(nil).AnyTimes()
+		targetClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&unstructured.Unstructured{})).
+			DoAndReturn(func(_ context.Context, key client.ObjectKey, obj *unstructured.Unstructured) error {
+				if obj.GetKind() == "Foo" {
+					return fmt.Errorf("no matches for kind \"Foo\" in version \"example.com/v1\"")
+				}
+				existing, ok := targetObjects[obj.GetKind()+"/"+key.String()]
+				if !ok {
+					return apierrors.NewNotFound(schema.GroupResource{Resource: obj.GetKind()}, key.Name)
+				}
+				*obj = *existing.DeepCopy()
+				return nil
+			}).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newObject := func(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		targetObjects[kind+"/"+types.NamespacedName{Namespace: namespace, Name: name}.String()] = obj
+		return obj
+	}
+
+	It("should return an error if the ManagedResource cannot be read", func() {
+		c := mockclient.NewMockClient(ctrl)
+		c.EXPECT().Get(ctx, key, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResource{})).
+			Return(apierrors.NewNotFound(resourcesv1alpha1.Resource("managedresources"), key.Name))
+
+		_, err := Diagnose(ctx, c, targetClient, key)
+		Expect(err).To(MatchError(ContainSubstring("could not get ManagedResource")))
+	})
+
+	It("should explain the state of a ManagedResource which is not deleted", func() {
+		mr.Generation = 3
+		mr.Spec.SecretRefs = append(mr.Spec.SecretRefs, corev1.LocalObjectReference{Name: "missing"})
+		sourceClient.EXPECT().Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: "missing"}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			Return(apierrors.NewNotFound(corev1.Resource("secrets"), "missing"))
+		newObject("apps/v1", "Deployment", "kube-system", "dep")
+
+		report, err := Diagnose(ctx, sourceClient, targetClient, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Class).To(Equal("resources"))
+		Expect(report.Conditions).To(ConsistOf(mr.Status.Conditions[1]))
+		Expect(report.Secrets).To(Equal([]Secret{{Name: "secret"}, {Name: "missing", Missing: true}}))
+		Expect(report.Objects).To(HaveLen(2))
+		Expect(report.Objects[0].Missing).To(BeFalse())
+		Expect(report.Objects[1].Missing).To(BeTrue())
+		Expect(report.Findings).To(Equal([]string{
+			`The generation 3 of the ManagedResource has not been observed yet (observed: 2), no gardener-resource-manager responsible for the class "resources" might be running.`,
+			"The condition ResourcesHealthy is False (ResourcesUnhealthy): Deployment is unhealthy",
+			"The referenced secret missing does not exist.",
+			"The object ConfigMap kube-system/cm does not exist in the target cluster, it is recreated by the next reconciliation.",
+		}))
+	})
+
+	It("should explain which finalizers and objects block the deletion of a ManagedResource", func() {
+		mr.DeletionTimestamp = &deletionTimestamp
+		mr.Finalizers = []string{"resources.gardener.cloud/gardener-resource-manager", "example.com/foo"}
+		mr.Status.Conditions = nil
+		mr.Status.Resources = append(mr.Status.Resources,
+			ref("v1", "Namespace", "", "foo"),
+			ref("v1", "Service", "kube-system", "kept"),
+			ref("example.com/v1", "Foo", "kube-system", "foo"),
+		)
+
+		dep := newObject("apps/v1", "Deployment", "kube-system", "dep")
+		dep.SetDeletionTimestamp(&deletionTimestamp)
+		dep.SetFinalizers([]string{"example.com/bar"})
+		newObject("v1", "Namespace", "", "foo")
+		newObject("v1", "Service", "kube-system", "kept").SetAnnotations(map[string]string{resourcesv1alpha1.KeepObject: "true"})
+
+		report, err := Diagnose(ctx, sourceClient, targetClient, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Objects[0].Finalizers).To(Equal([]string{"example.com/bar"}))
+		Expect(report.Objects[2].DeletionUnconfirmed).To(BeTrue())
+		Expect(report.Objects[3].Kept).To(BeTrue())
+		Expect(report.Findings).To(Equal([]string{
+			"The ManagedResource is being deleted since 2020-10-01T12:00:00Z, its deletion is blocked by the finalizers resources.gardener.cloud/gardener-resource-manager, example.com/foo.",
+			"The finalizer example.com/foo is not maintained by the gardener-resource-manager, it has to be removed by its owner.",
+			"The object Deployment kube-system/dep is being deleted since 2020-10-01T12:00:00Z, its deletion is blocked by the finalizers example.com/bar.",
+			"The object Namespace foo is only deleted once it is annotated with confirmation.gardener.cloud/deletion=true.",
+			`The object Foo kube-system/foo could not be read from the target cluster: no matches for kind "Foo" in version "example.com/v1"`,
+			"2 of 5 objects remain in the target cluster.",
+		}))
+	})
+
+	It("should explain that no objects remain once they are deleted", func() {
+		mr.DeletionTimestamp = &deletionTimestamp
+		mr.Finalizers = []string{"resources.gardener.cloud/gardener-resource-manager"}
+		mr.Status.Conditions = nil
+
+		report, err := Diagnose(ctx, sourceClient, targetClient, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Findings).To(Equal([]string{
+			"The ManagedResource is being deleted since 2020-10-01T12:00:00Z, its deletion is blocked by the finalizers resources.gardener.cloud/gardener-resource-manager.",
+			"No objects remain in the target cluster, the finalizers of the gardener-resource-manager are removed by the next reconciliation.",
+		}))
+	})
+
+	Describe("#Write", func() {
+		It("should write a human-readable report", func() {
+			mr.Status.Conditions = nil
+			newObject("apps/v1", "Deployment", "kube-system", "dep").SetFinalizers([]string{"example.com/bar"})
+			newObject("v1", "ConfigMap", "kube-system", "cm")
+
+			report, err := Diagnose(ctx, sourceClient, targetClient, key)
+			Expect(err).NotTo(HaveOccurred())
+
+			buf := &bytes.Buffer{}
+			Expect(report.Write(buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`ManagedResource:  shoot--foo--bar/mr
+Class:            resources
+Generation:       2 (observed: 2)
+Deleting:         no
+Finalizers:       <none>
+
+Secrets (source cluster):
+  secret  present
+
+Objects (target cluster):
+  apps/v1  Deployment kube-system/dep  present (finalizers: example.com/bar)
+  v1       ConfigMap kube-system/cm    present
+
+Findings:
+  No problems found.
+`))
+		})
+	})
+
+	Describe("#Tree", func() {
+		It("should return the ManagedResource with its secrets and the live health of its objects", func() {
+			mr.Status.Resources = append(mr.Status.Resources, ref("v1", "Service", "kube-system", "svc"))
+			newObject("apps/v1", "Deployment", "kube-system", "dep")
+			svc := newObject("v1", "Service", "kube-system", "svc")
+			svc.SetDeletionTimestamp(&deletionTimestamp)
+			svc.SetFinalizers([]string{"example.com/bar"})
+
+			tree, err := Tree(ctx, sourceClient, targetClient, scheme.Scheme, key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tree).To(Equal(&TreeNode{
+				Title:  "ManagedResource shoot--foo--bar/mr",
+				Status: "ResourcesApplied=True, ResourcesHealthy=False",
+				Children: []*TreeNode{
+					{Title: "Secrets", Children: []*TreeNode{
+						{Title: "Secret shoot--foo--bar/secret", Status: "present"},
+					}},
+					{Title: "Objects", Children: []*TreeNode{
+						{Title: "Deployment kube-system/dep", Status: `unhealthy: condition "Available" is missing`},
+						{Title: "ConfigMap kube-system/cm", Status: "missing"},
+						{Title: "Service kube-system/svc", Status: "deleting since 2020-10-01T12:00:00Z (finalizers: example.com/bar)"},
+					}},
+				},
+			}))
+		})
+
+		It("should write the tree", func() {
+			tree := &TreeNode{
+				Title:  "ManagedResource shoot--foo--bar/mr",
+				Status: "ResourcesApplied=True",
+				Children: []*TreeNode{
+					{Title: "Secrets", Children: []*TreeNode{
+						{Title: "Secret shoot--foo--bar/secret", Status: "present"},
+					}},
+					{Title: "Objects", Children: []*TreeNode{
+						{Title: "Deployment kube-system/dep", Status: "healthy"},
+						{Title: "ConfigMap kube-system/cm", Status: "missing"},
+					}},
+				},
+			}
+
+			buf := &bytes.Buffer{}
+			Expect(tree.Write(buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`ManagedResource shoot--foo--bar/mr  [ResourcesApplied=True]
+├── Secrets
+│   └── Secret shoot--foo--bar/secret  [present]
+└── Objects
+    ├── Deployment kube-system/dep  [healthy]
+    └── ConfigMap kube-system/cm  [missing]
+`))
+		})
+	})
+})
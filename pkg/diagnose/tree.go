@@ -0,0 +1,127 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/health"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TreeNode is a node of the tree view of a ManagedResource.
+type TreeNode struct {
+	// Title identifies the object of the node, e.g. `Deployment kube-system/foo`.
+	Title string `json:"title"`
+	// Status is the live status of the object of the node, e.g. `healthy` or `missing`.
+	Status   string      `json:"status,omitempty"`
+	Children []*TreeNode `json:"children,omitempty"`
+}
+
+// Tree reads the ManagedResource with the given key and returns a tree containing its secrets in the source cluster and
+// its objects in the target cluster together with their live health. The health of the objects is checked like by the
+// health controller, the given scheme is used for converting them to their typed representation.
+func Tree(ctx context.Context, sourceClient, targetClient client.Reader, targetScheme *runtime.Scheme, key types.NamespacedName) (*TreeNode, error) {
+	mr := &resourcesv1alpha1.ManagedResource{}
+	if err := sourceClient.Get(ctx, key, mr); err != nil {
+		return nil, fmt.Errorf("could not get ManagedResource %s: %+v", key, err)
+	}
+
+	var conditions []string
+	for _, condition := range mr.Status.Conditions {
+		conditions = append(conditions, fmt.Sprintf("%s=%s", condition.Type, condition.Status))
+	}
+	if mr.DeletionTimestamp != nil {
+		conditions = append(conditions, "deleting")
+	}
+	root := &TreeNode{Title: "ManagedResource " + key.String(), Status: strings.Join(conditions, ", ")}
+
+	if len(mr.Spec.SecretRefs) > 0 {
+		secrets := &TreeNode{Title: "Secrets"}
+		for _, ref := range mr.Spec.SecretRefs {
+			secret := diagnoseSecret(ctx, sourceClient, mr.Namespace, ref.Name)
+			secrets.Children = append(secrets.Children, &TreeNode{
+				Title:  fmt.Sprintf("Secret %s/%s", mr.Namespace, ref.Name),
+				Status: stateOf(secret.Missing, secret.Error, secret.DeletionTimestamp, secret.Finalizers),
+			})
+		}
+		root.Children = append(root.Children, secrets)
+	}
+
+	if len(mr.Status.Resources) > 0 {
+		objects := &TreeNode{Title: "Objects"}
+		for _, ref := range mr.Status.Resources {
+			objects.Children = append(objects.Children, &TreeNode{
+				Title:  Object{ObjectReference: ref.ObjectReference}.String(),
+				Status: objectStatus(ctx, targetClient, targetScheme, ref),
+			})
+		}
+		root.Children = append(root.Children, objects)
+	}
+
+	return root, nil
+}
+
+func objectStatus(ctx context.Context, c client.Reader, scheme *runtime.Scheme, ref resourcesv1alpha1.ObjectReference) string {
+	obj, err := getObject(ctx, c, ref.ObjectReference)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "missing"
+		}
+		return "error: " + err.Error()
+	}
+
+	if deletionTimestamp := obj.GetDeletionTimestamp(); deletionTimestamp != nil {
+		return fmt.Sprintf("deleting %s (finalizers: %s)", deletionOf(deletionTimestamp), listOf(obj.GetFinalizers()))
+	}
+	if err := health.CheckHealth(scheme, obj); err != nil {
+		return "unhealthy: " + err.Error()
+	}
+	return "healthy"
+}
+
+// Write writes the tree in a human-readable form to the given writer.
+func (n *TreeNode) Write(w io.Writer) error {
+	return n.write(w, "", "")
+}
+
+func (n *TreeNode) write(w io.Writer, prefix, childPrefix string) error {
+	line := prefix + n.Title
+	if n.Status != "" {
+		line += "  [" + n.Status + "]"
+	}
+	if _, err := fmt.Fprintln(w, line); err != nil {
+		return err
+	}
+
+	for i, child := range n.Children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.Children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		if err := child.write(w, childPrefix+branch, childPrefix+indent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
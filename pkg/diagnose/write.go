@@ -0,0 +1,100 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Write writes the report in a human-readable form to the given writer.
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "ManagedResource:\t%s/%s\n", r.Namespace, r.Name)
+	fmt.Fprintf(tw, "Class:\t%s\n", r.Class)
+	fmt.Fprintf(tw, "Generation:\t%d (observed: %d)\n", r.Generation, r.ObservedGeneration)
+	fmt.Fprintf(tw, "Deleting:\t%s\n", deletionOf(r.DeletionTimestamp))
+	fmt.Fprintf(tw, "Finalizers:\t%s\n", listOf(r.Finalizers))
+
+	if len(r.Conditions) > 0 {
+		fmt.Fprintln(tw, "\nConditions (not true):")
+		for _, condition := range r.Conditions {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+
+	if len(r.Secrets) > 0 {
+		fmt.Fprintln(tw, "\nSecrets (source cluster):")
+		for _, secret := range r.Secrets {
+			fmt.Fprintf(tw, "  %s\t%s\n", secret.Name, stateOf(secret.Missing, secret.Error, secret.DeletionTimestamp, secret.Finalizers))
+		}
+	}
+
+	if len(r.Objects) > 0 {
+		fmt.Fprintln(tw, "\nObjects (target cluster):")
+		for _, obj := range r.Objects {
+			state := stateOf(obj.Missing, obj.Error, obj.DeletionTimestamp, obj.Finalizers)
+			if obj.Kept {
+				state += ", kept"
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", obj.APIVersion, obj, state)
+		}
+	}
+
+	fmt.Fprintln(tw, "\nFindings:")
+	if len(r.Findings) == 0 {
+		fmt.Fprintln(tw, "  No problems found.")
+	}
+	for _, finding := range r.Findings {
+		fmt.Fprintf(tw, "  - %s\n", finding)
+	}
+
+	return tw.Flush()
+}
+
+func deletionOf(deletionTimestamp *metav1.Time) string {
+	if deletionTimestamp == nil {
+		return "no"
+	}
+	return "since " + deletionTimestamp.UTC().Format(time.RFC3339)
+}
+
+func listOf(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ", ")
+}
+
+func stateOf(missing bool, err string, deletionTimestamp *metav1.Time, finalizers []string) string {
+	switch {
+	case err != "":
+		return "error: " + err
+	case missing:
+		return "missing"
+	case deletionTimestamp != nil:
+		return fmt.Sprintf("deleting %s (finalizers: %s)", deletionOf(deletionTimestamp), listOf(finalizers))
+	case len(finalizers) > 0:
+		return fmt.Sprintf("present (finalizers: %s)", listOf(finalizers))
+	default:
+		return "present"
+	}
+}
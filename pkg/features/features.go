@@ -0,0 +1,38 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package features
+
+import (
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+// Every feature gate should be added here following this template:
+//
+// // owner: @username
+// // alpha: v0.x.y
+// MyFeature featuregate.Feature = "MyFeature"
+
+// DefaultFeatureGate is the feature gate of the gardener-resource-manager. It is set from the `--feature-gates` flag.
+var DefaultFeatureGate = featuregate.NewFeatureGate()
+
+// defaultFeatures contains all feature gates of the gardener-resource-manager with their default state and maturity.
+// Larger behavioral changes are added as alpha features disabled by default, so that they can be enabled
+// progressively per landscape.
+var defaultFeatures = map[featuregate.Feature]featuregate.FeatureSpec{}
+
+func init() {
+	utilruntime.Must(DefaultFeatureGate.Add(defaultFeatures))
+}
@@ -15,8 +15,6 @@
 package health
 
 import (
-	"github.com/gardener/gardener-resource-manager/pkg/health"
-
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -33,49 +31,49 @@ func CheckHealth(scheme *runtime.Scheme, obj runtime.Object) error {
 		if err := scheme.Convert(obj, crd, nil); err != nil {
 			return err
 		}
-		return health.CheckCustomResourceDefinition(crd)
+		return CheckCustomResourceDefinition(crd)
 	case appsv1.SchemeGroupVersion.WithKind("DaemonSet").GroupKind():
 		ds := &appsv1.DaemonSet{}
 		if err := scheme.Convert(obj, ds, nil); err != nil {
 			return err
 		}
-		return health.CheckDaemonSet(ds)
+		return CheckDaemonSet(ds)
 	case appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind():
 		deploy := &appsv1.Deployment{}
 		if err := scheme.Convert(obj, deploy, nil); err != nil {
 			return err
 		}
-		return health.CheckDeployment(deploy)
+		return CheckDeployment(deploy)
 	case batchv1.SchemeGroupVersion.WithKind("Job").GroupKind():
 		job := &batchv1.Job{}
 		if err := scheme.Convert(obj, job, nil); err != nil {
 			return err
 		}
-		return health.CheckJob(job)
+		return CheckJob(job)
 	case corev1.SchemeGroupVersion.WithKind("Pod").GroupKind():
 		pod := &corev1.Pod{}
 		if err := scheme.Convert(obj, pod, nil); err != nil {
 			return err
 		}
-		return health.CheckPod(pod)
+		return CheckPod(pod)
 	case appsv1.SchemeGroupVersion.WithKind("ReplicaSet").GroupKind():
 		rs := &appsv1.ReplicaSet{}
 		if err := scheme.Convert(obj, rs, nil); err != nil {
 			return err
 		}
-		return health.CheckReplicaSet(rs)
+		return CheckReplicaSet(rs)
 	case corev1.SchemeGroupVersion.WithKind("ReplicationController").GroupKind():
 		rc := &corev1.ReplicationController{}
 		if err := scheme.Convert(obj, rc, nil); err != nil {
 			return err
 		}
-		return health.CheckReplicationController(rc)
+		return CheckReplicationController(rc)
 	case appsv1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind():
 		statefulSet := &appsv1.StatefulSet{}
 		if err := scheme.Convert(obj, statefulSet, nil); err != nil {
 			return err
 		}
-		return health.CheckStatefulSet(statefulSet)
+		return CheckStatefulSet(statefulSet)
 	}
 
 	return nil
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthz
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// CacheSyncCheck is a health check that passes as soon as all informers of a cache have been synced.
+type CacheSyncCheck struct {
+	cache  cache.Informers
+	synced int32
+}
+
+var _ manager.LeaderElectionRunnable = &CacheSyncCheck{}
+
+// NewCacheSyncCheck creates a new CacheSyncCheck for the given cache. It has to be added to the manager, so that it
+// observes the cache's sync state.
+func NewCacheSyncCheck(cache cache.Informers) *CacheSyncCheck {
+	return &CacheSyncCheck{cache: cache}
+}
+
+// Start implements `manager.Runnable`. It waits until the cache has been synced.
+func (c *CacheSyncCheck) Start(stopCh <-chan struct{}) error {
+	if c.cache.WaitForCacheSync(stopCh) {
+		atomic.StoreInt32(&c.synced, 1)
+	}
+	return nil
+}
+
+// NeedLeaderElection implements `manager.LeaderElectionRunnable`. The sync state is observed by all instances.
+func (c *CacheSyncCheck) NeedLeaderElection() bool {
+	return false
+}
+
+// Check implements `healthz.Checker`.
+func (c *CacheSyncCheck) Check(_ *http.Request) error {
+	if atomic.LoadInt32(&c.synced) == 0 {
+		return fmt.Errorf("informers not synced yet")
+	}
+	return nil
+}
+
+// NewAPIServerCheck returns a health check that passes if the API server behind the given discovery client is reachable.
+func NewAPIServerCheck(client discovery.ServerVersionInterface) healthz.Checker {
+	return func(_ *http.Request) error {
+		if _, err := client.ServerVersion(); err != nil {
+			return fmt.Errorf("API server not reachable: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthz_test
+
+import (
+	"fmt"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/healthz"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+type fakeInformers struct {
+	cache.Informers
+	synced bool
+}
+
+func (f *fakeInformers) WaitForCacheSync(_ <-chan struct{}) bool {
+	return f.synced
+}
+
+type fakeServerVersion struct {
+	err error
+}
+
+func (f *fakeServerVersion) ServerVersion() (*version.Info, error) {
+	return &version.Info{}, f.err
+}
+
+var _ = Describe("Healthz", func() {
+	Describe("CacheSyncCheck", func() {
+		It("should fail before the cache has been synced", func() {
+			check := NewCacheSyncCheck(&fakeInformers{synced: true})
+			Expect(check.Check(nil)).To(HaveOccurred())
+		})
+
+		It("should pass after the cache has been synced", func() {
+			check := NewCacheSyncCheck(&fakeInformers{synced: true})
+			Expect(check.Start(make(chan struct{}))).To(Succeed())
+			Expect(check.Check(nil)).To(Succeed())
+		})
+
+		It("should fail if the cache could not be synced", func() {
+			check := NewCacheSyncCheck(&fakeInformers{synced: false})
+			Expect(check.Start(make(chan struct{}))).To(Succeed())
+			Expect(check.Check(nil)).To(HaveOccurred())
+		})
+	})
+
+	Describe("#NewAPIServerCheck", func() {
+		It("should pass if the API server is reachable", func() {
+			Expect(NewAPIServerCheck(&fakeServerVersion{})(nil)).To(Succeed())
+		})
+
+		It("should fail if the API server is not reachable", func() {
+			Expect(NewAPIServerCheck(&fakeServerVersion{err: fmt.Errorf("fake")})(nil)).To(MatchError(ContainSubstring("fake")))
+		})
+	})
+})
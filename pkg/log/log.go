@@ -15,12 +15,21 @@
 package log
 
 import (
+	"fmt"
+
 	"github.com/go-logr/logr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	logzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+const (
+	// FormatJSON is the log format producing one JSON document per log entry.
+	FormatJSON = "json"
+	// FormatText is the log format producing human readable console output.
+	FormatText = "text"
+)
+
 // ZapLogger is a Logger implementation.
 // If development is true, a Zap development config will be used
 // (stacktraces on warnings, no sampling), otherwise a Zap production
@@ -40,3 +49,32 @@ func ZapLogger(development bool) logr.Logger {
 		o.Development = development
 	})
 }
+
+// NewLogger creates a new production Zap logger which only logs entries of the given level or above and encodes
+// them in the given format (one of `json` or `text`).
+func NewLogger(level string, format string) (logr.Logger, error) {
+	var zapLevel zapcore.Level
+	if err := zapLevel.Set(level); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	encCfg := zap.NewProductionEncoderConfig()
+	encCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	var encoder zapcore.Encoder
+	switch format {
+	case FormatJSON:
+		encoder = zapcore.NewJSONEncoder(encCfg)
+	case FormatText:
+		encCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encCfg)
+	default:
+		return nil, fmt.Errorf("invalid log format %q, supported formats are %q and %q", format, FormatJSON, FormatText)
+	}
+
+	atomicLevel := zap.NewAtomicLevelAt(zapLevel)
+	return logzap.New(func(o *logzap.Options) {
+		o.Encoder = encoder
+		o.Level = &atomicLevel
+	}), nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	. "github.com/gardener/gardener-resource-manager/pkg/log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Log", func() {
+	Describe("#NewLogger", func() {
+		DescribeTable("should create a logger for valid levels and formats",
+			func(level, format string) {
+				logger, err := NewLogger(level, format)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger).NotTo(BeNil())
+			},
+			Entry("debug/json", "debug", FormatJSON),
+			Entry("info/text", "info", FormatText),
+			Entry("error/json", "error", FormatJSON),
+		)
+
+		It("should fail for an invalid level", func() {
+			_, err := NewLogger("verbose", FormatJSON)
+			Expect(err).To(MatchError(ContainSubstring("invalid log level")))
+		})
+
+		It("should fail for an invalid format", func() {
+			_, err := NewLogger("info", "xml")
+			Expect(err).To(MatchError(ContainSubstring("invalid log format")))
+		})
+	})
+})
@@ -0,0 +1,128 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// maxSampledErrors is the number of distinct errors above which errors that have not been seen within the sampling
+// interval are forgotten.
+const maxSampledErrors = 1000
+
+// now is the function used to determine the current time (overwritten in tests).
+var now = time.Now
+
+type sampledError struct {
+	lastLogged time.Time
+	suppressed int
+}
+
+type errorSampler struct {
+	lock     sync.Mutex
+	interval time.Duration
+	errors   map[string]*sampledError
+}
+
+// sample returns whether the error with the given key should be logged and how many identical errors have been
+// suppressed since it was logged the last time.
+func (s *errorSampler) sample(key string) (bool, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t := now()
+
+	e, ok := s.errors[key]
+	if !ok {
+		if len(s.errors) >= maxSampledErrors {
+			s.forgetStale(t)
+		}
+		s.errors[key] = &sampledError{lastLogged: t}
+		return true, 0
+	}
+
+	if t.Sub(e.lastLogged) < s.interval {
+		e.suppressed++
+		return false, 0
+	}
+
+	suppressed := e.suppressed
+	e.lastLogged, e.suppressed = t, 0
+	return true, suppressed
+}
+
+func (s *errorSampler) forgetStale(t time.Time) {
+	for key, e := range s.errors {
+		if t.Sub(e.lastLogged) >= s.interval {
+			delete(s.errors, key)
+		}
+	}
+}
+
+type samplingLogger struct {
+	logr.Logger
+
+	sampler *errorSampler
+	name    string
+	values  []interface{}
+}
+
+// NewSamplingLogger wraps the given logger so that identical errors (same logger name, message, error and key/value
+// pairs) are only logged once per interval. The first occurrence of an error is always logged, subsequent
+// occurrences are suppressed until the interval has passed. The next logged occurrence carries the number of
+// suppressed occurrences in the `repeated` key. Info messages are never sampled.
+func NewSamplingLogger(logger logr.Logger, interval time.Duration) logr.Logger {
+	return &samplingLogger{
+		Logger:  logger,
+		sampler: &errorSampler{interval: interval, errors: make(map[string]*sampledError)},
+	}
+}
+
+func (l *samplingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\x00%s\x00%v\x00%v\x00%v", l.name, msg, err, l.values, keysAndValues)
+
+	log, suppressed := l.sampler.sample(b.String())
+	if !log {
+		return
+	}
+	if suppressed > 0 {
+		keysAndValues = append(keysAndValues, "repeated", suppressed)
+	}
+	l.Logger.Error(err, msg, keysAndValues...)
+}
+
+func (l *samplingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &samplingLogger{
+		Logger:  l.Logger.WithValues(keysAndValues...),
+		sampler: l.sampler,
+		name:    l.name,
+		values:  append(l.values[:len(l.values):len(l.values)], keysAndValues...),
+	}
+}
+
+func (l *samplingLogger) WithName(name string) logr.Logger {
+	return &samplingLogger{
+		Logger:  l.Logger.WithName(name),
+		sampler: l.sampler,
+		name:    l.name + "." + name,
+		values:  l.values,
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type loggedError struct {
+	msg           string
+	keysAndValues []interface{}
+}
+
+type fakeLogger struct {
+	runtimelog.NullLogger
+	errors *[]loggedError
+}
+
+func (f fakeLogger) Error(_ error, msg string, keysAndValues ...interface{}) {
+	*f.errors = append(*f.errors, loggedError{msg, keysAndValues})
+}
+
+func (f fakeLogger) WithName(string) logr.Logger           { return f }
+func (f fakeLogger) WithValues(...interface{}) logr.Logger { return f }
+
+var _ = Describe("SamplingLogger", func() {
+	var (
+		oldNow  func() time.Time
+		current time.Time
+		errors  []loggedError
+		logger  logr.Logger
+		err     = fmt.Errorf("fake")
+	)
+
+	BeforeEach(func() {
+		oldNow = now
+		current = time.Unix(0, 0)
+		now = func() time.Time { return current }
+
+		errors = nil
+		logger = NewSamplingLogger(fakeLogger{errors: &errors}, time.Minute)
+	})
+
+	AfterEach(func() {
+		now = oldNow
+	})
+
+	It("should log identical errors only once per interval", func() {
+		logger.Error(err, "failed", "object", "foo")
+		logger.Error(err, "failed", "object", "foo")
+		current = current.Add(30 * time.Second)
+		logger.Error(err, "failed", "object", "foo")
+		Expect(errors).To(Equal([]loggedError{{"failed", []interface{}{"object", "foo"}}}))
+
+		current = current.Add(time.Minute)
+		logger.Error(err, "failed", "object", "foo")
+		Expect(errors).To(Equal([]loggedError{
+			{"failed", []interface{}{"object", "foo"}},
+			{"failed", []interface{}{"object", "foo", "repeated", 2}},
+		}))
+	})
+
+	It("should not sample different errors", func() {
+		logger.Error(err, "failed", "object", "foo")
+		logger.Error(err, "failed", "object", "bar")
+		logger.WithValues("object", "foo").Error(err, "failed")
+		logger.WithName("other").Error(err, "failed", "object", "foo")
+		logger.Error(fmt.Errorf("other"), "failed", "object", "foo")
+		Expect(errors).To(HaveLen(5))
+	})
+})
@@ -88,6 +88,16 @@ func (m *ManagedResource) ForceOverwriteLabels(v bool) *ManagedResource {
 	return m
 }
 
+func (m *ManagedResource) ForceOverwriteOwnerReferences(v bool) *ManagedResource {
+	m.resource.Spec.ForceOverwriteOwnerReferences = &v
+	return m
+}
+
+func (m *ManagedResource) ForceOverwriteFinalizers(v bool) *ManagedResource {
+	m.resource.Spec.ForceOverwriteFinalizers = &v
+	return m
+}
+
 func (m *ManagedResource) KeepObjects(v bool) *ManagedResource {
 	m.resource.Spec.KeepObjects = &v
 	return m
@@ -0,0 +1,213 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/health"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultWaitInterval is the default interval in which WaitUntilHealthy and WaitUntilDeleted check the
+// ManagedResource.
+const DefaultWaitInterval = 5 * time.Second
+
+var defaultTargetScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(kubernetesscheme.AddToScheme(defaultTargetScheme))
+	utilruntime.Must(apiextensionsv1beta1.AddToScheme(defaultTargetScheme))
+}
+
+// WaitOptions configures WaitUntilHealthy and WaitUntilDeleted.
+type WaitOptions struct {
+	// Interval is the interval in which the ManagedResource is checked, DefaultWaitInterval if not set.
+	Interval time.Duration
+	// TargetClient is a client for the target cluster. If set, the objects of the ManagedResource are checked as
+	// well, so that the progress reports all failing objects instead of only the first one mentioned in the
+	// ResourcesHealthy condition.
+	TargetClient client.Reader
+	// TargetScheme is the scheme used for checking the health of the objects, the scheme of client-go together with
+	// the apiextensions API if not set.
+	TargetScheme *runtime.Scheme
+	// Progress is called after every check of the ManagedResource that did not succeed.
+	Progress func(Progress)
+}
+
+// Progress describes why a ManagedResource is not yet healthy or deleted.
+type Progress struct {
+	// ManagedResource is the last observed state of the ManagedResource, nil if it does not exist.
+	ManagedResource *resourcesv1alpha1.ManagedResource
+	// Reason describes why the ManagedResource is not yet healthy or deleted.
+	Reason string
+	// FailingObjects are the objects that are not yet healthy or deleted, it is only filled if a TargetClient is
+	// given.
+	FailingObjects []FailingObject
+}
+
+// FailingObject is an object of a ManagedResource that is not yet healthy or deleted.
+type FailingObject struct {
+	corev1.ObjectReference
+	// Reason describes why the object is not yet healthy or deleted.
+	Reason string
+}
+
+// String returns the object in the form `<kind> <namespace>/<name>: <reason>`.
+func (o FailingObject) String() string {
+	return fmt.Sprintf("%s %s/%s: %s", o.Kind, o.Namespace, o.Name, o.Reason)
+}
+
+// WaitUntilHealthy waits until the ManagedResource with the given key is applied and healthy in its current
+// generation (see health.CheckManagedResource), or until the given context is cancelled.
+func WaitUntilHealthy(ctx context.Context, c client.Reader, key types.NamespacedName, opts WaitOptions) error {
+	return opts.poll(ctx, func() (bool, Progress, error) {
+		mr := &resourcesv1alpha1.ManagedResource{}
+		if err := c.Get(ctx, key, mr); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, Progress{Reason: "ManagedResource does not exist"}, nil
+			}
+			return false, Progress{}, err
+		}
+
+		if err := health.CheckManagedResource(mr); err != nil {
+			progress := Progress{ManagedResource: mr, Reason: err.Error()}
+			if opts.TargetClient != nil && mr.Status.ObservedGeneration == mr.Generation {
+				progress.FailingObjects = opts.unhealthyObjects(ctx, mr)
+			}
+			return false, progress, nil
+		}
+		return true, Progress{}, nil
+	}, func(progress Progress) error {
+		return fmt.Errorf("ManagedResource %s did not become healthy: %s", key, progress.Reason)
+	})
+}
+
+// WaitUntilDeleted waits until the ManagedResource with the given key does not exist anymore, or until the given
+// context is cancelled.
+func WaitUntilDeleted(ctx context.Context, c client.Reader, key types.NamespacedName, opts WaitOptions) error {
+	return opts.poll(ctx, func() (bool, Progress, error) {
+		mr := &resourcesv1alpha1.ManagedResource{}
+		if err := c.Get(ctx, key, mr); err != nil {
+			if apierrors.IsNotFound(err) {
+				return true, Progress{}, nil
+			}
+			return false, Progress{}, err
+		}
+
+		progress := Progress{ManagedResource: mr, Reason: "ManagedResource still exists"}
+		if mr.DeletionTimestamp == nil {
+			progress.Reason = "ManagedResource is not yet deleted"
+		} else if len(mr.Finalizers) > 0 {
+			progress.Reason = fmt.Sprintf("ManagedResource still has the finalizers %v", mr.Finalizers)
+		}
+		if opts.TargetClient != nil {
+			progress.FailingObjects = opts.remainingObjects(ctx, mr)
+		}
+		return false, progress, nil
+	}, func(progress Progress) error {
+		return fmt.Errorf("ManagedResource %s was not deleted: %s", key, progress.Reason)
+	})
+}
+
+func (o WaitOptions) poll(ctx context.Context, check func() (bool, Progress, error), timeoutError func(Progress) error) error {
+	interval := o.Interval
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+
+	var last Progress
+	err := wait.PollImmediateUntil(interval, func() (bool, error) {
+		done, progress, err := check()
+		if err != nil || done {
+			return done, err
+		}
+
+		last = progress
+		if o.Progress != nil {
+			o.Progress(progress)
+		}
+		return false, nil
+	}, ctx.Done())
+	if err == wait.ErrWaitTimeout {
+		return timeoutError(last)
+	}
+	return err
+}
+
+func (o WaitOptions) unhealthyObjects(ctx context.Context, mr *resourcesv1alpha1.ManagedResource) []FailingObject {
+	scheme := o.TargetScheme
+	if scheme == nil {
+		scheme = defaultTargetScheme
+	}
+
+	var out []FailingObject
+	for _, ref := range mr.Status.Resources {
+		obj, err := getObject(ctx, o.TargetClient, ref.ObjectReference)
+		if err != nil {
+			reason := err.Error()
+			if apierrors.IsNotFound(err) {
+				reason = "missing"
+			}
+			out = append(out, FailingObject{ObjectReference: ref.ObjectReference, Reason: reason})
+			continue
+		}
+
+		if err := health.CheckHealth(scheme, obj); err != nil {
+			out = append(out, FailingObject{ObjectReference: ref.ObjectReference, Reason: err.Error()})
+		}
+	}
+	return out
+}
+
+func (o WaitOptions) remainingObjects(ctx context.Context, mr *resourcesv1alpha1.ManagedResource) []FailingObject {
+	var out []FailingObject
+	for _, ref := range mr.Status.Resources {
+		obj, err := getObject(ctx, o.TargetClient, ref.ObjectReference)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				out = append(out, FailingObject{ObjectReference: ref.ObjectReference, Reason: err.Error()})
+			}
+			continue
+		}
+
+		reason := "not yet deleted"
+		if obj.GetDeletionTimestamp() != nil {
+			reason = fmt.Sprintf("deletion pending with finalizers %v", obj.GetFinalizers())
+		}
+		out = append(out, FailingObject{ObjectReference: ref.ObjectReference, Reason: reason})
+	}
+	return out
+}
+
+func getObject(ctx context.Context, c client.Reader, ref corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	return obj, c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj)
+}
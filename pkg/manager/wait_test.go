@@ -0,0 +1,134 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/test/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("Wait", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		scheme *runtime.Scheme
+		key    = types.NamespacedName{Namespace: "foo", Name: "bar"}
+		mr     *resourcesv1alpha1.ManagedResource
+		ref    = resourcesv1alpha1.ObjectReference{
+			ObjectReference: corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "dep"},
+		}
+		progress []Progress
+		opts     WaitOptions
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+
+		scheme = runtime.NewScheme()
+		Expect(kubernetesscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		mr = &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 1},
+			Status: resourcesv1alpha1.ManagedResourceStatus{
+				ObservedGeneration: 1,
+				Conditions: []resourcesv1alpha1.ManagedResourceCondition{
+					{Type: resourcesv1alpha1.ResourcesApplied, Status: resourcesv1alpha1.ConditionTrue},
+					{Type: resourcesv1alpha1.ResourcesHealthy, Status: resourcesv1alpha1.ConditionTrue},
+				},
+				Resources: []resourcesv1alpha1.ObjectReference{ref},
+			},
+		}
+
+		progress = nil
+		opts = WaitOptions{
+			Interval: 10 * time.Millisecond,
+			Progress: func(p Progress) { progress = append(progress, p) },
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	Describe("#WaitUntilHealthy", func() {
+		It("should succeed if the ManagedResource is healthy", func() {
+			c := fake.NewClient(scheme, mr)
+
+			Expect(WaitUntilHealthy(ctx, c, key, opts)).To(Succeed())
+			Expect(progress).To(BeEmpty())
+		})
+
+		It("should report the failing objects until the context is cancelled", func() {
+			mr.Status.Conditions[1].Status = resourcesv1alpha1.ConditionFalse
+			mr.Status.Conditions[1].Message = "Required Deployment \"dep\" in namespace \"default\" is unhealthy"
+			c := fake.NewClient(scheme, mr)
+			opts.TargetClient = fake.NewClient(scheme, &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dep", Generation: 2},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1},
+			})
+
+			err := WaitUntilHealthy(ctx, c, key, opts)
+			Expect(err).To(MatchError(ContainSubstring("ManagedResource foo/bar did not become healthy: condition ResourcesHealthy of managed resource foo/bar is False")))
+
+			Expect(progress).NotTo(BeEmpty())
+			Expect(progress[0].ManagedResource).NotTo(BeNil())
+			Expect(progress[0].FailingObjects).To(HaveLen(1))
+			Expect(progress[0].FailingObjects[0].ObjectReference).To(Equal(ref.ObjectReference))
+			Expect(progress[0].FailingObjects[0].String()).To(ContainSubstring("Deployment default/dep: observed generation outdated"))
+		})
+
+		It("should report missing objects", func() {
+			mr.Status.Conditions[1].Status = resourcesv1alpha1.ConditionFalse
+			c := fake.NewClient(scheme, mr)
+			opts.TargetClient = fake.NewClient(scheme)
+
+			Expect(WaitUntilHealthy(ctx, c, key, opts)).NotTo(Succeed())
+			Expect(progress[0].FailingObjects).To(ConsistOf(FailingObject{ObjectReference: ref.ObjectReference, Reason: "missing"}))
+		})
+
+		It("should wait for the ManagedResource to exist", func() {
+			Expect(WaitUntilHealthy(ctx, fake.NewClient(scheme), key, opts)).To(MatchError(ContainSubstring("ManagedResource does not exist")))
+			Expect(progress[0].ManagedResource).To(BeNil())
+		})
+	})
+
+	Describe("#WaitUntilDeleted", func() {
+		It("should succeed if the ManagedResource does not exist", func() {
+			Expect(WaitUntilDeleted(ctx, fake.NewClient(scheme), key, opts)).To(Succeed())
+			Expect(progress).To(BeEmpty())
+		})
+
+		It("should report the remaining objects until the context is cancelled", func() {
+			c := fake.NewClient(scheme, mr)
+			opts.TargetClient = fake.NewClient(scheme, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dep"}})
+
+			Expect(WaitUntilDeleted(ctx, c, key, opts)).To(MatchError("ManagedResource foo/bar was not deleted: ManagedResource is not yet deleted"))
+			Expect(progress[0].FailingObjects).To(ConsistOf(FailingObject{ObjectReference: ref.ObjectReference, Reason: "not yet deleted"}))
+		})
+	})
+})
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
 	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
 
 	corev1 "k8s.io/api/core/v1"
@@ -39,11 +40,6 @@ func (m *secretToManagedResourceMapper) InjectClient(client client.Client) error
 	return nil
 }
 
-func (m *secretToManagedResourceMapper) InjectStopChannel(stopCh <-chan struct{}) error {
-	m.ctx = utils.ContextFromStopChannel(stopCh)
-	return nil
-}
-
 func (m *secretToManagedResourceMapper) Map(obj handler.MapObject) []reconcile.Request {
 	if obj.Object == nil {
 		return nil
@@ -55,7 +51,7 @@ func (m *secretToManagedResourceMapper) Map(obj handler.MapObject) []reconcile.R
 	}
 
 	managedResourceList := &resourcesv1alpha1.ManagedResourceList{}
-	if err := m.client.List(m.ctx, managedResourceList, client.InNamespace(secret.Namespace)); err != nil {
+	if err := m.client.List(m.ctx, managedResourceList, managedresources.MatchingSecretRef(secret.Namespace, secret.Name)...); err != nil {
 		return nil
 	}
 
@@ -73,6 +69,7 @@ func (m *secretToManagedResourceMapper) Map(obj handler.MapObject) []reconcile.R
 						Name:      mr.Name,
 					},
 				})
+				break
 			}
 		}
 	}
@@ -80,7 +77,7 @@ func (m *secretToManagedResourceMapper) Map(obj handler.MapObject) []reconcile.R
 }
 
 // SecretToManagedResourceMapper returns a mapper that returns requests for ManagedResources whose
-// referenced secrets have been modified.
-func SecretToManagedResourceMapper(predicates ...predicate.Predicate) handler.Mapper {
-	return &secretToManagedResourceMapper{predicates: predicates}
+// referenced secrets have been modified. The ManagedResources are listed with the given context.
+func SecretToManagedResourceMapper(ctx context.Context, predicates ...predicate.Predicate) handler.Mapper {
+	return &secretToManagedResourceMapper{ctx: ctx, predicates: predicates}
 }
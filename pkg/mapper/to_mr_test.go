@@ -39,6 +39,7 @@ import (
 
 var _ = Describe("#SecretToManagedResourceMapper", func() {
 	var (
+		ctx    = context.TODO()
 		c      *mockclient.MockClient
 		ctrl   *gomock.Controller
 		m      handler.Mapper
@@ -59,7 +60,7 @@ var _ = Describe("#SecretToManagedResourceMapper", func() {
 
 		filter = managedresources.NewClassFilter("seed")
 
-		m = mapper.SecretToManagedResourceMapper(filter)
+		m = mapper.SecretToManagedResourceMapper(ctx, filter)
 
 		Expect(inject.ClientInto(c, m)).To(BeTrue())
 	})
@@ -68,10 +69,6 @@ var _ = Describe("#SecretToManagedResourceMapper", func() {
 		ctrl.Finish()
 	})
 
-	It("should be able to inject stop channel", func() {
-		Expect(inject.StopChannelInto(context.TODO().Done(), m)).To(BeTrue())
-	})
-
 	It("should do nothing, if Object is nil", func() {
 		requests := m.Map(handler.MapObject{})
 		Expect(requests).To(BeEmpty())
@@ -85,7 +82,7 @@ var _ = Describe("#SecretToManagedResourceMapper", func() {
 	})
 
 	It("should do nothing, if list fails", func() {
-		c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 			Return(fmt.Errorf("fake"))
 
 		requests := m.Map(handler.MapObject{
@@ -95,7 +92,7 @@ var _ = Describe("#SecretToManagedResourceMapper", func() {
 	})
 
 	It("should do nothing, if there are no ManagedResources", func() {
-		c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace))
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name})
 
 		requests := m.Map(handler.MapObject{
 			Object: secret,
@@ -108,7 +105,7 @@ var _ = Describe("#SecretToManagedResourceMapper", func() {
 			Spec: resourcesv1alpha1.ManagedResourceSpec{Class: pointer.StringPtr("other")},
 		}
 
-		c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 			DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 				list.(*resourcesv1alpha1.ManagedResourceList).Items = []resourcesv1alpha1.ManagedResource{mr}
 				return nil
@@ -132,7 +129,7 @@ var _ = Describe("#SecretToManagedResourceMapper", func() {
 			},
 		}
 
-		c.EXPECT().List(nil, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace)).
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
 			DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 				list.(*resourcesv1alpha1.ManagedResourceList).Items = []resourcesv1alpha1.ManagedResource{mr}
 				return nil
@@ -148,4 +145,25 @@ var _ = Describe("#SecretToManagedResourceMapper", func() {
 			}},
 		))
 	})
+
+	It("should map to ManagedResources referencing the secret multiple times only once", func() {
+		mr := resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mr",
+				Namespace: secret.Namespace,
+			},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				Class:      pointer.StringPtr(filter.ResourceClass()),
+				SecretRefs: []corev1.LocalObjectReference{{Name: secret.Name}, {Name: secret.Name}},
+			},
+		}
+
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
+			DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+				list.(*resourcesv1alpha1.ManagedResourceList).Items = []resourcesv1alpha1.ManagedResource{mr}
+				return nil
+			})
+
+		Expect(m.Map(handler.MapObject{Object: secret})).To(HaveLen(1))
+	})
 })
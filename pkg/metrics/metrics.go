@@ -0,0 +1,118 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/gardener/gardener-resource-manager/pkg/version"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Namespace is the namespace of all metrics exported by the gardener-resource-manager.
+const Namespace = "gardener_resource_manager"
+
+var managedResourceLabels = []string{"namespace", "name"}
+
+var (
+	// ManagedResourceBundleSize is the decoded size of all secrets referenced by a ManagedResource.
+	ManagedResourceBundleSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "managedresource_bundle_size_bytes",
+		Help:      "Decoded size of the data of all secrets referenced by a ManagedResource.",
+	}, managedResourceLabels)
+
+	// ManagedResourceLargestSecretSize is the decoded size of the largest secret referenced by a ManagedResource.
+	ManagedResourceLargestSecretSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "managedresource_largest_secret_size_bytes",
+		Help:      "Decoded size of the data of the largest secret referenced by a ManagedResource.",
+	}, managedResourceLabels)
+
+	// ManagedResourceObjects is the number of objects contained in a ManagedResource.
+	ManagedResourceObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "managedresource_objects",
+		Help:      "Number of objects decoded from the secrets referenced by a ManagedResource.",
+	}, managedResourceLabels)
+
+	// ApplyErrors is the number of objects which could not be applied, by the class of the error.
+	ApplyErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "apply_errors_total",
+		Help:      "Number of objects which could not be applied, by the class of the error.",
+	}, []string{"class"})
+
+	// TargetKubeconfigRotations is the number of rotations of the kubeconfig of the target cluster.
+	TargetKubeconfigRotations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "target_kubeconfig_rotations_total",
+		Help:      "Number of observed rotations of the secret containing the kubeconfig of the target cluster.",
+	})
+
+	// TargetKubeconfigValid is 1 if the kubeconfig of the target cluster in the given secret could connect to it, and 0
+	// otherwise.
+	TargetKubeconfigValid = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "target_kubeconfig_valid",
+		Help:      "Whether the last validation of the rotated kubeconfig of the target cluster succeeded (1) or failed (0).",
+	}, []string{"namespace", "name"})
+
+	// BuildInfo describes the build of the running binary. Its value is always 1.
+	BuildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "build_info",
+		Help:      "Build information of the gardener-resource-manager, the value is always 1.",
+	}, []string{"version", "git_commit", "build_date", "go_version"})
+)
+
+func init() {
+	info := version.Get()
+	BuildInfo.WithLabelValues(info.Version, info.GitCommit, info.BuildDate, info.GoVersion).Set(1)
+
+	metrics.Registry.MustRegister(
+		BuildInfo,
+		ManagedResourceBundleSize,
+		ManagedResourceLargestSecretSize,
+		ManagedResourceObjects,
+		ApplyErrors,
+		TargetKubeconfigRotations,
+		TargetKubeconfigValid,
+	)
+}
+
+// RecordBundle records the size and the number of objects of the bundle of the given ManagedResource.
+func RecordBundle(namespace, name string, size, largestSecretSize, objects int) {
+	ManagedResourceBundleSize.WithLabelValues(namespace, name).Set(float64(size))
+	ManagedResourceLargestSecretSize.WithLabelValues(namespace, name).Set(float64(largestSecretSize))
+	ManagedResourceObjects.WithLabelValues(namespace, name).Set(float64(objects))
+}
+
+// RecordTargetKubeconfigValidation records whether the kubeconfig of the target cluster in the given secret could
+// connect to it.
+func RecordTargetKubeconfigValidation(namespace, name string, valid bool) {
+	value := 0.0
+	if valid {
+		value = 1
+	}
+	TargetKubeconfigValid.WithLabelValues(namespace, name).Set(value)
+}
+
+// ForgetManagedResource deletes all metrics of the given ManagedResource.
+func ForgetManagedResource(namespace, name string) {
+	ManagedResourceBundleSize.DeleteLabelValues(namespace, name)
+	ManagedResourceLargestSecretSize.DeleteLabelValues(namespace, name)
+	ManagedResourceObjects.DeleteLabelValues(namespace, name)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"runtime"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/metrics"
+	"github.com/gardener/gardener-resource-manager/pkg/version"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func gaugeValue(vec *prometheus.GaugeVec, namespace, name string) float64 {
+	m := &dto.Metric{}
+	Expect(vec.WithLabelValues(namespace, name).Write(m)).To(Succeed())
+	return m.GetGauge().GetValue()
+}
+
+var _ = Describe("Metrics", func() {
+	It("should record and forget the bundle metrics of a ManagedResource", func() {
+		RecordBundle("foo", "bar", 300, 200, 5)
+
+		Expect(gaugeValue(ManagedResourceBundleSize, "foo", "bar")).To(Equal(float64(300)))
+		Expect(gaugeValue(ManagedResourceLargestSecretSize, "foo", "bar")).To(Equal(float64(200)))
+		Expect(gaugeValue(ManagedResourceObjects, "foo", "bar")).To(Equal(float64(5)))
+
+		ForgetManagedResource("foo", "bar")
+
+		Expect(gaugeValue(ManagedResourceBundleSize, "foo", "bar")).To(BeZero())
+		Expect(gaugeValue(ManagedResourceLargestSecretSize, "foo", "bar")).To(BeZero())
+		Expect(gaugeValue(ManagedResourceObjects, "foo", "bar")).To(BeZero())
+	})
+
+	It("should export the build information", func() {
+		info := version.Get()
+		m := &dto.Metric{}
+		Expect(BuildInfo.WithLabelValues(info.Version, info.GitCommit, info.BuildDate, info.GoVersion).Write(m)).To(Succeed())
+		Expect(m.GetGauge().GetValue()).To(Equal(float64(1)))
+		Expect(info.GoVersion).To(Equal(runtime.Version()))
+	})
+
+	It("should export the work queue metrics per controller", func() {
+		queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test-controller")
+		defer queue.ShutDown()
+		queue.AddRateLimited("foo")
+
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		var exported []string
+		for _, family := range families {
+			for _, m := range family.GetMetric() {
+				for _, l := range m.GetLabel() {
+					if l.GetName() == "name" && l.GetValue() == "test-controller" {
+						exported = append(exported, family.GetName())
+					}
+				}
+			}
+		}
+		Expect(exported).To(ContainElement("workqueue_depth"))
+		Expect(exported).To(ContainElement("workqueue_retries_total"))
+		Expect(exported).To(ContainElement("workqueue_longest_running_processor_seconds"))
+	})
+})
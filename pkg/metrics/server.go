@@ -0,0 +1,218 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Path is the path under which the metrics are served.
+const Path = "/metrics"
+
+// ServerOptions configures the transport security and the authentication of the metrics server.
+type ServerOptions struct {
+	// CertDir is the directory containing the serving certificate (tls.crt) and key (tls.key).
+	CertDir string
+	// ClientCAFile is the path to a CA bundle. If set, clients presenting a certificate signed by one of its CAs are
+	// allowed to scrape the metrics.
+	ClientCAFile string
+	// TokenReview allows clients to scrape the metrics with a bearer token, which is authenticated with a TokenReview
+	// and whose user must be allowed to get the metrics path according to a SubjectAccessReview.
+	TokenReview bool
+}
+
+// Server serves the metrics of the controller-runtime registry via TLS.
+type Server struct {
+	log     logr.Logger
+	address string
+	options ServerOptions
+	handler http.Handler
+}
+
+var _ manager.LeaderElectionRunnable = &Server{}
+
+// NewServer creates a new metrics server listening on the given address. The given client is used for creating
+// TokenReviews and SubjectAccessReviews if `TokenReview` is enabled.
+func NewServer(log logr.Logger, address string, c client.Client, options ServerOptions) *Server {
+	return &Server{
+		log:     log,
+		address: address,
+		options: options,
+		handler: NewAuthHandler(c, options, promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{ErrorHandling: promhttp.HTTPErrorOnError})),
+	}
+}
+
+// Start implements `manager.Runnable`. It serves the metrics until the stop channel is closed.
+func (s *Server) Start(stopCh <-chan struct{}) error {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// the certificate is loaded for every connection, so that rotated certificates are picked up
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(filepath.Join(s.options.CertDir, "tls.crt"), filepath.Join(s.options.CertDir, "tls.key"))
+			if err != nil {
+				return nil, err
+			}
+			return &cert, nil
+		},
+	}
+
+	if s.options.ClientCAFile != "" {
+		caBundle, err := ioutil.ReadFile(s.options.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("could not read metrics client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return fmt.Errorf("metrics client CA file %q does not contain any certificate", s.options.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		// clients authenticating with a bearer token do not present a certificate
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(Path, s.handler)
+	server := &http.Server{Addr: s.address, Handler: mux, TLSConfig: tlsConfig}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.log.Info("Serving metrics via TLS", "address", s.address)
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("error serving metrics: %w", err)
+	case <-stopCh:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(ctx)
+	}
+}
+
+// NeedLeaderElection implements `manager.LeaderElectionRunnable`. The metrics are served by all instances.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+type authHandler struct {
+	client  client.Client
+	options ServerOptions
+	handler http.Handler
+}
+
+// NewAuthHandler returns a handler which only passes requests to the given handler that are authenticated according
+// to the given options. All requests are passed if neither client certificates nor token reviews are enabled.
+func NewAuthHandler(c client.Client, options ServerOptions, handler http.Handler) http.Handler {
+	if options.ClientCAFile == "" && !options.TokenReview {
+		return handler
+	}
+	return &authHandler{client: c, options: options, handler: handler}
+}
+
+// ServeHTTP implements `http.Handler`.
+func (h *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the certificate chain has already been verified against the client CAs during the handshake
+	if h.options.ClientCAFile != "" && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	token := bearerToken(r)
+	if !h.options.TokenReview || token == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authenticate(r.Context(), token)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not authenticate request: %+v", err), http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	allowed, err := h.authorize(r.Context(), user, r.URL.Path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not authorize request: %+v", err), http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, fmt.Sprintf("Forbidden (user=%s, verb=get, path=%s)", user.Username, r.URL.Path), http.StatusForbidden)
+		return
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
+
+// authenticate returns the user the given token belongs to or nil if the token could not be authenticated.
+func (h *authHandler) authenticate(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := h.client.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	if !review.Status.Authenticated {
+		return nil, nil
+	}
+	return &review.Status.User, nil
+}
+
+// authorize checks whether the given user is allowed to get the given non-resource path.
+func (h *authHandler) authorize(ctx context.Context, user *authenticationv1.UserInfo, path string) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, values := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(values)
+	}
+
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:                  user.Username,
+		UID:                   user.UID,
+		Groups:                user.Groups,
+		Extra:                 extra,
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: path, Verb: "get"},
+	}}
+	if err := h.client.Create(ctx, review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+func bearerToken(r *http.Request) string {
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/metrics"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Server", func() {
+	Describe("#NewAuthHandler", func() {
+		var (
+			ctrl    *gomock.Controller
+			c       *mockclient.MockClient
+			handler http.Handler
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			c = mockclient.NewMockClient(ctrl)
+			handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprint(w, "metrics")
+			})
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		serve := func(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			return w
+		}
+
+		withToken := func(token string) *http.Request {
+			r := httptest.NewRequest(http.MethodGet, Path, nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+			return r
+		}
+
+		expectTokenReview := func(token string, authenticated bool) {
+			c.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&authenticationv1.TokenReview{})).DoAndReturn(
+				func(_ context.Context, review *authenticationv1.TokenReview, _ ...client.CreateOption) error {
+					Expect(review.Spec.Token).To(Equal(token))
+					review.Status.Authenticated = authenticated
+					review.Status.User = authenticationv1.UserInfo{Username: "prometheus", Groups: []string{"monitoring"}}
+					return nil
+				})
+		}
+
+		expectSubjectAccessReview := func(allowed bool) {
+			c.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&authorizationv1.SubjectAccessReview{})).DoAndReturn(
+				func(_ context.Context, review *authorizationv1.SubjectAccessReview, _ ...client.CreateOption) error {
+					Expect(review.Spec.User).To(Equal("prometheus"))
+					Expect(review.Spec.Groups).To(ConsistOf("monitoring"))
+					Expect(review.Spec.NonResourceAttributes).To(Equal(&authorizationv1.NonResourceAttributes{Path: Path, Verb: "get"}))
+					review.Status.Allowed = allowed
+					return nil
+				})
+		}
+
+		It("should pass all requests if authentication is disabled", func() {
+			w := serve(NewAuthHandler(c, ServerOptions{}, handler), httptest.NewRequest(http.MethodGet, Path, nil))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("metrics"))
+		})
+
+		It("should pass requests with verified client certificates", func() {
+			r := httptest.NewRequest(http.MethodGet, Path, nil)
+			r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+
+			w := serve(NewAuthHandler(c, ServerOptions{ClientCAFile: "ca.crt"}, handler), r)
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should reject requests without client certificate", func() {
+			r := httptest.NewRequest(http.MethodGet, Path, nil)
+			r.TLS = &tls.ConnectionState{}
+
+			w := serve(NewAuthHandler(c, ServerOptions{ClientCAFile: "ca.crt"}, handler), r)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should reject requests without token", func() {
+			w := serve(NewAuthHandler(c, ServerOptions{TokenReview: true}, handler), httptest.NewRequest(http.MethodGet, Path, nil))
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should reject requests with unauthenticated tokens", func() {
+			expectTokenReview("foo", false)
+
+			w := serve(NewAuthHandler(c, ServerOptions{TokenReview: true}, handler), withToken("foo"))
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should reject requests of unauthorized users", func() {
+			expectTokenReview("foo", true)
+			expectSubjectAccessReview(false)
+
+			w := serve(NewAuthHandler(c, ServerOptions{TokenReview: true}, handler), withToken("foo"))
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should pass requests of authorized users", func() {
+			expectTokenReview("foo", true)
+			expectSubjectAccessReview(true)
+
+			w := serve(NewAuthHandler(c, ServerOptions{ClientCAFile: "ca.crt", TokenReview: true}, handler), withToken("foo"))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("metrics"))
+		})
+
+		It("should fail if the token review fails", func() {
+			c.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&authenticationv1.TokenReview{})).Return(fmt.Errorf("fake"))
+
+			w := serve(NewAuthHandler(c, ServerOptions{TokenReview: true}, handler), withToken("foo"))
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate_test
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	managerpredicate "github.com/gardener/gardener-resource-manager/pkg/predicate"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var _ = Describe("#AnnotationChanged", func() {
+	var (
+		oldMR, newMR *resourcesv1alpha1.ManagedResource
+		predicate    predicate.Predicate
+	)
+
+	BeforeEach(func() {
+		predicate = managerpredicate.AnnotationChanged(resourcesv1alpha1.Ignore)
+		oldMR = &resourcesv1alpha1.ManagedResource{}
+		newMR = oldMR.DeepCopy()
+	})
+
+	update := func() bool {
+		return predicate.Update(event.UpdateEvent{MetaOld: &oldMR.ObjectMeta, ObjectOld: oldMR, MetaNew: &newMR.ObjectMeta, ObjectNew: newMR})
+	}
+
+	It("should not match if the annotation did not change", func() {
+		Expect(update()).To(BeFalse())
+
+		oldMR.Annotations = map[string]string{resourcesv1alpha1.Ignore: "true", "foo": "bar"}
+		newMR.Annotations = map[string]string{resourcesv1alpha1.Ignore: "true"}
+		Expect(update()).To(BeFalse())
+	})
+
+	It("should match if the annotation was added", func() {
+		newMR.Annotations = map[string]string{resourcesv1alpha1.Ignore: "true"}
+		Expect(update()).To(BeTrue())
+	})
+
+	It("should match if the annotation was removed", func() {
+		oldMR.Annotations = map[string]string{resourcesv1alpha1.Ignore: "true"}
+		Expect(update()).To(BeTrue())
+	})
+
+	It("should match if the value of the annotation changed", func() {
+		oldMR.Annotations = map[string]string{resourcesv1alpha1.Ignore: "true"}
+		newMR.Annotations = map[string]string{resourcesv1alpha1.Ignore: "false"}
+		Expect(update()).To(BeTrue())
+	})
+
+	It("should match create events", func() {
+		Expect(predicate.Create(event.CreateEvent{Meta: &newMR.ObjectMeta, Object: newMR})).To(BeTrue())
+	})
+})
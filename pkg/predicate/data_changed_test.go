@@ -0,0 +1,61 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate_test
+
+import (
+	managerpredicate "github.com/gardener/gardener-resource-manager/pkg/predicate"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var _ = Describe("#SecretDataChanged", func() {
+	var (
+		secret    *corev1.Secret
+		predicate predicate.Predicate
+	)
+
+	BeforeEach(func() {
+		predicate = managerpredicate.SecretDataChanged()
+		secret = &corev1.Secret{Data: map[string][]byte{"objects.yaml": []byte("foo")}}
+	})
+
+	It("should match create and delete events", func() {
+		Expect(predicate.Create(event.CreateEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeTrue())
+		Expect(predicate.Delete(event.DeleteEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeTrue())
+	})
+
+	It("should match updates of the data", func() {
+		old := secret.DeepCopy()
+		secret.Data["objects.yaml"] = []byte("bar")
+
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &old.ObjectMeta, ObjectOld: old, MetaNew: &secret.ObjectMeta, ObjectNew: secret})).To(BeTrue())
+	})
+
+	It("should not match updates of the metadata only", func() {
+		old := secret.DeepCopy()
+		secret.Finalizers = []string{"resources.gardener.cloud/gardener-resource-manager"}
+
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &old.ObjectMeta, ObjectOld: old, MetaNew: &secret.ObjectMeta, ObjectNew: secret})).To(BeFalse())
+	})
+
+	It("should not match updates of other objects", func() {
+		pod := &corev1.Pod{}
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &pod.ObjectMeta, ObjectOld: pod, MetaNew: &pod.ObjectMeta, ObjectNew: pod})).To(BeFalse())
+	})
+})
@@ -16,7 +16,6 @@ package predicate
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -27,6 +26,12 @@ import (
 // This is to ensure, that we properly remove the finalizer in case we missed an important
 // update event for a ManagedResource.
 func HasFinalizer(finalizer string) predicate.Predicate {
+	return HasMatchingFinalizer(func(f string) bool { return f == finalizer })
+}
+
+// HasMatchingFinalizer returns a predicate that detects if the object has a finalizer for which the given function
+// returns true, see `HasFinalizer`.
+func HasMatchingFinalizer(matches func(finalizer string) bool) predicate.Predicate {
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
 			// Create event is emitted on start-up, when the cache is populated from a complete list call for the first time.
@@ -37,7 +42,7 @@ func HasFinalizer(finalizer string) predicate.Predicate {
 				return false
 			}
 
-			return metaHasFinalizer(e.Meta, finalizer)
+			return metaHasMatchingFinalizer(e.Meta, matches)
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			// We only need to check MetaNew. If the finalizer was in MetaOld and is not in MetaNew, it is already
@@ -47,7 +52,7 @@ func HasFinalizer(finalizer string) predicate.Predicate {
 				return false
 			}
 
-			return metaHasFinalizer(e.MetaNew, finalizer)
+			return metaHasMatchingFinalizer(e.MetaNew, matches)
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			// If the secret is already deleted, all finalizers are already gone and we don't need to reconcile it.
@@ -59,6 +64,11 @@ func HasFinalizer(finalizer string) predicate.Predicate {
 	}
 }
 
-func metaHasFinalizer(meta metav1.Object, finalizer string) bool {
-	return sets.NewString(meta.GetFinalizers()...).Has(finalizer)
+func metaHasMatchingFinalizer(meta metav1.Object, matches func(finalizer string) bool) bool {
+	for _, finalizer := range meta.GetFinalizers() {
+		if matches(finalizer) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// HasLabel returns a predicate that detects if the object has the given label with the given value. It is used to
+// reconcile only the secrets with a certain purpose instead of all secrets in the cluster.
+func HasLabel(key, value string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return metaHasLabel(e.Meta, key, value)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return metaHasLabel(e.MetaNew, key, value)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return metaHasLabel(e.Meta, key, value)
+		},
+	}
+}
+
+func metaHasLabel(meta metav1.Object, key, value string) bool {
+	if meta == nil {
+		return false
+	}
+	v, ok := meta.GetLabels()[key]
+	return ok && v == value
+}
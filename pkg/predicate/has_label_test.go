@@ -0,0 +1,69 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate_test
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	managerpredicate "github.com/gardener/gardener-resource-manager/pkg/predicate"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var _ = Describe("#HasLabel", func() {
+	var (
+		secret    *corev1.Secret
+		predicate predicate.Predicate
+	)
+
+	BeforeEach(func() {
+		predicate = managerpredicate.HasLabel(resourcesv1alpha1.ResourceManagerPurpose, resourcesv1alpha1.LabelPurposeTokenRequest)
+		secret = &corev1.Secret{}
+	})
+
+	It("should not match objects without the label", func() {
+		Expect(predicate.Create(event.CreateEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeFalse())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &secret.ObjectMeta, ObjectOld: secret, MetaNew: &secret.ObjectMeta, ObjectNew: secret})).To(BeFalse())
+		Expect(predicate.Generic(event.GenericEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeFalse())
+	})
+
+	It("should not match objects with another value of the label", func() {
+		secret.Labels = map[string]string{resourcesv1alpha1.ResourceManagerPurpose: "other"}
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeFalse())
+	})
+
+	It("should match objects with the label", func() {
+		old := secret.DeepCopy()
+		secret.Labels = map[string]string{resourcesv1alpha1.ResourceManagerPurpose: resourcesv1alpha1.LabelPurposeTokenRequest}
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeTrue())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &old.ObjectMeta, ObjectOld: old, MetaNew: &secret.ObjectMeta, ObjectNew: secret})).To(BeTrue())
+		Expect(predicate.Generic(event.GenericEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeTrue())
+	})
+
+	It("should not match delete events", func() {
+		secret.Labels = map[string]string{resourcesv1alpha1.ResourceManagerPurpose: resourcesv1alpha1.LabelPurposeTokenRequest}
+
+		Expect(predicate.Delete(event.DeleteEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeFalse())
+	})
+
+	It("should not match events without metadata", func() {
+		Expect(predicate.Create(event.CreateEvent{Object: secret})).To(BeFalse())
+	})
+})
@@ -0,0 +1,44 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// HasName returns a predicate that detects if the object has the given namespace and name. It is used by controllers
+// observing a single object, e.g. the secret containing the kubeconfig of the target cluster.
+func HasName(namespace, name string) predicate.Predicate {
+	matches := func(meta metav1.Object) bool {
+		return meta != nil && meta.GetNamespace() == namespace && meta.GetName() == name
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return matches(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return matches(e.MetaNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return matches(e.Meta)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return matches(e.Meta)
+		},
+	}
+}
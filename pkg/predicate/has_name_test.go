@@ -0,0 +1,51 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate_test
+
+import (
+	managerpredicate "github.com/gardener/gardener-resource-manager/pkg/predicate"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("#HasName", func() {
+	var secret *corev1.Secret
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "garden", Name: "target-kubeconfig"}}
+	})
+
+	It("should match the object with the given namespace and name", func() {
+		predicate := managerpredicate.HasName("garden", "target-kubeconfig")
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeTrue())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &secret.ObjectMeta, ObjectOld: secret, MetaNew: &secret.ObjectMeta, ObjectNew: secret})).To(BeTrue())
+		Expect(predicate.Delete(event.DeleteEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeTrue())
+		Expect(predicate.Generic(event.GenericEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeTrue())
+	})
+
+	It("should not match objects with another namespace or name", func() {
+		Expect(managerpredicate.HasName("kube-system", "target-kubeconfig").Create(event.CreateEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeFalse())
+		Expect(managerpredicate.HasName("garden", "other").Create(event.CreateEvent{Meta: &secret.ObjectMeta, Object: secret})).To(BeFalse())
+	})
+
+	It("should not match events without metadata", func() {
+		Expect(managerpredicate.HasName("garden", "target-kubeconfig").Create(event.CreateEvent{Object: secret})).To(BeFalse())
+	})
+})
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// HasOperation returns a predicate that detects if the object is annotated with the given `gardener.cloud/operation`.
+// Unlike gardener's `HasOperationAnnotation`, it matches operations that are not known to gardener.
+func HasOperation(operation string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return metaHasOperation(e.Meta, operation)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return metaHasOperation(e.MetaNew, operation)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return metaHasOperation(e.Meta, operation)
+		},
+	}
+}
+
+func metaHasOperation(meta metav1.Object, operation string) bool {
+	return meta != nil && meta.GetAnnotations()[v1beta1constants.GardenerOperation] == operation
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate_test
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	managerpredicate "github.com/gardener/gardener-resource-manager/pkg/predicate"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var _ = Describe("#HasOperation", func() {
+	var (
+		mr        *resourcesv1alpha1.ManagedResource
+		predicate predicate.Predicate
+	)
+
+	BeforeEach(func() {
+		predicate = managerpredicate.HasOperation(resourcesv1alpha1.OperationForceApply)
+		mr = &resourcesv1alpha1.ManagedResource{}
+	})
+
+	It("should not match objects without operation", func() {
+		Expect(predicate.Create(event.CreateEvent{Meta: &mr.ObjectMeta, Object: mr})).To(BeFalse())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &mr.ObjectMeta, ObjectOld: mr, MetaNew: &mr.ObjectMeta, ObjectNew: mr})).To(BeFalse())
+		Expect(predicate.Generic(event.GenericEvent{Meta: &mr.ObjectMeta, Object: mr})).To(BeFalse())
+	})
+
+	It("should not match objects with other operations", func() {
+		mr.Annotations = map[string]string{v1beta1constants.GardenerOperation: v1beta1constants.GardenerOperationReconcile}
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &mr.ObjectMeta, Object: mr})).To(BeFalse())
+	})
+
+	It("should match objects with the operation", func() {
+		old := mr.DeepCopy()
+		mr.Annotations = map[string]string{v1beta1constants.GardenerOperation: resourcesv1alpha1.OperationForceApply}
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &mr.ObjectMeta, Object: mr})).To(BeTrue())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &old.ObjectMeta, ObjectOld: old, MetaNew: &mr.ObjectMeta, ObjectNew: mr})).To(BeTrue())
+		Expect(predicate.Generic(event.GenericEvent{Meta: &mr.ObjectMeta, Object: mr})).To(BeTrue())
+	})
+
+	It("should not match delete events", func() {
+		mr.Annotations = map[string]string{v1beta1constants.GardenerOperation: resourcesv1alpha1.OperationForceApply}
+
+		Expect(predicate.Delete(event.DeleteEvent{Meta: &mr.ObjectMeta, Object: mr})).To(BeFalse())
+	})
+
+	It("should not match events without metadata", func() {
+		Expect(predicate.Create(event.CreateEvent{Object: mr})).To(BeFalse())
+	})
+})
@@ -0,0 +1,60 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate_test
+
+import (
+	managerpredicate "github.com/gardener/gardener-resource-manager/pkg/predicate"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var _ = Describe("#HasTaint", func() {
+	var (
+		node      *corev1.Node
+		predicate predicate.Predicate
+	)
+
+	BeforeEach(func() {
+		predicate = managerpredicate.HasTaint("node.gardener.cloud/critical-components-not-ready")
+		node = &corev1.Node{}
+	})
+
+	It("should not match nodes without the taint", func() {
+		node.Spec.Taints = []corev1.Taint{{Key: "other", Effect: corev1.TaintEffectNoSchedule}}
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &node.ObjectMeta, Object: node})).To(BeFalse())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &node.ObjectMeta, ObjectOld: node, MetaNew: &node.ObjectMeta, ObjectNew: node})).To(BeFalse())
+		Expect(predicate.Generic(event.GenericEvent{Meta: &node.ObjectMeta, Object: node})).To(BeFalse())
+	})
+
+	It("should match nodes with the taint", func() {
+		old := node.DeepCopy()
+		node.Spec.Taints = []corev1.Taint{{Key: "node.gardener.cloud/critical-components-not-ready", Effect: corev1.TaintEffectNoSchedule}}
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &node.ObjectMeta, Object: node})).To(BeTrue())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &old.ObjectMeta, ObjectOld: old, MetaNew: &node.ObjectMeta, ObjectNew: node})).To(BeTrue())
+		Expect(predicate.Generic(event.GenericEvent{Meta: &node.ObjectMeta, Object: node})).To(BeTrue())
+		Expect(predicate.Delete(event.DeleteEvent{Meta: &node.ObjectMeta, Object: node})).To(BeFalse())
+	})
+
+	It("should not match other objects", func() {
+		pod := &corev1.Pod{}
+		Expect(predicate.Create(event.CreateEvent{Meta: &pod.ObjectMeta, Object: pod})).To(BeFalse())
+	})
+})
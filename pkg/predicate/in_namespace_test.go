@@ -0,0 +1,57 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate_test
+
+import (
+	managerpredicate "github.com/gardener/gardener-resource-manager/pkg/predicate"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("#InNamespace", func() {
+	var service *corev1.Service
+
+	BeforeEach(func() {
+		service = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring"}}
+	})
+
+	It("should match objects in the given namespaces", func() {
+		predicate := managerpredicate.InNamespace("logging", "monitoring")
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &service.ObjectMeta, Object: service})).To(BeTrue())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &service.ObjectMeta, ObjectOld: service, MetaNew: &service.ObjectMeta, ObjectNew: service})).To(BeTrue())
+		Expect(predicate.Delete(event.DeleteEvent{Meta: &service.ObjectMeta, Object: service})).To(BeTrue())
+		Expect(predicate.Generic(event.GenericEvent{Meta: &service.ObjectMeta, Object: service})).To(BeTrue())
+	})
+
+	It("should not match objects in other namespaces", func() {
+		predicate := managerpredicate.InNamespace("logging")
+
+		Expect(predicate.Create(event.CreateEvent{Meta: &service.ObjectMeta, Object: service})).To(BeFalse())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: &service.ObjectMeta, ObjectOld: service, MetaNew: &service.ObjectMeta, ObjectNew: service})).To(BeFalse())
+	})
+
+	It("should match objects in all namespaces if no namespace is given", func() {
+		Expect(managerpredicate.InNamespace().Create(event.CreateEvent{Meta: &service.ObjectMeta, Object: service})).To(BeTrue())
+	})
+
+	It("should not match events without metadata", func() {
+		Expect(managerpredicate.InNamespace().Create(event.CreateEvent{Object: service})).To(BeFalse())
+	})
+})
@@ -0,0 +1,214 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake contains an in-memory client for the target cluster recording all write operations, so that unit
+// tests can assert which objects are applied and deleted in which order without an API server.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Verb is the verb of a write operation.
+type Verb string
+
+// These are the verbs of the recorded write operations.
+const (
+	VerbCreate       Verb = "create"
+	VerbUpdate       Verb = "update"
+	VerbPatch        Verb = "patch"
+	VerbDelete       Verb = "delete"
+	VerbDeleteAllOf  Verb = "deleteallof"
+	VerbUpdateStatus Verb = "update-status"
+	VerbPatchStatus  Verb = "patch-status"
+)
+
+// Operation is a write operation issued with a fake Client.
+type Operation struct {
+	Verb      Verb
+	Kind      schema.GroupVersionKind
+	Namespace string
+	Name      string
+}
+
+// String returns the operation in the form `<verb> <apiVersion> <kind> [<namespace>/]<name>`, e.g.
+// `create apps/v1 Deployment kube-system/foo`.
+func (o Operation) String() string {
+	name := o.Name
+	if o.Namespace != "" {
+		name = o.Namespace + "/" + o.Name
+	}
+	apiVersion, kind := o.Kind.ToAPIVersionAndKind()
+	return fmt.Sprintf("%s %s %s %s", o.Verb, apiVersion, kind, name)
+}
+
+type failure struct {
+	verb      Verb
+	groupKind schema.GroupKind
+	namespace string
+	name      string
+}
+
+// Client is an in-memory client recording all write operations. Reads are served from memory and are not recorded.
+// Failures of individual operations can be injected with FailOn. It is safe for concurrent use, the operations are
+// recorded in the order in which they are issued.
+type Client struct {
+	client.Client
+
+	scheme     *runtime.Scheme
+	lock       sync.Mutex
+	operations []Operation
+	failures   map[failure]error
+}
+
+// NewClient returns a new fake client for the given scheme containing the given objects. Creating the initial objects
+// is not recorded.
+func NewClient(scheme *runtime.Scheme, objs ...runtime.Object) *Client {
+	return &Client{
+		Client:   fakeclient.NewFakeClientWithScheme(scheme, objs...),
+		scheme:   scheme,
+		failures: map[failure]error{},
+	}
+}
+
+// Operations returns the recorded write operations.
+func (c *Client) Operations() []Operation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]Operation(nil), c.operations...)
+}
+
+// OperationStrings returns the recorded write operations in their string form, e.g. for comparing them with an
+// expected sequence.
+func (c *Client) OperationStrings() []string {
+	var out []string
+	for _, operation := range c.Operations() {
+		out = append(out, operation.String())
+	}
+	return out
+}
+
+// Reset forgets the recorded write operations.
+func (c *Client) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.operations = nil
+}
+
+// FailOn makes all operations with the given verb on the object of the given kind, namespace and name fail with the
+// given error. A nil error removes the failure again. Failed operations are recorded as well.
+func (c *Client) FailOn(verb Verb, groupKind schema.GroupKind, namespace, name string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := failure{verb, groupKind, namespace, name}
+	if err == nil {
+		delete(c.failures, key)
+		return
+	}
+	c.failures[key] = err
+}
+
+func (c *Client) record(verb Verb, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.operations = append(c.operations, Operation{Verb: verb, Kind: gvk, Namespace: accessor.GetNamespace(), Name: accessor.GetName()})
+	return c.failures[failure{verb, gvk.GroupKind(), accessor.GetNamespace(), accessor.GetName()}]
+}
+
+// Create implements client.Client.
+func (c *Client) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	if err := c.record(VerbCreate, obj); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// Update implements client.Client.
+func (c *Client) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	if err := c.record(VerbUpdate, obj); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// Patch implements client.Client.
+func (c *Client) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.record(VerbPatch, obj); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// Delete implements client.Client.
+func (c *Client) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	if err := c.record(VerbDelete, obj); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf implements client.Client.
+func (c *Client) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
+	if err := c.record(VerbDeleteAllOf, obj); err != nil {
+		return err
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Status implements client.Client.
+func (c *Client) Status() client.StatusWriter {
+	return &statusWriter{c}
+}
+
+type statusWriter struct {
+	client *Client
+}
+
+// Update implements client.StatusWriter.
+func (w *statusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	if err := w.client.record(VerbUpdateStatus, obj); err != nil {
+		return err
+	}
+	return w.client.Client.Status().Update(ctx, obj, opts...)
+}
+
+// Patch implements client.StatusWriter.
+func (w *statusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := w.client.record(VerbPatchStatus, obj); err != nil {
+		return err
+	}
+	return w.client.Client.Status().Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/test/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		ctx       = context.TODO()
+		c         *Client
+		configMap *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing"}}
+		c = NewClient(scheme.Scheme, configMap.DeepCopy())
+	})
+
+	It("should record all write operations in order", func() {
+		newConfigMap := &unstructured.Unstructured{}
+		newConfigMap.SetAPIVersion("v1")
+		newConfigMap.SetKind("ConfigMap")
+		newConfigMap.SetNamespace("default")
+		newConfigMap.SetName("new")
+
+		Expect(c.Create(ctx, newConfigMap)).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "existing"}, configMap)).To(Succeed())
+		configMap.Data = map[string]string{"foo": "bar"}
+		Expect(c.Update(ctx, configMap)).To(Succeed())
+		patch := client.MergeFrom(configMap.DeepCopy())
+		configMap.Labels = map[string]string{"foo": "bar"}
+		Expect(c.Patch(ctx, configMap, patch)).To(Succeed())
+		Expect(c.Delete(ctx, newConfigMap)).To(Succeed())
+
+		Expect(c.OperationStrings()).To(Equal([]string{
+			"create v1 ConfigMap default/new",
+			"update v1 ConfigMap default/existing",
+			"patch v1 ConfigMap default/existing",
+			"delete v1 ConfigMap default/new",
+		}))
+		Expect(c.Operations()[0]).To(Equal(Operation{Verb: VerbCreate, Kind: corev1.SchemeGroupVersion.WithKind("ConfigMap"), Namespace: "default", Name: "new"}))
+
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "existing"}, configMap)).To(Succeed())
+		Expect(configMap.Data).To(Equal(map[string]string{"foo": "bar"}))
+		Expect(configMap.Labels).To(Equal(map[string]string{"foo": "bar"}))
+
+		c.Reset()
+		Expect(c.Operations()).To(BeEmpty())
+	})
+
+	It("should fail the operations with injected failures", func() {
+		err := errors.New("fake")
+		c.FailOn(VerbDelete, schema.GroupKind{Kind: "ConfigMap"}, "default", "existing", err)
+
+		Expect(c.Delete(ctx, configMap)).To(MatchError(err))
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "existing"}, &corev1.ConfigMap{})).To(Succeed())
+
+		c.FailOn(VerbDelete, schema.GroupKind{Kind: "ConfigMap"}, "default", "existing", nil)
+		Expect(c.Delete(ctx, configMap)).To(Succeed())
+		Expect(c.OperationStrings()).To(Equal([]string{
+			"delete v1 ConfigMap default/existing",
+			"delete v1 ConfigMap default/existing",
+		}))
+	})
+})
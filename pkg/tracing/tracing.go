@@ -0,0 +1,89 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/label"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+// TracerName is the name of the tracer used for instrumenting the gardener-resource-manager.
+const TracerName = "gardener-resource-manager"
+
+// Tracer returns the tracer used for instrumenting the controllers. As long as Setup has not been called,
+// all created spans are no-ops.
+func Tracer() trace.Tracer {
+	return global.Tracer(TracerName)
+}
+
+// Setup configures the global tracer provider to export spans via OTLP (gRPC) to the collector listening on the
+// given address. Only the given ratio of traces is sampled. It returns a function that flushes all pending spans
+// and shuts down the exporter.
+func Setup(address string, insecure bool, samplingRatio float64) (func(context.Context) error, error) {
+	opts := []otlp.ExporterOption{otlp.WithAddress(address)}
+	if insecure {
+		opts = append(opts, otlp.WithInsecure())
+	}
+
+	exporter, err := otlp.NewExporter(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create OTLP exporter: %w", err)
+	}
+
+	return setup(exporter, samplingRatio), nil
+}
+
+func setup(exporter export.SpanExporter, samplingRatio float64) func(context.Context) error {
+	bsp := sdktrace.NewBatchSpanProcessor(exporter)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRatio))}),
+		sdktrace.WithResource(sdkresource.New(semconv.ServiceNameKey.String(TracerName))),
+		sdktrace.WithSpanProcessor(bsp),
+	)
+	global.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		// unregistering shuts down the batch span processor, which exports the spans still queued
+		provider.UnregisterSpanProcessor(bsp)
+		return exporter.Shutdown(ctx)
+	}
+}
+
+// EndSpan records the given error (if any) on the span and ends it.
+func EndSpan(ctx context.Context, span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(ctx, err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ObjectAttributes returns the span attributes identifying an object by its namespace and name.
+func ObjectAttributes(namespace, name string) []label.KeyValue {
+	return []label.KeyValue{
+		label.String("namespace", namespace),
+		label.String("name", name),
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+// spanRecorder records the names of all exported spans.
+type spanRecorder struct {
+	lock     sync.Mutex
+	names    []string
+	shutdown bool
+}
+
+func (r *spanRecorder) ExportSpans(_ context.Context, spans []*export.SpanData) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, span := range spans {
+		r.names = append(r.names, span.Name)
+	}
+	return nil
+}
+
+func (r *spanRecorder) Shutdown(_ context.Context) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.shutdown = true
+	return nil
+}
+
+var _ = Describe("Setup", func() {
+	var provider trace.TracerProvider
+
+	BeforeEach(func() {
+		provider = global.TracerProvider()
+	})
+
+	AfterEach(func() {
+		global.SetTracerProvider(provider)
+	})
+
+	It("should export spans ended just before shutting down", func() {
+		recorder := &spanRecorder{}
+		shutdown := setup(recorder, 1)
+
+		_, span := Tracer().Start(context.TODO(), "reconcile")
+		span.End()
+
+		Expect(shutdown(context.TODO())).To(Succeed())
+		Expect(recorder.names).To(Equal([]string{"reconcile"}))
+		Expect(recorder.shutdown).To(BeTrue())
+	})
+})
@@ -0,0 +1,201 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package update contains helpers for writing objects with consistent handling of conflicts, so that all controllers
+// retry conflicting writes the same way.
+package update
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Strategy is the way a transformed object is written.
+type Strategy string
+
+const (
+	// StrategyUpdate updates the whole object. The update conflicts with all concurrent changes of the object.
+	StrategyUpdate Strategy = "Update"
+	// StrategyMergePatch patches the object with a JSON merge patch of the changes of the transformation. The patch
+	// only conflicts with concurrent changes if Options.OptimisticLock is set.
+	StrategyMergePatch Strategy = "MergePatch"
+	// StrategyApply applies the object with server-side apply. The object is not read before the transformation, which
+	// has to set all fields owned by Options.FieldOwner as well as the API version and kind. The apply only conflicts
+	// with concurrent changes if Options.OptimisticLock is set, or with fields of other owners unless
+	// Options.ForceOwnership is set.
+	StrategyApply Strategy = "Apply"
+)
+
+// Options configures how Try writes an object.
+type Options struct {
+	// Strategy is the way the object is written, StrategyUpdate if not set.
+	Strategy Strategy
+	// Status writes the status subresource of the object instead of the object itself.
+	Status bool
+	// OptimisticLock makes patches and applies conflict with concurrent changes of the object, by sending the
+	// resource version of the object as well.
+	OptimisticLock bool
+	// FieldOwner is the field manager of the write, it is required for StrategyApply.
+	FieldOwner string
+	// ForceOwnership takes the ownership of fields owned by other field managers with StrategyApply.
+	ForceOwnership bool
+	// Backoff is the backoff in which retriable errors are retried, retry.DefaultBackoff if Steps is not set.
+	Backoff wait.Backoff
+	// Retriable decides whether a write error is retried, apierrors.IsConflict if not set.
+	Retriable func(error) bool
+	// Skip is evaluated with the current state of the object before the transformation. If it returns true, the
+	// object is neither transformed nor written. It is ignored for StrategyApply.
+	Skip func(obj runtime.Object) bool
+}
+
+// Try reads the given object, applies the given transformation function onto it and writes it afterwards if the
+// transformation changed it. Writes failing with a retriable error (by default conflicts) are retried with the
+// current state of the object in an exponential backoff. If all retries fail, the last error is returned.
+func Try(ctx context.Context, c client.Client, obj runtime.Object, transform func() error, opts Options) error {
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return err
+	}
+
+	backoff := opts.Backoff
+	if backoff.Steps == 0 {
+		backoff = retry.DefaultBackoff
+	}
+	retriable := opts.Retriable
+	if retriable == nil {
+		retriable = apierrors.IsConflict
+	}
+
+	var lastErr error
+	err = exponentialBackoff(ctx, backoff, func() (bool, error) {
+		if opts.Strategy != StrategyApply {
+			if err := c.Get(ctx, key, obj); err != nil {
+				return false, err
+			}
+			if opts.Skip != nil && opts.Skip(obj) {
+				return true, nil
+			}
+		}
+
+		before := obj.DeepCopyObject()
+		if err := transform(); err != nil {
+			return false, err
+		}
+
+		if opts.Strategy != StrategyApply && reflect.DeepEqual(obj, before) {
+			return true, nil
+		}
+
+		if err := write(ctx, c, obj, before, opts); err != nil {
+			if retriable(err) {
+				lastErr = err
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+func write(ctx context.Context, c client.Client, obj, before runtime.Object, opts Options) error {
+	switch opts.Strategy {
+	case "", StrategyUpdate:
+		if opts.Status {
+			return c.Status().Update(ctx, obj)
+		}
+		return c.Update(ctx, obj)
+
+	case StrategyMergePatch:
+		if opts.OptimisticLock {
+			// the resource version is only part of the patch if it differs from the base
+			accessor, err := meta.Accessor(before)
+			if err != nil {
+				return err
+			}
+			accessor.SetResourceVersion("")
+		}
+		patch := client.MergeFrom(before)
+		if opts.Status {
+			return c.Status().Patch(ctx, obj, patch)
+		}
+		return c.Patch(ctx, obj, patch)
+
+	case StrategyApply:
+		if opts.FieldOwner == "" {
+			return fmt.Errorf("a field owner is required for strategy %s", StrategyApply)
+		}
+
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		if !opts.OptimisticLock {
+			accessor.SetResourceVersion("")
+		}
+		accessor.SetManagedFields(nil)
+
+		patchOpts := []client.PatchOption{client.FieldOwner(opts.FieldOwner)}
+		if opts.ForceOwnership {
+			patchOpts = append(patchOpts, client.ForceOwnership)
+		}
+		if opts.Status {
+			return c.Status().Patch(ctx, obj, client.Apply, patchOpts...)
+		}
+		return c.Patch(ctx, obj, client.Apply, patchOpts...)
+
+	default:
+		return fmt.Errorf("unknown update strategy %q", opts.Strategy)
+	}
+}
+
+func exponentialBackoff(ctx context.Context, backoff wait.Backoff, condition wait.ConditionFunc) error {
+	duration := backoff.Duration
+
+	for i := 0; i < backoff.Steps; i++ {
+		if ok, err := condition(); err != nil || ok {
+			return err
+		}
+
+		if i == backoff.Steps-1 {
+			break
+		}
+
+		adjusted := duration
+		if backoff.Jitter > 0.0 {
+			adjusted = wait.Jitter(duration, backoff.Jitter)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(adjusted):
+		}
+		duration = time.Duration(float64(duration) * backoff.Factor)
+	}
+
+	return wait.ErrWaitTimeout
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package update_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+	"github.com/gardener/gardener-resource-manager/pkg/test/fake"
+	. "github.com/gardener/gardener-resource-manager/pkg/update"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Update", func() {
+	var (
+		ctx       = context.TODO()
+		configMap *corev1.ConfigMap
+		conflict  = apierrors.NewConflict(corev1.Resource("configmaps"), "foo", errors.New("fake"))
+		backoff   = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+	)
+
+	BeforeEach(func() {
+		configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", ResourceVersion: "1"}}
+	})
+
+	setData := func(obj *corev1.ConfigMap) func() error {
+		return func() error {
+			obj.Data = map[string]string{"foo": "bar"}
+			return nil
+		}
+	}
+
+	Context("with an in-memory client", func() {
+		var c *fake.Client
+
+		BeforeEach(func() {
+			c = fake.NewClient(scheme.Scheme, configMap.DeepCopy())
+		})
+
+		It("should update the transformed object", func() {
+			Expect(Try(ctx, c, configMap, setData(configMap), Options{})).To(Succeed())
+			Expect(c.OperationStrings()).To(Equal([]string{"update v1 ConfigMap default/foo"}))
+
+			current := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, current)).To(Succeed())
+			Expect(current.Data).To(Equal(map[string]string{"foo": "bar"}))
+		})
+
+		It("should not write unchanged objects", func() {
+			Expect(Try(ctx, c, configMap, setData(configMap), Options{})).To(Succeed())
+			c.Reset()
+
+			Expect(Try(ctx, c, configMap, setData(configMap), Options{})).To(Succeed())
+			Expect(c.Operations()).To(BeEmpty())
+		})
+
+		It("should skip objects matching the predicate", func() {
+			skip := func(obj runtime.Object) bool { return obj.(*corev1.ConfigMap).ResourceVersion != "" }
+
+			Expect(Try(ctx, c, configMap, func() error {
+				Fail("transformation must not be called")
+				return nil
+			}, Options{Skip: skip})).To(Succeed())
+			Expect(c.Operations()).To(BeEmpty())
+		})
+
+		It("should patch the transformed object", func() {
+			Expect(Try(ctx, c, configMap, setData(configMap), Options{Strategy: StrategyMergePatch})).To(Succeed())
+			Expect(c.OperationStrings()).To(Equal([]string{"patch v1 ConfigMap default/foo"}))
+		})
+
+		It("should write the status", func() {
+			Expect(Try(ctx, c, configMap, setData(configMap), Options{Status: true})).To(Succeed())
+			Expect(Try(ctx, c, configMap, func() error {
+				configMap.Data = nil
+				return nil
+			}, Options{Status: true, Strategy: StrategyMergePatch})).To(Succeed())
+			Expect(c.OperationStrings()).To(Equal([]string{"update-status v1 ConfigMap default/foo", "patch-status v1 ConfigMap default/foo"}))
+		})
+
+		It("should return the errors of the transformation", func() {
+			Expect(Try(ctx, c, configMap, func() error { return errors.New("fake") }, Options{})).To(MatchError("fake"))
+			Expect(c.Operations()).To(BeEmpty())
+		})
+
+		It("should fail for unknown strategies", func() {
+			Expect(Try(ctx, c, configMap, setData(configMap), Options{Strategy: "Foo"})).To(MatchError(ContainSubstring(`unknown update strategy "Foo"`)))
+		})
+	})
+
+	Context("with a mock client", func() {
+		var (
+			ctrl *gomock.Controller
+			c    *mockclient.MockClient
+			obj  *corev1.ConfigMap
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			c = mockclient.NewMockClient(ctrl)
+			obj = configMap.DeepCopy()
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		expectGet := func() *gomock.Call {
+			return c.EXPECT().Get(ctx, types.NamespacedName{Namespace: "default", Name: "foo"}, gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+				DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *corev1.ConfigMap) error {
+					configMap.DeepCopyInto(obj)
+					return nil
+				})
+		}
+
+		It("should retry conflicts with the current state of the object", func() {
+			gomock.InOrder(
+				expectGet(),
+				c.EXPECT().Update(ctx, gomock.Any()).Return(conflict),
+				expectGet(),
+				c.EXPECT().Update(ctx, gomock.Any()),
+			)
+
+			Expect(Try(ctx, c, obj, setData(obj), Options{Backoff: backoff})).To(Succeed())
+		})
+
+		It("should return the last conflict if all retries fail", func() {
+			expectGet().Times(3)
+			c.EXPECT().Update(ctx, gomock.Any()).Return(conflict).Times(3)
+
+			err := Try(ctx, c, obj, setData(obj), Options{Backoff: backoff})
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+		})
+
+		It("should retry errors of the given retry policy", func() {
+			gomock.InOrder(
+				expectGet(),
+				c.EXPECT().Update(ctx, gomock.Any()).Return(apierrors.NewTooManyRequestsError("fake")),
+				expectGet(),
+				c.EXPECT().Update(ctx, gomock.Any()),
+			)
+
+			Expect(Try(ctx, c, obj, setData(obj), Options{Backoff: backoff, Retriable: apierrors.IsTooManyRequests})).To(Succeed())
+		})
+
+		It("should not retry other errors", func() {
+			expectGet()
+			c.EXPECT().Update(ctx, gomock.Any()).Return(errors.New("fake"))
+
+			Expect(Try(ctx, c, obj, setData(obj), Options{Backoff: backoff})).To(MatchError("fake"))
+		})
+
+		It("should include the resource version in patches with optimistic lock", func() {
+			expectGet()
+			c.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj runtime.Object, patch client.Patch, _ ...client.PatchOption) error {
+				data, err := patch.Data(obj)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(Equal(`{"data":{"foo":"bar"},"metadata":{"resourceVersion":"1"}}`))
+				return nil
+			})
+
+			Expect(Try(ctx, c, obj, setData(obj), Options{Strategy: StrategyMergePatch, OptimisticLock: true})).To(Succeed())
+		})
+
+		It("should apply the transformed object without reading it", func() {
+			c.EXPECT().Patch(ctx, gomock.Any(), client.Apply, client.FieldOwner("test"), client.ForceOwnership).DoAndReturn(func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+				Expect(obj.(*corev1.ConfigMap).ResourceVersion).To(BeEmpty())
+				return nil
+			})
+
+			Expect(Try(ctx, c, configMap, setData(configMap), Options{Strategy: StrategyApply, FieldOwner: "test", ForceOwnership: true})).To(Succeed())
+		})
+
+		It("should require a field owner for applies", func() {
+			Expect(Try(ctx, c, configMap, setData(configMap), Options{Strategy: StrategyApply})).To(MatchError(ContainSubstring("a field owner is required")))
+		})
+	})
+})
@@ -14,5 +14,36 @@
 
 package version
 
-// Version is a string that is overwritten during build time using ld flags.
-var Version = "binary not built correctly"
+import (
+	"fmt"
+	"runtime"
+)
+
+var (
+	// Version is a string that is overwritten during build time using ld flags.
+	Version = "binary not built correctly"
+	// GitCommit is the SHA of the commit the binary was built from, overwritten during build time using ld flags.
+	GitCommit = ""
+	// BuildDate is the date the binary was built at in RFC 3339 format, overwritten during build time using ld flags.
+	BuildDate = ""
+)
+
+// Info describes the build of the binary.
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build information of the binary.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificates
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+const (
+	// caValidity is the validity of a generated CA certificate.
+	caValidity = 2 * 365 * 24 * time.Hour
+	// servingCertValidity is the validity of a generated serving certificate.
+	servingCertValidity = 365 * 24 * time.Hour
+	// renewBefore is the remaining validity below which certificates are renewed.
+	renewBefore = 30 * 24 * time.Hour
+)
+
+// keyPair is a certificate together with its private key.
+type keyPair struct {
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func generateCA(now time.Time) (*keyPair, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("gardener-resource-manager-webhook-ca@%d", now.Unix())},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return generate(template, nil)
+}
+
+func generateServingCert(ca *keyPair, dnsNames []string, now time.Time) (*keyPair, error) {
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(servingCertValidity),
+		KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return generate(template, ca)
+}
+
+// generate creates a new key and a certificate for the given template, which is signed by the given CA or self-signed
+// if no CA is given.
+func generate(template *x509.Certificate, ca *keyPair) (*keyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate private key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
+	if err != nil {
+		return nil, fmt.Errorf("could not generate serial number: %w", err)
+	}
+	template.SerialNumber = serial
+
+	parent, signer := template, key
+	if ca != nil {
+		parent, signer = ca.cert, ca.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, fmt.Errorf("could not create certificate: %w", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse certificate: %w", err)
+	}
+
+	return &keyPair{
+		cert:    certificate,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: keyutil.RSAPrivateKeyBlockType, Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}, nil
+}
+
+// parseKeyPair parses the given PEM encoded certificate and key. If the certificate data contains multiple
+// certificates, the first one is used.
+func parseKeyPair(certPEM, keyPEM []byte) (*keyPair, error) {
+	certs, err := cert.ParseCertsPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keyutil.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return &keyPair{
+		cert:    certs[0],
+		key:     rsaKey,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: certs[0].Raw}),
+		keyPEM:  keyPEM,
+	}, nil
+}
+
+// needsRenewal returns true if the given certificate expires soon or does not match the given DNS names.
+func needsRenewal(certificate *x509.Certificate, dnsNames []string, now time.Time) bool {
+	if now.Add(renewBefore).After(certificate.NotAfter) {
+		return true
+	}
+	return dnsNames != nil && !sets.NewString(certificate.DNSNames...).Equal(sets.NewString(dnsNames...))
+}
+
+// caBundle returns the PEM encoded bundle of the given CA and all still valid certificates of the given old bundle.
+// Keeping the old CA certificates in the bundle allows webhook servers still serving a certificate signed by the old
+// CA to be trusted until they picked up the new one.
+func caBundle(ca *keyPair, oldBundle []byte, now time.Time) []byte {
+	bundle := append([]byte{}, ca.certPEM...)
+
+	oldCerts, err := cert.ParseCertsPEM(oldBundle)
+	if err != nil {
+		return bundle
+	}
+	for _, c := range oldCerts {
+		if c.Equal(ca.cert) || now.After(c.NotAfter) {
+			continue
+		}
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: c.Raw})...)
+	}
+	return bundle
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificates
+
+import (
+	"crypto/x509"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/cert"
+)
+
+var _ = Describe("Certificates", func() {
+	var (
+		now      = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		dnsNames = []string{"gardener-resource-manager.garden.svc", "gardener-resource-manager.garden.svc.cluster.local"}
+	)
+
+	It("should generate a serving certificate signed by the CA", func() {
+		ca, err := generateCA(now)
+		Expect(err).NotTo(HaveOccurred())
+		servingCert, err := generateServingCert(ca, dnsNames, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		pool := x509.NewCertPool()
+		pool.AddCert(ca.cert)
+		_, err = servingCert.cert.Verify(x509.VerifyOptions{
+			DNSName:     dnsNames[1],
+			Roots:       pool,
+			CurrentTime: now,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should parse generated key pairs", func() {
+		ca, err := generateCA(now)
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed, err := parseKeyPair(ca.certPEM, ca.keyPEM)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.cert.Equal(ca.cert)).To(BeTrue())
+		Expect(parsed.key.Equal(ca.key)).To(BeTrue())
+	})
+
+	Describe("#needsRenewal", func() {
+		var servingCert *keyPair
+
+		BeforeEach(func() {
+			ca, err := generateCA(now)
+			Expect(err).NotTo(HaveOccurred())
+			servingCert, err = generateServingCert(ca, dnsNames, now)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not renew valid certificates", func() {
+			Expect(needsRenewal(servingCert.cert, dnsNames, now)).To(BeFalse())
+			Expect(needsRenewal(servingCert.cert, nil, now)).To(BeFalse())
+		})
+
+		It("should renew certificates expiring soon", func() {
+			Expect(needsRenewal(servingCert.cert, dnsNames, now.Add(servingCertValidity-renewBefore+time.Minute))).To(BeTrue())
+		})
+
+		It("should renew certificates with different DNS names", func() {
+			Expect(needsRenewal(servingCert.cert, dnsNames[:1], now)).To(BeTrue())
+		})
+	})
+
+	Describe("#caBundle", func() {
+		It("should keep valid old CAs and drop expired ones", func() {
+			expiredCA, err := generateCA(now.Add(-caValidity - time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			oldCA, err := generateCA(now.Add(-time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			newCA, err := generateCA(now)
+			Expect(err).NotTo(HaveOccurred())
+
+			oldBundle := append(append([]byte{}, oldCA.certPEM...), expiredCA.certPEM...)
+
+			certs, err := cert.ParseCertsPEM(caBundle(newCA, oldBundle, now))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(certs).To(HaveLen(2))
+			Expect(certs[0].Equal(newCA.cert)).To(BeTrue())
+			Expect(certs[1].Equal(oldCA.cert)).To(BeTrue())
+		})
+
+		It("should not duplicate the current CA", func() {
+			ca, err := generateCA(now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(caBundle(ca, ca.certPEM, now)).To(Equal(ca.certPEM))
+		})
+	})
+})
@@ -0,0 +1,264 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	"github.com/gardener/gardener-resource-manager/pkg/update"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// InjectCABundleLabel is a label on MutatingWebhookConfigurations and ValidatingWebhookConfigurations. If set to
+	// true, the CA bundle of the self-managed webhook serving certificates is injected into all of their webhooks.
+	InjectCABundleLabel = "resources.gardener.cloud/inject-ca-bundle"
+
+	dataKeyCABundle = "ca.crt"
+	dataKeyCAKey    = "ca.key"
+	dataKeyCert     = corev1.TLSCertKey
+	dataKeyKey      = corev1.TLSPrivateKeyKey
+
+	syncPeriod = 5 * time.Minute
+)
+
+// Manager generates the serving certificate of the webhook server as well as the CA signing it, rotates them before
+// they expire, and injects the CA bundle into all webhook configurations labeled with `InjectCABundleLabel`.
+// The certificates are stored in a secret, so that all instances of the gardener-resource-manager serve the same
+// certificate.
+type Manager struct {
+	log            logr.Logger
+	client         client.Client
+	secretKey      types.NamespacedName
+	dnsNames       []string
+	certDir        string
+	webhookClients []client.Client
+
+	now func() time.Time
+}
+
+var _ manager.Runnable = &Manager{}
+
+// NewManager creates a new Manager storing the certificates in the secret with the given key using the given client.
+// The serving certificate is valid for the given DNS names and written to `tls.crt` and `tls.key` in the given
+// directory. The CA bundle is injected into the webhook configurations found with the given webhook clients.
+func NewManager(log logr.Logger, c client.Client, secretKey types.NamespacedName, dnsNames []string, certDir string, webhookClients ...client.Client) *Manager {
+	return &Manager{
+		log:            log,
+		client:         c,
+		secretKey:      secretKey,
+		dnsNames:       dnsNames,
+		certDir:        certDir,
+		webhookClients: webhookClients,
+		now:            time.Now,
+	}
+}
+
+// Start implements `manager.Runnable` and periodically syncs the certificates until the stop channel is closed.
+func (m *Manager) Start(stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	wait.Until(func() {
+		if err := m.Sync(ctx); err != nil {
+			m.log.Error(err, "Could not sync webhook certificates")
+		}
+	}, syncPeriod, stop)
+	return nil
+}
+
+// NeedLeaderElection implements `manager.LeaderElectionRunnable`, all instances serve webhooks and need certificates.
+func (m *Manager) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync ensures that valid certificates are stored in the secret and in the certificate directory and that the CA
+// bundle is injected into all labeled webhook configurations.
+func (m *Manager) Sync(ctx context.Context) error {
+	var secret *corev1.Secret
+
+	// concurrently starting instances may try to create the secret at the same time, so retry with the stored state
+	if err := retry.OnError(retry.DefaultBackoff, apierrors.IsAlreadyExists, func() error {
+		var err error
+		secret, err = m.ensureSecret(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := m.writeFiles(secret.Data[dataKeyCert], secret.Data[dataKeyKey]); err != nil {
+		return err
+	}
+
+	for _, c := range m.webhookClients {
+		if err := injectCABundle(ctx, c, secret.Data[dataKeyCABundle]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	var (
+		now    = m.now()
+		secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: m.secretKey.Namespace, Name: m.secretKey.Name}}
+	)
+
+	// the certificates are renewed with the current state of the secret if another instance updated it concurrently
+	err := update.Try(ctx, m.client, secret, func() error {
+		return m.renewCertificates(secret, now)
+	}, update.Options{})
+	if err == nil {
+		return secret, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("could not update webhook certificate secret: %w", err)
+	}
+
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: m.secretKey.Namespace, Name: m.secretKey.Name},
+		Type:       corev1.SecretTypeOpaque,
+	}
+	if err := m.renewCertificates(secret, now); err != nil {
+		return nil, err
+	}
+	return secret, m.client.Create(ctx, secret)
+}
+
+// renewCertificates generates the CA and the serving certificate in the data of the given secret if they are missing,
+// invalid or expire soon.
+func (m *Manager) renewCertificates(secret *corev1.Secret, now time.Time) error {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	caRenewed := false
+
+	ca, err := parseKeyPair(secret.Data[dataKeyCABundle], secret.Data[dataKeyCAKey])
+	if err != nil || needsRenewal(ca.cert, nil, now) {
+		m.log.Info("Generating new webhook CA")
+		if ca, err = generateCA(now); err != nil {
+			return err
+		}
+		secret.Data[dataKeyCABundle] = caBundle(ca, secret.Data[dataKeyCABundle], now)
+		secret.Data[dataKeyCAKey] = ca.keyPEM
+		caRenewed = true
+	}
+
+	servingCert, err := parseKeyPair(secret.Data[dataKeyCert], secret.Data[dataKeyKey])
+	if caRenewed || err != nil || needsRenewal(servingCert.cert, m.dnsNames, now) {
+		m.log.Info("Generating new webhook serving certificate", "dnsNames", m.dnsNames)
+		if servingCert, err = generateServingCert(ca, m.dnsNames, now); err != nil {
+			return err
+		}
+		secret.Data[dataKeyCert] = servingCert.certPEM
+		secret.Data[dataKeyKey] = servingCert.keyPEM
+	}
+	return nil
+}
+
+// writeFiles writes the given certificate and key to the certificate directory if they differ from the current files.
+// The webhook server watches the files and reloads the certificate on changes.
+func (m *Manager) writeFiles(certPEM, keyPEM []byte) error {
+	if err := os.MkdirAll(m.certDir, 0700); err != nil {
+		return fmt.Errorf("could not create webhook certificate directory: %w", err)
+	}
+
+	for name, data := range map[string][]byte{dataKeyKey: keyPEM, dataKeyCert: certPEM} {
+		path := filepath.Join(m.certDir, name)
+		if current, err := ioutil.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			continue
+		}
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			return fmt.Errorf("could not write webhook certificate file: %w", err)
+		}
+	}
+	return nil
+}
+
+// injectCABundle injects the given CA bundle into all labeled webhook configurations found with the given client.
+func injectCABundle(ctx context.Context, c client.Client, bundle []byte) error {
+	selector := client.MatchingLabels{InjectCABundleLabel: "true"}
+
+	mutatingList := &admissionregistrationv1beta1.MutatingWebhookConfigurationList{}
+	if err := utils.ListPages(ctx, c, mutatingList, utils.DefaultPageSize, func() error {
+		for _, config := range mutatingList.Items {
+			patch := client.MergeFrom(config.DeepCopy())
+			changed := false
+			for i := range config.Webhooks {
+				changed = setCABundle(&config.Webhooks[i].ClientConfig, bundle) || changed
+			}
+			if changed {
+				if err := c.Patch(ctx, &config, patch); err != nil {
+					return fmt.Errorf("could not inject CA bundle into mutating webhook configuration %s: %w", config.Name, err)
+				}
+			}
+		}
+		return nil
+	}, selector); err != nil {
+		return fmt.Errorf("could not inject CA bundle into mutating webhook configurations: %w", err)
+	}
+
+	validatingList := &admissionregistrationv1beta1.ValidatingWebhookConfigurationList{}
+	if err := utils.ListPages(ctx, c, validatingList, utils.DefaultPageSize, func() error {
+		for _, config := range validatingList.Items {
+			patch := client.MergeFrom(config.DeepCopy())
+			changed := false
+			for i := range config.Webhooks {
+				changed = setCABundle(&config.Webhooks[i].ClientConfig, bundle) || changed
+			}
+			if changed {
+				if err := c.Patch(ctx, &config, patch); err != nil {
+					return fmt.Errorf("could not inject CA bundle into validating webhook configuration %s: %w", config.Name, err)
+				}
+			}
+		}
+		return nil
+	}, selector); err != nil {
+		return fmt.Errorf("could not inject CA bundle into validating webhook configurations: %w", err)
+	}
+
+	return nil
+}
+
+func setCABundle(clientConfig *admissionregistrationv1beta1.WebhookClientConfig, bundle []byte) bool {
+	if bytes.Equal(clientConfig.CABundle, bundle) {
+		return false
+	}
+	clientConfig.CABundle = bundle
+	return true
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificates
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gardener/gardener-resource-manager/pkg/controller/utils"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Manager", func() {
+	var (
+		ctx       = context.TODO()
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		certDir   string
+		secretKey = types.NamespacedName{Namespace: "garden", Name: "webhook-certs"}
+		now       = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		m         *Manager
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+
+		var err error
+		certDir, err = ioutil.TempDir("", "webhook-certs")
+		Expect(err).NotTo(HaveOccurred())
+
+		m = NewManager(runtimelog.NullLogger{}, c, secretKey, []string{"gardener-resource-manager.garden.svc"}, certDir, c)
+		m.now = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+		Expect(os.RemoveAll(certDir)).To(Succeed())
+	})
+
+	expectWebhookConfigurations := func(bundle []byte, mutatingBundle []byte, expectPatch bool) {
+		selector := client.MatchingLabels{InjectCABundleLabel: "true"}
+
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&admissionregistrationv1beta1.MutatingWebhookConfigurationList{}), client.Limit(utils.DefaultPageSize), selector).
+			DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+				list.(*admissionregistrationv1beta1.MutatingWebhookConfigurationList).Items = []admissionregistrationv1beta1.MutatingWebhookConfiguration{{
+					Webhooks: []admissionregistrationv1beta1.MutatingWebhook{{ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{CABundle: mutatingBundle}}},
+				}}
+				return nil
+			})
+		if expectPatch {
+			c.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&admissionregistrationv1beta1.MutatingWebhookConfiguration{}), gomock.Any()).
+				DoAndReturn(func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+					Expect(obj.(*admissionregistrationv1beta1.MutatingWebhookConfiguration).Webhooks[0].ClientConfig.CABundle).To(Equal(bundle))
+					return nil
+				})
+		}
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&admissionregistrationv1beta1.ValidatingWebhookConfigurationList{}), client.Limit(utils.DefaultPageSize), selector)
+	}
+
+	It("should generate the certificates and inject the CA bundle", func() {
+		var secret *corev1.Secret
+
+		c.EXPECT().Get(ctx, secretKey, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			Return(apierrors.NewNotFound(corev1.Resource("secrets"), secretKey.Name))
+		c.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+				secret = obj.(*corev1.Secret)
+				return nil
+			})
+		bundle := []byte{}
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&admissionregistrationv1beta1.MutatingWebhookConfigurationList{}), gomock.Any()).
+			DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+				bundle = secret.Data[dataKeyCABundle]
+				return nil
+			})
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&admissionregistrationv1beta1.ValidatingWebhookConfigurationList{}), gomock.Any())
+
+		Expect(m.Sync(ctx)).To(Succeed())
+
+		Expect(secret.Namespace).To(Equal(secretKey.Namespace))
+		Expect(secret.Name).To(Equal(secretKey.Name))
+		Expect(secret.Data).To(HaveKey(dataKeyCAKey))
+		Expect(bundle).NotTo(BeEmpty())
+
+		servingCert, err := parseKeyPair(secret.Data[dataKeyCert], secret.Data[dataKeyKey])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(servingCert.cert.DNSNames).To(Equal([]string{"gardener-resource-manager.garden.svc"}))
+
+		Expect(ioutil.ReadFile(filepath.Join(certDir, "tls.crt"))).To(Equal(secret.Data[dataKeyCert]))
+		Expect(ioutil.ReadFile(filepath.Join(certDir, "tls.key"))).To(Equal(secret.Data[dataKeyKey]))
+	})
+
+	Context("existing certificates", func() {
+		var (
+			secret    *corev1.Secret
+			expectGet = func() {
+				c.EXPECT().Get(ctx, secretKey, gomock.AssignableToTypeOf(&corev1.Secret{})).
+					DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+						secret.DeepCopyInto(obj.(*corev1.Secret))
+						return nil
+					})
+			}
+		)
+
+		BeforeEach(func() {
+			ca, err := generateCA(now)
+			Expect(err).NotTo(HaveOccurred())
+			servingCert, err := generateServingCert(ca, m.dnsNames, now)
+			Expect(err).NotTo(HaveOccurred())
+
+			secret = &corev1.Secret{Data: map[string][]byte{
+				dataKeyCABundle: ca.certPEM,
+				dataKeyCAKey:    ca.keyPEM,
+				dataKeyCert:     servingCert.certPEM,
+				dataKeyKey:      servingCert.keyPEM,
+			}}
+			expectGet()
+		})
+
+		It("should keep valid certificates and only patch outdated CA bundles", func() {
+			expectWebhookConfigurations(secret.Data[dataKeyCABundle], []byte("outdated"), true)
+			Expect(m.Sync(ctx)).To(Succeed())
+
+			expectGet()
+			expectWebhookConfigurations(secret.Data[dataKeyCABundle], secret.Data[dataKeyCABundle], false)
+			Expect(m.Sync(ctx)).To(Succeed())
+
+			Expect(ioutil.ReadFile(filepath.Join(certDir, "tls.crt"))).To(Equal(secret.Data[dataKeyCert]))
+		})
+
+		It("should renew the serving certificate but keep the CA if the serving certificate expires soon", func() {
+			m.now = func() time.Time { return now.Add(servingCertValidity - renewBefore + time.Hour) }
+
+			var updated *corev1.Secret
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+					updated = obj.(*corev1.Secret)
+					return nil
+				})
+			expectWebhookConfigurations(secret.Data[dataKeyCABundle], secret.Data[dataKeyCABundle], false)
+
+			Expect(m.Sync(ctx)).To(Succeed())
+			Expect(updated.Data[dataKeyCABundle]).To(Equal(secret.Data[dataKeyCABundle]))
+			Expect(updated.Data[dataKeyCert]).NotTo(Equal(secret.Data[dataKeyCert]))
+		})
+
+		It("should renew the serving certificate again with the current state of the secret if the update conflicts", func() {
+			m.now = func() time.Time { return now.Add(servingCertValidity - renewBefore + time.Hour) }
+
+			var updated *corev1.Secret
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				Return(apierrors.NewConflict(corev1.Resource("secrets"), secretKey.Name, errors.New("fake")))
+			expectGet()
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+					updated = obj.(*corev1.Secret)
+					return nil
+				})
+			expectWebhookConfigurations(secret.Data[dataKeyCABundle], secret.Data[dataKeyCABundle], false)
+
+			Expect(m.Sync(ctx)).To(Succeed())
+			Expect(updated.Data[dataKeyCABundle]).To(Equal(secret.Data[dataKeyCABundle]))
+			Expect(updated.Data[dataKeyCert]).NotTo(Equal(secret.Data[dataKeyCert]))
+		})
+
+		It("should renew the CA and keep the old one in the bundle if the CA expires soon", func() {
+			m.now = func() time.Time { return now.Add(caValidity - renewBefore + time.Hour) }
+
+			var updated *corev1.Secret
+			c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).
+				DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+					updated = obj.(*corev1.Secret)
+					return nil
+				})
+			newBundle := []byte{}
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&admissionregistrationv1beta1.MutatingWebhookConfigurationList{}), gomock.Any()).
+				DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+					newBundle = updated.Data[dataKeyCABundle]
+					return nil
+				})
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&admissionregistrationv1beta1.ValidatingWebhookConfigurationList{}), gomock.Any())
+
+			oldCA := secret.Data[dataKeyCABundle]
+			Expect(m.Sync(ctx)).To(Succeed())
+			Expect(newBundle).To(HaveSuffix(string(oldCA)))
+			Expect(len(newBundle)).To(BeNumerically(">", len(oldCA)))
+		})
+	})
+})
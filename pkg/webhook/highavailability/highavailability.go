@@ -0,0 +1,167 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package highavailability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// Path is the path under which the high availability webhook is served.
+	Path = "/mutate-workloads-high-availability"
+
+	// Label is a label on namespaces and workloads. If set to true, the high availability defaults are applied to all
+	// Deployments and StatefulSets in the namespace respectively to the labeled workload. A value of false on a workload
+	// opts it out even if its namespace is labeled.
+	Label = "resources.gardener.cloud/high-availability"
+
+	zoneTopologyKey     = corev1.LabelZoneFailureDomain
+	hostnameTopologyKey = corev1.LabelHostname
+)
+
+type handler struct {
+	reader      client.Reader
+	minReplicas int32
+	decoder     *admission.Decoder
+}
+
+// NewHandler returns an admission handler applying high availability defaults to Deployments and StatefulSets labeled
+// with `Label` or residing in a namespace labeled with it: the replicas are raised to the given minimum, the pods are
+// spread across zones and preferably scheduled onto different nodes. The given reader is used to read namespaces.
+func NewHandler(reader client.Reader, minReplicas int32) admission.Handler {
+	return &handler{reader: reader, minReplicas: minReplicas}
+}
+
+// InjectDecoder implements `admission.DecoderInjector`.
+func (h *handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle implements `admission.Handler`.
+func (h *handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var (
+		obj      runtime.Object
+		meta     *metav1.ObjectMeta
+		replicas **int32
+		selector *metav1.LabelSelector
+		podSpec  *corev1.PodSpec
+	)
+
+	switch req.Kind.Kind {
+	case "Deployment":
+		deployment := &appsv1.Deployment{}
+		if err := h.decoder.Decode(req, deployment); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		obj, meta, replicas, selector, podSpec = deployment, &deployment.ObjectMeta, &deployment.Spec.Replicas, deployment.Spec.Selector, &deployment.Spec.Template.Spec
+	case "StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		if err := h.decoder.Decode(req, statefulSet); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		obj, meta, replicas, selector, podSpec = statefulSet, &statefulSet.ObjectMeta, &statefulSet.Spec.Replicas, statefulSet.Spec.Selector, &statefulSet.Spec.Template.Spec
+	default:
+		return admission.Allowed(fmt.Sprintf("kind %s is not supported", req.Kind.Kind))
+	}
+
+	highAvailability, err := h.isHighlyAvailable(ctx, req.Namespace, meta)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !highAvailability {
+		return admission.Allowed("workload is not highly available")
+	}
+
+	setReplicas(replicas, h.minReplicas)
+	if selector != nil {
+		setTopologySpreadConstraints(podSpec, selector)
+		setPodAntiAffinity(podSpec, selector)
+	}
+
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// isHighlyAvailable returns whether the label on the workload or, if the workload is not labeled, the label on its
+// namespace requests high availability.
+func (h *handler) isHighlyAvailable(ctx context.Context, namespace string, meta *metav1.ObjectMeta) (bool, error) {
+	if v, ok := meta.Labels[Label]; ok {
+		highAvailability, _ := strconv.ParseBool(v)
+		return highAvailability, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := h.reader.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return false, fmt.Errorf("could not get namespace %s: %w", namespace, err)
+	}
+	highAvailability, _ := strconv.ParseBool(ns.Labels[Label])
+	return highAvailability, nil
+}
+
+// setReplicas raises the replicas to the given minimum. Workloads scaled down to zero are not scaled up.
+func setReplicas(replicas **int32, minReplicas int32) {
+	if *replicas != nil && (**replicas == 0 || **replicas >= minReplicas) {
+		return
+	}
+	*replicas = &minReplicas
+}
+
+// setTopologySpreadConstraints spreads the pods across zones unless the workload specifies its own constraints.
+func setTopologySpreadConstraints(podSpec *corev1.PodSpec, selector *metav1.LabelSelector) {
+	if len(podSpec.TopologySpreadConstraints) > 0 {
+		return
+	}
+	podSpec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       zoneTopologyKey,
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector:     selector.DeepCopy(),
+	}}
+}
+
+// setPodAntiAffinity prefers scheduling the pods onto different nodes unless the workload specifies its own pod
+// anti-affinity.
+func setPodAntiAffinity(podSpec *corev1.PodSpec, selector *metav1.LabelSelector) {
+	if podSpec.Affinity != nil && podSpec.Affinity.PodAntiAffinity != nil {
+		return
+	}
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				TopologyKey:   hostnameTopologyKey,
+				LabelSelector: selector.DeepCopy(),
+			},
+		}},
+	}
+}
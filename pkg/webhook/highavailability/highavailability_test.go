@@ -0,0 +1,187 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package highavailability_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/highavailability"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("HighAvailability", func() {
+	var (
+		ctx        = context.TODO()
+		ctrl       *gomock.Controller
+		c          *mockclient.MockClient
+		handler    admission.Handler
+		deployment *appsv1.Deployment
+		selector   = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+		handler = NewHandler(c, 2)
+		Expect(admission.InjectDecoderInto(decoder, handler)).To(BeTrue())
+
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: pointer.Int32Ptr(1),
+				Selector: selector,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	handle := func(kind string, obj runtime.Object) (admission.Response, []byte) {
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		resp := handler.Handle(ctx, admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+			Operation: v1beta1.Create,
+			Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: kind},
+			Namespace: "default",
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+		if len(resp.Patches) == 0 {
+			return resp, raw
+		}
+
+		patch, err := json.Marshal(resp.Patches)
+		Expect(err).NotTo(HaveOccurred())
+		decodedPatch, err := jsonpatch.DecodePatch(patch)
+		Expect(err).NotTo(HaveOccurred())
+		patched, err := decodedPatch.Apply(raw)
+		Expect(err).NotTo(HaveOccurred())
+		return resp, patched
+	}
+
+	expectNamespace := func(labels map[string]string) {
+		c.EXPECT().Get(ctx, client.ObjectKey{Name: "default"}, gomock.AssignableToTypeOf(&corev1.Namespace{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*corev1.Namespace).Labels = labels
+				return nil
+			})
+	}
+
+	expectHighlyAvailable := func(podSpec corev1.PodSpec) {
+		Expect(podSpec.TopologySpreadConstraints).To(Equal([]corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelZoneFailureDomain,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     selector,
+		}}))
+		Expect(podSpec.Affinity).To(Equal(&corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight:          100,
+				PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: corev1.LabelHostname, LabelSelector: selector},
+			}},
+		}}))
+	}
+
+	It("should apply the defaults to deployments in labeled namespaces", func() {
+		expectNamespace(map[string]string{Label: "true"})
+
+		resp, raw := handle("Deployment", deployment)
+		Expect(resp.Allowed).To(BeTrue())
+
+		mutated := &appsv1.Deployment{}
+		Expect(json.Unmarshal(raw, mutated)).To(Succeed())
+		Expect(mutated.Spec.Replicas).To(Equal(pointer.Int32Ptr(2)))
+		expectHighlyAvailable(mutated.Spec.Template.Spec)
+	})
+
+	It("should apply the defaults to labeled statefulsets", func() {
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", Labels: map[string]string{Label: "true"}},
+			Spec:       appsv1.StatefulSetSpec{Selector: selector},
+		}
+
+		resp, raw := handle("StatefulSet", statefulSet)
+		Expect(resp.Allowed).To(BeTrue())
+
+		mutated := &appsv1.StatefulSet{}
+		Expect(json.Unmarshal(raw, mutated)).To(Succeed())
+		Expect(mutated.Spec.Replicas).To(Equal(pointer.Int32Ptr(2)))
+		expectHighlyAvailable(mutated.Spec.Template.Spec)
+	})
+
+	It("should not modify workloads opting out in labeled namespaces", func() {
+		deployment.Labels = map[string]string{Label: "false"}
+
+		resp, _ := handle("Deployment", deployment)
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should not modify workloads in namespaces without the label", func() {
+		expectNamespace(nil)
+
+		resp, _ := handle("Deployment", deployment)
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should keep sufficient and zero replicas as well as own scheduling constraints", func() {
+		deployment.Labels = map[string]string{Label: "true"}
+		deployment.Spec.Replicas = pointer.Int32Ptr(0)
+		deployment.Spec.Template.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{MaxSkew: 2, TopologyKey: "foo", WhenUnsatisfiable: corev1.DoNotSchedule}}
+		deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+
+		resp, _ := handle("Deployment", deployment)
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+
+		deployment.Spec.Replicas = pointer.Int32Ptr(3)
+		resp, _ = handle("Deployment", deployment)
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should return an error if the namespace cannot be read", func() {
+		c.EXPECT().Get(ctx, client.ObjectKey{Name: "default"}, gomock.AssignableToTypeOf(&corev1.Namespace{})).Return(fmt.Errorf("fake"))
+
+		resp, _ := handle("Deployment", deployment)
+		Expect(resp.Allowed).To(BeFalse())
+	})
+
+	It("should allow other kinds", func() {
+		resp, _ := handle("DaemonSet", &appsv1.DaemonSet{})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+})
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetesservicehost
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// Path is the path under which the kubernetes service host webhook is served.
+	Path = "/mutate-pods-kubernetes-service-host"
+
+	// SkipLabel is a label on pods. If set to true, the webhook does not inject the host of the API server.
+	SkipLabel = "resources.gardener.cloud/skip-kubernetes-service-host"
+
+	// EnvKubernetesServiceHost is the environment variable used by the Kubernetes client libraries to discover the host
+	// of the API server.
+	EnvKubernetesServiceHost = "KUBERNETES_SERVICE_HOST"
+)
+
+type handler struct {
+	host    string
+	decoder *admission.Decoder
+}
+
+// NewHandler returns an admission handler injecting the `KUBERNETES_SERVICE_HOST` environment variable with the given
+// host into all (init) containers of pods. Containers already specifying the variable are left untouched.
+func NewHandler(host string) admission.Handler {
+	return &handler{host: host}
+}
+
+// InjectDecoder implements `admission.DecoderInjector`.
+func (h *handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle implements `admission.Handler`.
+func (h *handler) Handle(_ context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	if err := h.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if pod.Labels[SkipLabel] == "true" {
+		return admission.Allowed("pod is labeled with " + SkipLabel)
+	}
+
+	for i := range pod.Spec.InitContainers {
+		h.injectEnv(&pod.Spec.InitContainers[i])
+	}
+	for i := range pod.Spec.Containers {
+		h.injectEnv(&pod.Spec.Containers[i])
+	}
+
+	marshaled, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+func (h *handler) injectEnv(container *corev1.Container) {
+	for _, env := range container.Env {
+		if env.Name == EnvKubernetesServiceHost {
+			return
+		}
+	}
+	container.Env = append(container.Env, corev1.EnvVar{Name: EnvKubernetesServiceHost, Value: h.host})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetesservicehost_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/kubernetesservicehost"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("KubernetesServiceHost", func() {
+	var (
+		ctx     = context.TODO()
+		handler admission.Handler
+		pod     *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+		handler = NewHandler("api.example.com")
+		Expect(admission.InjectDecoderInto(decoder, handler)).To(BeTrue())
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers: []corev1.Container{
+					{Name: "foo", Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}}},
+					{Name: "bar", Env: []corev1.EnvVar{{Name: EnvKubernetesServiceHost, Value: "other.example.com"}}},
+				},
+			},
+		}
+	})
+
+	handle := func() (admission.Response, *corev1.Pod) {
+		raw, err := json.Marshal(pod)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp := handler.Handle(ctx, admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+			Operation: v1beta1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+		Expect(resp.Allowed).To(BeTrue())
+
+		patch, err := json.Marshal(resp.Patches)
+		Expect(err).NotTo(HaveOccurred())
+		decodedPatch, err := jsonpatch.DecodePatch(patch)
+		Expect(err).NotTo(HaveOccurred())
+		patched, err := decodedPatch.Apply(raw)
+		Expect(err).NotTo(HaveOccurred())
+
+		mutated := &corev1.Pod{}
+		Expect(json.Unmarshal(patched, mutated)).To(Succeed())
+		return resp, mutated
+	}
+
+	It("should inject the host into all containers not specifying it", func() {
+		_, mutated := handle()
+
+		Expect(mutated.Spec.InitContainers[0].Env).To(ConsistOf(corev1.EnvVar{Name: EnvKubernetesServiceHost, Value: "api.example.com"}))
+		Expect(mutated.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: "FOO", Value: "bar"},
+			corev1.EnvVar{Name: EnvKubernetesServiceHost, Value: "api.example.com"},
+		))
+		Expect(mutated.Spec.Containers[1].Env).To(ConsistOf(corev1.EnvVar{Name: EnvKubernetesServiceHost, Value: "other.example.com"}))
+	})
+
+	It("should not modify pods labeled to be skipped", func() {
+		pod.Labels = map[string]string{SkipLabel: "true"}
+
+		resp, _ := handle()
+		Expect(resp.Patches).To(BeEmpty())
+	})
+})
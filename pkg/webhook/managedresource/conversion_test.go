@@ -0,0 +1,109 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresource_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1beta1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1beta1"
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/managedresource"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+var _ = Describe("Conversion", func() {
+	var webhook *conversion.Webhook
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(resourcesv1beta1.AddToScheme(scheme)).To(Succeed())
+
+		webhook = &conversion.Webhook{}
+		Expect(webhook.InjectScheme(scheme)).To(Succeed())
+	})
+
+	convert := func(desiredAPIVersion string, obj runtime.Object) *apiextensionsv1beta1.ConversionResponse {
+		review := &apiextensionsv1beta1.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: apiextensionsv1beta1.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+			Request: &apiextensionsv1beta1.ConversionRequest{
+				UID:               types.UID("1234"),
+				DesiredAPIVersion: desiredAPIVersion,
+				Objects:           []runtime.RawExtension{{Object: obj}},
+			},
+		}
+		body, err := json.Marshal(review)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := httptest.NewRequest(http.MethodPost, ConversionPath, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		webhook.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		result := &apiextensionsv1beta1.ConversionReview{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), result)).To(Succeed())
+		Expect(result.Response.Result.Status).To(Equal(metav1.StatusSuccess), result.Response.Result.Message)
+		return result.Response
+	}
+
+	It("should convert ManagedResources from v1alpha1 to v1beta1", func() {
+		resp := convert(resourcesv1beta1.SchemeGroupVersion.String(), &resourcesv1alpha1.ManagedResource{
+			TypeMeta:   metav1.TypeMeta{APIVersion: resourcesv1alpha1.SchemeGroupVersion.String(), Kind: "ManagedResource"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				SecretRefs:  []corev1.LocalObjectReference{{Name: "secret"}},
+				KeepObjects: pointer.BoolPtr(true),
+			},
+		})
+
+		Expect(resp.ConvertedObjects).To(HaveLen(1))
+		converted := &resourcesv1beta1.ManagedResource{}
+		Expect(json.Unmarshal(resp.ConvertedObjects[0].Raw, converted)).To(Succeed())
+		Expect(converted.APIVersion).To(Equal(resourcesv1beta1.SchemeGroupVersion.String()))
+		Expect(converted.Spec.SecretRefs).To(Equal([]resourcesv1beta1.SecretReference{{Name: "secret"}}))
+		Expect(converted.Spec.KeepObjects).To(BeTrue())
+	})
+
+	It("should convert ManagedResources from v1beta1 to v1alpha1", func() {
+		resp := convert(resourcesv1alpha1.SchemeGroupVersion.String(), &resourcesv1beta1.ManagedResource{
+			TypeMeta:   metav1.TypeMeta{APIVersion: resourcesv1beta1.SchemeGroupVersion.String(), Kind: "ManagedResource"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+			Spec: resourcesv1beta1.ManagedResourceSpec{
+				SecretRefs:  []resourcesv1beta1.SecretReference{{Name: "secret"}},
+				KeepObjects: true,
+			},
+		})
+
+		Expect(resp.ConvertedObjects).To(HaveLen(1))
+		converted := &resourcesv1alpha1.ManagedResource{}
+		Expect(json.Unmarshal(resp.ConvertedObjects[0].Raw, converted)).To(Succeed())
+		Expect(converted.APIVersion).To(Equal(resourcesv1alpha1.SchemeGroupVersion.String()))
+		Expect(converted.Spec.SecretRefs).To(Equal([]corev1.LocalObjectReference{{Name: "secret"}}))
+		Expect(converted.Spec.KeepObjects).To(Equal(pointer.BoolPtr(true)))
+	})
+})
@@ -0,0 +1,114 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresource
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaulterPath is the path under which the defaulting webhook for ManagedResources is served.
+const DefaulterPath = "/mutate-resources-gardener-cloud-v1alpha1-managedresource"
+
+type defaulter struct {
+	class   string
+	decoder *admission.Decoder
+}
+
+// NewDefaulter returns an admission handler defaulting ManagedResources. ManagedResources created without a class are
+// assigned the given class, i.e. the class of the instance serving the webhook.
+func NewDefaulter(class string) admission.Handler {
+	return &defaulter{class: class}
+}
+
+// InjectDecoder implements `admission.DecoderInjector`.
+func (d *defaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle implements `admission.Handler`.
+func (d *defaulter) Handle(_ context.Context, req admission.Request) admission.Response {
+	mr := &resourcesv1alpha1.ManagedResource{}
+	if err := d.decoder.Decode(req, mr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// The class is only defaulted on creation, otherwise updates of existing ManagedResources without a class would hand
+	// them over to the serving instance.
+	if req.Operation == v1beta1.Create {
+		setDefaultClass(mr, d.class)
+	}
+	setDefaults(mr)
+
+	marshaled, err := json.Marshal(mr)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+func setDefaultClass(mr *resourcesv1alpha1.ManagedResource, class string) {
+	if mr.Spec.Class == nil || len(*mr.Spec.Class) == 0 {
+		mr.Spec.Class = &class
+	}
+}
+
+func setDefaults(mr *resourcesv1alpha1.ManagedResource) {
+	for _, field := range []**bool{
+		&mr.Spec.ForceOverwriteLabels,
+		&mr.Spec.ForceOverwriteAnnotations,
+		&mr.Spec.ForceOverwriteOwnerReferences,
+		&mr.Spec.ForceOverwriteFinalizers,
+		&mr.Spec.KeepObjects,
+		&mr.Spec.DeletePersistentVolumeClaims,
+	} {
+		if *field == nil {
+			f := false
+			*field = &f
+		}
+	}
+
+	mr.Spec.SecretRefs = normalizeSecretRefs(mr.Spec.SecretRefs)
+}
+
+// normalizeSecretRefs trims the names of the given secret references and drops empty and duplicate references while
+// keeping the order of the remaining ones.
+func normalizeSecretRefs(refs []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+	var (
+		names      = sets.NewString()
+		normalized = make([]corev1.LocalObjectReference, 0, len(refs))
+	)
+
+	for _, ref := range refs {
+		name := strings.TrimSpace(ref.Name)
+		if len(name) == 0 || names.Has(name) {
+			continue
+		}
+		names.Insert(name)
+		normalized = append(normalized, corev1.LocalObjectReference{Name: name})
+	}
+
+	return normalized
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresource_test
+
+import (
+	"context"
+	"encoding/json"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/managedresource"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Defaulter", func() {
+	var (
+		ctx     = context.TODO()
+		handler admission.Handler
+		mr      *resourcesv1alpha1.ManagedResource
+		f       = false
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = NewDefaulter("seed")
+		Expect(admission.InjectDecoderInto(decoder, handler)).To(BeTrue())
+
+		mr = &resourcesv1alpha1.ManagedResource{
+			TypeMeta:   metav1.TypeMeta{APIVersion: resourcesv1alpha1.SchemeGroupVersion.String(), Kind: "ManagedResource"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				SecretRefs: []corev1.LocalObjectReference{{Name: " secret1 "}, {Name: ""}, {Name: "secret2"}, {Name: "secret1"}},
+			},
+		}
+	})
+
+	It("should default the class and the optional fields on creation", func() {
+		defaulted := defaultedObject(handler.Handle(ctx, request(v1beta1.Create, mr, nil)), mr)
+
+		class := "seed"
+		expected := mr.DeepCopy()
+		expected.Spec.Class = &class
+		expected.Spec.SecretRefs = []corev1.LocalObjectReference{{Name: "secret1"}, {Name: "secret2"}}
+		expected.Spec.ForceOverwriteLabels = &f
+		expected.Spec.ForceOverwriteAnnotations = &f
+		expected.Spec.ForceOverwriteOwnerReferences = &f
+		expected.Spec.ForceOverwriteFinalizers = &f
+		expected.Spec.KeepObjects = &f
+		expected.Spec.DeletePersistentVolumeClaims = &f
+		Expect(defaulted).To(Equal(expected))
+	})
+
+	It("should not overwrite an explicitly set class", func() {
+		class := "shoot"
+		mr.Spec.Class = &class
+
+		defaulted := defaultedObject(handler.Handle(ctx, request(v1beta1.Create, mr, nil)), mr)
+		Expect(*defaulted.Spec.Class).To(Equal("shoot"))
+	})
+
+	It("should not default the class on updates", func() {
+		defaulted := defaultedObject(handler.Handle(ctx, request(v1beta1.Update, mr, mr)), mr)
+		Expect(defaulted.Spec.Class).To(BeNil())
+		Expect(defaulted.Spec.KeepObjects).To(Equal(&f))
+	})
+
+	It("should not patch already defaulted objects", func() {
+		class := "seed"
+		mr.Spec.Class = &class
+		mr.Spec.SecretRefs = []corev1.LocalObjectReference{{Name: "secret1"}}
+		mr.Spec.ForceOverwriteLabels = &f
+		mr.Spec.ForceOverwriteAnnotations = &f
+		mr.Spec.ForceOverwriteOwnerReferences = &f
+		mr.Spec.ForceOverwriteFinalizers = &f
+		mr.Spec.KeepObjects = &f
+		mr.Spec.DeletePersistentVolumeClaims = &f
+
+		resp := handler.Handle(ctx, request(v1beta1.Create, mr, nil))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+})
+
+func defaultedObject(resp admission.Response, mr *resourcesv1alpha1.ManagedResource) *resourcesv1alpha1.ManagedResource {
+	Expect(resp.Allowed).To(BeTrue())
+
+	patch, err := json.Marshal(resp.Patches)
+	Expect(err).NotTo(HaveOccurred())
+	decodedPatch, err := jsonpatch.DecodePatch(patch)
+	Expect(err).NotTo(HaveOccurred())
+	patched, err := decodedPatch.Apply(mustMarshal(mr))
+	Expect(err).NotTo(HaveOccurred())
+
+	defaulted := &resourcesv1alpha1.ManagedResource{}
+	Expect(json.Unmarshal(patched, defaulted)).To(Succeed())
+	return defaulted
+}
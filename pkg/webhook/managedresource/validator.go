@@ -0,0 +1,66 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresource
+
+import (
+	"context"
+	"net/http"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/validation"
+
+	"k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidatorPath is the path under which the validating webhook for ManagedResources is served.
+const ValidatorPath = "/validate-resources-gardener-cloud-v1alpha1-managedresource"
+
+type validator struct {
+	decoder *admission.Decoder
+}
+
+// NewValidator returns an admission handler validating ManagedResources on creation and update.
+func NewValidator() admission.Handler {
+	return &validator{}
+}
+
+// InjectDecoder implements `admission.DecoderInjector`.
+func (v *validator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle implements `admission.Handler`.
+func (v *validator) Handle(_ context.Context, req admission.Request) admission.Response {
+	mr := &resourcesv1alpha1.ManagedResource{}
+	if err := v.decoder.Decode(req, mr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	allErrs := validation.ValidateManagedResource(mr)
+	if req.Operation == v1beta1.Update {
+		oldMR := &resourcesv1alpha1.ManagedResource{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldMR); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		allErrs = validation.ValidateManagedResourceUpdate(mr, oldMR)
+	}
+
+	if len(allErrs) > 0 {
+		return admission.Denied(allErrs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
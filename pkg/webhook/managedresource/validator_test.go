@@ -0,0 +1,110 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedresource_test
+
+import (
+	"context"
+	"encoding/json"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/managedresource"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Validator", func() {
+	var (
+		ctx     = context.TODO()
+		handler admission.Handler
+		mr      *resourcesv1alpha1.ManagedResource
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(resourcesv1alpha1.AddToScheme(scheme)).To(Succeed())
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = NewValidator()
+		Expect(admission.InjectDecoderInto(decoder, handler)).To(BeTrue())
+
+		mr = &resourcesv1alpha1.ManagedResource{
+			TypeMeta:   metav1.TypeMeta{APIVersion: resourcesv1alpha1.SchemeGroupVersion.String(), Kind: "ManagedResource"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				SecretRefs: []corev1.LocalObjectReference{{Name: "secret"}},
+			},
+		}
+	})
+
+	It("should allow valid ManagedResources", func() {
+		resp := handler.Handle(ctx, request(v1beta1.Create, mr, nil))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should deny ManagedResources without secret references", func() {
+		mr.Spec.SecretRefs = nil
+		resp := handler.Handle(ctx, request(v1beta1.Create, mr, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(string(resp.Result.Reason)).To(ContainSubstring("spec.secretRefs"))
+	})
+
+	It("should allow changing the class", func() {
+		class := "seed"
+		newMR := mr.DeepCopy()
+		newMR.Spec.Class = &class
+		resp := handler.Handle(ctx, request(v1beta1.Update, newMR, mr))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should deny changing the class to an invalid class", func() {
+		class := "Foo_Bar"
+		newMR := mr.DeepCopy()
+		newMR.Spec.Class = &class
+		resp := handler.Handle(ctx, request(v1beta1.Update, newMR, mr))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(string(resp.Result.Reason)).To(ContainSubstring("spec.class"))
+	})
+
+	It("should return an error for undecodable objects", func() {
+		resp := handler.Handle(ctx, admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+			Operation: v1beta1.Create,
+			Object:    runtime.RawExtension{Raw: []byte("{")},
+		}})
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(BeEquivalentTo(400))
+	})
+})
+
+func request(op v1beta1.Operation, obj, oldObj *resourcesv1alpha1.ManagedResource) admission.Request {
+	req := admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{Operation: op}}
+	req.Object = runtime.RawExtension{Raw: mustMarshal(obj)}
+	if oldObj != nil {
+		req.OldObject = runtime.RawExtension{Raw: mustMarshal(oldObj)}
+	}
+	return req
+}
+
+func mustMarshal(obj interface{}) []byte {
+	data, err := json.Marshal(obj)
+	Expect(err).NotTo(HaveOccurred())
+	return data
+}
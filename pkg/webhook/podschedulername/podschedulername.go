@@ -0,0 +1,64 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podschedulername
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Path is the path under which the pod scheduler name webhook is served.
+const Path = "/mutate-pods-scheduler-name"
+
+type handler struct {
+	schedulerName string
+	decoder       *admission.Decoder
+}
+
+// NewHandler returns an admission handler setting the scheduler name of pods to the given scheduler. Only pods using
+// the default scheduler are mutated, pods explicitly requesting another scheduler are left untouched.
+func NewHandler(schedulerName string) admission.Handler {
+	return &handler{schedulerName: schedulerName}
+}
+
+// InjectDecoder implements `admission.DecoderInjector`.
+func (h *handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle implements `admission.Handler`.
+func (h *handler) Handle(_ context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	if err := h.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if pod.Spec.SchedulerName != "" && pod.Spec.SchedulerName != corev1.DefaultSchedulerName {
+		return admission.Allowed("pod explicitly requests scheduler " + pod.Spec.SchedulerName)
+	}
+
+	pod.Spec.SchedulerName = h.schedulerName
+
+	marshaled, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
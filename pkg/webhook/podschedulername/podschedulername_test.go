@@ -0,0 +1,65 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podschedulername_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/podschedulername"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"gomodules.xyz/jsonpatch/v2"
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("PodSchedulerName", func() {
+	var (
+		ctx     = context.TODO()
+		handler admission.Handler
+	)
+
+	BeforeEach(func() {
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+		handler = NewHandler("bin-packing-scheduler")
+		Expect(admission.InjectDecoderInto(decoder, handler)).To(BeTrue())
+	})
+
+	DescribeTable("#Handle",
+		func(schedulerName string, expectedPatches []jsonpatch.JsonPatchOperation) {
+			raw, err := json.Marshal(&corev1.Pod{Spec: corev1.PodSpec{SchedulerName: schedulerName}})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp := handler.Handle(ctx, admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+				Operation: v1beta1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(Equal(expectedPatches))
+		},
+		Entry("should set the scheduler name if it is empty", "",
+			[]jsonpatch.JsonPatchOperation{{Operation: "add", Path: "/spec/schedulerName", Value: "bin-packing-scheduler"}}),
+		Entry("should replace the default scheduler", corev1.DefaultSchedulerName,
+			[]jsonpatch.JsonPatchOperation{{Operation: "replace", Path: "/spec/schedulerName", Value: "bin-packing-scheduler"}}),
+		Entry("should keep other schedulers", "other-scheduler", nil),
+	)
+})
@@ -0,0 +1,157 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectedtokenmount
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// Path is the path under which the projected token mount webhook is served.
+	Path = "/mutate-pods-projected-token-mount"
+
+	// InjectLabel is a label on pods. If set to true, a volume with a projected ServiceAccount token is mounted into all
+	// containers of the pod.
+	InjectLabel = "resources.gardener.cloud/projected-token-mount"
+	// ExpirationSecondsAnnotation is an annotation on pods overriding the expiration of the projected token.
+	ExpirationSecondsAnnotation = "resources.gardener.cloud/projected-token-expiration-seconds"
+
+	volumeName = "kube-api-access-gardener"
+	mountPath  = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// rootCAConfigMapName is the name of the config map containing the CA bundle of the API server, which is
+	// published into every namespace by the kube-controller-manager.
+	rootCAConfigMapName = "kube-root-ca.crt"
+
+	// minExpiration is the minimum expiration of projected tokens accepted by the API server.
+	minExpiration = 10 * time.Minute
+)
+
+type handler struct {
+	expiration time.Duration
+	decoder    *admission.Decoder
+}
+
+// NewHandler returns an admission handler mounting a projected ServiceAccount token with the given expiration into
+// pods labeled with `InjectLabel`.
+func NewHandler(expiration time.Duration) admission.Handler {
+	return &handler{expiration: expiration}
+}
+
+// InjectDecoder implements `admission.DecoderInjector`.
+func (h *handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle implements `admission.Handler`.
+func (h *handler) Handle(_ context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	if err := h.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if inject, _ := strconv.ParseBool(pod.Labels[InjectLabel]); !inject {
+		return admission.Allowed("pod is not labeled for projected token mount")
+	}
+	if hasServiceAccountMount(pod) {
+		return admission.Allowed("pod already mounts a service account token")
+	}
+
+	expiration := h.expiration
+	if v, ok := pod.Annotations[ExpirationSecondsAnnotation]; ok {
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return admission.Denied("invalid value for annotation " + ExpirationSecondsAnnotation + ": " + err.Error())
+		}
+		expiration = time.Duration(seconds) * time.Second
+	}
+	if expiration < minExpiration {
+		expiration = minExpiration
+	}
+
+	injectProjectedToken(pod, expiration)
+
+	marshaled, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+func hasServiceAccountMount(pod *corev1.Pod) bool {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			for _, mount := range container.VolumeMounts {
+				if mount.MountPath == mountPath {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func injectProjectedToken(pod *corev1.Pod, expiration time.Duration) {
+	var (
+		expirationSeconds = int64(expiration / time.Second)
+		defaultMode       = int32(0644)
+	)
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				DefaultMode: &defaultMode,
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							ExpirationSeconds: &expirationSeconds,
+							Path:              "token",
+						},
+					},
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: rootCAConfigMapName},
+							Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+						},
+					},
+					{
+						DownwardAPI: &corev1.DownwardAPIProjection{
+							Items: []corev1.DownwardAPIVolumeFile{{
+								Path:     "namespace",
+								FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.namespace"},
+							}},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	mount := corev1.VolumeMount{Name: volumeName, MountPath: mountPath, ReadOnly: true}
+	for i := range pod.Spec.InitContainers {
+		pod.Spec.InitContainers[i].VolumeMounts = append(pod.Spec.InitContainers[i].VolumeMounts, mount)
+	}
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, mount)
+	}
+}
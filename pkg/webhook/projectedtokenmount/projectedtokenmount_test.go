@@ -0,0 +1,134 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectedtokenmount_test
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/projectedtokenmount"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("ProjectedTokenMount", func() {
+	var (
+		ctx     = context.TODO()
+		handler admission.Handler
+		pod     *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+		handler = NewHandler(time.Hour)
+		Expect(admission.InjectDecoderInto(decoder, handler)).To(BeTrue())
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "foo",
+				Labels:    map[string]string{InjectLabel: "true"},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers:     []corev1.Container{{Name: "foo"}, {Name: "bar"}},
+			},
+		}
+	})
+
+	handle := func() (admission.Response, *corev1.Pod) {
+		raw, err := json.Marshal(pod)
+		Expect(err).NotTo(HaveOccurred())
+		resp := handler.Handle(ctx, admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+			Operation: v1beta1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+		if len(resp.Patches) == 0 {
+			return resp, pod
+		}
+
+		patch, err := json.Marshal(resp.Patches)
+		Expect(err).NotTo(HaveOccurred())
+		decodedPatch, err := jsonpatch.DecodePatch(patch)
+		Expect(err).NotTo(HaveOccurred())
+		patched, err := decodedPatch.Apply(raw)
+		Expect(err).NotTo(HaveOccurred())
+
+		mutated := &corev1.Pod{}
+		Expect(json.Unmarshal(patched, mutated)).To(Succeed())
+		return resp, mutated
+	}
+
+	It("should mount a projected token into all containers", func() {
+		resp, mutated := handle()
+		Expect(resp.Allowed).To(BeTrue())
+
+		Expect(mutated.Spec.Volumes).To(HaveLen(1))
+		volume := mutated.Spec.Volumes[0]
+		Expect(volume.Projected).NotTo(BeNil())
+		Expect(volume.Projected.Sources).To(HaveLen(3))
+		Expect(volume.Projected.Sources[0].ServiceAccountToken.ExpirationSeconds).To(Equal(pointer.Int64Ptr(3600)))
+		Expect(volume.Projected.Sources[1].ConfigMap.Name).To(Equal("kube-root-ca.crt"))
+		Expect(volume.Projected.Sources[2].DownwardAPI.Items[0].FieldRef.FieldPath).To(Equal("metadata.namespace"))
+
+		expectedMount := corev1.VolumeMount{Name: volume.Name, MountPath: "/var/run/secrets/kubernetes.io/serviceaccount", ReadOnly: true}
+		Expect(mutated.Spec.InitContainers[0].VolumeMounts).To(ConsistOf(expectedMount))
+		Expect(mutated.Spec.Containers[0].VolumeMounts).To(ConsistOf(expectedMount))
+		Expect(mutated.Spec.Containers[1].VolumeMounts).To(ConsistOf(expectedMount))
+	})
+
+	It("should use the expiration of the annotation", func() {
+		pod.Annotations = map[string]string{ExpirationSecondsAnnotation: "7200"}
+		_, mutated := handle()
+		Expect(mutated.Spec.Volumes[0].Projected.Sources[0].ServiceAccountToken.ExpirationSeconds).To(Equal(pointer.Int64Ptr(7200)))
+	})
+
+	It("should not use an expiration below the minimum", func() {
+		pod.Annotations = map[string]string{ExpirationSecondsAnnotation: "60"}
+		_, mutated := handle()
+		Expect(mutated.Spec.Volumes[0].Projected.Sources[0].ServiceAccountToken.ExpirationSeconds).To(Equal(pointer.Int64Ptr(600)))
+	})
+
+	It("should deny pods with an invalid expiration annotation", func() {
+		pod.Annotations = map[string]string{ExpirationSecondsAnnotation: "foo"}
+		resp, _ := handle()
+		Expect(resp.Allowed).To(BeFalse())
+	})
+
+	It("should not mutate pods without the label", func() {
+		pod.Labels = nil
+		resp, _ := handle()
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should not mutate pods already mounting a service account token", func() {
+		pod.Spec.Containers[1].VolumeMounts = []corev1.VolumeMount{{Name: "default-token-abcde", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount"}}
+		resp, _ := handle()
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+})
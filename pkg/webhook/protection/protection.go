@@ -0,0 +1,106 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Path is the path under which the protection webhook for objects managed by the gardener-resource-manager is served.
+const Path = "/validate-managed-objects"
+
+type handler struct {
+	allowedUsers sets.String
+}
+
+// NewHandler returns an admission handler rejecting updates and deletions of objects carrying the origin label unless
+// they are requested by one of the given users (i.e. the identity of the gardener-resource-manager in the target cluster)
+// or the object is annotated with the protection override annotation.
+func NewHandler(allowedUsers ...string) admission.Handler {
+	return &handler{allowedUsers: sets.NewString(allowedUsers...)}
+}
+
+// partialObject contains the metadata of an arbitrary object, it is sufficient for deciding whether it is protected.
+type partialObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+// Handle implements `admission.Handler`.
+func (h *handler) Handle(_ context.Context, req admission.Request) admission.Response {
+	// subresources like status or scale are modified by other controllers and autoscalers
+	if len(req.SubResource) > 0 || h.allowedUsers.Has(req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+
+	var overrideObject runtime.RawExtension
+	switch req.Operation {
+	case v1beta1.Update:
+		overrideObject = req.Object
+	case v1beta1.Delete:
+		overrideObject = req.OldObject
+	default:
+		return admission.Allowed("")
+	}
+
+	// the old object of delete requests is only sent by API servers >= 1.15
+	if len(req.OldObject.Raw) == 0 {
+		return admission.Allowed("old object is unknown")
+	}
+
+	oldObj := &partialObject{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldObj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if _, ok := oldObj.Labels[resourcesv1alpha1.OriginLabel]; !ok {
+		return admission.Allowed("")
+	}
+
+	obj := &partialObject{}
+	if err := json.Unmarshal(overrideObject.Raw, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if override, _ := strconv.ParseBool(obj.Annotations[resourcesv1alpha1.ProtectionOverride]); override {
+		return admission.Allowed("protection is overridden")
+	}
+
+	return admission.Denied(fmt.Sprintf("%s %q is managed by the gardener-resource-manager, annotate it with %s=true to %s it manually",
+		req.Kind.Kind, objectKey(req), resourcesv1alpha1.ProtectionOverride, operationVerb(req.Operation)))
+}
+
+func objectKey(req admission.Request) string {
+	if len(req.Namespace) == 0 {
+		return req.Name
+	}
+	return req.Namespace + "/" + req.Name
+}
+
+func operationVerb(op v1beta1.Operation) string {
+	if op == v1beta1.Delete {
+		return "delete"
+	}
+	return "modify"
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection_test
+
+import (
+	"context"
+	"encoding/json"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/protection"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Protection", func() {
+	const resourceManagerUser = "system:serviceaccount:kube-system:gardener-resource-manager"
+
+	var (
+		ctx       = context.TODO()
+		handler   admission.Handler
+		configMap *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		handler = NewHandler(resourceManagerUser)
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "kube-system",
+				Name:      "foo",
+				Labels:    map[string]string{resourcesv1alpha1.OriginLabel: "resources"},
+			},
+		}
+	})
+
+	request := func(op v1beta1.Operation, user string, obj, oldObj *corev1.ConfigMap) admission.Request {
+		req := admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+			Operation: op,
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace: configMap.Namespace,
+			Name:      configMap.Name,
+			UserInfo:  authenticationv1.UserInfo{Username: user},
+		}}
+		if obj != nil {
+			req.Object = runtime.RawExtension{Raw: mustMarshal(obj)}
+		}
+		if oldObj != nil {
+			req.OldObject = runtime.RawExtension{Raw: mustMarshal(oldObj)}
+		}
+		return req
+	}
+
+	It("should deny updates of managed objects by other users", func() {
+		resp := handler.Handle(ctx, request(v1beta1.Update, "admin", configMap, configMap))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(string(resp.Result.Reason)).To(Equal(`ConfigMap "kube-system/foo" is managed by the gardener-resource-manager, annotate it with resources.gardener.cloud/protection-override=true to modify it manually`))
+	})
+
+	It("should deny removing the origin label", func() {
+		newConfigMap := configMap.DeepCopy()
+		newConfigMap.Labels = nil
+		resp := handler.Handle(ctx, request(v1beta1.Update, "admin", newConfigMap, configMap))
+		Expect(resp.Allowed).To(BeFalse())
+	})
+
+	It("should deny deletions of managed objects by other users", func() {
+		resp := handler.Handle(ctx, request(v1beta1.Delete, "admin", nil, configMap))
+		Expect(resp.Allowed).To(BeFalse())
+	})
+
+	It("should allow updates and deletions by the gardener-resource-manager", func() {
+		Expect(handler.Handle(ctx, request(v1beta1.Update, resourceManagerUser, configMap, configMap)).Allowed).To(BeTrue())
+		Expect(handler.Handle(ctx, request(v1beta1.Delete, resourceManagerUser, nil, configMap)).Allowed).To(BeTrue())
+	})
+
+	It("should allow updates and deletions of unmanaged objects", func() {
+		configMap.Labels = nil
+		Expect(handler.Handle(ctx, request(v1beta1.Update, "admin", configMap, configMap)).Allowed).To(BeTrue())
+		Expect(handler.Handle(ctx, request(v1beta1.Delete, "admin", nil, configMap)).Allowed).To(BeTrue())
+	})
+
+	It("should allow updates of subresources", func() {
+		req := request(v1beta1.Update, "admin", configMap, configMap)
+		req.SubResource = "status"
+		Expect(handler.Handle(ctx, req).Allowed).To(BeTrue())
+	})
+
+	It("should allow updates if the new object carries the override annotation", func() {
+		newConfigMap := configMap.DeepCopy()
+		newConfigMap.Annotations = map[string]string{resourcesv1alpha1.ProtectionOverride: "true"}
+		Expect(handler.Handle(ctx, request(v1beta1.Update, "admin", newConfigMap, configMap)).Allowed).To(BeTrue())
+	})
+
+	It("should allow deletions if the object carries the override annotation", func() {
+		configMap.Annotations = map[string]string{resourcesv1alpha1.ProtectionOverride: "true"}
+		Expect(handler.Handle(ctx, request(v1beta1.Delete, "admin", nil, configMap)).Allowed).To(BeTrue())
+	})
+
+	It("should allow deletions if the old object is not sent", func() {
+		Expect(handler.Handle(ctx, request(v1beta1.Delete, "admin", nil, nil)).Allowed).To(BeTrue())
+	})
+})
+
+func mustMarshal(obj interface{}) []byte {
+	data, err := json.Marshal(obj)
+	Expect(err).NotTo(HaveOccurred())
+	return data
+}
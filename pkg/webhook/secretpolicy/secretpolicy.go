@@ -0,0 +1,129 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretpolicy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Path is the path under which the secret policy webhook is served.
+const Path = "/validate-managed-resource-secrets"
+
+// Policy describes the conventions secrets referenced by ManagedResources have to follow.
+type Policy struct {
+	// MaxSize is the maximum decoded size of the data of a secret in bytes. No limit is enforced if it is 0.
+	MaxSize int
+	// KeyExtensions are the file extensions (e.g. `.yaml`) of which the keys of a secret must end with one. Keys are
+	// not checked if it is empty.
+	KeyExtensions []string
+}
+
+// Enabled returns true if the policy enforces any convention.
+func (p Policy) Enabled() bool {
+	return p.MaxSize > 0 || len(p.KeyExtensions) > 0
+}
+
+type handler struct {
+	reader  client.Reader
+	class   *managedresources.ClassFilter
+	policy  Policy
+	decoder *admission.Decoder
+}
+
+// NewHandler returns an admission handler rejecting secrets which are referenced by a ManagedResource of the given class
+// and violate the given policy. The given reader is used to read ManagedResources.
+func NewHandler(reader client.Reader, class *managedresources.ClassFilter, policy Policy) admission.Handler {
+	return &handler{reader: reader, class: class, policy: policy}
+}
+
+// InjectDecoder implements `admission.DecoderInjector`.
+func (h *handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle implements `admission.Handler`.
+func (h *handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	secret := &corev1.Secret{}
+	if err := h.decoder.Decode(req, secret); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	mrList := &resourcesv1alpha1.ManagedResourceList{}
+	if err := h.reader.List(ctx, mrList, managedresources.MatchingSecretRef(req.Namespace, secret.Name)...); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	var managedResource *resourcesv1alpha1.ManagedResource
+	for i, mr := range mrList.Items {
+		if !h.class.Responsible(&mr) {
+			continue
+		}
+		for _, ref := range mr.Spec.SecretRefs {
+			if ref.Name == secret.Name {
+				managedResource = &mrList.Items[i]
+				break
+			}
+		}
+	}
+	if managedResource == nil {
+		return admission.Allowed("secret is not referenced by a ManagedResource of this class")
+	}
+
+	if violations := h.policy.violations(secret); len(violations) > 0 {
+		return admission.Denied(fmt.Sprintf("secret is referenced by ManagedResource %q and violates the policy of class %q: %s",
+			managedResource.Name, managedresources.ResourceClassOf(managedResource), strings.Join(violations, ", ")))
+	}
+	return admission.Allowed("")
+}
+
+func (p Policy) violations(secret *corev1.Secret) []string {
+	var (
+		violations    []string
+		size          int
+		invalidKeys   []string
+		keyExtensions = sets.NewString(p.KeyExtensions...)
+	)
+
+	for key, data := range secret.Data {
+		size += len(data)
+		if keyExtensions.Len() > 0 && !keyExtensions.Has(filepath.Ext(key)) {
+			invalidKeys = append(invalidKeys, key)
+		}
+	}
+
+	if p.MaxSize > 0 && size > p.MaxSize {
+		violations = append(violations, fmt.Sprintf("size of %d bytes exceeds the maximum of %d bytes", size, p.MaxSize))
+	}
+	if len(invalidKeys) > 0 {
+		sort.Strings(invalidKeys)
+		violations = append(violations, fmt.Sprintf("keys %s do not have one of the extensions %s",
+			strings.Join(invalidKeys, ", "), strings.Join(keyExtensions.List(), ", ")))
+	}
+	return violations
+}
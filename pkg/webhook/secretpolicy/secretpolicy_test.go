@@ -0,0 +1,159 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretpolicy_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener-resource-manager/pkg/controller/managedresources"
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/secretpolicy"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("SecretPolicy", func() {
+	var (
+		ctx     = context.TODO()
+		ctrl    *gomock.Controller
+		c       *mockclient.MockClient
+		handler admission.Handler
+		secret  *corev1.Secret
+		mrs     []resourcesv1alpha1.ManagedResource
+	)
+
+	newHandler := func(policy Policy) {
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+		handler = NewHandler(c, managedresources.NewClassFilter("seed"), policy)
+		Expect(admission.InjectDecoderInto(decoder, handler)).To(BeTrue())
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		newHandler(Policy{MaxSize: 10, KeyExtensions: []string{".yaml", ".json"}})
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "managedresource-foo"},
+			Data:       map[string][]byte{"foo.yaml": []byte("foo"), "bar.json": []byte("bar")},
+		}
+		mrs = []resourcesv1alpha1.ManagedResource{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: secret.Namespace, Name: "foo"},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				Class:      pointer.StringPtr("seed"),
+				SecretRefs: []corev1.LocalObjectReference{{Name: secret.Name}},
+			},
+		}}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	request := func() admission.Request {
+		raw, err := json.Marshal(secret)
+		Expect(err).NotTo(HaveOccurred())
+		return admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+			Operation: v1beta1.Update,
+			Namespace: secret.Namespace,
+			Name:      secret.Name,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	expectManagedResources := func() {
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).
+			DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+				list.(*resourcesv1alpha1.ManagedResourceList).Items = mrs
+				return nil
+			})
+	}
+
+	It("should allow secrets following the policy", func() {
+		expectManagedResources()
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should reject secrets exceeding the maximum size", func() {
+		secret.Data["baz.yaml"] = []byte("bazbaz")
+		expectManagedResources()
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(string(resp.Result.Reason)).To(ContainSubstring(`secret is referenced by ManagedResource "foo" and violates the policy of class "seed"`))
+		Expect(string(resp.Result.Reason)).To(ContainSubstring("size of 12 bytes exceeds the maximum of 10 bytes"))
+	})
+
+	It("should reject secrets with keys without the required extensions", func() {
+		secret.Data["baz"] = []byte("b")
+		secret.Data["config.yml"] = []byte("c")
+		expectManagedResources()
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(string(resp.Result.Reason)).To(ContainSubstring("keys baz, config.yml do not have one of the extensions .json, .yaml"))
+	})
+
+	It("should not enforce conventions which are not configured", func() {
+		newHandler(Policy{MaxSize: 10})
+		secret.Data = map[string][]byte{"foo": []byte("foo")}
+		expectManagedResources()
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should allow secrets referenced by ManagedResources of other classes only", func() {
+		secret.Data["baz"] = []byte("bazbazbazbaz")
+		mrs[0].Spec.Class = nil
+		expectManagedResources()
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should allow secrets not referenced by any ManagedResource", func() {
+		secret.Data["baz"] = []byte("bazbazbazbaz")
+		mrs[0].Spec.SecretRefs = []corev1.LocalObjectReference{{Name: "other"}}
+		expectManagedResources()
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should return an error if the ManagedResources cannot be listed", func() {
+		c.EXPECT().List(ctx, gomock.AssignableToTypeOf(&resourcesv1alpha1.ManagedResourceList{}), client.InNamespace(secret.Namespace), client.MatchingFields{managedresources.SecretRefsIndex: secret.Name}).Return(fmt.Errorf("fake"))
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(BeEquivalentTo(500))
+	})
+})
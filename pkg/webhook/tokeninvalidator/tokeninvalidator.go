@@ -0,0 +1,108 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokeninvalidator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// Path is the path under which the token invalidator webhook is served.
+	Path = "/mutate-service-account-token-secrets"
+
+	// ProjectedTokenOnlyLabel is a label on ServiceAccounts. If set to true, the token in the secrets automatically
+	// generated for the ServiceAccount is invalidated, so that only projected tokens can be used.
+	ProjectedTokenOnlyLabel = "resources.gardener.cloud/projected-token-only"
+	// TokenInvalidatedLabel is a label set on secrets whose token has been invalidated.
+	TokenInvalidatedLabel = "resources.gardener.cloud/token-invalidated"
+)
+
+// invalidToken replaces the token of invalidated secrets. It must not be empty, as otherwise the token controller
+// would generate a new token.
+var invalidToken = []byte("\u0000\u0000\u0000\u0000\u0000\u0000\u0000\u0000")
+
+type handler struct {
+	reader  client.Reader
+	decoder *admission.Decoder
+}
+
+// NewHandler returns an admission handler invalidating the tokens of ServiceAccount token secrets whose ServiceAccount
+// is labeled with `ProjectedTokenOnlyLabel`. The given reader is used to read ServiceAccounts.
+func NewHandler(reader client.Reader) admission.Handler {
+	return &handler{reader: reader}
+}
+
+// InjectDecoder implements `admission.DecoderInjector`.
+func (h *handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle implements `admission.Handler`.
+func (h *handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	secret := &corev1.Secret{}
+	if err := h.decoder.Decode(req, secret); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if secret.Type != corev1.SecretTypeServiceAccountToken {
+		return admission.Allowed("not a service account token secret")
+	}
+
+	serviceAccountName := secret.Annotations[corev1.ServiceAccountNameKey]
+	if len(serviceAccountName) == 0 {
+		return admission.Allowed("secret does not reference a service account")
+	}
+
+	serviceAccount := &corev1.ServiceAccount{}
+	if err := h.reader.Get(ctx, client.ObjectKey{Namespace: req.Namespace, Name: serviceAccountName}, serviceAccount); err != nil {
+		if apierrors.IsNotFound(err) {
+			return admission.Allowed("service account not found")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if projectedTokenOnly, _ := strconv.ParseBool(serviceAccount.Labels[ProjectedTokenOnlyLabel]); !projectedTokenOnly {
+		return admission.Allowed("service account does not use projected tokens only")
+	}
+
+	if bytes.Equal(secret.Data[corev1.ServiceAccountTokenKey], invalidToken) && secret.Labels[TokenInvalidatedLabel] == "true" {
+		return admission.Allowed("token is already invalidated")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[corev1.ServiceAccountTokenKey] = invalidToken
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[TokenInvalidatedLabel] = "true"
+
+	marshaled, err := json.Marshal(secret)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
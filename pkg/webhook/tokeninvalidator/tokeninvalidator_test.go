@@ -0,0 +1,162 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokeninvalidator_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	mockclient "github.com/gardener/gardener-resource-manager/pkg/mock/controller-runtime/client"
+	. "github.com/gardener/gardener-resource-manager/pkg/webhook/tokeninvalidator"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("TokenInvalidator", func() {
+	var (
+		ctx     = context.TODO()
+		ctrl    *gomock.Controller
+		c       *mockclient.MockClient
+		handler admission.Handler
+		secret  *corev1.Secret
+		saKey   = client.ObjectKey{Namespace: "default", Name: "foo"}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+		handler = NewHandler(c)
+		Expect(admission.InjectDecoderInto(decoder, handler)).To(BeTrue())
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   saKey.Namespace,
+				Name:        "foo-token-abcde",
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: saKey.Name},
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+			Data: map[string][]byte{corev1.ServiceAccountTokenKey: []byte("token"), "namespace": []byte("default")},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	request := func() admission.Request {
+		raw, err := json.Marshal(secret)
+		Expect(err).NotTo(HaveOccurred())
+		return admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+			Operation: v1beta1.Create,
+			Namespace: secret.Namespace,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	expectServiceAccount := func(labels map[string]string) {
+		c.EXPECT().Get(ctx, saKey, gomock.AssignableToTypeOf(&corev1.ServiceAccount{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*corev1.ServiceAccount).Labels = labels
+				return nil
+			})
+	}
+
+	It("should invalidate the token if the service account uses projected tokens only", func() {
+		expectServiceAccount(map[string]string{ProjectedTokenOnlyLabel: "true"})
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+
+		patch, err := json.Marshal(resp.Patches)
+		Expect(err).NotTo(HaveOccurred())
+		decodedPatch, err := jsonpatch.DecodePatch(patch)
+		Expect(err).NotTo(HaveOccurred())
+		patched, err := decodedPatch.Apply(request().Object.Raw)
+		Expect(err).NotTo(HaveOccurred())
+
+		invalidated := &corev1.Secret{}
+		Expect(json.Unmarshal(patched, invalidated)).To(Succeed())
+		Expect(invalidated.Labels).To(HaveKeyWithValue(TokenInvalidatedLabel, "true"))
+		Expect(invalidated.Data[corev1.ServiceAccountTokenKey]).NotTo(BeEmpty())
+		Expect(invalidated.Data[corev1.ServiceAccountTokenKey]).NotTo(Equal([]byte("token")))
+		Expect(invalidated.Data["namespace"]).To(Equal([]byte("default")))
+	})
+
+	It("should not patch already invalidated secrets", func() {
+		expectServiceAccount(map[string]string{ProjectedTokenOnlyLabel: "true"})
+		resp := handler.Handle(ctx, request())
+		patch, err := json.Marshal(resp.Patches)
+		Expect(err).NotTo(HaveOccurred())
+		decodedPatch, err := jsonpatch.DecodePatch(patch)
+		Expect(err).NotTo(HaveOccurred())
+		patched, err := decodedPatch.Apply(request().Object.Raw)
+		Expect(err).NotTo(HaveOccurred())
+		secret = &corev1.Secret{}
+		Expect(json.Unmarshal(patched, secret)).To(Succeed())
+
+		expectServiceAccount(map[string]string{ProjectedTokenOnlyLabel: "true"})
+		resp = handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should not modify the token if the service account is not labeled", func() {
+		expectServiceAccount(nil)
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should not modify the token if the service account does not exist", func() {
+		c.EXPECT().Get(ctx, saKey, gomock.AssignableToTypeOf(&corev1.ServiceAccount{})).
+			Return(apierrors.NewNotFound(corev1.Resource("serviceaccounts"), saKey.Name))
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should return an error if the service account cannot be read", func() {
+		c.EXPECT().Get(ctx, saKey, gomock.AssignableToTypeOf(&corev1.ServiceAccount{})).Return(fmt.Errorf("fake"))
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(BeEquivalentTo(500))
+	})
+
+	It("should ignore other secrets", func() {
+		secret.Type = corev1.SecretTypeOpaque
+
+		resp := handler.Handle(ctx, request())
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+})
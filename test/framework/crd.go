@@ -0,0 +1,78 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1beta1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1beta1"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagedResourceCRD returns the CustomResourceDefinition of ManagedResources, which is installed into the source
+// cluster of every test environment. It equals the CustomResourceDefinition in `example/10-crd-managedresource.yaml`.
+func ManagedResourceCRD() *apiextensionsv1beta1.CustomResourceDefinition {
+	return &apiextensionsv1beta1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1beta1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "managedresources." + resourcesv1alpha1.SchemeGroupVersion.Group},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group: resourcesv1alpha1.SchemeGroupVersion.Group,
+			Versions: []apiextensionsv1beta1.CustomResourceDefinitionVersion{
+				{Name: resourcesv1alpha1.SchemeGroupVersion.Version, Served: true, Storage: true},
+				{Name: resourcesv1beta1.SchemeGroupVersion.Version, Served: true, Storage: false},
+			},
+			Version: resourcesv1alpha1.SchemeGroupVersion.Version,
+			Scope:   apiextensionsv1beta1.NamespaceScoped,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Plural:     "managedresources",
+				Singular:   "managedresource",
+				Kind:       "ManagedResource",
+				ShortNames: []string{"mr"},
+			},
+			AdditionalPrinterColumns: []apiextensionsv1beta1.CustomResourceColumnDefinition{
+				{
+					Name:        "Class",
+					Type:        "string",
+					Description: "The class identifies which resource manager is responsible for this ManagedResource.",
+					JSONPath:    ".spec.class",
+				},
+				{
+					Name:        "Applied",
+					Type:        "string",
+					Description: "Indicates whether all resources have been applied.",
+					JSONPath:    `.status.conditions[?(@.type=="ResourcesApplied")].status`,
+				},
+				{
+					Name:        "Healthy",
+					Type:        "string",
+					Description: "Indicates whether all resources are healthy.",
+					JSONPath:    `.status.conditions[?(@.type=="ResourcesHealthy")].status`,
+				},
+				{
+					Name:     "Age",
+					Type:     "date",
+					JSONPath: ".metadata.creationTimestamp",
+				},
+			},
+			Subresources: &apiextensionsv1beta1.CustomResourceSubresources{
+				Status: &apiextensionsv1beta1.CustomResourceSubresourceStatus{},
+			},
+		},
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package framework contains helpers for integration tests of ManagedResources. It starts the source and the target
+// cluster with envtest (which requires the `kube-apiserver` and `etcd` binaries in `$KUBEBUILDER_ASSETS` or
+// `/usr/local/kubebuilder/bin`), runs the gardener-resource-manager against them, and creates and inspects
+// ManagedResources.
+package framework
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gardener/gardener-resource-manager/cmd/gardener-resource-manager/app"
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1beta1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1beta1"
+
+	apiextensionsinstall "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/yaml"
+)
+
+// Scheme is the scheme of the clients of a test environment. It contains the standard APIs, CustomResourceDefinitions
+// and ManagedResources.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
+	apiextensionsinstall.Install(Scheme)
+	utilruntime.Must(resourcesv1alpha1.AddToScheme(Scheme))
+	utilruntime.Must(resourcesv1beta1.AddToScheme(Scheme))
+}
+
+// BinariesAvailable returns true if the binaries needed for starting a test environment are available, e.g. for
+// skipping integration tests otherwise.
+func BinariesAvailable() bool {
+	dir := os.Getenv("KUBEBUILDER_ASSETS")
+	if dir == "" {
+		dir = "/usr/local/kubebuilder/bin"
+	}
+	for _, binary := range []string{"kube-apiserver", "etcd"} {
+		if _, err := os.Stat(filepath.Join(dir, binary)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Options configures a test environment.
+type Options struct {
+	// SeparateTargetCluster starts a second control plane for the target cluster. Otherwise, the source cluster is the
+	// target cluster as well.
+	SeparateTargetCluster bool
+	// TargetCRDs are installed into the target cluster, e.g. the CustomResourceDefinitions of custom resources
+	// contained in ManagedResources.
+	TargetCRDs []*apiextensionsv1beta1.CustomResourceDefinition
+	// TargetCRDDirectoryPaths are directories containing manifests of CustomResourceDefinitions which are installed
+	// into the target cluster.
+	TargetCRDDirectoryPaths []string
+}
+
+// Environment is a test environment consisting of a source cluster containing the ManagedResources, and a target
+// cluster to which their objects are applied.
+type Environment struct {
+	// SourceConfig is the config of the source cluster.
+	SourceConfig *rest.Config
+	// TargetConfig is the config of the target cluster, it equals SourceConfig if the clusters are not separate.
+	TargetConfig *rest.Config
+	// SourceClient is an uncached client for the source cluster.
+	SourceClient client.Client
+	// TargetClient is an uncached client for the target cluster.
+	TargetClient client.Client
+
+	source *envtest.Environment
+	target *envtest.Environment
+	dir    string
+}
+
+// Start starts a new test environment. It has to be stopped with Stop, even if an error is returned.
+func Start(opts Options) (*Environment, error) {
+	env := &Environment{
+		source: &envtest.Environment{CRDs: []*apiextensionsv1beta1.CustomResourceDefinition{ManagedResourceCRD()}},
+	}
+
+	if opts.SeparateTargetCluster {
+		env.target = &envtest.Environment{CRDs: opts.TargetCRDs, CRDDirectoryPaths: opts.TargetCRDDirectoryPaths}
+	} else {
+		env.source.CRDs = append(env.source.CRDs, opts.TargetCRDs...)
+		env.source.CRDDirectoryPaths = opts.TargetCRDDirectoryPaths
+	}
+
+	var err error
+	if env.SourceConfig, err = env.source.Start(); err != nil {
+		return env, fmt.Errorf("could not start source cluster: %+v", err)
+	}
+	env.TargetConfig = env.SourceConfig
+	if env.target != nil {
+		if env.TargetConfig, err = env.target.Start(); err != nil {
+			return env, fmt.Errorf("could not start target cluster: %+v", err)
+		}
+	}
+
+	if env.SourceClient, err = client.New(env.SourceConfig, client.Options{Scheme: Scheme}); err != nil {
+		return env, fmt.Errorf("could not create client for source cluster: %+v", err)
+	}
+	if env.TargetClient, err = client.New(env.TargetConfig, client.Options{Scheme: Scheme}); err != nil {
+		return env, fmt.Errorf("could not create client for target cluster: %+v", err)
+	}
+
+	if env.dir, err = ioutil.TempDir("", "gardener-resource-manager-test-"); err != nil {
+		return env, err
+	}
+	if err := writeKubeconfig(env.SourceKubeconfig(), env.SourceConfig); err != nil {
+		return env, fmt.Errorf("could not write kubeconfig of source cluster: %+v", err)
+	}
+	if err := writeKubeconfig(env.TargetKubeconfig(), env.TargetConfig); err != nil {
+		return env, fmt.Errorf("could not write kubeconfig of target cluster: %+v", err)
+	}
+
+	return env, nil
+}
+
+// Stop stops the clusters of the test environment and deletes its kubeconfigs.
+func (e *Environment) Stop() error {
+	var errs []error
+	if e.target != nil {
+		if err := e.target.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("could not stop target cluster: %+v", err))
+		}
+	}
+	if err := e.source.Stop(); err != nil {
+		errs = append(errs, fmt.Errorf("could not stop source cluster: %+v", err))
+	}
+	if e.dir != "" {
+		if err := os.RemoveAll(e.dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("could not stop test environment: %v", errs)
+	}
+	return nil
+}
+
+// SourceKubeconfig returns the path of a kubeconfig for the source cluster.
+func (e *Environment) SourceKubeconfig() string {
+	return filepath.Join(e.dir, "source.yaml")
+}
+
+// TargetKubeconfig returns the path of a kubeconfig for the target cluster.
+func (e *Environment) TargetKubeconfig() string {
+	return filepath.Join(e.dir, "target.yaml")
+}
+
+// StartResourceManager runs the gardener-resource-manager against the clusters of the test environment until the
+// given context is cancelled. Leader election, the metrics and the health endpoints are disabled, the given
+// arguments are passed in addition (e.g. `--sync-period=10s`). The returned channel receives the result of the
+// gardener-resource-manager once it has stopped.
+func (e *Environment) StartResourceManager(ctx context.Context, args ...string) <-chan error {
+	cmd := app.NewControllerManagerCommand(ctx)
+	cmd.SetArgs(append([]string{
+		"--kubeconfig=" + e.SourceKubeconfig(),
+		"--target-kubeconfig=" + e.TargetKubeconfig(),
+		"--leader-election=false",
+		"--metrics-bind-address=0",
+		"--health-bind-address=",
+	}, args...))
+
+	result := make(chan error, 1)
+	go func() {
+		result <- cmd.Execute()
+	}()
+	return result
+}
+
+// writeKubeconfig writes a kubeconfig for the given config to the given path.
+func writeKubeconfig(path string, config *rest.Config) error {
+	const name = "envtest"
+
+	kubeconfig := clientcmdv1.Config{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: []clientcmdv1.NamedCluster{{Name: name, Cluster: clientcmdv1.Cluster{
+			Server:                   config.Host,
+			CertificateAuthorityData: config.CAData,
+		}}},
+		AuthInfos: []clientcmdv1.NamedAuthInfo{{Name: name, AuthInfo: clientcmdv1.AuthInfo{
+			ClientCertificateData: config.CertData,
+			ClientKeyData:         config.KeyData,
+			Token:                 config.BearerToken,
+		}}},
+		Contexts:       []clientcmdv1.NamedContext{{Name: name, Context: clientcmdv1.Context{Cluster: name, AuthInfo: name}}},
+		CurrentContext: name,
+	}
+
+	data, err := yaml.Marshal(kubeconfig)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
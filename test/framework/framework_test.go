@@ -0,0 +1,119 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework_test
+
+import (
+	"context"
+	"io/ioutil"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/test/framework"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Framework", func() {
+	Describe("#ManagedResourceCRD", func() {
+		It("should equal the example CustomResourceDefinition", func() {
+			data, err := ioutil.ReadFile("../../example/10-crd-managedresource.yaml")
+			Expect(err).NotTo(HaveOccurred())
+
+			crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+			Expect(yaml.Unmarshal(data, crd)).To(Succeed())
+			Expect(ManagedResourceCRD()).To(Equal(crd))
+		})
+	})
+
+	Describe("#NewSecret", func() {
+		It("should encode the objects with their API version and kind", func() {
+			secret, err := NewSecret("foo", "bar", &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cm"},
+				Data:       map[string]string{"foo": "bar"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Namespace).To(Equal("foo"))
+			Expect(secret.Name).To(Equal("bar"))
+			Expect(string(secret.Data[SecretDataKey])).To(Equal(`---
+apiVersion: v1
+data:
+  foo: bar
+kind: ConfigMap
+metadata:
+  creationTimestamp: null
+  name: cm
+  namespace: default
+`))
+		})
+	})
+
+	Describe("Environment", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			env    *Environment
+		)
+
+		BeforeEach(func() {
+			if !BinariesAvailable() {
+				Skip("the envtest binaries are not available")
+			}
+
+			ctx, cancel = context.WithTimeout(context.Background(), 2*time.Minute)
+
+			var err error
+			env, err = Start(Options{SeparateTargetCluster: true})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			if env == nil {
+				return
+			}
+			cancel()
+			Expect(env.Stop()).To(Succeed())
+		})
+
+		It("should apply and delete the objects of ManagedResources", func() {
+			result := env.StartResourceManager(ctx, "--sync-period=10s")
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cm"},
+				Data:       map[string]string{"foo": "bar"},
+			}
+			mr, err := env.CreateManagedResource(ctx, metav1.NamespaceDefault, "test", configMap)
+			Expect(err).NotTo(HaveOccurred())
+
+			key := types.NamespacedName{Namespace: mr.Namespace, Name: mr.Name}
+			Expect(WaitForCondition(ctx, env.SourceClient, key, resourcesv1alpha1.ResourcesApplied, resourcesv1alpha1.ConditionTrue)).To(Succeed())
+			Expect(env.TargetClient.Get(ctx, types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, &corev1.ConfigMap{})).To(Succeed())
+
+			Expect(env.SourceClient.Delete(ctx, mr)).To(Succeed())
+			Expect(WaitUntilDeleted(ctx, env.SourceClient, key)).To(Succeed())
+			err = env.TargetClient.Get(ctx, types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, &corev1.ConfigMap{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			cancel()
+			Eventually(result, time.Minute).Should(Receive(BeNil()))
+		})
+	})
+})
@@ -0,0 +1,141 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+	"github.com/gardener/gardener-resource-manager/pkg/manager"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultPollInterval is the interval in which the Wait* functions check the state of a ManagedResource.
+const DefaultPollInterval = 500 * time.Millisecond
+
+// SecretDataKey is the key under which NewSecret stores the objects.
+const SecretDataKey = "objects.yaml"
+
+// NewSecret returns a secret containing the given objects as YAML documents. The API version and kind of the
+// objects are looked up in Scheme if they are not set.
+func NewSecret(namespace, name string, objs ...runtime.Object) (*corev1.Secret, error) {
+	var data bytes.Buffer
+	for _, obj := range objs {
+		obj = obj.DeepCopyObject()
+		if obj.GetObjectKind().GroupVersionKind().Empty() {
+			gvk, err := apiutil.GVKForObject(obj, Scheme)
+			if err != nil {
+				return nil, err
+			}
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		data.WriteString("---\n")
+		data.Write(out)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       map[string][]byte{SecretDataKey: data.Bytes()},
+	}, nil
+}
+
+// CreateManagedResource creates a secret containing the given objects and a ManagedResource referencing it in the
+// source cluster. Both have the given namespace and name, the ManagedResource has the default resource class.
+func (e *Environment) CreateManagedResource(ctx context.Context, namespace, name string, objs ...runtime.Object) (*resourcesv1alpha1.ManagedResource, error) {
+	secret, err := NewSecret(namespace, name, objs...)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.SourceClient.Create(ctx, secret); err != nil {
+		return nil, fmt.Errorf("could not create secret %s/%s: %+v", namespace, name, err)
+	}
+
+	mr := &resourcesv1alpha1.ManagedResource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: resourcesv1alpha1.ManagedResourceSpec{
+			SecretRefs: []corev1.LocalObjectReference{{Name: secret.Name}},
+		},
+	}
+	if err := e.SourceClient.Create(ctx, mr); err != nil {
+		return nil, fmt.Errorf("could not create ManagedResource %s/%s: %+v", namespace, name, err)
+	}
+	return mr, nil
+}
+
+// CreateNamespace creates a namespace with the given name in the given cluster unless it exists already.
+func CreateNamespace(ctx context.Context, c client.Client, name string) error {
+	if err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// WaitForCondition waits until the condition of the given type of the ManagedResource with the given key has the
+// given status and reflects its current generation, or until the given context is cancelled.
+func WaitForCondition(ctx context.Context, c client.Client, key types.NamespacedName, conditionType resourcesv1alpha1.ConditionType, status resourcesv1alpha1.ConditionStatus) error {
+	var lastState string
+
+	err := wait.PollImmediateUntil(DefaultPollInterval, func() (bool, error) {
+		mr := &resourcesv1alpha1.ManagedResource{}
+		if err := c.Get(ctx, key, mr); err != nil {
+			if apierrors.IsNotFound(err) {
+				lastState = "ManagedResource does not exist"
+				return false, nil
+			}
+			return false, err
+		}
+
+		if mr.Status.ObservedGeneration != mr.Generation {
+			lastState = fmt.Sprintf("generation %d not yet observed", mr.Generation)
+			return false, nil
+		}
+
+		condition := resourcesv1alpha1helper.GetCondition(mr.Status.Conditions, conditionType)
+		if condition == nil {
+			lastState = fmt.Sprintf("condition %s not yet set", conditionType)
+			return false, nil
+		}
+		lastState = fmt.Sprintf("condition %s is %s (%s): %s", conditionType, condition.Status, condition.Reason, condition.Message)
+		return condition.Status == status, nil
+	}, ctx.Done())
+	if err != nil {
+		return fmt.Errorf("ManagedResource %s did not reach %s=%s, %s: %+v", key, conditionType, status, lastState, err)
+	}
+	return nil
+}
+
+// WaitUntilDeleted waits until the ManagedResource with the given key does not exist anymore, or until the given
+// context is cancelled.
+func WaitUntilDeleted(ctx context.Context, c client.Client, key types.NamespacedName) error {
+	return manager.WaitUntilDeleted(ctx, c, key, manager.WaitOptions{Interval: DefaultPollInterval})
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	resourcesv1alpha1helper "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1/helper"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LabelLoadTest is the label of all objects created by the load test.
+const LabelLoadTest = "load-test.resources.gardener.cloud/generated"
+
+// Scheme is the scheme of the client used by the load test.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
+	utilruntime.Must(resourcesv1alpha1.AddToScheme(Scheme))
+}
+
+// Options configures a load test.
+type Options struct {
+	// Namespace is the namespace of the ManagedResources and their secrets.
+	Namespace string
+	// Class is the resource class of the ManagedResources.
+	Class string
+	// ManagedResources is the number of ManagedResources.
+	ManagedResources int
+	// Objects is the number of objects per ManagedResource.
+	Objects int
+	// ObjectSize is the size of the data of each object in bytes.
+	ObjectSize int
+	// Updates is the number of times the secrets of all ManagedResources are changed.
+	Updates int
+	// MetricsURL is the URL of the metrics endpoint of the gardener-resource-manager.
+	MetricsURL string
+	// Timeout is the maximum duration of each phase.
+	Timeout time.Duration
+	// Cleanup controls whether the ManagedResources and secrets are deleted afterwards.
+	Cleanup bool
+}
+
+// Validate checks whether the options are valid.
+func (o Options) Validate() error {
+	if o.Namespace == "" {
+		return fmt.Errorf("namespace must not be empty")
+	}
+	if o.ManagedResources < 1 || o.Objects < 1 {
+		return fmt.Errorf("number of ManagedResources and objects must be at least 1")
+	}
+	if o.ObjectSize < 0 || o.Updates < 0 {
+		return fmt.Errorf("object size and number of updates must not be negative")
+	}
+	if o.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	return nil
+}
+
+// Harness runs a load test.
+type Harness struct {
+	client  client.Client
+	options Options
+	out     io.Writer
+	scraper *Scraper
+}
+
+// NewHarness creates a new Harness using the given client. Progress is written to the given writer.
+func NewHarness(c client.Client, options Options, out io.Writer) *Harness {
+	h := &Harness{client: c, options: options, out: out}
+	if options.MetricsURL != "" {
+		h.scraper = NewScraper(options.MetricsURL)
+	}
+	return h
+}
+
+// Run creates the ManagedResources, changes their secrets for the configured number of times and waits for the
+// ManagedResources to be reconciled after each change. It returns a report of all finished phases, also if an error
+// occurred.
+func (h *Harness) Run(ctx context.Context) (*Report, error) {
+	report := &Report{ManagedResources: h.options.ManagedResources, Objects: h.options.Objects}
+
+	if err := h.ensureNamespace(ctx); err != nil {
+		return nil, err
+	}
+	if h.options.Cleanup {
+		defer func() {
+			if err := h.cleanup(context.Background()); err != nil {
+				fmt.Fprintf(h.out, "cleanup failed: %v\n", err)
+			}
+		}()
+	}
+
+	for revision := 0; revision <= h.options.Updates; revision++ {
+		name := "create"
+		if revision > 0 {
+			name = fmt.Sprintf("update-%d", revision)
+		}
+
+		phase, err := h.runPhase(ctx, name, revision)
+		if phase != nil {
+			report.Phases = append(report.Phases, *phase)
+		}
+		if err != nil {
+			return report, fmt.Errorf("phase %s failed: %w", name, err)
+		}
+	}
+	return report, nil
+}
+
+func (h *Harness) runPhase(ctx context.Context, name string, revision int) (*Phase, error) {
+	before, err := h.scrape()
+	if err != nil {
+		return nil, err
+	}
+
+	// conditions only have a resolution of seconds, hence the phase starts with a full second so that conditions
+	// updated in the previous phase are not mistaken for this one
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	start := time.Now()
+	fmt.Fprintf(h.out, "%s: writing %d ManagedResources with %d objects each\n", name, h.options.ManagedResources, h.options.Objects)
+
+	for i := 0; i < h.options.ManagedResources; i++ {
+		if err := h.write(ctx, i, revision); err != nil {
+			return nil, err
+		}
+	}
+	written := time.Since(start)
+
+	if err := h.waitUntilReconciled(ctx, start); err != nil {
+		return nil, err
+	}
+
+	after, err := h.scrape()
+	if err != nil {
+		return nil, err
+	}
+
+	phase := newPhase(name, written, time.Since(start), before, after)
+	fmt.Fprintf(h.out, "%s: all ManagedResources reconciled after %s\n", name, phase.Duration)
+	return &phase, nil
+}
+
+func (h *Harness) scrape() (Sample, error) {
+	if h.scraper == nil {
+		return nil, nil
+	}
+	return h.scraper.Scrape()
+}
+
+func (h *Harness) ensureNamespace(ctx context.Context) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: h.options.Namespace}}
+	if err := h.client.Create(ctx, namespace); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("could not create namespace: %w", err)
+	}
+	return nil
+}
+
+func (h *Harness) write(ctx context.Context, index, revision int) error {
+	name := fmt.Sprintf("load-test-%d", index)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: h.options.Namespace, Name: name, Labels: map[string]string{LabelLoadTest: "true"}}}
+	secret.Data = map[string][]byte{"objects.yaml": GenerateObjects(name, h.options.Objects, h.options.ObjectSize, revision)}
+	if err := createOrUpdate(ctx, h.client, secret, func(existing runtime.Object) {
+		existing.(*corev1.Secret).Data = secret.Data
+	}); err != nil {
+		return fmt.Errorf("could not write secret %s: %w", name, err)
+	}
+
+	mr := &resourcesv1alpha1.ManagedResource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: h.options.Namespace, Name: name, Labels: map[string]string{LabelLoadTest: "true"}},
+		Spec:       resourcesv1alpha1.ManagedResourceSpec{SecretRefs: []corev1.LocalObjectReference{{Name: name}}},
+	}
+	if h.options.Class != "" {
+		mr.Spec.Class = &h.options.Class
+	}
+	if err := createOrUpdate(ctx, h.client, mr, func(runtime.Object) {}); err != nil {
+		return fmt.Errorf("could not write ManagedResource %s: %w", name, err)
+	}
+	return nil
+}
+
+// GenerateObjects returns the YAML documents of the given number of ConfigMaps for the given ManagedResource. The
+// data of each ConfigMap has the given size and depends on the revision, so that the ConfigMaps change with it.
+func GenerateObjects(managedResource string, objects, size, revision int) []byte {
+	var (
+		b     strings.Builder
+		value = strings.Repeat("x", size)
+	)
+	for i := 0; i < objects; i++ {
+		fmt.Fprintf(&b, `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s-%d
+  namespace: default
+  labels:
+    %s: "true"
+data:
+  revision: "%d"
+  value: %q
+`, managedResource, i, LabelLoadTest, revision, value)
+	}
+	return []byte(b.String())
+}
+
+func createOrUpdate(ctx context.Context, c client.Client, obj runtime.Object, mutate func(existing runtime.Object)) error {
+	if err := c.Create(ctx, obj.DeepCopyObject()); !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		existing := obj.DeepCopyObject()
+		if err := c.Get(ctx, key, existing); err != nil {
+			return err
+		}
+		mutate(existing)
+		return c.Update(ctx, existing)
+	})
+}
+
+// waitUntilReconciled waits until all ManagedResources have been applied successfully since the given time.
+func (h *Harness) waitUntilReconciled(ctx context.Context, since time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, h.options.Timeout)
+	defer cancel()
+
+	return wait.PollImmediateUntil(time.Second, func() (bool, error) {
+		list := &resourcesv1alpha1.ManagedResourceList{}
+		if err := h.client.List(ctx, list, client.InNamespace(h.options.Namespace), client.MatchingLabels{LabelLoadTest: "true"}); err != nil {
+			return false, err
+		}
+
+		reconciled := 0
+		for _, mr := range list.Items {
+			if IsReconciledSince(&mr, since) {
+				reconciled++
+			}
+		}
+		fmt.Fprintf(h.out, "  %d/%d ManagedResources reconciled\n", reconciled, h.options.ManagedResources)
+		return reconciled == h.options.ManagedResources, nil
+	}, ctx.Done())
+}
+
+// IsReconciledSince returns whether the given ManagedResource has been applied successfully since the given time.
+func IsReconciledSince(mr *resourcesv1alpha1.ManagedResource, since time.Time) bool {
+	if mr.Status.ObservedGeneration != mr.Generation {
+		return false
+	}
+	condition := resourcesv1alpha1helper.GetCondition(mr.Status.Conditions, resourcesv1alpha1.ResourcesApplied)
+	return condition != nil &&
+		condition.Status == resourcesv1alpha1.ConditionTrue &&
+		!condition.LastUpdateTime.Time.Before(since.Truncate(time.Second))
+}
+
+func (h *Harness) cleanup(ctx context.Context) error {
+	fmt.Fprintf(h.out, "cleanup: deleting ManagedResources and secrets\n")
+
+	opts := []client.DeleteAllOfOption{client.InNamespace(h.options.Namespace), client.MatchingLabels{LabelLoadTest: "true"}}
+	if err := h.client.DeleteAllOf(ctx, &resourcesv1alpha1.ManagedResource{}, opts...); err != nil {
+		return fmt.Errorf("could not delete ManagedResources: %w", err)
+	}
+
+	// the secrets are protected by finalizers until the ManagedResources are gone
+	if err := wait.PollImmediate(time.Second, h.options.Timeout, func() (bool, error) {
+		list := &resourcesv1alpha1.ManagedResourceList{}
+		if err := h.client.List(ctx, list, client.InNamespace(h.options.Namespace), client.MatchingLabels{LabelLoadTest: "true"}); err != nil {
+			return false, err
+		}
+		return len(list.Items) == 0, nil
+	}); err != nil {
+		return fmt.Errorf("ManagedResources have not been deleted: %w", err)
+	}
+
+	if err := h.client.DeleteAllOf(ctx, &corev1.Secret{}, opts...); err != nil {
+		return fmt.Errorf("could not delete secrets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command load generates synthetic ManagedResources in a cluster with a running gardener-resource-manager and reports
+// how fast they are reconciled, how many API requests the gardener-resource-manager issues for them and how much
+// memory it needs. See docs/development/load-test.md.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+func main() {
+	// the kubeconfig is taken from the --kubeconfig flag registered by controller-runtime, $KUBECONFIG or the
+	// in-cluster config
+	options := Options{}
+
+	flag.StringVar(&options.Namespace, "namespace", "load-test", "namespace for the ManagedResources and their secrets, it is created if it does not exist")
+	flag.StringVar(&options.Class, "resource-class", "", "resource class of the ManagedResources")
+	flag.IntVar(&options.ManagedResources, "managed-resources", 100, "number of ManagedResources to create")
+	flag.IntVar(&options.Objects, "objects", 10, "number of objects (ConfigMaps) per ManagedResource")
+	flag.IntVar(&options.ObjectSize, "object-size", 1024, "size of the data of each object in bytes")
+	flag.IntVar(&options.Updates, "updates", 1, "number of times the secrets of all ManagedResources are changed after they have been reconciled")
+	flag.StringVar(&options.MetricsURL, "metrics-url", "", "URL of the metrics endpoint of the gardener-resource-manager, e.g. http://localhost:8080/metrics (API requests and memory are not reported if empty)")
+	flag.DurationVar(&options.Timeout, "timeout", 10*time.Minute, "maximum duration to wait for the ManagedResources to be reconciled in each phase")
+	flag.BoolVar(&options.Cleanup, "cleanup", true, "delete the ManagedResources and their secrets afterwards")
+	flag.Parse()
+
+	if err := run(options); err != nil {
+		fmt.Fprintf(os.Stderr, "load test failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(options Options) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("could not load kubeconfig: %w", err)
+	}
+	restConfig.QPS, restConfig.Burst = 100, 200
+
+	c, err := client.New(restConfig, client.Options{Scheme: Scheme})
+	if err != nil {
+		return fmt.Errorf("could not create client: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		defer cancel()
+		<-signals.SetupSignalHandler()
+	}()
+
+	report, err := NewHarness(c, options, os.Stdout).Run(ctx)
+	if report != nil {
+		report.Print(os.Stdout)
+	}
+	return err
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Sample contains the metric families scraped from the metrics endpoint at one point in time.
+type Sample map[string]*dto.MetricFamily
+
+// Scraper scrapes the metrics endpoint of the gardener-resource-manager.
+type Scraper struct {
+	url    string
+	client *http.Client
+}
+
+// NewScraper creates a new Scraper for the given URL.
+func NewScraper(url string) *Scraper {
+	return &Scraper{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Scrape fetches and parses the current metrics.
+func (s *Scraper) Scrape() (Sample, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("could not scrape metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not scrape metrics: unexpected status code %d", resp.StatusCode)
+	}
+	return ParseSample(resp.Body)
+}
+
+// ParseSample parses metrics in the Prometheus text format.
+func ParseSample(r io.Reader) (Sample, error) {
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse metrics: %w", err)
+	}
+	return families, nil
+}
+
+// Sum returns the sum of the values of all series of the given metric whose labels match the given ones.
+func (s Sample) Sum(name string, labels map[string]string) float64 {
+	family, ok := s[name]
+	if !ok {
+		return 0
+	}
+
+	var sum float64
+	for _, metric := range family.GetMetric() {
+		if matchesLabels(metric, labels) {
+			sum += valueOf(metric)
+		}
+	}
+	return sum
+}
+
+// SumBy returns the sums of the values of all series of the given metric grouped by the value of the given label.
+func (s Sample) SumBy(name, label string) map[string]float64 {
+	family, ok := s[name]
+	if !ok {
+		return nil
+	}
+
+	sums := map[string]float64{}
+	for _, metric := range family.GetMetric() {
+		for _, pair := range metric.GetLabel() {
+			if pair.GetName() == label {
+				sums[pair.GetValue()] += valueOf(metric)
+			}
+		}
+	}
+	return sums
+}
+
+func valueOf(metric *dto.Metric) float64 {
+	switch {
+	case metric.Counter != nil:
+		return metric.Counter.GetValue()
+	case metric.Gauge != nil:
+		return metric.Gauge.GetValue()
+	case metric.Untyped != nil:
+		return metric.Untyped.GetValue()
+	}
+	return 0
+}
+
+func matchesLabels(metric *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, pair := range metric.GetLabel() {
+		if value, ok := labels[pair.GetName()]; ok {
+			if value != pair.GetValue() {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(labels)
+}
+
+const (
+	metricRESTClientRequests = "rest_client_requests_total"
+	metricWorkqueueAdds      = "workqueue_adds_total"
+	metricResidentMemory     = "process_resident_memory_bytes"
+)
+
+// Phase is the result of one phase of a load test.
+type Phase struct {
+	// Name is the name of the phase.
+	Name string
+	// Written is the duration it took to write all secrets and ManagedResources.
+	Written time.Duration
+	// Duration is the duration until all ManagedResources were reconciled.
+	Duration time.Duration
+	// Reconciliations is the number of items added to the work queue of the resource controller.
+	Reconciliations float64
+	// Requests is the number of API requests sent by the gardener-resource-manager.
+	Requests float64
+	// RequestsByMethod is the number of API requests sent by the gardener-resource-manager per HTTP method.
+	RequestsByMethod map[string]float64
+	// Memory is the resident memory of the gardener-resource-manager at the end of the phase.
+	Memory float64
+	// HasMetrics is true if metrics were scraped during the phase.
+	HasMetrics bool
+}
+
+func newPhase(name string, written, duration time.Duration, before, after Sample) Phase {
+	phase := Phase{Name: name, Written: written, Duration: duration}
+	if before == nil || after == nil {
+		return phase
+	}
+
+	phase.HasMetrics = true
+	phase.Reconciliations = after.Sum(metricWorkqueueAdds, map[string]string{"name": "resource-controller"}) -
+		before.Sum(metricWorkqueueAdds, map[string]string{"name": "resource-controller"})
+	phase.Requests = after.Sum(metricRESTClientRequests, nil) - before.Sum(metricRESTClientRequests, nil)
+	phase.RequestsByMethod = map[string]float64{}
+	beforeByMethod := before.SumBy(metricRESTClientRequests, "method")
+	for method, count := range after.SumBy(metricRESTClientRequests, "method") {
+		if delta := count - beforeByMethod[method]; delta > 0 {
+			phase.RequestsByMethod[method] = delta
+		}
+	}
+	phase.Memory = after.Sum(metricResidentMemory, nil)
+	return phase
+}
+
+// Report is the result of a load test.
+type Report struct {
+	// ManagedResources is the number of ManagedResources.
+	ManagedResources int
+	// Objects is the number of objects per ManagedResource.
+	Objects int
+	// Phases are the results of all finished phases.
+	Phases []Phase
+}
+
+// Print writes the report as a table to the given writer.
+func (r *Report) Print(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PHASE\tWRITTEN\tRECONCILED\tMR/S\tOBJECTS/S\tRECONCILIATIONS\tREQUESTS\tMEMORY")
+
+	for _, phase := range r.Phases {
+		seconds := phase.Duration.Seconds()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.1f\t%.1f\t", phase.Name, phase.Written.Round(time.Millisecond), phase.Duration.Round(time.Millisecond),
+			float64(r.ManagedResources)/seconds, float64(r.ManagedResources*r.Objects)/seconds)
+
+		if !phase.HasMetrics {
+			fmt.Fprintln(w, "-\t-\t-")
+			continue
+		}
+		fmt.Fprintf(w, "%.0f\t%.0f%s\t%.0fMi\n", phase.Reconciliations, phase.Requests, formatByMethod(phase.RequestsByMethod), phase.Memory/(1<<20))
+	}
+	_ = w.Flush()
+}
+
+func formatByMethod(byMethod map[string]float64) string {
+	if len(byMethod) == 0 {
+		return ""
+	}
+
+	methods := make([]string, 0, len(byMethod))
+	for method := range byMethod {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	s := " ("
+	for i, method := range methods {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%s %.0f", method, byMethod[method])
+	}
+	return s + ")"
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	resourcesv1alpha1 "github.com/gardener/gardener-resource-manager/pkg/apis/resources/v1alpha1"
+	. "github.com/gardener/gardener-resource-manager/test/load"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const metrics = `# TYPE rest_client_requests_total counter
+rest_client_requests_total{code="200",host="a",method="GET"} 10
+rest_client_requests_total{code="200",host="a",method="PATCH"} 5
+rest_client_requests_total{code="409",host="a",method="PATCH"} 1
+# TYPE workqueue_adds_total counter
+workqueue_adds_total{name="resource-controller"} 7
+workqueue_adds_total{name="health-controller"} 3
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes 1.048576e+08
+`
+
+var _ = Describe("Load", func() {
+	Describe("Sample", func() {
+		It("should sum the series of a metric", func() {
+			sample, err := ParseSample(strings.NewReader(metrics))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sample.Sum("rest_client_requests_total", nil)).To(Equal(16.0))
+			Expect(sample.Sum("rest_client_requests_total", map[string]string{"method": "PATCH"})).To(Equal(6.0))
+			Expect(sample.Sum("workqueue_adds_total", map[string]string{"name": "resource-controller"})).To(Equal(7.0))
+			Expect(sample.Sum("workqueue_adds_total", map[string]string{"controller": "resource-controller"})).To(BeZero())
+			Expect(sample.Sum("unknown", nil)).To(BeZero())
+			Expect(sample.SumBy("rest_client_requests_total", "method")).To(Equal(map[string]float64{"GET": 10, "PATCH": 6}))
+		})
+	})
+
+	Describe("Report", func() {
+		It("should print the phases", func() {
+			report := &Report{ManagedResources: 10, Objects: 5, Phases: []Phase{
+				{Name: "create", Written: time.Second, Duration: 2 * time.Second},
+				{Name: "update-1", Written: time.Second, Duration: 5 * time.Second, HasMetrics: true, Reconciliations: 12, Requests: 30,
+					RequestsByMethod: map[string]float64{"PATCH": 20, "GET": 10}, Memory: 100 << 20},
+			}}
+
+			out := &bytes.Buffer{}
+			report.Print(out)
+
+			Expect(strings.Split(strings.TrimSpace(out.String()), "\n")).To(Equal([]string{
+				"PHASE     WRITTEN  RECONCILED  MR/S  OBJECTS/S  RECONCILIATIONS  REQUESTS               MEMORY",
+				"create    1s       2s          5.0   25.0       -                -                      -",
+				"update-1  1s       5s          2.0   10.0       12               30 (GET 10, PATCH 20)  100Mi",
+			}))
+		})
+	})
+
+	Describe("#IsReconciledSince", func() {
+		var (
+			since = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			mr    *resourcesv1alpha1.ManagedResource
+		)
+
+		BeforeEach(func() {
+			mr = &resourcesv1alpha1.ManagedResource{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: resourcesv1alpha1.ManagedResourceStatus{
+					ObservedGeneration: 2,
+					Conditions: []resourcesv1alpha1.ManagedResourceCondition{{
+						Type:           resourcesv1alpha1.ResourcesApplied,
+						Status:         resourcesv1alpha1.ConditionTrue,
+						LastUpdateTime: metav1.NewTime(since),
+					}},
+				},
+			}
+		})
+
+		It("should be true if the resources have been applied since the given time", func() {
+			Expect(IsReconciledSince(mr, since)).To(BeTrue())
+		})
+
+		It("should be false if the resources have been applied before", func() {
+			Expect(IsReconciledSince(mr, since.Add(time.Second))).To(BeFalse())
+		})
+
+		It("should be false if the generation has not been observed", func() {
+			mr.Generation = 3
+			Expect(IsReconciledSince(mr, since)).To(BeFalse())
+		})
+
+		It("should be false if the resources could not be applied", func() {
+			mr.Status.Conditions[0].Status = resourcesv1alpha1.ConditionFalse
+			Expect(IsReconciledSince(mr, since)).To(BeFalse())
+		})
+	})
+})